@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/flare/helpers"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/core/secrets"
+	"github.com/DataDog/datadog-agent/pkg/config/utils"
+	"github.com/DataDog/datadog-agent/pkg/flare/selfflare"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+	"github.com/DataDog/datadog-agent/pkg/util/input"
+)
+
+// selfFlareCliParams are the command-line arguments for the self flares subcommands
+type selfFlareCliParams struct {
+	*command.GlobalParams
+
+	// args are the positional command-line arguments
+	args []string
+
+	customerEmail string
+	autoconfirm   bool
+}
+
+// selfFlareCommands returns the subcommands listing and sending the self flares
+func selfFlareCommands(globalParams *command.GlobalParams) []*cobra.Command {
+	cliParams := &selfFlareCliParams{
+		GlobalParams: globalParams,
+	}
+	runE := func(callback interface{}) func(*cobra.Command, []string) error {
+		return func(_ *cobra.Command, args []string) error {
+			cliParams.args = args
+			return fxutil.OneShot(callback,
+				fx.Supply(cliParams),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewAgentParams(globalParams.ConfFilePath,
+						config.WithExtraConfFiles(globalParams.ExtraConfFilePath),
+						config.WithFleetPoliciesDirPath(globalParams.FleetPoliciesDirPath),
+					),
+					SecretParams: secrets.NewEnabledParams(),
+					LogParams:    log.ForOneShot(command.LoggerName, "off", false),
+				}),
+				core.Bundle(),
+			)
+		}
+	}
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the self flares captured by the agent",
+		Long:  ``,
+		Args:  cobra.NoArgs,
+		RunE:  runE(listSelfFlares),
+	}
+
+	sendCmd := &cobra.Command{
+		Use:   "send <name> [caseID]",
+		Short: "Send a self flare captured by the agent to Datadog",
+		Long:  ``,
+		Args:  cobra.RangeArgs(1, 2),
+		RunE:  runE(sendSelfFlare),
+	}
+	sendCmd.Flags().StringVarP(&cliParams.customerEmail, "email", "e", "", "Your email")
+	sendCmd.Flags().BoolVarP(&cliParams.autoconfirm, "send", "s", false, "Automatically send flare (don't prompt for confirmation)")
+
+	return []*cobra.Command{listCmd, sendCmd}
+}
+
+func listSelfFlares(config config.Component) error {
+	store := selfFlareStore(config)
+	flares, err := store.List()
+	if err != nil {
+		return fmt.Errorf("unable to list the self flares in %s: %w", store.Path(), err)
+	}
+	if len(flares) == 0 {
+		fmt.Fprintf(color.Output, "No self flares in %s\n", store.Path())
+		if !config.GetBool("self_flare.enabled") {
+			fmt.Fprintln(color.Output, color.YellowString("Self flares are disabled, set `self_flare.enabled` to true to enable them."))
+		}
+		return nil
+	}
+
+	w := tabwriter.NewWriter(color.Output, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTRIGGER\tCREATED\tSIZE")
+	for _, flare := range flares {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", flare.Name, flare.Trigger, flare.CreatedAt.Local().Format(time.RFC3339), flare.Size)
+	}
+	return w.Flush()
+}
+
+func sendSelfFlare(config config.Component, cliParams *selfFlareCliParams) error {
+	flare, err := selfFlareStore(config).Get(cliParams.args[0])
+	if err != nil {
+		return err
+	}
+	caseID := ""
+	if len(cliParams.args) > 1 {
+		caseID = cliParams.args[1]
+	}
+
+	customerEmail := cliParams.customerEmail
+	if customerEmail == "" {
+		customerEmail, err = input.AskForEmail()
+		if err != nil {
+			fmt.Println("Error reading email, please retry or contact support")
+			return err
+		}
+	}
+
+	fmt.Fprintf(color.Output, "%s is going to be uploaded to Datadog\n", color.YellowString(flare.Path))
+	if !cliParams.autoconfirm {
+		confirmation := input.AskForConfirmation("Are you sure you want to upload a flare? [y/N]")
+		if !confirmation {
+			fmt.Fprintln(color.Output, "Aborting.")
+			return nil
+		}
+	}
+
+	response, err := helpers.SendTo(config, flare.Path, caseID, customerEmail, config.GetString("api_key"), utils.GetInfraEndpoint(config), helpers.NewLocalFlareSource())
+	fmt.Println(response)
+	return err
+}
+
+func selfFlareStore(config config.Component) *selfflare.Store {
+	cfg := selfflare.ReadConfig(config)
+	return selfflare.NewStore(cfg.Path, cfg.MaxCount, cfg.MaxSize)
+}
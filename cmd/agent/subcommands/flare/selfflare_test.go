@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/cmd/agent/command"
+	"github.com/DataDog/datadog-agent/comp/core"
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/pkg/flare/selfflare"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestListCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"flare", "list"},
+		listSelfFlares,
+		func(_ core.BundleParams) {})
+}
+
+func TestSendCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"flare", "send", "selfflare-20240301T123015.250Z-panic.zip", "1234", "--email", "user@example.com"},
+		sendSelfFlare,
+		func(cliParams *selfFlareCliParams) {
+			require.Equal(t, []string{"selfflare-20240301T123015.250Z-panic.zip", "1234"}, cliParams.args)
+			require.Equal(t, "user@example.com", cliParams.customerEmail)
+		})
+}
+
+func TestListSelfFlares(t *testing.T) {
+	cfg := config.NewMock(t)
+	cfg.SetWithoutSource("self_flare.path", t.TempDir())
+	require.NoError(t, listSelfFlares(cfg))
+
+	_, err := selfFlareStore(cfg).Write(selfflare.TriggerMemory, time.Now(), nil)
+	require.NoError(t, err)
+	require.NoError(t, listSelfFlares(cfg))
+}
+
+func TestSendSelfFlareUnknown(t *testing.T) {
+	cfg := config.NewMock(t)
+	cfg.SetWithoutSource("self_flare.path", t.TempDir())
+
+	err := sendSelfFlare(cfg, &selfFlareCliParams{args: []string{"selfflare-20240301T123015.250Z-panic.zip"}})
+	require.Error(t, err)
+}
@@ -490,7 +490,7 @@ func startAgent(
 	_ internalAPI.Component,
 	invChecks inventorychecks.Component,
 	logReceiver optional.Option[integrations.Component],
-	_ status.Component,
+	statusComponent status.Component,
 	collector collector.Component,
 	cfg config.Component,
 	_ cloudfoundrycontainer.Component,
@@ -521,6 +521,8 @@ func startAgent(
 	telemetryHandler := telemetry.Handler()
 
 	http.Handle("/telemetry", telemetryHandler)
+	// The status providers can be scraped by Prometheus on the expvar server
+	http.Handle("/status/openmetrics", status.OpenMetricsHandler(statusComponent))
 
 	ctx, _ := pkgcommon.GetMainCtxCancel()
 
@@ -7,11 +7,17 @@
 package snmp
 
 import (
+	"bufio"
+	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/DataDog/datadog-agent/cmd/agent/command"
@@ -31,6 +37,7 @@ import (
 	snmpscanfx "github.com/DataDog/datadog-agent/comp/snmpscan/fx"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/snmplog"
 	"github.com/DataDog/datadog-agent/pkg/snmp/snmpparse"
+	"github.com/DataDog/datadog-agent/pkg/snmp/snmpsim"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 
 	"github.com/gosnmp/gosnmp"
@@ -44,6 +51,7 @@ const (
 	defaultTimeout                 = 10 // Timeout better suited to walking
 	defaultRetries                 = 3
 	defaultUseUnconnectedUDPSocket = false
+	defaultSimulateListenAddress   = "127.0.0.1:1161"
 )
 
 var authOpts = NewOptions(OptPairs[gosnmp.SnmpV3AuthProtocol]{
@@ -87,6 +95,15 @@ type snmpConnectionParams struct {
 	// fields that aren't part of snmpparse.SNMPConfig
 	SecurityLevel           string
 	UseUnconnectedUDPSocket bool
+	// RecordFile is the .snmprec file the walk is written to, if set
+	RecordFile string
+}
+
+type simulateParams struct {
+	// ListenAddress is the UDP address the simulated device listens on
+	ListenAddress string
+	// EngineID is the SNMP v3 engine ID of the simulated device, in hexadecimal
+	EngineID string
 }
 
 // configErr wraps any error caused by invalid configuration.
@@ -173,8 +190,56 @@ func Commands(globalParams *command.GlobalParams) []*cobra.Command {
 	snmpWalkCmd.Flags().IntVarP(&connParams.Timeout, "timeout", "t", defaultTimeout, "Set the request timeout (in seconds)")
 	snmpWalkCmd.Flags().BoolVar(&connParams.UseUnconnectedUDPSocket, "use-unconnected-udp-socket", defaultUseUnconnectedUDPSocket, "If specified, changes net connection to be unconnected UDP socket")
 
+	// recording options
+	snmpWalkCmd.Flags().StringVar(&connParams.RecordFile, "record", "", "Write the walk to this file in the .snmprec format, to be served by 'snmp simulate'")
+
 	snmpCmd.AddCommand(snmpWalkCmd)
 
+	simParams := &simulateParams{}
+	simConnParams := &snmpConnectionParams{}
+	snmpSimulateCmd := &cobra.Command{
+		Use:   "simulate <file>",
+		Short: "Simulate a device from a recorded walk.",
+		Long: `Serve a recorded walk as a local SNMP agent answering GET, GETNEXT and GETBULK requests, to develop and test profiles without the real device.
+		The file is either a .snmprec file, as written by 'snmp walk --record', or the output of 'snmpwalk -On'.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+
+			err := fxutil.OneShot(snmpSimulate,
+				fx.Supply(simConnParams, simParams),
+				fx.Provide(func() argsType { return args }),
+				fx.Supply(core.BundleParams{
+					ConfigParams: config.NewAgentParams(globalParams.ConfFilePath, config.WithExtraConfFiles(globalParams.ExtraConfFilePath), config.WithFleetPoliciesDirPath(globalParams.FleetPoliciesDirPath)),
+					SecretParams: secrets.NewEnabledParams(),
+					LogParams:    log.ForOneShot(command.LoggerName, "info", true)}),
+				core.Bundle(),
+			)
+			if err != nil {
+				var ue configErr
+				if errors.As(err, &ue) {
+					fmt.Println("Usage:", cmd.UseLine())
+				}
+				return err
+			}
+			return nil
+		},
+	}
+	snmpSimulateCmd.Flags().StringVar(&simParams.ListenAddress, "listen-address", defaultSimulateListenAddress, "Set the UDP address to listen on")
+	snmpSimulateCmd.Flags().VarP(versionOpts.Flag(&simConnParams.Version), "snmp-version", "v", fmt.Sprintf("Specify SNMP version to accept (%s)", versionOpts.OptsStr()))
+
+	// snmp v1 or v2c specific
+	snmpSimulateCmd.Flags().StringVarP(&simConnParams.CommunityString, "community-string", "C", "", "Set the community string")
+
+	// snmp v3 specific
+	snmpSimulateCmd.Flags().VarP(authOpts.Flag(&simConnParams.AuthProtocol), "auth-protocol", "a", fmt.Sprintf("Set authentication protocol (%s)", authOpts.OptsStr()))
+	snmpSimulateCmd.Flags().StringVarP(&simConnParams.AuthKey, "auth-key", "A", "", "Set authentication protocol pass phrase")
+	snmpSimulateCmd.Flags().VarP(levelOpts.Flag(&simConnParams.SecurityLevel), "security-level", "l", fmt.Sprintf("Set the minimum security level (%s)", levelOpts.OptsStr()))
+	snmpSimulateCmd.Flags().StringVarP(&simConnParams.Username, "user-name", "u", "", "Set security name")
+	snmpSimulateCmd.Flags().VarP(privOpts.Flag(&simConnParams.PrivProtocol), "priv-protocol", "x", fmt.Sprintf("Set privacy protocol (%s)", privOpts.OptsStr()))
+	snmpSimulateCmd.Flags().StringVarP(&simConnParams.PrivKey, "priv-key", "X", "", "Set privacy protocol pass phrase")
+	snmpSimulateCmd.Flags().StringVarP(&simParams.EngineID, "engine-id", "e", "", "Set the engine ID, in hexadecimal (generated if not specified)")
+
+	snmpCmd.AddCommand(snmpSimulateCmd)
+
 	// This command does nothing until the backend supports it, so it isn't visible yet.
 	snmpScanCmd := &cobra.Command{
 		Hidden: true,
@@ -457,7 +522,11 @@ func snmpWalk(connParams *snmpConnectionParams, args argsType, snmpScanner snmps
 	}
 	defer snmp.Conn.Close()
 
-	err = snmpScanner.RunSnmpWalk(snmp, oid)
+	if connParams.RecordFile != "" {
+		err = recordSnmpWalk(snmpScanner, snmp, oid, connParams.RecordFile)
+	} else {
+		err = snmpScanner.RunSnmpWalk(snmp, oid)
+	}
 
 	if err != nil {
 		return fmt.Errorf("unable to walk SNMP agent on %s:%d: %w", connParams.IPAddress, connParams.Port, err)
@@ -465,3 +534,86 @@ func snmpWalk(connParams *snmpConnectionParams, args argsType, snmpScanner snmps
 
 	return nil
 }
+
+// recordSnmpWalk writes the walk to a .snmprec file.
+func recordSnmpWalk(snmpScanner snmpscan.Component, snmp *gosnmp.GoSNMP, oid string, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to create record file: %w", err)
+	}
+
+	w := bufio.NewWriter(f)
+	if err := snmpScanner.RecordSnmpWalk(snmp, oid, w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return fmt.Errorf("unable to write record file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("unable to write record file: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Walk recorded to %s\n", path)
+	return nil
+}
+
+// snmpSimulate serves a recorded walk as a local SNMP agent, until interrupted.
+func snmpSimulate(connParams *snmpConnectionParams, simParams *simulateParams, args argsType, logger log.Component) error {
+	// Parse args
+	if len(args) != 1 {
+		return confErrf("expected exactly one argument: the recorded walk file")
+	}
+
+	pdus, err := snmpsim.LoadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("unable to load %s: %w", args[0], err)
+	}
+	store, err := snmpsim.NewStore(pdus)
+	if err != nil {
+		return fmt.Errorf("unable to load %s: %w", args[0], err)
+	}
+
+	// the connection parameters are validated as for a walk, the timeout
+	// being irrelevant here
+	connParams.Timeout = defaultTimeout
+	params, err := newSNMP(connParams, logger)
+	if err != nil {
+		// newSNMP only returns config errors, so any problem is a usage error
+		return configErr{err}
+	}
+	if simParams.EngineID != "" {
+		engineID, err := hex.DecodeString(strings.TrimPrefix(simParams.EngineID, "0x"))
+		if err != nil {
+			return confErrf("invalid engine ID %q: %v", simParams.EngineID, err)
+		}
+		params.SecurityParameters.(*gosnmp.UsmSecurityParameters).AuthoritativeEngineID = string(engineID)
+	}
+
+	agent, err := snmpsim.NewAgent(store, params)
+	if err != nil {
+		return configErr{err}
+	}
+
+	conn, err := net.ListenPacket("udp", simParams.ListenAddress)
+	if err != nil {
+		return fmt.Errorf("unable to listen on %s: %w", simParams.ListenAddress, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- agent.Serve(conn)
+	}()
+	fmt.Fprintf(os.Stderr, "Serving %d OIDs over SNMP %s on %s, press Ctrl+C to stop\n", store.Len(), params.Version, conn.LocalAddr())
+
+	select {
+	case <-ctx.Done():
+		return agent.Close()
+	case err := <-errs:
+		return err
+	}
+}
@@ -38,6 +38,42 @@ func TestWalkCommand(t *testing.T) {
 		})
 }
 
+func TestWalkRecordCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"snmp", "walk", "1.2.3.4", "--record", "device.snmprec"},
+		snmpWalk,
+		func(cliParams *snmpConnectionParams, args argsType) {
+			require.Equal(t, argsType{"1.2.3.4"}, args)
+			require.Equal(t, "device.snmprec", cliParams.RecordFile)
+		})
+}
+
+func TestSimulateCommand(t *testing.T) {
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"snmp", "simulate", "device.snmprec", "-v", "3", "-u", "datadog", "-a", "SHA", "-A", "authkey", "-e", "8000000001020304"},
+		snmpSimulate,
+		func(cliParams *snmpConnectionParams, simParams *simulateParams, args argsType) {
+			require.Equal(t, argsType{"device.snmprec"}, args)
+			require.Equal(t, "3", cliParams.Version)
+			require.Equal(t, "datadog", cliParams.Username)
+			require.Equal(t, "SHA", cliParams.AuthProtocol)
+			require.Equal(t, "8000000001020304", simParams.EngineID)
+			require.Equal(t, defaultSimulateListenAddress, simParams.ListenAddress)
+		})
+
+	fxutil.TestOneShotSubcommand(t,
+		Commands(&command.GlobalParams{}),
+		[]string{"snmp", "simulate", "device.walk", "--listen-address", "0.0.0.0:161", "-C", "private"},
+		snmpSimulate,
+		func(cliParams *snmpConnectionParams, simParams *simulateParams, args argsType) {
+			require.Equal(t, argsType{"device.walk"}, args)
+			require.Equal(t, "private", cliParams.CommunityString)
+			require.Equal(t, "0.0.0.0:161", simParams.ListenAddress)
+		})
+}
+
 func TestScanCommand(t *testing.T) {
 	// this command has _lots_ of options, so the test just exercises a few
 	fxutil.TestOneShotSubcommand(t,
@@ -57,6 +57,8 @@ func adjustUSM(cfg model.Config) {
 	applyDefault(cfg, smNS("enable_ring_buffers"), true)
 	applyDefault(cfg, smNS("max_postgres_stats_buffered"), 100000)
 	applyDefault(cfg, smNS("max_redis_stats_buffered"), 100000)
+	applyDefault(cfg, smNS("max_memcached_stats_buffered"), 100000)
+	applyDefault(cfg, smNS("max_cassandra_stats_buffered"), 100000)
 
 	validateInt(cfg, smNS("http_notification_threshold"), cfg.GetInt(smNS("max_tracked_http_connections"))/2, func(v int) error {
 		limit := cfg.GetInt(smNS("max_tracked_http_connections"))
@@ -28,8 +28,10 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/network"
 	networkconfig "github.com/DataDog/datadog-agent/pkg/network/config"
 	"github.com/DataDog/datadog-agent/pkg/network/encoding/marshal"
+	cassandradebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra/debugging"
 	httpdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/http/debugging"
 	kafkadebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/kafka/debugging"
+	memcacheddebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/memcached/debugging"
 	postgresdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/postgres/debugging"
 	redisdebugging "github.com/DataDog/datadog-agent/pkg/network/protocols/redis/debugging"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/telemetry"
@@ -131,6 +133,13 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		}
 	}))
 
+	// The DNS resolver stats are reset on every call, so they are meant to be
+	// consumed by a single client: the network check of the core agent
+	httpMux.HandleFunc("/check", utils.WithConcurrencyLimit(1, func(w http.ResponseWriter, _ *http.Request) {
+		stats := marshal.FormatDNSResolverStats(nt.tracer.GetDNSResolverStats())
+		utils.WriteAsJSON(w, stats)
+	}))
+
 	httpMux.HandleFunc("/debug/net_maps", func(w http.ResponseWriter, req *http.Request) {
 		cs, err := nt.tracer.DebugNetworkMaps()
 		if err != nil {
@@ -219,6 +228,38 @@ func (nt *networkTracer) Register(httpMux *module.Router) error {
 		utils.WriteAsJSON(w, redisdebugging.Redis(cs.Redis))
 	})
 
+	httpMux.HandleFunc("/debug/memcached_monitoring", func(w http.ResponseWriter, req *http.Request) {
+		if !coreconfig.SystemProbe().GetBool("service_monitoring_config.enable_memcached_monitoring") {
+			writeDisabledProtocolMessage("memcached", w)
+			return
+		}
+		id := getClientID(req)
+		cs, err := nt.tracer.GetActiveConnections(id)
+		if err != nil {
+			log.Errorf("unable to retrieve connections: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		utils.WriteAsJSON(w, memcacheddebugging.Memcached(cs.Memcached))
+	})
+
+	httpMux.HandleFunc("/debug/cassandra_monitoring", func(w http.ResponseWriter, req *http.Request) {
+		if !coreconfig.SystemProbe().GetBool("service_monitoring_config.enable_cassandra_monitoring") {
+			writeDisabledProtocolMessage("cassandra", w)
+			return
+		}
+		id := getClientID(req)
+		cs, err := nt.tracer.GetActiveConnections(id)
+		if err != nil {
+			log.Errorf("unable to retrieve connections: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		utils.WriteAsJSON(w, cassandradebugging.Cassandra(cs.Cassandra))
+	})
+
 	httpMux.HandleFunc("/debug/http2_monitoring", func(w http.ResponseWriter, req *http.Request) {
 		if !coreconfig.SystemProbe().GetBool("service_monitoring_config.enable_http2_monitoring") {
 			writeDisabledProtocolMessage("http2", w)
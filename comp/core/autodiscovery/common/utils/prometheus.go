@@ -19,12 +19,15 @@ import (
 const (
 	openmetricsCheckName  = "openmetrics"
 	openmetricsInitConfig = "{}"
+	// coreLoader is the name of the loader of the native Go checks
+	coreLoader = "core"
 )
 
 // buildInstances generates check config instances based on the Prometheus config and the object annotations
 // The second returned value is true if more than one instance is found
 func buildInstances(pc *types.PrometheusCheck, annotations map[string]string, namespacedName string) ([]integration.Data, bool) {
 	openmetricsVersion := pkgconfigsetup.Datadog().GetInt("prometheus_scrape.version")
+	useCoreCheck := pkgconfigsetup.Datadog().GetBool("prometheus_scrape.use_core_check")
 
 	instances := []integration.Data{}
 	for k, v := range pc.AD.KubeAnnotations.Incl {
@@ -48,6 +51,10 @@ func buildInstances(pc *types.PrometheusCheck, annotations map[string]string, na
 						}
 					}
 				}
+				// the core check only supports the openmetrics v2 instances
+				if useCoreCheck && openmetricsVersion == 2 && instanceValues.Loader == "" {
+					instanceValues.Loader = coreLoader
+				}
 				// The `PrometheusCheck` config may come from two sources:
 				// Either it comes from the `DD_PROMETHEUS_SCRAPE_CHECKS` environment variable.
 				//   In this case, it has been parsed by JSON decoder
@@ -43,6 +43,7 @@ type ProfileData map[string][]byte
 type dependencies struct {
 	fx.In
 
+	Lc                    fx.Lifecycle
 	Log                   log.Component
 	Config                config.Component
 	Diagnosesendermanager diagnosesendermanager.Component
@@ -94,6 +95,8 @@ func newFlare(deps dependencies) provides {
 		f.collectConfigFiles,
 	)
 
+	f.setupSelfFlares(deps.Lc)
+
 	return provides{
 		Comp:       f,
 		Endpoint:   api.NewAgentEndpointProvider(f.createAndReturnFlarePath, "/flare", "POST"),
@@ -183,6 +186,11 @@ func (f *flare) Send(flarePath string, caseID string, email string, source helpe
 //
 // If providerTimeout is 0 or negative, the timeout from the configuration will be used.
 func (f *flare) Create(pdata ProfileData, providerTimeout time.Duration, ipcError error) (string, error) {
+	return f.create(pdata, providerTimeout, ipcError, nil)
+}
+
+// create creates a new flare, calling extra before running the providers when it isn't nil.
+func (f *flare) create(pdata ProfileData, providerTimeout time.Duration, ipcError error, extra types.FlareCallback) (string, error) {
 	if providerTimeout <= 0 {
 		providerTimeout = f.config.GetDuration("flare_provider_timeout")
 	}
@@ -207,6 +215,10 @@ func (f *flare) Create(pdata ProfileData, providerTimeout time.Duration, ipcErro
 		fb.AddFileWithoutScrubbing(filepath.Join("profiles", name), data) //nolint:errcheck
 	}
 
+	if extra != nil {
+		extra(fb) //nolint:errcheck
+	}
+
 	f.runProviders(fb, providerTimeout)
 
 	return fb.Save()
@@ -6,6 +6,8 @@
 package flare
 
 import (
+	"archive/zip"
+	"path/filepath"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -25,6 +27,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/secrets/secretsimpl"
 	nooptelemetry "github.com/DataDog/datadog-agent/comp/core/telemetry/noopsimpl"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
+	"github.com/DataDog/datadog-agent/pkg/flare/selfflare"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
 )
@@ -116,3 +119,37 @@ func TestRunProviders(t *testing.T) {
 	require.True(t, secondRan.Load())
 	require.False(t, secondDone.Load())
 }
+
+func TestCaptureSelfFlare(t *testing.T) {
+	var providerRan atomic.Bool
+	f := &flare{
+		log:    logmock.New(t),
+		config: config.NewMock(t),
+		providers: []types.FlareCallback{
+			func(_ types.FlareBuilder) error {
+				providerRan.Store(true)
+				return nil
+			},
+		},
+	}
+	store := selfflare.NewStore(t.TempDir(), 5, 0)
+
+	f.captureSelfFlare(store, selfflare.TriggerStuckChecks, "1 checks are stuck")
+
+	require.True(t, providerRan.Load())
+	flares, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, flares, 1)
+	assert.Equal(t, selfflare.TriggerStuckChecks, flares[0].Trigger)
+
+	r, err := zip.OpenReader(flares[0].Path)
+	require.NoError(t, err)
+	defer r.Close()
+	var names []string
+	for _, file := range r.File {
+		names = append(names, filepath.Base(file.Name))
+	}
+	assert.Contains(t, names, "self_flare.log")
+	assert.Contains(t, names, "goroutine.pprof")
+	assert.Contains(t, names, "heap.pprof")
+}
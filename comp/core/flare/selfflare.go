@@ -0,0 +1,88 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package flare
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/comp/core/flare/types"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/transaction"
+	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
+	"github.com/DataDog/datadog-agent/pkg/flare/selfflare"
+	agentruntime "github.com/DataDog/datadog-agent/pkg/runtime"
+)
+
+// setupSelfFlares captures self flares when the triggers enabled in the configuration fire
+func (f *flare) setupSelfFlares(lc fx.Lifecycle) {
+	// self flares are only captured by the running agent, not the CLI
+	if f.params.local {
+		return
+	}
+	cfg := selfflare.ReadConfig(f.config)
+	if !cfg.Enabled {
+		return
+	}
+
+	// the panic trigger is installed by the trace-agent, the core agent has
+	// no panic recovery to hook it into
+	store := selfflare.NewStore(cfg.Path, cfg.MaxCount, cfg.MaxSize)
+
+	monitor := selfflare.NewMonitor(cfg.CheckInterval, cfg.MinInterval, func(trigger selfflare.Trigger, details string) {
+		f.captureSelfFlare(store, trigger, details)
+	})
+	if cfg.TriggerEnabled(selfflare.TriggerMemory) {
+		monitor.Add(selfflare.TriggerMemory, selfflare.MemoryDetector(cfg.MemoryThreshold, agentruntime.GoMemLimitUsage))
+	}
+	if cfg.TriggerEnabled(selfflare.TriggerStuckChecks) {
+		monitor.Add(selfflare.TriggerStuckChecks, selfflare.StuckChecksDetector(expvars.GetStuckChecksCount))
+	}
+	if cfg.TriggerEnabled(selfflare.TriggerForwarderErrors) {
+		monitor.Add(selfflare.TriggerForwarderErrors, selfflare.ErrorsDetector(cfg.ForwarderErrorsThreshold, forwarderErrorsCount))
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(_ context.Context) error {
+			f.log.Infof("Self flares are enabled, they are stored in %s", store.Path())
+			monitor.Start()
+			return nil
+		},
+		OnStop: func(_ context.Context) error {
+			monitor.Stop()
+			return nil
+		},
+	})
+}
+
+// captureSelfFlare creates a flare with the profiles of the agent and moves it into store
+func (f *flare) captureSelfFlare(store *selfflare.Store, trigger selfflare.Trigger, details string) {
+	createdAt := time.Now()
+	path, err := f.create(selfflare.Profiles(), 0, nil, func(fb types.FlareBuilder) error {
+		return fb.AddFile("self_flare.log", []byte(fmt.Sprintf("Self flare captured at %s by the %s trigger: %s\n", createdAt.Format(time.RFC3339), trigger, details)))
+	})
+	if err != nil {
+		f.log.Errorf("Unable to create a self flare for the %s trigger: %v", trigger, err)
+		return
+	}
+	flare, err := store.Add(path, trigger, createdAt)
+	if err != nil {
+		f.log.Errorf("Unable to store the self flare for the %s trigger: %v", trigger, err)
+		return
+	}
+	f.log.Warnf("Captured the self flare %s, it can be sent with `agent flare send %s`", flare.Path, flare.Name)
+}
+
+// forwarderErrorsCount returns the number of forwarder transactions errors
+func forwarderErrorsCount() int64 {
+	if errors, ok := transaction.TransactionsExpvars.Get("Errors").(*expvar.Int); ok {
+		return errors.Value()
+	}
+	return 0
+}
@@ -8,31 +8,9 @@
 package secretsimpl
 
 import (
-	"fmt"
-	"syscall"
-
-	"golang.org/x/sys/unix"
+	"github.com/DataDog/datadog-agent/pkg/util/filesystem"
 )
 
 func checkRights(path string, allowGroupExec bool) error {
-	var stat syscall.Stat_t
-	if err := syscall.Stat(path, &stat); err != nil {
-		return fmt.Errorf("invalid executable '%s': can't stat it: %s", path, err)
-	}
-
-	if allowGroupExec {
-		if stat.Mode&(syscall.S_IWGRP|syscall.S_IRWXO) != 0 {
-			return fmt.Errorf("invalid executable '%s', 'others' have rights on it or 'group' has write permissions on it", path)
-		}
-	} else {
-		if stat.Mode&(syscall.S_IRWXG|syscall.S_IRWXO) != 0 {
-			return fmt.Errorf("invalid executable '%s', 'group' or 'others' have rights on it", path)
-		}
-	}
-
-	if err := syscall.Access(path, unix.X_OK); err != nil {
-		return fmt.Errorf("invalid executable '%s': can't access it: %s", path, err)
-	}
-
-	return nil
+	return filesystem.CheckExecutableRights(path, allowGroupExec)
 }
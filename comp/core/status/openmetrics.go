@@ -0,0 +1,204 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package status
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// OpenMetricsContentType is the content type of the OpenMetrics text format
+	OpenMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+	// openMetricsPrefix is prepended to the names of all the metric families
+	openMetricsPrefix = "datadog_agent_"
+)
+
+// MetricType is the type of an OpenMetrics metric family.
+type MetricType string
+
+const (
+	// Gauge is a value which can go up and down
+	Gauge MetricType = "gauge"
+	// Counter is a monotonically increasing value
+	Counter MetricType = "counter"
+)
+
+// MetricFamily is a set of metrics sharing a name, a type and a help text.
+type MetricFamily struct {
+	Name    string
+	Help    string
+	Type    MetricType
+	Metrics []Metric
+}
+
+// Metric is a sample of a metric family.
+type Metric struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// NewGauge returns an empty gauge metric family.
+func NewGauge(name, help string) *MetricFamily {
+	return &MetricFamily{Name: name, Help: help, Type: Gauge}
+}
+
+// NewCounter returns an empty counter metric family. The name must not have
+// the `_total` suffix, it is added to the samples when rendered.
+func NewCounter(name, help string) *MetricFamily {
+	return &MetricFamily{Name: name, Help: help, Type: Counter}
+}
+
+// Add adds a sample to the family. labels is a list of label name and value pairs.
+func (f *MetricFamily) Add(value float64, labels ...string) {
+	var m map[string]string
+	if len(labels) > 0 {
+		m = make(map[string]string, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			m[labels[i]] = labels[i+1]
+		}
+	}
+	f.Metrics = append(f.Metrics, Metric{Labels: m, Value: value})
+}
+
+// OpenMetricsProvider is implemented by the status providers exposing their
+// information as OpenMetrics families.
+type OpenMetricsProvider interface {
+	// OpenMetrics returns the metric families of the provider. It is called
+	// on every scrape so it must only read the current state.
+	OpenMetrics() ([]*MetricFamily, error)
+}
+
+// RenderOpenMetrics writes the families in the OpenMetrics text format. The
+// families are sorted by name, the ones sharing a name are merged and their
+// samples are sorted by labels so that the output is stable across scrapes.
+func RenderOpenMetrics(w io.Writer, families []*MetricFamily) error {
+	merged := make(map[string]*MetricFamily, len(families))
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		if family == nil {
+			continue
+		}
+		existing, found := merged[family.Name]
+		if !found {
+			merged[family.Name] = &MetricFamily{
+				Name:    family.Name,
+				Help:    family.Help,
+				Type:    family.Type,
+				Metrics: append([]Metric(nil), family.Metrics...),
+			}
+			names = append(names, family.Name)
+			continue
+		}
+		if existing.Type != family.Type {
+			return fmt.Errorf("metric family %s is both a %s and a %s", family.Name, existing.Type, family.Type)
+		}
+		existing.Metrics = append(existing.Metrics, family.Metrics...)
+	}
+	sort.Strings(names)
+
+	bw := bufio.NewWriter(w)
+	for _, name := range names {
+		family := merged[name]
+		fullName := openMetricsPrefix + family.Name
+		fmt.Fprintf(bw, "# TYPE %s %s\n", fullName, family.Type)
+		if family.Help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", fullName, escapeHelp(family.Help))
+		}
+		sampleName := fullName
+		if family.Type == Counter {
+			sampleName += "_total"
+		}
+		samples := make([]sample, 0, len(family.Metrics))
+		for _, metric := range family.Metrics {
+			samples = append(samples, sample{labels: formatLabels(metric.Labels), value: metric.Value})
+		}
+		sort.SliceStable(samples, func(i, j int) bool {
+			return samples[i].labels < samples[j].labels
+		})
+		for _, sample := range samples {
+			bw.WriteString(sampleName)
+			bw.WriteString(sample.labels)
+			bw.WriteByte(' ')
+			bw.WriteString(formatValue(sample.value))
+			bw.WriteByte('\n')
+		}
+	}
+	bw.WriteString("# EOF\n")
+	return bw.Flush()
+}
+
+// OpenMetricsHandler returns a handler serving the OpenMetrics status of the
+// component, to be registered on the servers scraped by Prometheus.
+func OpenMetricsHandler(c Component) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		body, err := c.GetStatus("openmetrics", false)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", OpenMetricsContentType)
+		w.Write(body) //nolint:errcheck
+	})
+}
+
+type sample struct {
+	labels string
+	value  float64
+}
+
+func formatLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var w strings.Builder
+	w.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(name)
+		w.WriteString(`="`)
+		w.WriteString(labelValueReplacer.Replace(labels[name]))
+		w.WriteByte('"')
+	}
+	w.WriteByte('}')
+	return w.String()
+}
+
+var (
+	labelValueReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpReplacer       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeHelp(help string) string {
+	return helpReplacer.Replace(help)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "NaN"
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
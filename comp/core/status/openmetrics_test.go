@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package status
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderOpenMetrics(t *testing.T) {
+	runs := NewCounter("check_runs", "Number of check runs.")
+	runs.Add(3, "check_name", "cpu", "check_id", "cpu")
+	running := NewGauge("running_checks", "Number of running checks.")
+	running.Add(1)
+	moreRuns := NewCounter("check_runs", "Number of check runs.")
+	moreRuns.Add(5, "check_name", `my"check`)
+	odd := NewGauge("odd_values", "Help with a \\ and a\nnewline.")
+	odd.Add(math.Inf(1), "path", "C:\\agent\n")
+	odd.Add(math.NaN())
+	odd.Add(0.5)
+
+	var b bytes.Buffer
+	require.NoError(t, RenderOpenMetrics(&b, []*MetricFamily{runs, running, nil, moreRuns, odd}))
+
+	expected := `# TYPE datadog_agent_check_runs counter
+# HELP datadog_agent_check_runs Number of check runs.
+datadog_agent_check_runs_total{check_id="cpu",check_name="cpu"} 3
+datadog_agent_check_runs_total{check_name="my\"check"} 5
+# TYPE datadog_agent_odd_values gauge
+# HELP datadog_agent_odd_values Help with a \\ and a\nnewline.
+datadog_agent_odd_values NaN
+datadog_agent_odd_values 0.5
+datadog_agent_odd_values{path="C:\\agent\n"} +Inf
+# TYPE datadog_agent_running_checks gauge
+# HELP datadog_agent_running_checks Number of running checks.
+datadog_agent_running_checks 1
+# EOF
+`
+	assert.Equal(t, expected, b.String())
+	// the families given to the renderer are not modified
+	assert.Len(t, runs.Metrics, 1)
+}
+
+func TestRenderOpenMetricsTypeConflict(t *testing.T) {
+	counter := NewCounter("packets", "")
+	gauge := NewGauge("packets", "")
+
+	var b bytes.Buffer
+	assert.Error(t, RenderOpenMetrics(&b, []*MetricFamily{counter, gauge}))
+}
+
+func TestRenderOpenMetricsEmpty(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, RenderOpenMetrics(&b, nil))
+	assert.Equal(t, "# EOF\n", b.String())
+}
@@ -17,6 +17,7 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"sync"
 	"text/template"
 	"unicode"
 
@@ -60,6 +61,9 @@ type statusImplementation struct {
 	sortedSectionNames       []string
 	sortedProvidersBySection map[string][]status.Provider
 	log                      log.Component
+
+	// openMetricsMu serializes the OpenMetrics scrapes
+	openMetricsMu sync.Mutex
 }
 
 // Module defines the fx options for this component.
@@ -275,6 +279,22 @@ func (s *statusImplementation) GetStatus(format string, verbose bool, excludeSec
 			}
 		}
 		return b.Bytes(), nil
+	case "openmetrics":
+		var providers []interface{}
+		for _, sc := range s.sortedHeaderProviders {
+			if !present(sc.Name(), excludeSections) {
+				providers = append(providers, sc)
+			}
+		}
+		for _, section := range s.sortedSectionNames {
+			if present(section, excludeSections) {
+				continue
+			}
+			for _, provider := range s.sortedProvidersBySection[section] {
+				providers = append(providers, provider)
+			}
+		}
+		return s.renderOpenMetrics(providers)
 	default:
 		return []byte{}, nil
 	}
@@ -340,6 +360,12 @@ func (s *statusImplementation) GetStatusBySections(sections []string, format str
 				}
 			}
 			return b.Bytes(), nil
+		case "openmetrics":
+			var openMetricsProviders []interface{}
+			for _, sc := range providers {
+				openMetricsProviders = append(openMetricsProviders, sc)
+			}
+			return s.renderOpenMetrics(openMetricsProviders)
 		default:
 			return []byte{}, nil
 		}
@@ -409,11 +435,47 @@ func (s *statusImplementation) GetStatusBySections(sections []string, format str
 			}
 		}
 		return b.Bytes(), nil
+	case "openmetrics":
+		var openMetricsProviders []interface{}
+		for _, sc := range providers {
+			openMetricsProviders = append(openMetricsProviders, sc)
+		}
+		return s.renderOpenMetrics(openMetricsProviders)
 	default:
 		return []byte{}, nil
 	}
 }
 
+// renderOpenMetrics renders the families of the providers implementing
+// status.OpenMetricsProvider. The scrapes are serialized so that concurrent
+// scrapers don't collect the state of the providers at the same time. The
+// errors of a provider are logged and its families are skipped, so that a
+// single failing provider doesn't fail the whole scrape.
+func (s *statusImplementation) renderOpenMetrics(providers []interface{}) ([]byte, error) {
+	s.openMetricsMu.Lock()
+	defer s.openMetricsMu.Unlock()
+
+	var families []*status.MetricFamily
+	for _, provider := range providers {
+		omProvider, ok := provider.(status.OpenMetricsProvider)
+		if !ok {
+			continue
+		}
+		providerFamilies, err := omProvider.OpenMetrics()
+		if err != nil {
+			s.log.Warnf("Error collecting the OpenMetrics status: %v", err)
+			continue
+		}
+		families = append(families, providerFamilies...)
+	}
+
+	b := new(bytes.Buffer)
+	if err := status.RenderOpenMetrics(b, families); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
 func (s *statusImplementation) GetSections() []string {
 	return append([]string{"header"}, s.sortedSectionNames...)
 }
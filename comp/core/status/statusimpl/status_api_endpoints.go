@@ -11,11 +11,14 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
 )
 
 var mimeTypeMap = map[string]string{
-	"text": "text/plain",
-	"json": "application/json",
+	"text":        "text/plain",
+	"json":        "application/json",
+	"openmetrics": status.OpenMetricsContentType,
 }
 
 // SetJSONError writes a server error as JSON with the correct http error code
@@ -49,7 +52,7 @@ func (s *statusImplementation) getStatus(w http.ResponseWriter, r *http.Request,
 	}
 
 	if err != nil {
-		if format == "text" {
+		if format == "text" || format == "openmetrics" {
 			http.Error(w, s.log.Errorf("Error getting status. Error: %v.", err).Error(), http.StatusInternalServerError)
 			return
 		}
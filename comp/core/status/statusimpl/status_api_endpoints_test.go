@@ -118,6 +118,22 @@ func TestStatusAPIEndpoints(t *testing.T) {
 				require.Equal(t, "application/json", rr.Header().Get("Content-Type"))
 			},
 		},
+		{
+			testDesc:    "openmetrics format",
+			method:      "GET",
+			routerPath:  "/status",
+			testedPath:  "/status?format=openmetrics",
+			httpHandler: provider.APIGetStatus.Provider.HandlerFunc(),
+			expectedBody: func() []byte {
+				status, err := provider.Comp.GetStatus("openmetrics", false)
+				require.NoError(t, err)
+				return status
+			}(),
+			expectedCode: http.StatusOK,
+			additionalTests: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				require.Equal(t, status.OpenMetricsContentType, rr.Header().Get("Content-Type"))
+			},
+		},
 		{
 			testDesc:    "unknown format",
 			method:      "GET",
@@ -1144,3 +1144,74 @@ func TestGetStatusBySectionIncorrect(t *testing.T) {
 	assert.Nil(t, bytesResult)
 	assert.EqualError(t, err, `unknown status section 'consectetur', available sections are: ["header","amet","dolor","ipsum","lorem","sit"]`)
 }
+
+type mockOpenMetricsProvider struct {
+	mockProvider
+	families []*status.MetricFamily
+}
+
+func (m mockOpenMetricsProvider) OpenMetrics() ([]*status.MetricFamily, error) {
+	if m.returnError {
+		return nil, fmt.Errorf("OpenMetrics error")
+	}
+	return m.families, nil
+}
+
+func TestGetStatusOpenMetrics(t *testing.T) {
+	runs := status.NewCounter("check_runs", "Number of check runs.")
+	runs.Add(2, "check_name", "cpu")
+	packets := status.NewCounter("dogstatsd_packets", "Number of packets.")
+	packets.Add(10, "listener", "udp")
+
+	deps := fxutil.Test[dependencies](t, fx.Options(
+		config.MockModule(),
+		fx.Provide(func() log.Component { return logmock.New(t) }),
+		fx.Supply(
+			agentParams,
+			status.NewInformationProvider(mockOpenMetricsProvider{
+				mockProvider: mockProvider{name: "collector", section: status.CollectorSection},
+				families:     []*status.MetricFamily{runs},
+			}),
+			status.NewInformationProvider(mockOpenMetricsProvider{
+				mockProvider: mockProvider{name: "dogstatsd", section: "dogstatsd"},
+				families:     []*status.MetricFamily{packets},
+			}),
+			status.NewInformationProvider(mockOpenMetricsProvider{
+				mockProvider: mockProvider{name: "failing", section: "failing", returnError: true},
+			}),
+			// providers not implementing OpenMetrics are skipped
+			status.NewInformationProvider(mockProvider{name: "text only", section: "other", text: "text"}),
+		),
+	))
+
+	statusComponent := newStatus(deps).Comp
+
+	expectedAll := `# TYPE datadog_agent_check_runs counter
+# HELP datadog_agent_check_runs Number of check runs.
+datadog_agent_check_runs_total{check_name="cpu"} 2
+# TYPE datadog_agent_dogstatsd_packets counter
+# HELP datadog_agent_dogstatsd_packets Number of packets.
+datadog_agent_dogstatsd_packets_total{listener="udp"} 10
+# EOF
+`
+	output, err := statusComponent.GetStatus("openmetrics", false)
+	assert.NoError(t, err)
+	assert.Equal(t, expectedAll, string(output))
+
+	output, err = statusComponent.GetStatus("openmetrics", false, "collector")
+	assert.NoError(t, err)
+	assert.NotContains(t, string(output), "check_runs")
+	assert.Contains(t, string(output), "dogstatsd_packets")
+
+	output, err = statusComponent.GetStatusBySections([]string{"collector"}, "openmetrics", false)
+	assert.NoError(t, err)
+	assert.Equal(t, `# TYPE datadog_agent_check_runs counter
+# HELP datadog_agent_check_runs Number of check runs.
+datadog_agent_check_runs_total{check_name="cpu"} 2
+# EOF
+`, string(output))
+
+	output, err = statusComponent.GetStatusBySections([]string{"header"}, "openmetrics", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "# EOF\n", string(output))
+}
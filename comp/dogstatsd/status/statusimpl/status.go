@@ -99,3 +99,60 @@ func (s statusProvider) populateStatus(stats map[string]interface{}) {
 		stats["dogstatsdStats"] = dogstatsdStats
 	}
 }
+
+type serverStats struct {
+	MetricPackets            int64
+	EventPackets             int64
+	ServiceCheckPackets      int64
+	MetricParseErrors        int64
+	EventParseErrors         int64
+	ServiceCheckParseErrors  int64
+	UnterminatedMetricErrors int64
+}
+
+type listenerStats struct {
+	Packets             int64
+	Bytes               int64
+	PacketReadingErrors int64
+}
+
+// OpenMetrics returns the packet stats of the server and of its listeners
+func (s statusProvider) OpenMetrics() ([]*status.MetricFamily, error) {
+	if expvar.Get("dogstatsd") == nil {
+		return nil, nil
+	}
+
+	var server serverStats
+	if err := json.Unmarshal([]byte(expvar.Get("dogstatsd").String()), &server); err != nil {
+		return nil, err
+	}
+	packets := status.NewCounter("dogstatsd_packets", "Number of DogStatsD messages parsed by type.")
+	packets.Add(float64(server.MetricPackets), "type", "metric")
+	packets.Add(float64(server.EventPackets), "type", "event")
+	packets.Add(float64(server.ServiceCheckPackets), "type", "service_check")
+	parseErrors := status.NewCounter("dogstatsd_parse_errors", "Number of DogStatsD messages which could not be parsed by type.")
+	parseErrors.Add(float64(server.MetricParseErrors), "type", "metric")
+	parseErrors.Add(float64(server.EventParseErrors), "type", "event")
+	parseErrors.Add(float64(server.ServiceCheckParseErrors), "type", "service_check")
+	unterminated := status.NewCounter("dogstatsd_unterminated_metric_errors", "Number of DogStatsD metrics dropped for lacking a trailing newline.")
+	unterminated.Add(float64(server.UnterminatedMetricErrors))
+
+	listenerPackets := status.NewCounter("dogstatsd_listener_packets", "Number of packets received by the listener.")
+	listenerBytes := status.NewCounter("dogstatsd_listener_bytes", "Number of bytes received by the listener.")
+	readingErrors := status.NewCounter("dogstatsd_listener_packet_reading_errors", "Number of errors reading packets on the listener.")
+	for listener, expvarName := range map[string]string{"udp": "dogstatsd-udp", "uds": "dogstatsd-uds"} {
+		listenerExpvar := expvar.Get(expvarName)
+		if listenerExpvar == nil {
+			continue
+		}
+		var stats listenerStats
+		if err := json.Unmarshal([]byte(listenerExpvar.String()), &stats); err != nil {
+			return nil, err
+		}
+		listenerPackets.Add(float64(stats.Packets), "listener", listener)
+		listenerBytes.Add(float64(stats.Bytes), "listener", listener)
+		readingErrors.Add(float64(stats.PacketReadingErrors), "listener", listener)
+	}
+
+	return []*status.MetricFamily{packets, parseErrors, unterminated, listenerPackets, listenerBytes, readingErrors}, nil
+}
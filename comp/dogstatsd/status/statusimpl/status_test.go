@@ -10,6 +10,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
 	// We need to include this to make sure the Dogstatsd expvars are initialized
 	_ "github.com/DataDog/datadog-agent/comp/dogstatsd/server"
 )
@@ -45,6 +47,17 @@ func TestStatusOut(t *testing.T) {
 
 			assert.NotEmpty(t, b.String())
 		}},
+		{"OpenMetrics", func(t *testing.T) {
+			families, err := headerProvider.(status.OpenMetricsProvider).OpenMetrics()
+			assert.NoError(t, err)
+
+			b := new(bytes.Buffer)
+			assert.NoError(t, status.RenderOpenMetrics(b, families))
+
+			assert.Contains(t, b.String(), `datadog_agent_dogstatsd_packets_total{type="metric"} 0`+"\n")
+			assert.Contains(t, b.String(), `datadog_agent_dogstatsd_listener_packets_total{listener="udp"} 0`+"\n")
+			assert.Contains(t, b.String(), `datadog_agent_dogstatsd_listener_bytes_total{listener="uds"} 0`+"\n")
+		}},
 	}
 
 	for _, test := range tests {
@@ -0,0 +1,361 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package defaultforwarder
+
+import (
+	"context"
+	"expvar"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/endpoints"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/transaction"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+)
+
+// priorityClass groups the transactions sharing the same priority when the
+// bandwidth budget is exceeded.
+type priorityClass string
+
+const (
+	serviceChecksClass         priorityClass = "service_checks"
+	metadataClass              priorityClass = "metadata"
+	eventsClass                priorityClass = "events"
+	seriesClass                priorityClass = "series"
+	sketchesClass              priorityClass = "sketches"
+	processClass               priorityClass = "process"
+	orchestratorManifestsClass priorityClass = "orchestrator_manifests"
+)
+
+// defaultPriorityOrder lists the priority classes from the highest to the lowest priority.
+var defaultPriorityOrder = []priorityClass{
+	serviceChecksClass,
+	metadataClass,
+	eventsClass,
+	seriesClass,
+	sketchesClass,
+	processClass,
+	orchestratorManifestsClass,
+}
+
+var (
+	bandwidthBudgetExpvars       = expvar.Map{}
+	throttledBytesByClass        = expvar.Map{}
+	throttledTransactionsByClass = expvar.Map{}
+	spilledBytesByClass          = expvar.Map{}
+
+	tlmBandwidthThrottledBytes = telemetry.NewCounter("forwarder", "bandwidth_budget_throttled_bytes",
+		[]string{"class"}, "Bytes of the transactions delayed because the egress bandwidth budget was exceeded")
+	tlmBandwidthThrottledTransactions = telemetry.NewCounter("forwarder", "bandwidth_budget_throttled_transactions",
+		[]string{"class"}, "Count of transactions delayed because the egress bandwidth budget was exceeded")
+	tlmBandwidthSpilledBytes = telemetry.NewCounter("forwarder", "bandwidth_budget_spilled_bytes",
+		[]string{"class"}, "Bytes of the transactions sent back to the retry queue because the egress bandwidth budget was exceeded for too long")
+)
+
+func initBandwidthBudgetExpvars() {
+	transaction.ForwarderExpvars.Set("BandwidthBudget", &bandwidthBudgetExpvars)
+	bandwidthBudgetExpvars.Set("ThrottledBytesByClass", &throttledBytesByClass)
+	bandwidthBudgetExpvars.Set("ThrottledTransactionsByClass", &throttledTransactionsByClass)
+	bandwidthBudgetExpvars.Set("SpilledBytesByClass", &spilledBytesByClass)
+}
+
+// getPriorityClass returns the priority class of a transaction.
+func getPriorityClass(t transaction.Transaction) priorityClass {
+	switch t.GetKind() {
+	case transaction.ServiceChecks, transaction.CheckRuns:
+		return serviceChecksClass
+	case transaction.Metadata:
+		return metadataClass
+	case transaction.Events:
+		return eventsClass
+	case transaction.Series:
+		return seriesClass
+	case transaction.Sketches:
+		return sketchesClass
+	}
+	if t.GetEndpointName() == endpoints.OrchestratorManifestEndpoint.Name {
+		return orchestratorManifestsClass
+	}
+	return processClass
+}
+
+type budgetWaiter struct {
+	size    float64
+	granted chan struct{}
+}
+
+// bandwidthBudget is a token bucket limiting the egress byte rate of all the
+// domain forwarders. When the budget is exceeded, the workers wait for it to be
+// refilled and the transactions with the highest priority are sent first.
+// Transactions waiting for longer than `maxWait` are sent back to the retry
+// queue, which stores them on disk when it is full. The retried transactions
+// don't wait: they stay in the retry queue while the budget is exhausted.
+type bandwidthBudget struct {
+	bytesPerSec float64
+	burst       float64
+	maxWait     time.Duration
+
+	// ranks maps each priority class to its index in `waiters`, 0 being
+	// the highest priority.
+	ranks map[priorityClass]int
+
+	mutex      sync.Mutex
+	tokens     float64
+	lastRefill time.Time
+	waiters    [][]*budgetWaiter
+	timer      *time.Timer
+}
+
+// newBandwidthBudget returns the bandwidth budget shared by all the domain
+// forwarders, or nil when it is disabled.
+func newBandwidthBudget(config config.Component, log log.Component) *bandwidthBudget {
+	bytesPerSec := config.GetFloat64("forwarder_bandwidth_budget.bytes_per_sec")
+	if bytesPerSec <= 0 {
+		return nil
+	}
+
+	burst := config.GetFloat64("forwarder_bandwidth_budget.burst_bytes")
+	if burst <= 0 {
+		burst = bytesPerSec
+	}
+	maxWait := time.Duration(config.GetInt("forwarder_bandwidth_budget.max_wait_sec")) * time.Second
+
+	var order []priorityClass
+	for _, class := range config.GetStringSlice("forwarder_bandwidth_budget.priority_order") {
+		order = append(order, priorityClass(class))
+	}
+
+	log.Infof("The egress bandwidth of the forwarder is limited to %.0f bytes per second", bytesPerSec)
+	return newBandwidthBudgetWithOrder(log, bytesPerSec, burst, maxWait, order)
+}
+
+func newBandwidthBudgetWithOrder(log log.Component, bytesPerSec float64, burst float64, maxWait time.Duration, order []priorityClass) *bandwidthBudget {
+	ranks := make(map[priorityClass]int, len(defaultPriorityOrder))
+	for _, class := range order {
+		if !isKnownPriorityClass(class) {
+			log.Warnf("Unknown priority class %q in forwarder_bandwidth_budget.priority_order, valid classes are %v", class, defaultPriorityOrder)
+			continue
+		}
+		if _, found := ranks[class]; !found {
+			ranks[class] = len(ranks)
+		}
+	}
+	// The classes which are not listed have the lowest priority
+	for _, class := range defaultPriorityOrder {
+		if _, found := ranks[class]; !found {
+			ranks[class] = len(ranks)
+		}
+	}
+
+	return &bandwidthBudget{
+		bytesPerSec: bytesPerSec,
+		burst:       burst,
+		maxWait:     maxWait,
+		ranks:       ranks,
+		tokens:      burst,
+		lastRefill:  time.Now(),
+		waiters:     make([][]*budgetWaiter, len(ranks)),
+	}
+}
+
+func isKnownPriorityClass(class priorityClass) bool {
+	for _, c := range defaultPriorityOrder {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// acquire blocks until the transaction fits in the budget. It returns false
+// when the transaction could not be sent within `maxWait` or when the context
+// is canceled. A nil budget never blocks.
+func (b *bandwidthBudget) acquire(ctx context.Context, t transaction.Transaction) bool {
+	if b == nil {
+		return true
+	}
+
+	class := getPriorityClass(t)
+	rank := b.ranks[class]
+	size := float64(t.GetPayloadSize())
+
+	b.mutex.Lock()
+	if b.takeLocked(rank, size) {
+		b.mutex.Unlock()
+		return true
+	}
+
+	w := &budgetWaiter{size: size, granted: make(chan struct{})}
+	b.waiters[rank] = append(b.waiters[rank], w)
+	b.scheduleLocked()
+	b.mutex.Unlock()
+
+	recordThrottled(class, size)
+
+	timer := time.NewTimer(b.maxWait)
+	defer timer.Stop()
+	select {
+	case <-w.granted:
+		return true
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if !b.removeWaiterLocked(rank, w) {
+		// The budget was granted while giving up
+		return true
+	}
+	b.scheduleLocked()
+
+	if ctx.Err() == nil {
+		recordSpilled(class, size)
+	}
+	return false
+}
+
+// tryAcquire takes the budget of the transaction only when it is available
+// right away. The workers use it for the low priority transactions, which are
+// sent back to the retry queue instead of blocking a worker in front of the
+// high priority ones. They are already counted as spilled, they only count as
+// throttled. A nil budget always succeeds.
+func (b *bandwidthBudget) tryAcquire(t transaction.Transaction) bool {
+	if b == nil {
+		return true
+	}
+
+	class := getPriorityClass(t)
+	size := float64(t.GetPayloadSize())
+
+	b.mutex.Lock()
+	taken := b.takeLocked(b.ranks[class], size)
+	b.mutex.Unlock()
+
+	if !taken {
+		recordThrottled(class, size)
+	}
+	return taken
+}
+
+// availableTokens returns the bytes which can be sent right away, 0 when
+// transactions are waiting for the budget. The domain forwarders retry the
+// transactions within it. A nil budget is unlimited.
+func (b *bandwidthBudget) availableTokens() float64 {
+	if b == nil {
+		return math.Inf(1)
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refillLocked()
+	if b.hasWaitersLocked(len(b.waiters) - 1) {
+		return 0
+	}
+	return b.tokens
+}
+
+// takeLocked takes the tokens of a payload when no transaction with the same
+// or a higher priority is waiting and the budget is available.
+func (b *bandwidthBudget) takeLocked(rank int, size float64) bool {
+	b.refillLocked()
+	if b.hasWaitersLocked(rank) || b.tokens < b.requiredTokens(size) {
+		return false
+	}
+	b.tokens -= size
+	return true
+}
+
+func recordThrottled(class priorityClass, size float64) {
+	throttledBytesByClass.Add(string(class), int64(size))
+	throttledTransactionsByClass.Add(string(class), 1)
+	tlmBandwidthThrottledBytes.Add(size, string(class))
+	tlmBandwidthThrottledTransactions.Inc(string(class))
+}
+
+func recordSpilled(class priorityClass, size float64) {
+	spilledBytesByClass.Add(string(class), int64(size))
+	tlmBandwidthSpilledBytes.Add(size, string(class))
+}
+
+// requiredTokens returns the tokens needed to send a payload. Payloads bigger
+// than the burst only wait for a full bucket, the difference is paid afterwards.
+func (b *bandwidthBudget) requiredTokens(size float64) float64 {
+	return min(size, b.burst)
+}
+
+func (b *bandwidthBudget) refillLocked() {
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.lastRefill).Seconds()*b.bytesPerSec)
+	b.lastRefill = now
+}
+
+// hasWaitersLocked returns true if a transaction with the same or a higher
+// priority is waiting.
+func (b *bandwidthBudget) hasWaitersLocked(rank int) bool {
+	for r := 0; r <= rank; r++ {
+		if len(b.waiters[r]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *bandwidthBudget) removeWaiterLocked(rank int, w *budgetWaiter) bool {
+	for i, waiter := range b.waiters[rank] {
+		if waiter == w {
+			b.waiters[rank] = append(b.waiters[rank][:i], b.waiters[rank][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// scheduleLocked plans the next dispatch for when the budget is refilled
+// enough to send the first transaction with the highest priority.
+func (b *bandwidthBudget) scheduleLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	for _, waiters := range b.waiters {
+		if len(waiters) == 0 {
+			continue
+		}
+		missing := b.requiredTokens(waiters[0].size) - b.tokens
+		delay := time.Duration(max(missing, 0) / b.bytesPerSec * float64(time.Second))
+		b.timer = time.AfterFunc(delay, b.dispatch)
+		return
+	}
+}
+
+// dispatch grants the budget to the waiting transactions by order of priority.
+func (b *bandwidthBudget) dispatch() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.refillLocked()
+	for rank, waiters := range b.waiters {
+		for len(waiters) > 0 {
+			w := waiters[0]
+			if b.tokens < b.requiredTokens(w.size) {
+				// Lower priority transactions must not overtake this one
+				b.waiters[rank] = waiters
+				b.scheduleLocked()
+				return
+			}
+			b.tokens -= w.size
+			waiters = waiters[1:]
+			close(w.granted)
+		}
+		b.waiters[rank] = waiters
+	}
+	b.timer = nil
+}
@@ -0,0 +1,175 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package defaultforwarder
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/endpoints"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/transaction"
+	mock "github.com/DataDog/datadog-agent/pkg/config/mock"
+)
+
+func newBudgetTestTransaction(kind transaction.Kind, endpoint transaction.Endpoint, size int) *transaction.HTTPTransaction {
+	t := transaction.NewHTTPTransaction()
+	t.Kind = kind
+	t.Endpoint = endpoint
+	t.Payload = transaction.NewBytesPayloadWithoutMetaData(make([]byte, size))
+	return t
+}
+
+func (b *bandwidthBudget) waitersCount() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	count := 0
+	for _, waiters := range b.waiters {
+		count += len(waiters)
+	}
+	return count
+}
+
+func TestGetPriorityClass(t *testing.T) {
+	tests := []struct {
+		kind     transaction.Kind
+		endpoint transaction.Endpoint
+		expected priorityClass
+	}{
+		{transaction.ServiceChecks, endpoints.V1CheckRunsEndpoint, serviceChecksClass},
+		{transaction.CheckRuns, endpoints.V1CheckRunsEndpoint, serviceChecksClass},
+		{transaction.Metadata, endpoints.V1MetadataEndpoint, metadataClass},
+		{transaction.Events, endpoints.V1IntakeEndpoint, eventsClass},
+		{transaction.Series, endpoints.SeriesEndpoint, seriesClass},
+		{transaction.Sketches, endpoints.SketchSeriesEndpoint, sketchesClass},
+		{transaction.Process, endpoints.ProcessesEndpoint, processClass},
+		{transaction.Process, endpoints.OrchestratorManifestEndpoint, orchestratorManifestsClass},
+	}
+	for _, tt := range tests {
+		tr := newBudgetTestTransaction(tt.kind, tt.endpoint, 1)
+		assert.Equal(t, tt.expected, getPriorityClass(tr), "kind %v, endpoint %s", tt.kind, tt.endpoint.Name)
+	}
+}
+
+func TestNewBandwidthBudget(t *testing.T) {
+	mockConfig := mock.New(t)
+	log := logmock.New(t)
+
+	assert.Nil(t, newBandwidthBudget(mockConfig, log))
+
+	mockConfig.SetWithoutSource("forwarder_bandwidth_budget.bytes_per_sec", 1000)
+	mockConfig.SetWithoutSource("forwarder_bandwidth_budget.max_wait_sec", 3)
+	mockConfig.SetWithoutSource("forwarder_bandwidth_budget.priority_order", []string{"sketches", "unknown", "series", "sketches"})
+	b := newBandwidthBudget(mockConfig, log)
+	require.NotNil(t, b)
+
+	assert.Equal(t, 1000.0, b.bytesPerSec)
+	assert.Equal(t, 1000.0, b.burst)
+	assert.Equal(t, 3*time.Second, b.maxWait)
+	assert.Equal(t, map[priorityClass]int{
+		sketchesClass:              0,
+		seriesClass:                1,
+		serviceChecksClass:         2,
+		metadataClass:              3,
+		eventsClass:                4,
+		processClass:               5,
+		orchestratorManifestsClass: 6,
+	}, b.ranks)
+}
+
+func TestBandwidthBudgetNil(t *testing.T) {
+	var b *bandwidthBudget
+	assert.True(t, b.acquire(context.Background(), newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 100)))
+}
+
+func TestBandwidthBudgetBurst(t *testing.T) {
+	b := newBandwidthBudgetWithOrder(logmock.New(t), 10, 1000, time.Millisecond, nil)
+
+	// Payloads bigger than the burst are sent when the bucket is full
+	assert.True(t, b.acquire(context.Background(), newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 5000)))
+	assert.False(t, b.acquire(context.Background(), newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 1)))
+	assert.Equal(t, 0, b.waitersCount())
+}
+
+func TestBandwidthBudgetPriority(t *testing.T) {
+	b := newBandwidthBudgetWithOrder(logmock.New(t), 10000, 1000, time.Minute, nil)
+
+	// Exhaust the budget for 300ms
+	require.True(t, b.acquire(context.Background(), newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 3000)))
+
+	var mutex sync.Mutex
+	var order []priorityClass
+	var wg sync.WaitGroup
+	for i, tr := range []*transaction.HTTPTransaction{
+		newBudgetTestTransaction(transaction.Process, endpoints.OrchestratorManifestEndpoint, 1000),
+		newBudgetTestTransaction(transaction.Sketches, endpoints.SketchSeriesEndpoint, 1000),
+		newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 1000),
+		newBudgetTestTransaction(transaction.ServiceChecks, endpoints.V1CheckRunsEndpoint, 1000),
+	} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.True(t, b.acquire(context.Background(), tr))
+			mutex.Lock()
+			order = append(order, getPriorityClass(tr))
+			mutex.Unlock()
+		}()
+		require.Eventually(t, func() bool { return b.waitersCount() == i+1 }, time.Second, time.Millisecond)
+	}
+	wg.Wait()
+
+	assert.Equal(t, []priorityClass{serviceChecksClass, seriesClass, sketchesClass, orchestratorManifestsClass}, order)
+}
+
+func TestBandwidthBudgetMaxWait(t *testing.T) {
+	b := newBandwidthBudgetWithOrder(logmock.New(t), 1, 100, 10*time.Millisecond, nil)
+	require.True(t, b.acquire(context.Background(), newBudgetTestTransaction(transaction.Sketches, endpoints.SketchSeriesEndpoint, 100)))
+
+	spilled := spilledBytesByClass.Get(string(sketchesClass))
+	var spilledBefore int64
+	if spilled != nil {
+		spilledBefore = spilled.(interface{ Value() int64 }).Value()
+	}
+
+	assert.False(t, b.acquire(context.Background(), newBudgetTestTransaction(transaction.Sketches, endpoints.SketchSeriesEndpoint, 50)))
+	assert.Equal(t, 0, b.waitersCount())
+	assert.Equal(t, spilledBefore+50, spilledBytesByClass.Get(string(sketchesClass)).(interface{ Value() int64 }).Value())
+}
+
+func TestBandwidthBudgetContextCanceled(t *testing.T) {
+	b := newBandwidthBudgetWithOrder(logmock.New(t), 1, 100, time.Minute, nil)
+	require.True(t, b.acquire(context.Background(), newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 100)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		done <- b.acquire(ctx, newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 100))
+	}()
+	require.Eventually(t, func() bool { return b.waitersCount() == 1 }, time.Second, time.Millisecond)
+	cancel()
+
+	assert.False(t, <-done)
+	assert.Equal(t, 0, b.waitersCount())
+}
+
+func TestBandwidthBudgetTryAcquire(t *testing.T) {
+	b := newBandwidthBudgetWithOrder(logmock.New(t), 1, 100, time.Minute, nil)
+	assert.True(t, b.tryAcquire(newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 100)))
+
+	// The budget is exceeded, the transaction doesn't wait
+	assert.False(t, b.tryAcquire(newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 50)))
+	assert.Equal(t, 0, b.waitersCount())
+
+	var nilBudget *bandwidthBudget
+	assert.True(t, nilBudget.tryAcquire(newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 100)))
+}
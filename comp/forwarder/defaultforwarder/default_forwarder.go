@@ -105,6 +105,8 @@ type Options struct {
 	DomainResolvers                map[string]resolver.DomainResolver
 	ConnectionResetInterval        time.Duration
 	CompletionHandler              transaction.HTTPCompletionHandler
+	// RoutedDomains lists the domains which only receive the payloads routed to them
+	RoutedDomains map[string]struct{}
 }
 
 // SetFeature sets forwarder features in a feature set
@@ -212,6 +214,9 @@ type DefaultForwarder struct {
 	internalState    *atomic.Uint32
 	m                sync.Mutex // To control Start/Stop races
 
+	// domainRoutes maps the routed domains to the route of their payloads
+	domainRoutes map[string]string
+
 	completionHandler transaction.HTTPCompletionHandler
 
 	agentName                       string
@@ -229,6 +234,7 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 		NumberOfWorkers:  options.NumberOfWorkers,
 		domainForwarders: map[string]*domainForwarder{},
 		domainResolvers:  map[string]resolver.DomainResolver{},
+		domainRoutes:     map[string]string{},
 		internalState:    atomic.NewUint32(Stopped),
 		healthChecker: &forwarderHealth{
 			log:                   log,
@@ -243,10 +249,15 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 	var optionalRemovalPolicy *retry.FileRemovalPolicy
 	storageMaxSize := config.GetInt64("forwarder_storage_max_size_in_bytes")
 	var diskUsageLimit *retry.DiskUsageLimit
+	var fileEncryption *retry.FileEncryption
+	var fileEncryptionErr error
 
 	// Disk Persistence is a core-only feature for now.
 	if storageMaxSize == 0 {
 		log.Infof("Retry queue storage on disk is disabled")
+	} else if fileEncryption, fileEncryptionErr = newRetryFileEncryption(config); fileEncryptionErr != nil {
+		// Never fall back to plain text files when the encryption is requested.
+		log.Errorf("Retry queue storage on disk is disabled. Cannot initialize the encryption of the retry files: %v", fileEncryptionErr)
 	} else if agentName != "" {
 		storagePath := config.GetString("forwarder_storage_path")
 		if storagePath == "" {
@@ -277,6 +288,8 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 	flushToDiskMemRatio := config.GetFloat64("forwarder_flush_to_disk_mem_ratio")
 	domainForwarderSort := transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: true}
 	transactionContainerSort := transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: false}
+	// The bandwidth budget is shared by all the domain forwarders
+	bandwidthBudget := newBandwidthBudget(config, log)
 
 	for domain, resolver := range options.DomainResolvers {
 		isMRF := false
@@ -292,6 +305,10 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 			}
 
 		}
+		route := ""
+		if _, routed := options.RoutedDomains[domain]; routed {
+			route = domain
+		}
 		domain, _ := utils.AddAgentVersionToDomain(domain, "app")
 		resolver.SetBaseDomain(domain)
 		if resolver.GetAPIKeys() == nil || len(resolver.GetAPIKeys()) == 0 {
@@ -313,10 +330,14 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 				flushToDiskMemRatio,
 				domainFolderPath,
 				diskUsageLimit,
+				fileEncryption,
 				transactionContainerSort,
 				resolver,
 				pointCountTelemetry)
 			f.domainResolvers[domain] = resolver
+			if route != "" {
+				f.domainRoutes[domain] = route
+			}
 			fwd := newDomainForwarder(
 				config,
 				log,
@@ -326,7 +347,8 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 				options.NumberOfWorkers,
 				options.ConnectionResetInterval,
 				domainForwarderSort,
-				pointCountTelemetry)
+				pointCountTelemetry,
+				bandwidthBudget)
 			f.domainForwarders[domain] = fwd
 			// Register all alternate domains for each forwarder
 			for _, v := range resolver.GetAlternateDomains() {
@@ -335,6 +357,18 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 		}
 	}
 
+	config.OnUpdate(func(setting string, _, newValue any) {
+		// The key is updated when it is refreshed by the secrets backend
+		if setting != "forwarder_storage_encryption_key" || fileEncryption == nil {
+			return
+		}
+		if newKey, ok := newValue.(string); ok {
+			if err := fileEncryption.RotateKey(newKey); err != nil {
+				log.Errorf("Cannot rotate the encryption key of the retry files, the previous key is still used: %v", err)
+			}
+		}
+	})
+
 	config.OnUpdate(func(setting string, oldValue, newValue any) {
 		if setting != "api_key" {
 			return
@@ -368,6 +402,16 @@ func NewDefaultForwarder(config config.Component, log log.Component, options *Op
 	return f
 }
 
+// newRetryFileEncryption returns the encryption of the retry files, or nil when
+// it is not enabled. The keys can be sourced from the secrets backend.
+func newRetryFileEncryption(config config.Component) (*retry.FileEncryption, error) {
+	key := config.GetString("forwarder_storage_encryption_key")
+	if key == "" {
+		return nil, nil
+	}
+	return retry.NewFileEncryption(key, config.GetStringSlice("forwarder_storage_encryption_previous_keys"))
+}
+
 func getAgentName(options *Options) string {
 	if HasFeature(options.EnabledFeatures, CoreFeatures) {
 		return "core"
@@ -474,6 +518,9 @@ func (f *DefaultForwarder) createAdvancedHTTPTransactions(endpoint transaction.E
 
 	for _, payload := range payloads {
 		for domain, dr := range f.domainResolvers {
+			if f.domainRoutes[domain] != payload.Route {
+				continue
+			}
 			for _, apiKey := range dr.GetAPIKeys() {
 				t := transaction.NewHTTPTransaction()
 				t.Domain, _ = dr.Resolve(endpoint)
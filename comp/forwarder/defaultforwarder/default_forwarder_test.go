@@ -55,3 +55,21 @@ func TestDefaultForwarderUpdateAPIKey(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, expectData, string(data))
 }
+
+func TestNewRetryFileEncryption(t *testing.T) {
+	mockConfig := config.NewMock(t)
+
+	encryption, err := newRetryFileEncryption(mockConfig)
+	require.NoError(t, err)
+	assert.Nil(t, encryption)
+
+	mockConfig.SetWithoutSource("forwarder_storage_encryption_key", "too short")
+	_, err = newRetryFileEncryption(mockConfig)
+	assert.Error(t, err)
+
+	mockConfig.SetWithoutSource("forwarder_storage_encryption_key", "0123456789abcdef0123456789abcdef")
+	mockConfig.SetWithoutSource("forwarder_storage_encryption_previous_keys", []string{"fedcba9876543210fedcba9876543210"})
+	encryption, err = newRetryFileEncryption(mockConfig)
+	require.NoError(t, err)
+	assert.NotNil(t, encryption)
+}
@@ -45,6 +45,10 @@ type domainForwarder struct {
 	transactionPrioritySorter retry.TransactionPrioritySorter
 	blockedList               *blockedEndpoints
 	pointCountTelemetry       *retry.PointCountTelemetry
+	bandwidthBudget           *bandwidthBudget
+	// priorityQueue orders the new transactions by priority class before the
+	// workers pick them, it is only used with a bandwidth budget.
+	priorityQueue *priorityQueue
 }
 
 func newDomainForwarder(
@@ -56,7 +60,8 @@ func newDomainForwarder(
 	numberOfWorkers int,
 	connectionResetInterval time.Duration,
 	transactionPrioritySorter retry.TransactionPrioritySorter,
-	pointCountTelemetry *retry.PointCountTelemetry) *domainForwarder {
+	pointCountTelemetry *retry.PointCountTelemetry,
+	bandwidthBudget *bandwidthBudget) *domainForwarder {
 	return &domainForwarder{
 		config:                    config,
 		log:                       log,
@@ -70,6 +75,7 @@ func newDomainForwarder(
 		blockedList:               newBlockedEndpoints(config, log),
 		transactionPrioritySorter: transactionPrioritySorter,
 		pointCountTelemetry:       pointCountTelemetry,
+		bandwidthBudget:           bandwidthBudget,
 	}
 }
 
@@ -95,9 +101,17 @@ func (f *domainForwarder) retryTransactions(_ time.Time) {
 
 	f.transactionPrioritySorter.Sort(transactions)
 
+	// The transactions are parked in the retry queue while the bandwidth budget
+	// is exhausted, instead of looping through the workers. The last retried
+	// transaction may exceed it, like the payloads bigger than the burst.
+	budget := f.bandwidthBudget.availableTokens()
+
 	for _, t := range transactions {
 		transactionEndpointName := t.GetEndpointName()
-		if !f.blockedList.isBlock(t.GetTarget()) {
+		if budget <= 0 {
+			droppedRetryQueueFull += f.addToTransactionRetryQueue(t)
+		} else if !f.blockedList.isBlock(t.GetTarget()) {
+			budget -= float64(t.GetPayloadSize())
 			select {
 			case f.lowPrio <- t:
 				transactionsRetriedByEndpoint.Add(transactionEndpointName, 1)
@@ -191,6 +205,10 @@ func (f *domainForwarder) init() {
 	f.highPrio = make(chan transaction.Transaction, highPrioBuffSize)
 	f.lowPrio = make(chan transaction.Transaction, lowPrioBuffSize)
 	f.requeuedTransaction = make(chan transaction.Transaction, requeuedTransactionBuffSize)
+	f.priorityQueue = nil
+	if f.bandwidthBudget != nil {
+		f.priorityQueue = newPriorityQueue(f.highPrio, f.bandwidthBudget.ranks, highPrioBuffSize)
+	}
 	f.stopRetry = make(chan bool)
 	f.stopConnectionReset = make(chan bool)
 	f.workers = []*Worker{}
@@ -209,8 +227,13 @@ func (f *domainForwarder) Start() error {
 	// reset internal state to purge transactions from past starts
 	f.init()
 
+	highPrio := f.highPrio
+	if f.priorityQueue != nil {
+		f.priorityQueue.start()
+		highPrio = f.priorityQueue.output
+	}
 	for i := 0; i < f.numberOfWorkers; i++ {
-		w := NewWorker(f.config, f.log, f.highPrio, f.lowPrio, f.requeuedTransaction, f.blockedList, f.pointCountTelemetry)
+		w := NewWorker(f.config, f.log, highPrio, f.lowPrio, f.requeuedTransaction, f.blockedList, f.pointCountTelemetry, f.bandwidthBudget)
 		w.Start()
 		f.workers = append(f.workers, w)
 	}
@@ -238,9 +261,19 @@ func (f *domainForwarder) Stop(purgeHighPrio bool) {
 		f.stopConnectionReset <- true
 	}
 	f.stopRetry <- true
+	var queued []transaction.Transaction
+	if f.priorityQueue != nil {
+		queued = f.priorityQueue.stopAndDrain()
+	}
 	for _, w := range f.workers {
 		w.Stop(purgeHighPrio)
 	}
+	if purgeHighPrio && len(f.workers) > 0 {
+		for _, t := range queued {
+			f.log.Debugf("Flushing one queued transaction before stopping the domainForwarder")
+			f.workers[0].callProcess(t, false) //nolint:errcheck
+		}
+	}
 	f.workers = []*Worker{}
 	close(f.highPrio)
 	close(f.lowPrio)
@@ -18,6 +18,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/endpoints"
 	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/internal/retry"
 	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/transaction"
 	mock "github.com/DataDog/datadog-agent/pkg/config/mock"
@@ -256,6 +257,30 @@ func TestRetryTransactions(t *testing.T) {
 	assert.Equal(t, int64(1), transaction.TransactionsDropped.Value())
 }
 
+func TestRetryTransactionsBandwidthBudgetExhausted(t *testing.T) {
+	mockConfig := mock.New(t)
+	log := logmock.New(t)
+	forwarder := newDomainForwarderForTest(mockConfig, log, 0, false)
+	forwarder.bandwidthBudget = newBandwidthBudgetWithOrder(log, 1, 100, time.Minute, nil)
+	forwarder.init()
+	require.NotNil(t, forwarder.priorityQueue)
+
+	forwarder.requeueTransaction(newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 1))
+	forwarder.requeueTransaction(newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 1))
+
+	// The transactions are parked in the retry queue while the budget is exhausted
+	forwarder.bandwidthBudget.tokens = -1
+	forwarder.retryTransactions(time.Now())
+	requireLenForwarderRetryQueue(t, forwarder, 2)
+	assert.Len(t, forwarder.lowPrio, 0)
+
+	// Only the transactions within the budget are retried
+	forwarder.bandwidthBudget.tokens = 0.5
+	forwarder.retryTransactions(time.Now())
+	requireLenForwarderRetryQueue(t, forwarder, 1)
+	assert.Len(t, forwarder.lowPrio, 1)
+}
+
 func TestForwarderRetry(t *testing.T) {
 	mockConfig := mock.New(t)
 	log := logmock.New(t)
@@ -372,7 +397,7 @@ func TestDomainForwarderRetryQueueAllPayloadsMaxSize(t *testing.T) {
 		retry.NewPointCountTelemetryMock())
 	mockConfig := mock.New(t)
 	log := logmock.New(t)
-	forwarder := newDomainForwarder(mockConfig, log, "test", false, transactionRetryQueue, 0, 10, transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: true}, retry.NewPointCountTelemetry("domain"))
+	forwarder := newDomainForwarder(mockConfig, log, "test", false, transactionRetryQueue, 0, 10, transaction.SortByCreatedTimeAndPriority{HighPriorityFirst: true}, retry.NewPointCountTelemetry("domain"), nil)
 	forwarder.blockedList.close("blocked")
 	forwarder.blockedList.errorPerEndpoint["blocked"].until = time.Now().Add(1 * time.Minute)
 
@@ -434,7 +459,7 @@ func newDomainForwarderForTest(config config.Component, log log.Component, conne
 		telemetry,
 		retry.NewPointCountTelemetryMock())
 
-	return newDomainForwarder(config, log, "test", ha, transactionRetryQueue, 1, connectionResetInterval, sorter, retry.NewPointCountTelemetry("domain"))
+	return newDomainForwarder(config, log, "test", ha, transactionRetryQueue, 1, connectionResetInterval, sorter, retry.NewPointCountTelemetry("domain"), nil)
 }
 
 func requireLenForwarderRetryQueue(t *testing.T, forwarder *domainForwarder, expectedValue int) {
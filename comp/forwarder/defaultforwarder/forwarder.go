@@ -57,6 +57,10 @@ func createOptions(params Params, config config.Component, log log.Component) *O
 		options.DisableAPIKeyChecking = disableAPIKeyChecking
 	}
 	options.SetEnabledFeatures(params.features)
+	options.RoutedDomains = make(map[string]struct{})
+	for domain := range utils.GetMetricsRoutingRules(config) {
+		options.RoutedDomains[domain] = struct{}{}
+	}
 
 	return options
 }
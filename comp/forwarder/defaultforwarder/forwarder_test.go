@@ -209,6 +209,59 @@ func TestCreateHTTPTransactionsWithMultipleDomains(t *testing.T) {
 	assert.Equal(t, txBar[0].Headers.Get("DD-Api-Key"), "api-key-3")
 }
 
+func TestCreateHTTPTransactionsWithRoutes(t *testing.T) {
+	mockConfig := mock.New(t)
+	log := logmock.New(t)
+	options := NewOptionsWithResolvers(mockConfig, log, resolver.NewSingleDomainResolvers(keysWithMultipleDomains))
+	options.RoutedDomains = map[string]struct{}{"datadog.bar": {}}
+	forwarder := NewDefaultForwarder(mockConfig, log, options)
+	endpoint := transaction.Endpoint{Route: "/api/foo", Name: "foo"}
+	p1 := []byte("A payload")
+	p2 := []byte("A routed payload")
+	payloads := transaction.NewBytesPayloadsWithoutMetaData([]*[]byte{&p1, &p2})
+	payloads[1].Route = "datadog.bar"
+
+	transactions := forwarder.createHTTPTransactions(endpoint, payloads, transaction.Series, make(http.Header))
+	require.Len(t, transactions, 3)
+
+	for _, tr := range transactions {
+		if tr.Domain == "datadog.bar" {
+			assert.Equal(t, p2, tr.Payload.GetContent())
+			assert.Equal(t, "api-key-3", tr.Headers.Get("DD-Api-Key"))
+		} else {
+			assert.Equal(t, testVersionDomain, tr.Domain)
+			assert.Equal(t, p1, tr.Payload.GetContent())
+		}
+	}
+}
+
+func TestCreateHTTPTransactionsWithRouteOnMainDomain(t *testing.T) {
+	mockConfig := mock.NewFromYAML(t, `
+api_key: api-key-1
+dd_url: "http://app.datadoghq.com"
+additional_endpoints:
+  "http://app.datadoghq.com":
+  - api-key-2
+additional_endpoints_routing:
+  "http://app.datadoghq.com":
+  - app.billing.*
+`)
+	log := logmock.New(t)
+	options := createOptions(NewParams(), mockConfig, log)
+	// the API keys of the additional endpoint are merged with the main ones, routing it would filter the main organization
+	assert.Empty(t, options.RoutedDomains)
+
+	forwarder := NewDefaultForwarder(mockConfig, log, options)
+	endpoint := transaction.Endpoint{Route: "/api/foo", Name: "foo"}
+	p1 := []byte("A payload")
+	payloads := transaction.NewBytesPayloadsWithoutMetaData([]*[]byte{&p1})
+
+	transactions := forwarder.createHTTPTransactions(endpoint, payloads, transaction.Series, make(http.Header))
+	require.Len(t, transactions, 2)
+	assert.Equal(t, "api-key-1", transactions[0].Headers.Get("DD-Api-Key"))
+	assert.Equal(t, "api-key-2", transactions[1].Headers.Get("DD-Api-Key"))
+}
+
 func TestCreateHTTPTransactionsWithDifferentResolvers(t *testing.T) {
 	resolvers := resolver.NewSingleDomainResolvers(keysWithMultipleDomains)
 	additionalResolver := resolver.NewMultiDomainResolver("datadog.vector", []string{"api-key-4"})
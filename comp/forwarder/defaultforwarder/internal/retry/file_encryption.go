@@ -0,0 +1,158 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package retry
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	// minEncryptionKeyLength is the minimum length of the secret used to
+	// derive the encryption key.
+	minEncryptionKeyLength = 32
+
+	encryptedFileVersion = 1
+	keyIDSize            = 8
+)
+
+// encryptedFileMagic starts every encrypted `.retry` file. It cannot be the
+// start of a serialized HttpTransactionProtoCollection.
+var encryptedFileMagic = []byte("DDRQ")
+
+// encryptedFileHeaderSize is the size of the header of an encrypted file:
+// the magic, the version and the ID of the key. The header is authenticated
+// along with the content of the file.
+var encryptedFileHeaderSize = len(encryptedFileMagic) + 1 + keyIDSize
+
+type keyID [keyIDSize]byte
+
+type encryptionKey struct {
+	id   keyID
+	aead cipher.AEAD
+}
+
+// FileEncryption encrypts and authenticates the content of the `.retry` files
+// with AES-256-GCM.
+// Files are always written with the current key. The previous keys are kept
+// so that files written before a key rotation can still be read.
+type FileEncryption struct {
+	mutex   sync.RWMutex
+	current *encryptionKey
+	keys    map[keyID]*encryptionKey
+}
+
+// NewFileEncryption creates a new instance of FileEncryption. `key` is used to
+// encrypt new files while `previousKeys` are only used to decrypt existing files.
+func NewFileEncryption(key string, previousKeys []string) (*FileEncryption, error) {
+	e := &FileEncryption{
+		keys: make(map[keyID]*encryptionKey),
+	}
+	for _, previousKey := range previousKeys {
+		k, err := newEncryptionKey(previousKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid previous encryption key: %v", err)
+		}
+		e.keys[k.id] = k
+	}
+	if err := e.RotateKey(key); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// RotateKey replaces the key used to encrypt new files. The replaced key is
+// still used to decrypt the files it encrypted.
+func (e *FileEncryption) RotateKey(key string) error {
+	k, err := newEncryptionKey(key)
+	if err != nil {
+		return fmt.Errorf("invalid encryption key: %v", err)
+	}
+
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.current = k
+	e.keys[k.id] = k
+	return nil
+}
+
+func (e *FileEncryption) encrypt(plaintext []byte) ([]byte, error) {
+	e.mutex.RLock()
+	k := e.current
+	e.mutex.RUnlock()
+
+	nonceSize := k.aead.NonceSize()
+	out := make([]byte, encryptedFileHeaderSize+nonceSize, encryptedFileHeaderSize+nonceSize+len(plaintext)+k.aead.Overhead())
+	copy(out, encryptedFileMagic)
+	out[len(encryptedFileMagic)] = encryptedFileVersion
+	copy(out[len(encryptedFileMagic)+1:], k.id[:])
+
+	nonce := out[encryptedFileHeaderSize:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return k.aead.Seal(out, nonce, plaintext, out[:encryptedFileHeaderSize]), nil
+}
+
+func (e *FileEncryption) decrypt(data []byte) ([]byte, error) {
+	if !isEncryptedFile(data) || len(data) < encryptedFileHeaderSize {
+		return nil, errors.New("the file is not encrypted")
+	}
+	if version := data[len(encryptedFileMagic)]; version != encryptedFileVersion {
+		return nil, fmt.Errorf("unsupported encrypted file version %d", version)
+	}
+
+	var id keyID
+	copy(id[:], data[len(encryptedFileMagic)+1:encryptedFileHeaderSize])
+
+	e.mutex.RLock()
+	k, found := e.keys[id]
+	e.mutex.RUnlock()
+	if !found {
+		return nil, errors.New("the file was encrypted with an unknown key")
+	}
+
+	nonceSize := k.aead.NonceSize()
+	if len(data) < encryptedFileHeaderSize+nonceSize {
+		return nil, errors.New("the encrypted file is truncated")
+	}
+	nonce := data[encryptedFileHeaderSize : encryptedFileHeaderSize+nonceSize]
+	return k.aead.Open(nil, nonce, data[encryptedFileHeaderSize+nonceSize:], data[:encryptedFileHeaderSize])
+}
+
+func isEncryptedFile(data []byte) bool {
+	return bytes.HasPrefix(data, encryptedFileMagic)
+}
+
+// newEncryptionKey derives an AES-256 key from the secret. The key ID is a
+// hash of the derived key, so it does not reveal the key itself.
+func newEncryptionKey(secret string) (*encryptionKey, error) {
+	if len(secret) < minEncryptionKeyLength {
+		return nil, fmt.Errorf("the key must be at least %d characters long", minEncryptionKeyLength)
+	}
+
+	key := sha256.Sum256([]byte(secret))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &encryptionKey{aead: aead}
+	keyHash := sha256.Sum256(key[:])
+	copy(k.id[:], keyHash[:])
+	return k, nil
+}
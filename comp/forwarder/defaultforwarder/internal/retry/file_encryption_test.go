@@ -0,0 +1,127 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package retry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testEncryptionKey1 = "0123456789abcdef0123456789abcdef"
+	testEncryptionKey2 = "fedcba9876543210fedcba9876543210"
+)
+
+func TestFileEncryption(t *testing.T) {
+	a := assert.New(t)
+	e, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+
+	plaintext := []byte("payload with an api key")
+	encrypted, err := e.encrypt(plaintext)
+	a.NoError(err)
+	a.True(isEncryptedFile(encrypted))
+	a.NotContains(string(encrypted), string(plaintext))
+
+	// A random nonce is used for each file.
+	encryptedTwice, err := e.encrypt(plaintext)
+	a.NoError(err)
+	a.NotEqual(encrypted, encryptedTwice)
+
+	decrypted, err := e.decrypt(encrypted)
+	a.NoError(err)
+	a.Equal(plaintext, decrypted)
+}
+
+func TestFileEncryptionAuthentication(t *testing.T) {
+	a := assert.New(t)
+	e, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+
+	encrypted, err := e.encrypt([]byte("payload"))
+	a.NoError(err)
+
+	// Tamper with the content
+	tampered := append([]byte{}, encrypted...)
+	tampered[len(tampered)-1] ^= 0xff
+	_, err = e.decrypt(tampered)
+	a.Error(err)
+
+	// Tamper with the header
+	tampered = append([]byte{}, encrypted...)
+	tampered[len(encryptedFileMagic)] = encryptedFileVersion + 1
+	_, err = e.decrypt(tampered)
+	a.Error(err)
+
+	_, err = e.decrypt(encrypted[:encryptedFileHeaderSize+1])
+	a.Error(err)
+
+	_, err = e.decrypt([]byte("not encrypted"))
+	a.Error(err)
+}
+
+func TestFileEncryptionKeyRotation(t *testing.T) {
+	a := assert.New(t)
+	e, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+
+	encryptedWithKey1, err := e.encrypt([]byte("payload1"))
+	a.NoError(err)
+
+	a.NoError(e.RotateKey(testEncryptionKey2))
+	encryptedWithKey2, err := e.encrypt([]byte("payload2"))
+	a.NoError(err)
+
+	// Files written with the previous key can still be read
+	decrypted, err := e.decrypt(encryptedWithKey1)
+	a.NoError(err)
+	a.Equal([]byte("payload1"), decrypted)
+
+	// After a restart, only the keys that are still configured can be used
+	e, err = NewFileEncryption(testEncryptionKey2, nil)
+	a.NoError(err)
+	_, err = e.decrypt(encryptedWithKey1)
+	a.Error(err)
+
+	e, err = NewFileEncryption(testEncryptionKey2, []string{testEncryptionKey1})
+	a.NoError(err)
+	decrypted, err = e.decrypt(encryptedWithKey1)
+	a.NoError(err)
+	a.Equal([]byte("payload1"), decrypted)
+	decrypted, err = e.decrypt(encryptedWithKey2)
+	a.NoError(err)
+	a.Equal([]byte("payload2"), decrypted)
+
+	// The current key is still used to write new files
+	encrypted, err := e.encrypt([]byte("payload3"))
+	a.NoError(err)
+	e, err = NewFileEncryption(testEncryptionKey2, nil)
+	a.NoError(err)
+	_, err = e.decrypt(encrypted)
+	a.NoError(err)
+}
+
+func TestFileEncryptionInvalidKey(t *testing.T) {
+	a := assert.New(t)
+	_, err := NewFileEncryption("too short", nil)
+	a.Error(err)
+
+	_, err = NewFileEncryption(testEncryptionKey1, []string{"too short"})
+	a.Error(err)
+
+	e, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+	a.Error(e.RotateKey("too short"))
+
+	// The current key is kept
+	encrypted, err := e.encrypt([]byte("payload"))
+	a.NoError(err)
+	e, err = NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+	_, err = e.decrypt(encrypted)
+	a.NoError(err)
+}
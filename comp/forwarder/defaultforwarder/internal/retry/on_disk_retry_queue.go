@@ -6,6 +6,7 @@
 package retry
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -26,6 +27,7 @@ type onDiskRetryQueue struct {
 	serializer          *HTTPTransactionsSerializer
 	storagePath         string
 	diskUsageLimit      *DiskUsageLimit
+	encryption          *FileEncryption
 	filenames           []string
 	currentSizeInBytes  int64
 	telemetry           onDiskRetryQueueTelemetry
@@ -37,6 +39,7 @@ func newOnDiskRetryQueue(
 	serializer *HTTPTransactionsSerializer,
 	storagePath string,
 	diskUsageLimit *DiskUsageLimit,
+	encryption *FileEncryption,
 	telemetry onDiskRetryQueueTelemetry,
 	pointCountTelemetry *PointCountTelemetry) (*onDiskRetryQueue, error) {
 
@@ -49,6 +52,7 @@ func newOnDiskRetryQueue(
 		serializer:          serializer,
 		storagePath:         storagePath,
 		diskUsageLimit:      diskUsageLimit,
+		encryption:          encryption,
 		telemetry:           telemetry,
 		pointCountTelemetry: pointCountTelemetry,
 	}
@@ -82,6 +86,9 @@ func (s *onDiskRetryQueue) Store(transactions []transaction.Transaction) error {
 	if err != nil {
 		return err
 	}
+	if bytes, err = s.encode(bytes); err != nil {
+		return err
+	}
 	bufferSize := int64(len(bytes))
 
 	if err := s.makeRoomFor(bufferSize); err != nil {
@@ -130,6 +137,10 @@ func (s *onDiskRetryQueue) ExtractLast() ([]transaction.Transaction, error) {
 		return nil, err
 	}
 
+	if bytes, err = s.decode(bytes); err != nil {
+		return nil, err
+	}
+
 	transactions, errorsCount, err := s.serializer.Deserialize(bytes)
 	if err != nil {
 		return nil, err
@@ -167,6 +178,9 @@ func (s *onDiskRetryQueue) makeRoomFor(bufferSize int64) error {
 		s.log.Errorf("Maximum disk space for retry transactions is reached. Removing %s", filename)
 
 		bytes, err := os.ReadFile(filename)
+		if err == nil {
+			bytes, err = s.decode(bytes)
+		}
 		if err != nil {
 			s.log.Errorf("Cannot read the file %v: %v", filename, err)
 		} else if transactions, _, errDeserialize := s.serializer.Deserialize(bytes); errDeserialize == nil {
@@ -188,6 +202,32 @@ func (s *onDiskRetryQueue) makeRoomFor(bufferSize int64) error {
 	return nil
 }
 
+// encode encrypts the serialized transactions when the encryption is enabled.
+func (s *onDiskRetryQueue) encode(bytes []byte) ([]byte, error) {
+	if s.encryption == nil {
+		return bytes, nil
+	}
+	return s.encryption.encrypt(bytes)
+}
+
+// decode returns the serialized transactions stored in a file.
+func (s *onDiskRetryQueue) decode(bytes []byte) ([]byte, error) {
+	if !isEncryptedFile(bytes) {
+		// Files written before the encryption was enabled are read as is.
+		return bytes, nil
+	}
+	if s.encryption == nil {
+		return nil, errors.New("the file is encrypted but no encryption key is configured")
+	}
+
+	decrypted, err := s.encryption.decrypt(bytes)
+	if err != nil {
+		s.telemetry.addDecryptionErrorsCount()
+		return nil, err
+	}
+	return decrypted, nil
+}
+
 func (s *onDiskRetryQueue) onPointDropped(count int) {
 	s.telemetry.addPointDroppedCount(count)
 	s.pointCountTelemetry.OnPointDropped(count)
@@ -8,8 +8,10 @@
 package retry
 
 import (
+	"os"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -102,6 +104,144 @@ func TestOnDiskRetryQueueReloadExistingRetryFiles(t *testing.T) {
 	a.Equal([]string{"endpoint1", "endpoint2"}, getEndpointsFromTransactions(transactions))
 }
 
+func TestOnDiskRetryQueueEncryption(t *testing.T) {
+	a := assert.New(t)
+	path := t.TempDir()
+
+	encryption, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+	q := newTestOnDiskRetryQueueWithEncryption(t, a, path, 1000, encryption)
+	a.NoError(q.Store(createHTTPTransactionCollectionTests("endpoint1", "endpoint2")))
+
+	a.NoError(encryption.RotateKey(testEncryptionKey2))
+	a.NoError(q.Store(createHTTPTransactionCollectionTests("endpoint3", "endpoint4")))
+
+	var diskSpaceUsed int64
+	for _, filename := range q.filenames {
+		content, err := os.ReadFile(filename)
+		a.NoError(err)
+		a.True(isEncryptedFile(content))
+		a.NotContains(string(content), "endpoint")
+		diskSpaceUsed += int64(len(content))
+	}
+	a.Equal(diskSpaceUsed, q.GetDiskSpaceUsed())
+
+	transactions, err := q.ExtractLast()
+	a.NoError(err)
+	a.Equal([]string{"endpoint3", "endpoint4"}, getEndpointsFromTransactions(transactions))
+
+	transactions, err = q.ExtractLast()
+	a.NoError(err)
+	a.Equal([]string{"endpoint1", "endpoint2"}, getEndpointsFromTransactions(transactions))
+	a.Equal(int64(0), q.GetDiskSpaceUsed())
+}
+
+func TestOnDiskRetryQueueEncryptionMaxSize(t *testing.T) {
+	a := assert.New(t)
+	path := t.TempDir()
+
+	maxSizeInBytes := int64(200)
+	pointDropped := fileStoragePointDroppedCountTelemetry.expvar.Value()
+	encryption, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+	q := newTestOnDiskRetryQueueWithEncryption(t, a, path, maxSizeInBytes, encryption)
+
+	a.NoError(q.Store(createHTTPTransactionCollectionTests("0")))
+	maxNumberOfFiles := int(maxSizeInBytes / q.GetDiskSpaceUsed())
+	a.Greaterf(maxNumberOfFiles, 1, "Not enough files for this test, increase maxSizeInBytes")
+
+	// The files removed to make room are decrypted to count the points dropped
+	expectedPointDrop := int64(0)
+	for i := 1; i <= maxNumberOfFiles; i++ {
+		transactions := createHTTPTransactionCollectionTests(strconv.Itoa(i))
+		if i == maxNumberOfFiles {
+			expectedPointDrop = int64(transactions[0].GetPointCount())
+		}
+		a.NoError(q.Store(transactions))
+	}
+	a.LessOrEqual(q.GetDiskSpaceUsed(), maxSizeInBytes)
+	a.Equal(maxNumberOfFiles, q.getFilesCount())
+	a.Equal(pointDropped+expectedPointDrop, fileStoragePointDroppedCountTelemetry.expvar.Value())
+}
+
+func TestOnDiskRetryQueueEncryptionReload(t *testing.T) {
+	a := assert.New(t)
+	path := t.TempDir()
+
+	// Files written before the encryption was enabled are still read
+	plainQueue := newTestOnDiskRetryQueue(t, a, path, 1000)
+	a.NoError(plainQueue.Store(createHTTPTransactionCollectionTests("endpoint1")))
+
+	encryption, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+	encryptedQueue := newTestOnDiskRetryQueueWithEncryption(t, a, path, 1000, encryption)
+	a.NoError(encryptedQueue.Store(createHTTPTransactionCollectionTests("endpoint2")))
+
+	// The previous key must be configured to read the files after a key rotation
+	encryption, err = NewFileEncryption(testEncryptionKey2, []string{testEncryptionKey1})
+	a.NoError(err)
+	rotatedQueue := newTestOnDiskRetryQueueWithEncryption(t, a, path, 1000, encryption)
+	a.Equal(2, rotatedQueue.getFilesCount())
+
+	transactions, err := rotatedQueue.ExtractLast()
+	a.NoError(err)
+	a.Equal([]string{"endpoint2"}, getEndpointsFromTransactions(transactions))
+	transactions, err = rotatedQueue.ExtractLast()
+	a.NoError(err)
+	a.Equal([]string{"endpoint1"}, getEndpointsFromTransactions(transactions))
+}
+
+func TestOnDiskRetryQueueEncryptionErrors(t *testing.T) {
+	a := assert.New(t)
+	path := t.TempDir()
+
+	encryption, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+	encryptedQueue := newTestOnDiskRetryQueueWithEncryption(t, a, path, 1000, encryption)
+	a.NoError(encryptedQueue.Store(createHTTPTransactionCollectionTests("endpoint1")))
+	a.NoError(encryptedQueue.Store(createHTTPTransactionCollectionTests("endpoint2")))
+
+	decryptionErrors := decryptionErrorsCountTelemetry.expvar.Value()
+	encryption, err = NewFileEncryption(testEncryptionKey2, nil)
+	a.NoError(err)
+	q := newTestOnDiskRetryQueueWithEncryption(t, a, path, 1000, encryption)
+	_, err = q.ExtractLast()
+	a.Error(err)
+	a.Equal(decryptionErrors+1, decryptionErrorsCountTelemetry.expvar.Value())
+
+	q = newTestOnDiskRetryQueue(t, a, path, 1000)
+	_, err = q.ExtractLast()
+	a.Error(err)
+
+	// The files which cannot be read are removed
+	a.Equal(0, q.getFilesCount())
+	a.Equal(int64(0), q.GetDiskSpaceUsed())
+}
+
+func TestOnDiskRetryQueueEncryptionRemovalPolicy(t *testing.T) {
+	a := assert.New(t)
+	root := t.TempDir()
+	p, err := NewFileRemovalPolicy(root, 2, FileRemovalPolicyTelemetry{})
+	a.NoError(err)
+	domain, err := p.RegisterDomain(domainName)
+	a.NoError(err)
+
+	encryption, err := NewFileEncryption(testEncryptionKey1, nil)
+	a.NoError(err)
+	q := newTestOnDiskRetryQueueWithEncryption(t, a, domain, 1000, encryption)
+	a.NoError(q.Store(createHTTPTransactionCollectionTests("endpoint1")))
+	a.NoError(q.Store(createHTTPTransactionCollectionTests("endpoint2")))
+
+	outdatedFile := q.filenames[0]
+	modTime := time.Now().Add(time.Duration(-3*24) * time.Hour)
+	a.NoError(os.Chtimes(outdatedFile, modTime, modTime))
+
+	pathsRemoved, err := p.RemoveOutdatedFiles()
+	a.NoError(err)
+	assertFilenamesEqual(a, []string{outdatedFile}, pathsRemoved)
+	assertFilenamesEqual(a, []string{q.filenames[1]}, getRemainingFiles(a, root))
+}
+
 func createHTTPTransactionCollectionTests(endpoints ...string) []transaction.Transaction {
 	var transactions []transaction.Transaction
 
@@ -126,6 +266,10 @@ func getEndpointsFromTransactions(transactions []transaction.Transaction) []stri
 }
 
 func newTestOnDiskRetryQueue(t *testing.T, a *assert.Assertions, path string, maxSizeInBytes int64) *onDiskRetryQueue {
+	return newTestOnDiskRetryQueueWithEncryption(t, a, path, maxSizeInBytes, nil)
+}
+
+func newTestOnDiskRetryQueueWithEncryption(t *testing.T, a *assert.Assertions, path string, maxSizeInBytes int64, encryption *FileEncryption) *onDiskRetryQueue {
 	telemetry := newOnDiskRetryQueueTelemetry("domain")
 	disk := diskUsageRetrieverMock{
 		diskUsage: &filesystem.DiskUsage{
@@ -134,7 +278,7 @@ func newTestOnDiskRetryQueue(t *testing.T, a *assert.Assertions, path string, ma
 		}}
 	diskUsageLimit := NewDiskUsageLimit("", disk, maxSizeInBytes, 1)
 	log := logmock.New(t)
-	storage, err := newOnDiskRetryQueue(log, NewHTTPTransactionsSerializer(log, resolver.NewSingleDomainResolver(domainName, nil)), path, diskUsageLimit, telemetry, NewPointCountTelemetryMock())
+	storage, err := newOnDiskRetryQueue(log, NewHTTPTransactionsSerializer(log, resolver.NewSingleDomainResolver(domainName, nil)), path, diskUsageLimit, encryption, telemetry, NewPointCountTelemetryMock())
 	a.NoError(err)
 	return storage
 }
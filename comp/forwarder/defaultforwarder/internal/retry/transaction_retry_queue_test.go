@@ -185,6 +185,7 @@ func newOnDiskRetryQueueTest(t *testing.T, a *assert.Assertions) *onDiskRetryQue
 		NewHTTPTransactionsSerializer(log, resolver.NewSingleDomainResolver("", nil)),
 		path,
 		diskUsageLimit,
+		nil,
 		newOnDiskRetryQueueTelemetry("domain"),
 		NewPointCountTelemetryMock())
 	a.NoError(err)
@@ -0,0 +1,114 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package defaultforwarder
+
+import (
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/transaction"
+)
+
+// priorityQueue sits between a domain forwarder and its workers when the
+// bandwidth budget is enabled. It stores the new transactions by priority
+// class, so that a worker always picks the queued transaction with the highest
+// priority instead of the oldest one, even when it is the only worker.
+type priorityQueue struct {
+	input  <-chan transaction.Transaction
+	output chan transaction.Transaction
+	ranks  map[priorityClass]int
+
+	// queues stores the transactions of each rank, 0 being the highest priority
+	queues  [][]transaction.Transaction
+	size    int
+	maxSize int
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// newPriorityQueue returns a queue reading the transactions from input and
+// storing up to maxSize of them before it stops reading. The workers receive
+// the transactions from the unbuffered output channel.
+func newPriorityQueue(input <-chan transaction.Transaction, ranks map[priorityClass]int, maxSize int) *priorityQueue {
+	return &priorityQueue{
+		input:   input,
+		output:  make(chan transaction.Transaction),
+		ranks:   ranks,
+		queues:  make([][]transaction.Transaction, len(ranks)),
+		maxSize: max(maxSize, 1),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+}
+
+// start starts moving the transactions from the input to the workers.
+func (q *priorityQueue) start() {
+	go func() {
+		defer close(q.stopped)
+		for {
+			// When the queue is full, the input channel fills up and the domain
+			// forwarder sends the new transactions to the retry queue.
+			input := q.input
+			if q.size >= q.maxSize {
+				input = nil
+			}
+			var output chan transaction.Transaction
+			next := q.peek()
+			if next != nil {
+				output = q.output
+			}
+
+			select {
+			case t := <-input:
+				q.push(t)
+			case output <- next:
+				q.pop()
+			case <-q.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopAndDrain stops the queue and returns the transactions it stores, by
+// order of priority.
+func (q *priorityQueue) stopAndDrain() []transaction.Transaction {
+	close(q.stop)
+	<-q.stopped
+
+	var transactions []transaction.Transaction
+	for t := q.peek(); t != nil; t = q.peek() {
+		transactions = append(transactions, t)
+		q.pop()
+	}
+	return transactions
+}
+
+func (q *priorityQueue) push(t transaction.Transaction) {
+	rank := q.ranks[getPriorityClass(t)]
+	q.queues[rank] = append(q.queues[rank], t)
+	q.size++
+}
+
+// peek returns the oldest transaction with the highest priority, or nil when
+// the queue is empty.
+func (q *priorityQueue) peek() transaction.Transaction {
+	for _, transactions := range q.queues {
+		if len(transactions) > 0 {
+			return transactions[0]
+		}
+	}
+	return nil
+}
+
+func (q *priorityQueue) pop() {
+	for rank, transactions := range q.queues {
+		if len(transactions) > 0 {
+			transactions[0] = nil
+			q.queues[rank] = transactions[1:]
+			q.size--
+			return
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+package defaultforwarder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/endpoints"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/transaction"
+)
+
+func newTestPriorityQueue(t *testing.T, input chan transaction.Transaction, maxSize int) *priorityQueue {
+	b := newBandwidthBudgetWithOrder(logmock.New(t), 1, 100, time.Minute, nil)
+	return newPriorityQueue(input, b.ranks, maxSize)
+}
+
+func TestPriorityQueueOrder(t *testing.T) {
+	input := make(chan transaction.Transaction, 3)
+	q := newTestPriorityQueue(t, input, 10)
+
+	manifests := newBudgetTestTransaction(transaction.Process, endpoints.OrchestratorManifestEndpoint, 10)
+	series := newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 10)
+	serviceChecks := newBudgetTestTransaction(transaction.ServiceChecks, endpoints.ServiceChecksEndpoint, 10)
+	input <- manifests
+	input <- series
+	input <- serviceChecks
+	q.start()
+	defer q.stopAndDrain()
+
+	// The worker is busy while the transactions are queued
+	require.Eventually(t, func() bool { return len(input) == 0 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+
+	assert.Same(t, serviceChecks, <-q.output)
+	assert.Same(t, series, <-q.output)
+	assert.Same(t, manifests, <-q.output)
+}
+
+func TestPriorityQueueFull(t *testing.T) {
+	input := make(chan transaction.Transaction, 3)
+	q := newTestPriorityQueue(t, input, 2)
+	q.start()
+
+	for i := 0; i < 3; i++ {
+		input <- newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 10)
+	}
+	// The queue stops reading its input once it is full
+	require.Eventually(t, func() bool { return len(input) == 1 }, time.Second, time.Millisecond)
+	time.Sleep(10 * time.Millisecond)
+	assert.Len(t, input, 1)
+
+	<-q.output
+	require.Eventually(t, func() bool { return len(input) == 0 }, time.Second, time.Millisecond)
+	assert.Len(t, q.stopAndDrain(), 2)
+}
+
+func TestPriorityQueueStopAndDrain(t *testing.T) {
+	input := make(chan transaction.Transaction, 2)
+	q := newTestPriorityQueue(t, input, 10)
+
+	series := newBudgetTestTransaction(transaction.Series, endpoints.SeriesEndpoint, 10)
+	metadata := newBudgetTestTransaction(transaction.Metadata, endpoints.V1MetadataEndpoint, 10)
+	input <- series
+	input <- metadata
+	q.start()
+	require.Eventually(t, func() bool { return len(input) == 0 }, time.Second, time.Millisecond)
+
+	transactions := q.stopAndDrain()
+	require.Len(t, transactions, 2)
+	assert.Same(t, metadata, transactions[0])
+	assert.Same(t, series, transactions[1])
+}
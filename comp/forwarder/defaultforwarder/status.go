@@ -63,3 +63,56 @@ func (s statusProvider) Text(_ bool, buffer io.Writer) error {
 func (s statusProvider) HTML(_ bool, buffer io.Writer) error {
 	return status.RenderHTML(templatesFS, "forwarderHTML.tmpl", buffer, s.getStatusInfo())
 }
+
+// transactionsStats is the part of the forwarder expvar exposed as OpenMetrics
+type transactionsStats struct {
+	Transactions struct {
+		SuccessByEndpoint     map[string]int64
+		DroppedByEndpoint     map[string]int64
+		RequeuedByEndpoint    map[string]int64
+		RetriedByEndpoint     map[string]int64
+		ErrorsByType          map[string]int64
+		HTTPErrorsByCode      map[string]int64
+		HighPriorityQueueFull int64
+		RetryQueueSize        int64
+	}
+}
+
+func (s statusProvider) OpenMetrics() ([]*status.MetricFamily, error) {
+	return transactionsMetricFamilies([]byte(expvar.Get("forwarder").String()))
+}
+
+func transactionsMetricFamilies(forwarderStatsJSON []byte) ([]*status.MetricFamily, error) {
+	var stats transactionsStats
+	if err := json.Unmarshal(forwarderStatsJSON, &stats); err != nil {
+		return nil, err
+	}
+	transactions := stats.Transactions
+
+	byEndpoint := func(name, help string, values map[string]int64) *status.MetricFamily {
+		family := status.NewCounter(name, help)
+		for endpoint, value := range values {
+			family.Add(float64(value), "endpoint", endpoint)
+		}
+		return family
+	}
+	success := byEndpoint("forwarder_transactions_success", "Number of transactions successfully sent.", transactions.SuccessByEndpoint)
+	dropped := byEndpoint("forwarder_transactions_dropped", "Number of transactions dropped.", transactions.DroppedByEndpoint)
+	requeued := byEndpoint("forwarder_transactions_requeued", "Number of transactions requeued after an error.", transactions.RequeuedByEndpoint)
+	retried := byEndpoint("forwarder_transactions_retried", "Number of transactions retried.", transactions.RetriedByEndpoint)
+
+	errors := status.NewCounter("forwarder_transactions_errors", "Number of transactions which failed to be sent.")
+	for errorType, value := range transactions.ErrorsByType {
+		errors.Add(float64(value), "error_type", errorType)
+	}
+	httpErrors := status.NewCounter("forwarder_transactions_http_errors", "Number of transactions which got an HTTP error response.")
+	for code, value := range transactions.HTTPErrorsByCode {
+		httpErrors.Add(float64(value), "code", code)
+	}
+	highPriorityQueueFull := status.NewCounter("forwarder_high_priority_queue_full", "Number of times the high priority queue was full.")
+	highPriorityQueueFull.Add(float64(transactions.HighPriorityQueueFull))
+	retryQueueSize := status.NewGauge("forwarder_retry_queue_size", "Number of transactions in the retry queue.")
+	retryQueueSize.Add(float64(transactions.RetryQueueSize))
+
+	return []*status.MetricFamily{success, dropped, requeued, retried, errors, httpErrors, highPriorityQueueFull, retryQueueSize}, nil
+}
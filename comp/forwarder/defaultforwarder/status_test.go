@@ -14,6 +14,7 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/status"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 )
 
@@ -85,3 +86,39 @@ func TestHTML(t *testing.T) {
 
 	assert.NotEqual(t, "", b.String())
 }
+
+func TestOpenMetrics(t *testing.T) {
+	forwarderStatsJSON := `{
+		"APIKeyStatus": {},
+		"Transactions": {
+			"Success": 12,
+			"SuccessByEndpoint": {"series_v2": 10, "check_run_v1": 2},
+			"DroppedByEndpoint": {"series_v2": 1},
+			"RequeuedByEndpoint": {},
+			"RetriedByEndpoint": {"series_v2": 3},
+			"ErrorsByType": {"DNSErrors": 0, "ConnectionErrors": 4},
+			"HTTPErrorsByCode": {"503": 3},
+			"HighPriorityQueueFull": 0,
+			"RetryQueueSize": 2,
+			"Pods": {"Success": 0}
+		}
+	}`
+	families, err := transactionsMetricFamilies([]byte(forwarderStatsJSON))
+	assert.NoError(t, err)
+
+	b := new(bytes.Buffer)
+	assert.NoError(t, status.RenderOpenMetrics(b, families))
+
+	for _, line := range []string{
+		`datadog_agent_forwarder_transactions_success_total{endpoint="check_run_v1"} 2`,
+		`datadog_agent_forwarder_transactions_success_total{endpoint="series_v2"} 10`,
+		`datadog_agent_forwarder_transactions_dropped_total{endpoint="series_v2"} 1`,
+		`datadog_agent_forwarder_transactions_retried_total{endpoint="series_v2"} 3`,
+		`datadog_agent_forwarder_transactions_errors_total{error_type="ConnectionErrors"} 4`,
+		`datadog_agent_forwarder_transactions_http_errors_total{code="503"} 3`,
+		`datadog_agent_forwarder_high_priority_queue_full_total 0`,
+		`datadog_agent_forwarder_retry_queue_size 2`,
+	} {
+		assert.Contains(t, b.String(), line+"\n")
+	}
+}
@@ -46,6 +46,7 @@ func init() {
 	initOrchestratorExpVars()
 	initTransactionsExpvars()
 	initForwarderHealthExpvars()
+	initBandwidthBudgetExpvars()
 	initEndpointExpvars()
 }
 
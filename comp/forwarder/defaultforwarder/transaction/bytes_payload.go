@@ -11,6 +11,9 @@ type BytesPayload struct {
 	content     []byte
 	pointCount  int
 	Destination Destination
+	// Route is the additional endpoint the payload is routed to. Payloads
+	// without route are sent to every endpoint without routing rules.
+	Route string
 }
 
 // NewBytesPayload creates a new instance of BytesPayload.
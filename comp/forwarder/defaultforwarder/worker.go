@@ -39,6 +39,7 @@ type Worker struct {
 	stopped               chan struct{}
 	blockedList           *blockedEndpoints
 	pointSuccessfullySent PointSuccessfullySent
+	bandwidthBudget       *bandwidthBudget
 }
 
 // PointSuccessfullySent is called when sending successfully a point to the intake.
@@ -56,6 +57,7 @@ func NewWorker(
 	requeueChan chan<- transaction.Transaction,
 	blocked *blockedEndpoints,
 	pointSuccessfullySent PointSuccessfullySent,
+	bandwidthBudget *bandwidthBudget,
 ) *Worker {
 	return &Worker{
 		config:                config,
@@ -69,6 +71,7 @@ func NewWorker(
 		Client:                NewHTTPClient(config),
 		blockedList:           blocked,
 		pointSuccessfullySent: pointSuccessfullySent,
+		bandwidthBudget:       bandwidthBudget,
 	}
 }
 
@@ -94,7 +97,7 @@ func (w *Worker) Stop(purgeHighPrio bool) {
 			select {
 			case t := <-w.HighPrio:
 				w.log.Debugf("Flushing one new transaction before stopping Worker")
-				w.callProcess(t) //nolint:errcheck
+				w.callProcess(t, false) //nolint:errcheck
 			default:
 				break L
 			}
@@ -112,7 +115,7 @@ func (w *Worker) Start() {
 			// handling high priority transactions first
 			select {
 			case t := <-w.HighPrio:
-				if w.callProcess(t) == nil {
+				if w.callProcess(t, false) == nil {
 					continue
 				}
 				return
@@ -123,11 +126,11 @@ func (w *Worker) Start() {
 
 			select {
 			case t := <-w.HighPrio:
-				if w.callProcess(t) != nil {
+				if w.callProcess(t, false) != nil {
 					return
 				}
 			case t := <-w.LowPrio:
-				if w.callProcess(t) != nil {
+				if w.callProcess(t, true) != nil {
 					return
 				}
 			case <-w.stopChan:
@@ -149,7 +152,7 @@ func (w *Worker) ScheduleConnectionReset() {
 
 // callProcess will process a transaction and cancel it if we need to stop the
 // worker.
-func (w *Worker) callProcess(t transaction.Transaction) error {
+func (w *Worker) callProcess(t transaction.Transaction, lowPriority bool) error {
 	// poll for connection reset events first
 	select {
 	case <-w.resetConnectionChan:
@@ -161,7 +164,7 @@ func (w *Worker) callProcess(t transaction.Transaction) error {
 	ctx = httptrace.WithClientTrace(ctx, transaction.GetClientTrace(w.log))
 	done := make(chan interface{})
 	go func() {
-		w.process(ctx, t)
+		w.process(ctx, t, lowPriority)
 		done <- nil
 	}()
 
@@ -179,12 +182,18 @@ func (w *Worker) callProcess(t transaction.Transaction) error {
 	return nil
 }
 
-func (w *Worker) process(ctx context.Context, t transaction.Transaction) {
+func (w *Worker) process(ctx context.Context, t transaction.Transaction, lowPriority bool) {
 	// Run the endpoint through our blockedEndpoints circuit breaker
 	target := t.GetTarget()
 	if w.blockedList.isBlock(target) {
 		w.requeue(t)
 		w.log.Errorf("Too many errors for endpoint '%s': retrying later", target)
+	} else if !w.acquireBandwidthBudget(ctx, t, lowPriority) {
+		// When the worker is stopped, the transaction is requeued by callProcess
+		if ctx.Err() == nil {
+			w.requeue(t)
+			w.log.Debugf("Egress bandwidth budget exceeded for endpoint '%s': retrying later", target)
+		}
 	} else if err := t.Process(ctx, w.config, w.log, w.Client); err != nil {
 		w.blockedList.close(target)
 		w.requeue(t)
@@ -195,6 +204,16 @@ func (w *Worker) process(ctx context.Context, t transaction.Transaction) {
 	}
 }
 
+// acquireBandwidthBudget waits for the bandwidth budget of the high priority
+// transactions. The low priority ones must not block the worker while high
+// priority transactions are waiting behind them, they are requeued instead.
+func (w *Worker) acquireBandwidthBudget(ctx context.Context, t transaction.Transaction, lowPriority bool) bool {
+	if lowPriority {
+		return w.bandwidthBudget.tryAcquire(t)
+	}
+	return w.bandwidthBudget.acquire(ctx, t)
+}
+
 func (w *Worker) requeue(t transaction.Transaction) {
 	select {
 	case w.RequeueChan <- t:
@@ -26,6 +26,7 @@ import (
 	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameimpl"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/core/status"
 	"github.com/DataDog/datadog-agent/comp/core/tagger"
 	"github.com/DataDog/datadog-agent/comp/core/tagger/taggerimpl"
 	workloadmeta "github.com/DataDog/datadog-agent/comp/core/workloadmeta/def"
@@ -265,6 +266,48 @@ func (suite *AgentTestSuite) TestStatusProvider() {
 	}
 }
 
+func (suite *AgentTestSuite) TestStatusOpenMetrics() {
+	originalProvider := logsProvider
+	logsProvider = func(_ bool) logsStatus.Status {
+		return logsStatus.Status{
+			IsRunning: true,
+			StatusMetrics: map[string]string{
+				"LogsProcessed":  "12",
+				"LogsSent":       "10",
+				"BytesSent":      "2048",
+				"RetryCount":     "1",
+				"RetryTimeSpent": "1s",
+			},
+			Integrations: []logsStatus.Integration{
+				{Name: "nginx", Sources: []logsStatus.Source{{Type: "file", Status: "OK"}, {Type: "file", Status: "open /var/log/nginx: permission denied"}}},
+				{Name: "redis", Sources: []logsStatus.Source{{Type: "file", Status: "OK"}, {Type: "tcp", Status: "Pending"}}},
+			},
+		}
+	}
+	defer func() {
+		logsProvider = originalProvider
+	}()
+
+	families, err := StatusProvider{}.OpenMetrics()
+	assert.NoError(suite.T(), err)
+
+	b := new(bytes.Buffer)
+	assert.NoError(suite.T(), status.RenderOpenMetrics(b, families))
+	for _, line := range []string{
+		`datadog_agent_logs_running 1`,
+		`datadog_agent_logs_sources{status="error",type="file"} 1`,
+		`datadog_agent_logs_sources{status="success",type="file"} 2`,
+		`datadog_agent_logs_sources{status="pending",type="tcp"} 1`,
+		`datadog_agent_logs_processed_total 12`,
+		`datadog_agent_logs_sent_total 10`,
+		`datadog_agent_logs_bytes_sent_total 2048`,
+		`datadog_agent_logs_retries_total 1`,
+	} {
+		assert.Contains(suite.T(), b.String(), line+"\n")
+	}
+	assert.NotContains(suite.T(), b.String(), "logs_encoded_bytes_sent")
+}
+
 func (suite *AgentTestSuite) TestStatusOut() {
 	originalProvider := logsProvider
 
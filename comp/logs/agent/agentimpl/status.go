@@ -7,7 +7,9 @@ package agentimpl
 
 import (
 	"embed"
+	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/DataDog/datadog-agent/comp/core/status"
 	logsStatus "github.com/DataDog/datadog-agent/pkg/logs/status"
@@ -61,6 +63,74 @@ func (p StatusProvider) HTML(verbose bool, buffer io.Writer) error {
 	return status.RenderHTML(templatesFS, "logsagentHTML.tmpl", buffer, p.getStatusInfo(verbose))
 }
 
+// logsStatusMetrics maps the metrics of the logs agent status to the
+// OpenMetrics counters they are exposed as
+var logsStatusMetrics = []struct {
+	key  string
+	name string
+	help string
+}{
+	{"LogsProcessed", "logs_processed", "Number of logs processed."},
+	{"LogsSent", "logs_sent", "Number of logs sent."},
+	{"BytesSent", "logs_bytes_sent", "Number of bytes of logs sent, before encoding."},
+	{"EncodedBytesSent", "logs_encoded_bytes_sent", "Number of bytes of logs sent, after encoding."},
+	{"RetryCount", "logs_retries", "Number of retries of the logs payloads."},
+}
+
+// OpenMetrics returns the state of the logs agent and of its sources
+func (p StatusProvider) OpenMetrics() ([]*status.MetricFamily, error) {
+	logsStats := logsProvider(false)
+
+	running := status.NewGauge("logs_running", "Whether the logs agent is running.")
+	if logsStats.IsRunning {
+		running.Add(1)
+	} else {
+		running.Add(0)
+	}
+	families := []*status.MetricFamily{running}
+
+	// the sources are counted by type and status
+	type sourceKey struct{ sourceType, status string }
+	counts := make(map[sourceKey]int)
+	for _, integration := range logsStats.Integrations {
+		for _, source := range integration.Sources {
+			counts[sourceKey{source.Type, sourceStatus(source.Status)}]++
+		}
+	}
+	sources := status.NewGauge("logs_sources", "Number of logs sources by type and status.")
+	for key, count := range counts {
+		sources.Add(float64(count), "type", key.sourceType, "status", key.status)
+	}
+	families = append(families, sources)
+
+	for _, metric := range logsStatusMetrics {
+		value, found := logsStats.StatusMetrics[metric.key]
+		if !found {
+			continue
+		}
+		parsed, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s logs metric: %w", metric.key, err)
+		}
+		counter := status.NewCounter(metric.name, metric.help)
+		counter.Add(parsed)
+		families = append(families, counter)
+	}
+	return families, nil
+}
+
+// sourceStatus returns the status label of a source from its rendered status,
+// which is the error message for the sources in error
+func sourceStatus(rendered string) string {
+	switch rendered {
+	case "OK":
+		return "success"
+	case "Pending":
+		return "pending"
+	}
+	return "error"
+}
+
 // AddGlobalWarning keeps track of a warning message to display on the status.
 func (p StatusProvider) AddGlobalWarning(key string, warning string) {
 	logsStatus.AddGlobalWarning(key, warning)
@@ -56,8 +56,9 @@ func TestGetAdditionalEndpoints(t *testing.T) {
 			APIKey:     "apiKey3",
 			IsReliable: pointer.Ptr(false),
 			Endpoint: Endpoint{
-				Host: "http://localhost2",
-				Port: 5678,
+				Host:         "http://localhost2",
+				Port:         5678,
+				RoutingRules: []string{"service:payments"},
 			},
 		},
 	}
@@ -76,7 +77,8 @@ func TestGetAdditionalEndpoints(t *testing.T) {
 			"api_key":     "apiKey3",
 			"Host":        "http://localhost2",
 			"Port":        5678,
-			"is_reliable": false
+			"is_reliable": false,
+			"routing_rules": ["service:payments"]
 		}]`
 	configMock.SetWithoutSource("logs_config.additional_endpoints", jsonString)
 
@@ -95,10 +97,11 @@ func TestGetAdditionalEndpoints(t *testing.T) {
 				"use_ssl":     true,
 			},
 			{
-				"api_key":     "apiKey3",
-				"Host":        "http://localhost2",
-				"Port":        5678,
-				"is_reliable": false,
+				"api_key":       "apiKey3",
+				"Host":          "http://localhost2",
+				"Port":          5678,
+				"is_reliable":   false,
+				"routing_rules": []string{"service:payments"},
 			},
 		})
 	endpoints = l.getAdditionalEndpoints()
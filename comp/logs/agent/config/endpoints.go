@@ -57,6 +57,10 @@ type Endpoint struct {
 	TrackType IntakeTrackType
 	Protocol  IntakeProtocol
 	Origin    IntakeOrigin
+
+	// RoutingRules restricts the logs sent to an additional endpoint to the ones
+	// matching at least one rule, for instance `service:payments`.
+	RoutingRules []string `mapstructure:"routing_rules" json:"routing_rules"`
 }
 
 // unmarshalEndpoint is used to load additional endpoints from the configuration which stored as JSON/mapstructure.
@@ -136,6 +140,7 @@ func loadTCPAdditionalEndpoints(main Endpoint, l *LogsConfigKeys) []Endpoint {
 		newE.TrackType = e.TrackType
 		newE.Protocol = e.Protocol
 		newE.Origin = e.Origin
+		newE.RoutingRules = e.RoutingRules
 
 		if e.UseSSL != nil {
 			newE.useSSL = *e.UseSSL
@@ -168,6 +173,7 @@ func loadHTTPAdditionalEndpoints(main Endpoint, l *LogsConfigKeys, intakeTrackTy
 		newE.TrackType = e.TrackType
 		newE.Protocol = e.Protocol
 		newE.Origin = e.Origin
+		newE.RoutingRules = e.RoutingRules
 
 		if e.UseSSL != nil {
 			newE.useSSL = *e.UseSSL
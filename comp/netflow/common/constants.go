@@ -26,4 +26,10 @@ const (
 
 	// DefaultPrometheusListenerAddress is the default goflow prometheus listener address
 	DefaultPrometheusListenerAddress = "localhost:9090"
+
+	// DefaultGeoIPReloadInterval is the default interval in seconds at which GeoIP databases are checked for changes
+	DefaultGeoIPReloadInterval = 60
+
+	// DefaultReplicationQueueSize is the default number of datagrams buffered for each replication target
+	DefaultReplicationQueueSize = 1000
 )
@@ -33,6 +33,10 @@ type Flow struct {
 	Bytes   uint64
 	Packets uint64
 
+	// Bytes and Packets multiplied by the sampling rate, set during Flow aggregation processing
+	EstimatedBytes   uint64
+	EstimatedPackets uint64
+
 	// Source/destination addresses
 	SrcAddr []byte // FLOW KEY
 	DstAddr []byte // FLOW KEY
@@ -131,6 +135,16 @@ var (
 	}
 )
 
+// FilterAction is used to configure what happens to the flows matching a filter rule
+type FilterAction string
+
+var (
+	// FilterInclude keeps the flows matching the rule
+	FilterInclude FilterAction = "include"
+	// FilterExclude drops the flows matching the rule
+	FilterExclude FilterAction = "exclude"
+)
+
 // AggregationHash return a hash used as aggregation key
 func (f *Flow) AggregationHash() uint64 {
 	h := fnv.New64()
@@ -162,3 +176,13 @@ func IsEqualFlowContext(a Flow, b Flow) bool {
 	}
 	return false
 }
+
+// ReplicationEncapsulation is used to configure how received datagrams are forwarded to a replication target
+type ReplicationEncapsulation string
+
+var (
+	// ReplicationEncapsulationNone forwards the datagrams as is, the target sees the Agent as the exporter
+	ReplicationEncapsulationNone ReplicationEncapsulation = "none"
+	// ReplicationEncapsulationProxyV2 prefixes the datagrams with a PROXY protocol v2 header carrying the exporter address
+	ReplicationEncapsulationProxyV2 ReplicationEncapsulation = "proxy_v2"
+)
@@ -7,6 +7,10 @@ package common
 
 import (
 	"cmp"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
 )
 
 // Min returns the smaller of two items, for any ordered type.
@@ -24,3 +28,61 @@ func Max[T cmp.Ordered](a T, b T) T {
 	}
 	return b
 }
+
+// PortRange is an inclusive range of ports
+type PortRange struct {
+	Low  int32
+	High int32
+}
+
+// Contains returns true if the port is in the range
+func (r PortRange) Contains(port int32) bool {
+	return port >= r.Low && port <= r.High
+}
+
+// ParsePortRange parses a port (`53`) or an inclusive range of ports (`1024-65535`)
+func ParsePortRange(value string) (PortRange, error) {
+	lowStr, highStr, isRange := strings.Cut(strings.TrimSpace(value), "-")
+	low, err := parsePort(lowStr)
+	if err != nil {
+		return PortRange{}, fmt.Errorf("invalid port range `%s`: %w", value, err)
+	}
+	if !isRange {
+		return PortRange{Low: low, High: low}, nil
+	}
+	high, err := parsePort(highStr)
+	if err != nil {
+		return PortRange{}, fmt.Errorf("invalid port range `%s`: %w", value, err)
+	}
+	if high < low {
+		return PortRange{}, fmt.Errorf("invalid port range `%s`: %d is lower than %d", value, high, low)
+	}
+	return PortRange{Low: low, High: high}, nil
+}
+
+func parsePort(value string) (int32, error) {
+	port, err := strconv.ParseUint(strings.TrimSpace(value), 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	return int32(port), nil
+}
+
+// ParseIPNetwork parses a CIDR (`10.0.0.0/8`) or a single IP address (`10.0.0.1`)
+func ParseIPNetwork(value string) (*net.IPNet, error) {
+	if strings.Contains(value, "/") {
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid IP address `%s`", value)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
@@ -6,9 +6,11 @@
 package common
 
 import (
+	"net"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMaxUint64(t *testing.T) {
@@ -30,3 +32,41 @@ func TestMaxUint32(t *testing.T) {
 	assert.Equal(t, uint32(10), Max(uint32(10), uint32(5)))
 	assert.Equal(t, uint32(10), Max(uint32(5), uint32(10)))
 }
+
+func TestParsePortRange(t *testing.T) {
+	portRange, err := ParsePortRange("53")
+	require.NoError(t, err)
+	assert.Equal(t, PortRange{Low: 53, High: 53}, portRange)
+	assert.True(t, portRange.Contains(53))
+	assert.False(t, portRange.Contains(54))
+
+	portRange, err = ParsePortRange("1024-65535")
+	require.NoError(t, err)
+	assert.Equal(t, PortRange{Low: 1024, High: 65535}, portRange)
+	assert.True(t, portRange.Contains(8080))
+	assert.False(t, portRange.Contains(-1))
+
+	for _, value := range []string{"", "abc", "65536", "-1", "10-", "20-10"} {
+		_, err := ParsePortRange(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseIPNetwork(t *testing.T) {
+	network, err := ParseIPNetwork("10.0.0.0/8")
+	require.NoError(t, err)
+	assert.True(t, network.Contains(net.ParseIP("10.1.2.3")))
+
+	network, err = ParseIPNetwork("192.168.1.1")
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.1/32", network.String())
+
+	network, err = ParseIPNetwork("2001:db8::1")
+	require.NoError(t, err)
+	assert.Equal(t, "2001:db8::1/128", network.String())
+
+	_, err = ParseIPNetwork("10.0.0.0/33")
+	assert.Error(t, err)
+	_, err = ParseIPNetwork("not-an-ip")
+	assert.Error(t, err)
+}
@@ -7,7 +7,9 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
@@ -36,6 +38,19 @@ type NetflowConfig struct {
 	PrometheusListenerEnabled bool   `mapstructure:"prometheus_listener_enabled"`
 
 	ReverseDNSEnrichmentEnabled bool `mapstructure:"reverse_dns_enrichment_enabled"`
+
+	GeoIPEnrichment GeoIPEnrichmentConfig `mapstructure:"geoip_enrichment"`
+}
+
+// GeoIPEnrichmentConfig contains configuration for the GeoIP and ASN enrichment of flows
+type GeoIPEnrichmentConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// LocationDatabasePath is the path to a MaxMind-format City or Country database
+	LocationDatabasePath string `mapstructure:"location_database_path"`
+	// ASNDatabasePath is the path to a MaxMind-format ASN database
+	ASNDatabasePath string `mapstructure:"asn_database_path"`
+	// ReloadInterval is the interval in seconds at which the databases are reloaded if they changed
+	ReloadInterval int `mapstructure:"reload_interval"`
 }
 
 // ListenerConfig contains configuration for a single flow listener
@@ -46,6 +61,54 @@ type ListenerConfig struct {
 	Workers   int             `mapstructure:"workers"`
 	Namespace string          `mapstructure:"namespace"`
 	Mapping   []Mapping       `mapstructure:"mapping"`
+	Filters   []FilterRule    `mapstructure:"filters"`
+	// SamplingRateOverrides replace the sampling rate reported by exporters
+	SamplingRateOverrides []SamplingRateOverride `mapstructure:"sampling_rate_overrides"`
+	// Replication forwards the received datagrams to downstream collectors
+	Replication ReplicationConfig `mapstructure:"replication"`
+}
+
+// ReplicationConfig contains configuration for the forwarding of the datagrams received by a listener
+type ReplicationConfig struct {
+	Targets []ReplicationTarget `mapstructure:"targets"`
+	// QueueSize is the number of datagrams buffered for each target, datagrams are dropped when it is full
+	QueueSize int `mapstructure:"queue_size"`
+}
+
+// ReplicationTarget contains configuration for a downstream collector
+type ReplicationTarget struct {
+	// Address is the `host:port` UDP address of the collector
+	Address       string                          `mapstructure:"address"`
+	Encapsulation common.ReplicationEncapsulation `mapstructure:"encapsulation"`
+}
+
+// SamplingRateOverride contains configuration for the sampling rate of the flows of some exporters
+type SamplingRateOverride struct {
+	// ExporterIP is an IP address or a CIDR
+	ExporterIP   string `mapstructure:"exporter_ip"`
+	SamplingRate uint64 `mapstructure:"sampling_rate"`
+}
+
+// FilterRule contains configuration for a rule keeping or dropping flows before they are aggregated.
+// A flow matches a rule when it matches all the conditions set in the rule, and a condition
+// listing several values matches any of them. Rules are evaluated in order and the first
+// matching rule applies. Flows matching no rule are kept, unless the listener has include
+// rules, in which case they are dropped.
+type FilterRule struct {
+	Name   string              `mapstructure:"name"`
+	Action common.FilterAction `mapstructure:"action"`
+	// ExporterIPs, SourceCIDRs and DestinationCIDRs contain IP addresses or CIDRs
+	ExporterIPs      []string `mapstructure:"exporter_ips"`
+	SourceCIDRs      []string `mapstructure:"source_cidrs"`
+	DestinationCIDRs []string `mapstructure:"destination_cidrs"`
+	// SourcePorts and DestinationPorts contain ports (`53`) or port ranges (`1024-65535`)
+	SourcePorts      []string `mapstructure:"source_ports"`
+	DestinationPorts []string `mapstructure:"destination_ports"`
+	IPProtocols      []uint32 `mapstructure:"ip_protocols"`
+	InputInterfaces  []uint32 `mapstructure:"input_interfaces"`
+	OutputInterfaces []uint32 `mapstructure:"output_interfaces"`
+	// AdditionalFields maps additional fields collected with `mapping` to their expected value
+	AdditionalFields map[string]string `mapstructure:"additional_fields"`
 }
 
 // Mapping contains configuration for a Netflow/IPFIX field mapping
@@ -111,6 +174,38 @@ func (mainConfig *NetflowConfig) SetDefaults(namespace string, logger log.Compon
 				mapping.Type = fieldType
 			}
 		}
+
+		for i := range listenerConfig.Filters {
+			rule := &listenerConfig.Filters[i]
+			if rule.Name == "" {
+				rule.Name = fmt.Sprintf("rule_%d", i+1)
+			}
+			if err := rule.validate(); err != nil {
+				return fmt.Errorf("invalid filter rule `%s`: %w", rule.Name, err)
+			}
+		}
+
+		for _, override := range listenerConfig.SamplingRateOverrides {
+			if _, err := common.ParseIPNetwork(override.ExporterIP); err != nil {
+				return fmt.Errorf("invalid sampling rate override exporter_ip `%s`: %w", override.ExporterIP, err)
+			}
+			if override.SamplingRate == 0 {
+				return fmt.Errorf("invalid sampling rate override for exporter_ip `%s`: sampling_rate must be greater than 0", override.ExporterIP)
+			}
+		}
+
+		if len(listenerConfig.Replication.Targets) > 0 && listenerConfig.Replication.QueueSize == 0 {
+			listenerConfig.Replication.QueueSize = common.DefaultReplicationQueueSize
+		}
+		for i := range listenerConfig.Replication.Targets {
+			target := &listenerConfig.Replication.Targets[i]
+			if target.Encapsulation == "" {
+				target.Encapsulation = common.ReplicationEncapsulationNone
+			}
+			if err := target.validate(); err != nil {
+				return fmt.Errorf("invalid replication target `%s`: %w", target.Address, err)
+			}
+		}
 	}
 
 	if mainConfig.StopTimeout == 0 {
@@ -138,6 +233,15 @@ func (mainConfig *NetflowConfig) SetDefaults(namespace string, logger log.Compon
 		mainConfig.PrometheusListenerAddress = common.DefaultPrometheusListenerAddress
 	}
 
+	if mainConfig.GeoIPEnrichment.Enabled {
+		if mainConfig.GeoIPEnrichment.LocationDatabasePath == "" && mainConfig.GeoIPEnrichment.ASNDatabasePath == "" {
+			return errors.New("geoip enrichment is enabled but neither `location_database_path` nor `asn_database_path` is set")
+		}
+		if mainConfig.GeoIPEnrichment.ReloadInterval == 0 {
+			mainConfig.GeoIPEnrichment.ReloadInterval = common.DefaultGeoIPReloadInterval
+		}
+	}
+
 	return nil
 }
 
@@ -145,3 +249,38 @@ func (mainConfig *NetflowConfig) SetDefaults(namespace string, logger log.Compon
 func (c *ListenerConfig) Addr() string {
 	return fmt.Sprintf("%s:%d", c.BindHost, c.Port)
 }
+
+func (rule *FilterRule) validate() error {
+	if rule.Action != common.FilterInclude && rule.Action != common.FilterExclude {
+		return fmt.Errorf("the action must be `%s` or `%s`, got `%s`", common.FilterInclude, common.FilterExclude, rule.Action)
+	}
+	for _, networks := range [][]string{rule.ExporterIPs, rule.SourceCIDRs, rule.DestinationCIDRs} {
+		for _, network := range networks {
+			if _, err := common.ParseIPNetwork(network); err != nil {
+				return err
+			}
+		}
+	}
+	for _, ports := range [][]string{rule.SourcePorts, rule.DestinationPorts} {
+		for _, port := range ports {
+			if _, err := common.ParsePortRange(port); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (target *ReplicationTarget) validate() error {
+	if target.Encapsulation != common.ReplicationEncapsulationNone && target.Encapsulation != common.ReplicationEncapsulationProxyV2 {
+		return fmt.Errorf("the encapsulation must be `%s` or `%s`, got `%s`", common.ReplicationEncapsulationNone, common.ReplicationEncapsulationProxyV2, target.Encapsulation)
+	}
+	host, port, err := net.SplitHostPort(target.Address)
+	if err != nil {
+		return err
+	}
+	if host == "" || port == "" {
+		return errors.New("the address must be in the `host:port` format")
+	}
+	return nil
+}
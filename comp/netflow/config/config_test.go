@@ -213,6 +213,259 @@ network_devices:
 				ReverseDNSEnrichmentEnabled: false,
 			},
 		},
+		{
+			name: "filter rules",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+        filters:
+          - name: multicast
+            action: exclude
+            destination_cidrs: [224.0.0.0/4]
+          - action: include
+            exporter_ips: [10.0.0.1]
+            destination_ports: ["53", "1024-2048"]
+            ip_protocols: [6, 17]
+            additional_fields:
+              vlan: 10
+`,
+			expectedConfig: NetflowConfig{
+				Enabled:                                true,
+				StopTimeout:                            5,
+				AggregatorBufferSize:                   10000,
+				AggregatorFlushInterval:                300,
+				AggregatorFlowContextTTL:               300,
+				AggregatorPortRollupThreshold:          10,
+				AggregatorRollupTrackerRefreshInterval: 300,
+				PrometheusListenerAddress:              "localhost:9090",
+				Listeners: []ListenerConfig{
+					{
+						FlowType:  common.TypeNetFlow9,
+						BindHost:  "0.0.0.0",
+						Port:      uint16(2055),
+						Workers:   1,
+						Namespace: "default",
+						Filters: []FilterRule{
+							{
+								Name:             "multicast",
+								Action:           common.FilterExclude,
+								DestinationCIDRs: []string{"224.0.0.0/4"},
+							},
+							{
+								Name:             "rule_2",
+								Action:           common.FilterInclude,
+								ExporterIPs:      []string{"10.0.0.1"},
+								DestinationPorts: []string{"53", "1024-2048"},
+								IPProtocols:      []uint32{6, 17},
+								AdditionalFields: map[string]string{"vlan": "10"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid filter rule",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+        filters:
+          - name: bad-cidr
+            action: exclude
+            source_cidrs: [10.0.0.0/40]
+`,
+			expectedError: "invalid filter rule `bad-cidr`: invalid CIDR address: 10.0.0.0/40",
+		},
+		{
+			name: "invalid filter action",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+        filters:
+          - action: drop
+`,
+			expectedError: "invalid filter rule `rule_1`: the action must be `include` or `exclude`, got `drop`",
+		},
+		{
+			name: "sampling rate overrides",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: sflow5
+        sampling_rate_overrides:
+          - exporter_ip: 10.0.0.1
+            sampling_rate: 1000
+          - exporter_ip: 10.1.0.0/16
+            sampling_rate: 512
+`,
+			expectedConfig: NetflowConfig{
+				Enabled:                                true,
+				StopTimeout:                            5,
+				AggregatorBufferSize:                   10000,
+				AggregatorFlushInterval:                300,
+				AggregatorFlowContextTTL:               300,
+				AggregatorPortRollupThreshold:          10,
+				AggregatorRollupTrackerRefreshInterval: 300,
+				PrometheusListenerAddress:              "localhost:9090",
+				Listeners: []ListenerConfig{
+					{
+						FlowType:  common.TypeSFlow5,
+						BindHost:  "0.0.0.0",
+						Port:      uint16(6343),
+						Workers:   1,
+						Namespace: "default",
+						SamplingRateOverrides: []SamplingRateOverride{
+							{ExporterIP: "10.0.0.1", SamplingRate: 1000},
+							{ExporterIP: "10.1.0.0/16", SamplingRate: 512},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid sampling rate override",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: sflow5
+        sampling_rate_overrides:
+          - exporter_ip: 10.0.0.1
+`,
+			expectedError: "invalid sampling rate override for exporter_ip `10.0.0.1`: sampling_rate must be greater than 0",
+		},
+		{
+			name: "replication",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+        replication:
+          targets:
+            - address: collector-1:2055
+            - address: 10.0.0.2:4739
+              encapsulation: proxy_v2
+`,
+			expectedConfig: NetflowConfig{
+				Enabled:                                true,
+				StopTimeout:                            5,
+				AggregatorBufferSize:                   10000,
+				AggregatorFlushInterval:                300,
+				AggregatorFlowContextTTL:               300,
+				AggregatorPortRollupThreshold:          10,
+				AggregatorRollupTrackerRefreshInterval: 300,
+				PrometheusListenerAddress:              "localhost:9090",
+				Listeners: []ListenerConfig{
+					{
+						FlowType:  common.TypeNetFlow9,
+						BindHost:  "0.0.0.0",
+						Port:      uint16(2055),
+						Workers:   1,
+						Namespace: "default",
+						Replication: ReplicationConfig{
+							Targets: []ReplicationTarget{
+								{Address: "collector-1:2055", Encapsulation: common.ReplicationEncapsulationNone},
+								{Address: "10.0.0.2:4739", Encapsulation: common.ReplicationEncapsulationProxyV2},
+							},
+							QueueSize: 1000,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "invalid replication encapsulation",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+        replication:
+          targets:
+            - address: 10.0.0.2:4739
+              encapsulation: gre
+`,
+			expectedError: "invalid replication target `10.0.0.2:4739`: the encapsulation must be `none` or `proxy_v2`, got `gre`",
+		},
+		{
+			name: "invalid replication address",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+        replication:
+          targets:
+            - address: 10.0.0.2
+`,
+			expectedError: "invalid replication target `10.0.0.2`: address 10.0.0.2: missing port in address",
+		},
+		{
+			name: "geoip enrichment",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+    geoip_enrichment:
+      enabled: true
+      asn_database_path: /opt/geoip/asn.mmdb
+`,
+			expectedConfig: NetflowConfig{
+				Enabled:                                true,
+				StopTimeout:                            5,
+				AggregatorBufferSize:                   10000,
+				AggregatorFlushInterval:                300,
+				AggregatorFlowContextTTL:               300,
+				AggregatorPortRollupThreshold:          10,
+				AggregatorRollupTrackerRefreshInterval: 300,
+				PrometheusListenerAddress:              "localhost:9090",
+				Listeners: []ListenerConfig{
+					{
+						FlowType:  common.TypeNetFlow9,
+						BindHost:  "0.0.0.0",
+						Port:      uint16(2055),
+						Workers:   1,
+						Namespace: "default",
+					},
+				},
+				GeoIPEnrichment: GeoIPEnrichmentConfig{
+					Enabled:         true,
+					ASNDatabasePath: "/opt/geoip/asn.mmdb",
+					ReloadInterval:  60,
+				},
+			},
+		},
+		{
+			name: "geoip enrichment without database",
+			configYaml: `
+network_devices:
+  netflow:
+    enabled: true
+    listeners:
+      - flow_type: netflow9
+    geoip_enrichment:
+      enabled: true
+`,
+			expectedError: "geoip enrichment is enabled but neither `location_database_path` nor `asn_database_path` is set",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
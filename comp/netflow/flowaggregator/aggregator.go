@@ -19,6 +19,7 @@ import (
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	"github.com/DataDog/datadog-agent/comp/forwarder/eventplatform"
 	"github.com/DataDog/datadog-agent/comp/netflow/format"
+	"github.com/DataDog/datadog-agent/comp/netflow/payload"
 	rdnsquerier "github.com/DataDog/datadog-agent/comp/rdnsquerier/def"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/logs/message"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/netflow/common"
 	"github.com/DataDog/datadog-agent/comp/netflow/config"
+	"github.com/DataDog/datadog-agent/comp/netflow/geoip"
 	"github.com/DataDog/datadog-agent/comp/netflow/goflowlib"
 )
 
@@ -49,6 +51,7 @@ type FlowAggregator struct {
 	flushedFlowCount             *atomic.Uint64
 	hostname                     string
 	goflowPrometheusGatherer     prometheus.Gatherer
+	geoIPEnricher                *geoip.Enricher  // nil if GeoIP enrichment is disabled
 	TimeNowFunction              func() time.Time // Allows to mock time in tests
 
 	lastSequencePerExporter   map[sequenceDeltaKey]uint32
@@ -82,6 +85,18 @@ func NewFlowAggregator(sender sender.Sender, epForwarder eventplatform.Forwarder
 	flushInterval := time.Duration(config.AggregatorFlushInterval) * time.Second
 	flowContextTTL := time.Duration(config.AggregatorFlowContextTTL) * time.Second
 	rollupTrackerRefreshInterval := time.Duration(config.AggregatorRollupTrackerRefreshInterval) * time.Second
+
+	var geoIPEnricher *geoip.Enricher
+	if config.GeoIPEnrichment.Enabled {
+		enricher, err := geoip.NewEnricher(config.GeoIPEnrichment, logger)
+		if err != nil {
+			logger.Errorf("GeoIP enrichment is disabled, error loading the databases: %s", err)
+		} else {
+			logger.Infof("GeoIP enrichment is enabled for NDM NetFlow")
+			geoIPEnricher = enricher
+		}
+	}
+
 	return &FlowAggregator{
 		flowIn:                       make(chan *common.Flow, config.AggregatorBufferSize),
 		flowAcc:                      newFlowAccumulator(flushInterval, flowContextTTL, config.AggregatorPortRollupThreshold, config.AggregatorPortRollupDisabled, logger, rdnsQuerier),
@@ -96,6 +111,7 @@ func NewFlowAggregator(sender sender.Sender, epForwarder eventplatform.Forwarder
 		flushedFlowCount:             atomic.NewUint64(0),
 		hostname:                     hostname,
 		goflowPrometheusGatherer:     prometheus.DefaultGatherer,
+		geoIPEnricher:                geoIPEnricher,
 		TimeNowFunction:              time.Now,
 		lastSequencePerExporter:      make(map[sequenceDeltaKey]uint32),
 		logger:                       logger,
@@ -106,6 +122,9 @@ func NewFlowAggregator(sender sender.Sender, epForwarder eventplatform.Forwarder
 func (agg *FlowAggregator) Start() {
 	agg.logger.Info("Flow Aggregator started")
 	go agg.run()
+	if agg.geoIPEnricher != nil {
+		go agg.geoIPEnricher.Start()
+	}
 	agg.flushLoop() // blocking call
 }
 
@@ -114,6 +133,9 @@ func (agg *FlowAggregator) Stop() {
 	close(agg.stopChan)
 	<-agg.flushLoopDone
 	<-agg.runDone
+	if agg.geoIPEnricher != nil {
+		agg.geoIPEnricher.Close()
+	}
 }
 
 // GetFlowInChan returns flow input chan
@@ -138,6 +160,9 @@ func (agg *FlowAggregator) run() {
 func (agg *FlowAggregator) sendFlows(flows []*common.Flow, flushTime time.Time) {
 	for _, flow := range flows {
 		flowPayload := buildPayload(flow, agg.hostname, flushTime)
+		if agg.geoIPEnricher != nil {
+			agg.enrichWithGeoIP(flow, &flowPayload)
+		}
 
 		// Calling MarshalJSON directly as it's faster than calling json.Marshall
 		payloadBytes, err := flowPayload.MarshalJSON()
@@ -157,6 +182,13 @@ func (agg *FlowAggregator) sendFlows(flows []*common.Flow, flushTime time.Time)
 	}
 }
 
+func (agg *FlowAggregator) enrichWithGeoIP(flow *common.Flow, flowPayload *payload.FlowPayload) {
+	flowPayload.Source.GeoIP = agg.geoIPEnricher.Lookup(flow.SrcAddr)
+	flowPayload.Destination.GeoIP = agg.geoIPEnricher.Lookup(flow.DstAddr)
+	geoip.ObserveTraffic("source", flowPayload.Source.GeoIP, flow.EstimatedBytes, flow.EstimatedPackets)
+	geoip.ObserveTraffic("destination", flowPayload.Destination.GeoIP, flow.EstimatedBytes, flow.EstimatedPackets)
+}
+
 func (agg *FlowAggregator) sendExporterMetadata(flows []*common.Flow, flushTime time.Time) {
 	// exporterMap structure: map[NAMESPACE]map[EXPORTER_ID]metadata.NetflowExporter
 	exporterMap := make(map[string]map[string]metadata.NetflowExporter)
@@ -191,7 +223,7 @@ func (agg *FlowAggregator) sendExporterMetadata(flows []*common.Flow, flushTime
 		for _, exporterID := range ids {
 			netflowExporters = append(netflowExporters, exporterMap[namespace][exporterID])
 		}
-		metadataPayloads := metadata.BatchPayloads(namespace, "", flushTime, metadata.PayloadMetadataBatchSize, nil, nil, nil, nil, netflowExporters, nil)
+		metadataPayloads := metadata.BatchPayloads(namespace, "", flushTime, metadata.PayloadMetadataBatchSize, nil, nil, nil, nil, nil, netflowExporters, nil)
 		for _, payload := range metadataPayloads {
 			payloadBytes, err := json.Marshal(payload)
 			if err != nil {
@@ -110,6 +110,8 @@ func TestAggregator(t *testing.T) {
     }
   },
   "end": 1234569,
+  "estimated_bytes": 20,
+  "estimated_packets": 4,
   "ether_type": "IPv4",
   "exporter": {
     "ip": "127.0.0.1"
@@ -293,7 +295,7 @@ func TestAggregator_withMockPayload(t *testing.T) {
 	listenerErr := atomic.NewString("")
 	listenerFlowCount := atomic.NewInt64(0)
 
-	flowState, err := goflowlib.StartFlowRoutine(common.TypeNetFlow5, "127.0.0.1", port, 1, "default", nil, aggregator.GetFlowInChan(), logger, listenerErr, listenerFlowCount)
+	flowState, err := goflowlib.StartFlowRoutine(common.TypeNetFlow5, "127.0.0.1", port, 1, "default", nil, nil, nil, nil, aggregator.GetFlowInChan(), logger, listenerErr, listenerFlowCount)
 	assert.NoError(t, err)
 
 	time.Sleep(100 * time.Millisecond) // wait to make sure goflow listener is started before sending
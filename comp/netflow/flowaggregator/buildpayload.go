@@ -26,12 +26,14 @@ func buildPayload(aggFlow *common.Flow, hostname string, flushTime time.Time) pa
 		Exporter: payload.Exporter{
 			IP: format.IPAddr(aggFlow.ExporterAddr),
 		},
-		Start:      aggFlow.StartTimestamp,
-		End:        aggFlow.EndTimestamp,
-		Bytes:      aggFlow.Bytes,
-		Packets:    aggFlow.Packets,
-		EtherType:  format.EtherType(aggFlow.EtherType),
-		IPProtocol: format.IPProtocol(aggFlow.IPProtocol),
+		Start:            aggFlow.StartTimestamp,
+		End:              aggFlow.EndTimestamp,
+		Bytes:            aggFlow.Bytes,
+		Packets:          aggFlow.Packets,
+		EstimatedBytes:   aggFlow.EstimatedBytes,
+		EstimatedPackets: aggFlow.EstimatedPackets,
+		EtherType:        format.EtherType(aggFlow.EtherType),
+		IPProtocol:       format.IPProtocol(aggFlow.IPProtocol),
 		Source: payload.Endpoint{
 			IP:                 format.IPAddr(aggFlow.SrcAddr),
 			Port:               format.Port(aggFlow.SrcPort),
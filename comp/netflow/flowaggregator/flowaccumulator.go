@@ -119,6 +119,10 @@ func (f *flowAccumulator) add(flowToAdd *common.Flow) {
 		}
 	}
 
+	// upscale sampled flows, flows of different sampling rates can be aggregated together
+	flowToAdd.EstimatedBytes = estimateSampledCount(flowToAdd.Bytes, flowToAdd.SamplingRate)
+	flowToAdd.EstimatedPackets = estimateSampledCount(flowToAdd.Packets, flowToAdd.SamplingRate)
+
 	f.flowsMutex.Lock()
 	defer f.flowsMutex.Unlock()
 
@@ -140,6 +144,8 @@ func (f *flowAccumulator) add(flowToAdd *common.Flow) {
 		// accumulate flowToAdd with existing flow(s) with same hash
 		aggFlow.flow.Bytes += flowToAdd.Bytes
 		aggFlow.flow.Packets += flowToAdd.Packets
+		aggFlow.flow.EstimatedBytes += flowToAdd.EstimatedBytes
+		aggFlow.flow.EstimatedPackets += flowToAdd.EstimatedPackets
 		aggFlow.flow.StartTimestamp = common.Min(aggFlow.flow.StartTimestamp, flowToAdd.StartTimestamp)
 		aggFlow.flow.EndTimestamp = common.Max(aggFlow.flow.EndTimestamp, flowToAdd.EndTimestamp)
 		aggFlow.flow.SequenceNum = common.Max(aggFlow.flow.SequenceNum, flowToAdd.SequenceNum)
@@ -161,6 +167,15 @@ func (f *flowAccumulator) add(flowToAdd *common.Flow) {
 	f.flows[aggHash] = aggFlow
 }
 
+// estimateSampledCount returns the number of bytes or packets represented by a sampled count,
+// flows without sampling rate are considered unsampled
+func estimateSampledCount(count uint64, samplingRate uint64) uint64 {
+	if samplingRate == 0 {
+		return count
+	}
+	return count * samplingRate
+}
+
 func (f *flowAccumulator) setSrcReverseDNSHostname(aggHash uint64, hostname string, acquireLock bool) {
 	if hostname == "" {
 		return
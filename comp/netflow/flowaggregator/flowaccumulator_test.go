@@ -115,6 +115,43 @@ func Test_flowAccumulator_add(t *testing.T) {
 	assert.Equal(t, []byte{10, 10, 10, 30}, wrappedFlowB.flow.DstAddr)
 }
 
+func Test_flowAccumulator_addSampledFlows(t *testing.T) {
+	logger := logmock.New(t)
+	rdnsQuerier := fxutil.Test[rdnsquerier.Component](t, rdnsquerierfxmock.MockModule())
+
+	newFlow := func(bytes uint64, packets uint64, samplingRate uint64) *common.Flow {
+		return &common.Flow{
+			FlowType:     common.TypeNetFlow9,
+			ExporterAddr: []byte{127, 0, 0, 1},
+			SamplingRate: samplingRate,
+			Bytes:        bytes,
+			Packets:      packets,
+			SrcAddr:      []byte{10, 10, 10, 10},
+			DstAddr:      []byte{10, 10, 10, 20},
+			IPProtocol:   uint32(6),
+			SrcPort:      2000,
+			DstPort:      80,
+		}
+	}
+	flowA1 := newFlow(1500, 1, 1000)
+	// the sampling rate of an exporter can change between flows
+	flowA2 := newFlow(3000, 2, 100)
+	// flows without sampling rate are not sampled
+	flowA3 := newFlow(40, 1, 0)
+
+	acc := newFlowAccumulator(common.DefaultAggregatorFlushInterval, common.DefaultAggregatorFlushInterval, common.DefaultAggregatorPortRollupThreshold, false, logger, rdnsQuerier)
+	acc.add(flowA1)
+	acc.add(flowA2)
+	acc.add(flowA3)
+
+	assert.Equal(t, 1, len(acc.flows))
+	wrappedFlowA := acc.flows[flowA1.AggregationHash()]
+	assert.Equal(t, uint64(4540), wrappedFlowA.flow.Bytes)
+	assert.Equal(t, uint64(4), wrappedFlowA.flow.Packets)
+	assert.Equal(t, uint64(1500*1000+3000*100+40), wrappedFlowA.flow.EstimatedBytes)
+	assert.Equal(t, uint64(1*1000+2*100+1), wrappedFlowA.flow.EstimatedPackets)
+}
+
 func Test_flowAccumulator_portRollUp(t *testing.T) {
 	logger := logmock.New(t)
 	rdnsQuerier := fxutil.Test[rdnsquerier.Component](t, rdnsquerierfxmock.MockModule())
@@ -0,0 +1,215 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package flowfilter keeps or drops flows according to the filter rules of a listener,
+// before they are aggregated.
+package flowfilter
+
+import (
+	"fmt"
+	"net"
+	"slices"
+
+	"go.uber.org/atomic"
+
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+)
+
+// NoIncludeRuleMatched is the name under which are counted the flows dropped
+// because they didn't match any include rule
+const NoIncludeRuleMatched = "no include rule matched"
+
+// RuleDropCount is the number of flows dropped by a rule
+type RuleDropCount struct {
+	Rule    string
+	Dropped int64
+}
+
+// Filter evaluates the filter rules of a listener
+type Filter struct {
+	rules           []*rule
+	hasIncludeRules bool
+	defaultDrops    *atomic.Int64
+}
+
+type rule struct {
+	name             string
+	action           common.FilterAction
+	exporterIPs      []*net.IPNet
+	sourceCIDRs      []*net.IPNet
+	destinationCIDRs []*net.IPNet
+	sourcePorts      []common.PortRange
+	destinationPorts []common.PortRange
+	ipProtocols      []uint32
+	inputInterfaces  []uint32
+	outputInterfaces []uint32
+	additionalFields map[string]string
+	drops            *atomic.Int64
+}
+
+// New compiles the filter rules, it returns nil if there are no rules
+func New(rules []config.FilterRule) (*Filter, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	filter := &Filter{
+		defaultDrops: atomic.NewInt64(0),
+	}
+	for _, ruleConfig := range rules {
+		r, err := newRule(ruleConfig)
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter rule `%s`: %w", ruleConfig.Name, err)
+		}
+		if r.action == common.FilterInclude {
+			filter.hasIncludeRules = true
+		}
+		filter.rules = append(filter.rules, r)
+	}
+	return filter, nil
+}
+
+func newRule(ruleConfig config.FilterRule) (*rule, error) {
+	r := &rule{
+		name:             ruleConfig.Name,
+		action:           ruleConfig.Action,
+		ipProtocols:      ruleConfig.IPProtocols,
+		inputInterfaces:  ruleConfig.InputInterfaces,
+		outputInterfaces: ruleConfig.OutputInterfaces,
+		additionalFields: ruleConfig.AdditionalFields,
+		drops:            atomic.NewInt64(0),
+	}
+	if r.action != common.FilterInclude && r.action != common.FilterExclude {
+		return nil, fmt.Errorf("unknown action `%s`", r.action)
+	}
+
+	var err error
+	if r.exporterIPs, err = parseNetworks(ruleConfig.ExporterIPs); err != nil {
+		return nil, err
+	}
+	if r.sourceCIDRs, err = parseNetworks(ruleConfig.SourceCIDRs); err != nil {
+		return nil, err
+	}
+	if r.destinationCIDRs, err = parseNetworks(ruleConfig.DestinationCIDRs); err != nil {
+		return nil, err
+	}
+	if r.sourcePorts, err = parsePortRanges(ruleConfig.SourcePorts); err != nil {
+		return nil, err
+	}
+	if r.destinationPorts, err = parsePortRanges(ruleConfig.DestinationPorts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func parseNetworks(values []string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, value := range values {
+		network, err := common.ParseIPNetwork(value)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+func parsePortRanges(values []string) ([]common.PortRange, error) {
+	var portRanges []common.PortRange
+	for _, value := range values {
+		portRange, err := common.ParsePortRange(value)
+		if err != nil {
+			return nil, err
+		}
+		portRanges = append(portRanges, portRange)
+	}
+	return portRanges, nil
+}
+
+// Keep returns true if the flow must be aggregated, and counts the dropped flows
+func (f *Filter) Keep(flow *common.Flow) bool {
+	for _, r := range f.rules {
+		if !r.matches(flow) {
+			continue
+		}
+		if r.action == common.FilterExclude {
+			r.drops.Inc()
+			return false
+		}
+		return true
+	}
+	if f.hasIncludeRules {
+		f.defaultDrops.Inc()
+		return false
+	}
+	return true
+}
+
+// DropCounts returns the number of flows dropped by each rule
+func (f *Filter) DropCounts() []RuleDropCount {
+	var counts []RuleDropCount
+	for _, r := range f.rules {
+		if r.action == common.FilterExclude {
+			counts = append(counts, RuleDropCount{Rule: r.name, Dropped: r.drops.Load()})
+		}
+	}
+	if f.hasIncludeRules {
+		counts = append(counts, RuleDropCount{Rule: NoIncludeRuleMatched, Dropped: f.defaultDrops.Load()})
+	}
+	return counts
+}
+
+func (r *rule) matches(flow *common.Flow) bool {
+	if len(r.exporterIPs) > 0 && !containsIP(r.exporterIPs, flow.ExporterAddr) {
+		return false
+	}
+	if len(r.sourceCIDRs) > 0 && !containsIP(r.sourceCIDRs, flow.SrcAddr) {
+		return false
+	}
+	if len(r.destinationCIDRs) > 0 && !containsIP(r.destinationCIDRs, flow.DstAddr) {
+		return false
+	}
+	if len(r.sourcePorts) > 0 && !containsPort(r.sourcePorts, flow.SrcPort) {
+		return false
+	}
+	if len(r.destinationPorts) > 0 && !containsPort(r.destinationPorts, flow.DstPort) {
+		return false
+	}
+	if len(r.ipProtocols) > 0 && !slices.Contains(r.ipProtocols, flow.IPProtocol) {
+		return false
+	}
+	if len(r.inputInterfaces) > 0 && !slices.Contains(r.inputInterfaces, flow.InputInterface) {
+		return false
+	}
+	if len(r.outputInterfaces) > 0 && !slices.Contains(r.outputInterfaces, flow.OutputInterface) {
+		return false
+	}
+	for field, expected := range r.additionalFields {
+		value, ok := flow.AdditionalFields[field]
+		if !ok || fmt.Sprint(value) != expected {
+			return false
+		}
+	}
+	return true
+}
+
+func containsIP(networks []*net.IPNet, addr []byte) bool {
+	ip := net.IP(addr)
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsPort(portRanges []common.PortRange, port int32) bool {
+	for _, portRange := range portRanges {
+		if portRange.Contains(port) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package flowfilter
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+)
+
+func newFlow(exporter string, src string, srcPort int32, dst string, dstPort int32) *common.Flow {
+	return &common.Flow{
+		ExporterAddr: net.ParseIP(exporter).To4(),
+		SrcAddr:      net.ParseIP(src).To4(),
+		SrcPort:      srcPort,
+		DstAddr:      net.ParseIP(dst).To4(),
+		DstPort:      dstPort,
+		IPProtocol:   6,
+	}
+}
+
+func TestNewWithoutRules(t *testing.T) {
+	filter, err := New(nil)
+	require.NoError(t, err)
+	assert.Nil(t, filter)
+}
+
+func TestNewInvalidRules(t *testing.T) {
+	for _, rule := range []config.FilterRule{
+		{Name: "action", Action: "drop"},
+		{Name: "cidr", Action: common.FilterExclude, SourceCIDRs: []string{"10.0.0.0/99"}},
+		{Name: "exporter", Action: common.FilterExclude, ExporterIPs: []string{"exporter"}},
+		{Name: "port", Action: common.FilterExclude, DestinationPorts: []string{"80-70"}},
+	} {
+		_, err := New([]config.FilterRule{rule})
+		assert.ErrorContains(t, err, "invalid filter rule `"+rule.Name+"`")
+	}
+}
+
+func TestExcludeRules(t *testing.T) {
+	filter, err := New([]config.FilterRule{
+		{Name: "intra-vpc", Action: common.FilterExclude, SourceCIDRs: []string{"10.0.0.0/8"}, DestinationCIDRs: []string{"10.0.0.0/8"}},
+		{Name: "multicast", Action: common.FilterExclude, DestinationCIDRs: []string{"224.0.0.0/4", "ff00::/8"}},
+		{Name: "lab-exporter", Action: common.FilterExclude, ExporterIPs: []string{"192.168.0.10"}},
+		{Name: "ephemeral", Action: common.FilterExclude, SourcePorts: []string{"49152-65535"}, DestinationPorts: []string{"49152-65535"}},
+		{Name: "udp-uplink", Action: common.FilterExclude, IPProtocols: []uint32{17}, OutputInterfaces: []uint32{3}},
+	})
+	require.NoError(t, err)
+
+	// intra-vpc
+	assert.False(t, filter.Keep(newFlow("192.168.0.1", "10.0.0.1", 1000, "10.1.0.1", 443)))
+	assert.True(t, filter.Keep(newFlow("192.168.0.1", "10.0.0.1", 1000, "8.8.8.8", 443)))
+	// multicast
+	assert.False(t, filter.Keep(newFlow("192.168.0.1", "172.16.0.1", 1000, "239.255.255.250", 1900)))
+	assert.False(t, filter.Keep(&common.Flow{SrcAddr: net.ParseIP("2001:db8::1"), DstAddr: net.ParseIP("ff02::1")}))
+	// exporter
+	assert.False(t, filter.Keep(newFlow("192.168.0.10", "172.16.0.1", 1000, "8.8.8.8", 443)))
+	// ports
+	assert.False(t, filter.Keep(newFlow("192.168.0.1", "172.16.0.1", 50000, "8.8.8.8", 60000)))
+	assert.True(t, filter.Keep(newFlow("192.168.0.1", "172.16.0.1", 50000, "8.8.8.8", 443)))
+	// protocol and interface
+	udpFlow := newFlow("192.168.0.1", "172.16.0.1", 1000, "8.8.8.8", 53)
+	udpFlow.IPProtocol = 17
+	assert.True(t, filter.Keep(udpFlow))
+	udpFlow.OutputInterface = 3
+	assert.False(t, filter.Keep(udpFlow))
+
+	assert.Equal(t, []RuleDropCount{
+		{Rule: "intra-vpc", Dropped: 1},
+		{Rule: "multicast", Dropped: 2},
+		{Rule: "lab-exporter", Dropped: 1},
+		{Rule: "ephemeral", Dropped: 1},
+		{Rule: "udp-uplink", Dropped: 1},
+	}, filter.DropCounts())
+}
+
+func TestIncludeRules(t *testing.T) {
+	filter, err := New([]config.FilterRule{
+		{Name: "not-dns-server", Action: common.FilterExclude, ExporterIPs: []string{"192.168.0.10"}},
+		{Name: "dns", Action: common.FilterInclude, DestinationPorts: []string{"53"}},
+		{Name: "vlan", Action: common.FilterInclude, InputInterfaces: []uint32{1, 2}, AdditionalFields: map[string]string{"vlan": "10"}},
+	})
+	require.NoError(t, err)
+
+	// the first matching rule applies
+	assert.False(t, filter.Keep(newFlow("192.168.0.10", "172.16.0.1", 1000, "8.8.8.8", 53)))
+	assert.True(t, filter.Keep(newFlow("192.168.0.1", "172.16.0.1", 1000, "8.8.8.8", 53)))
+
+	vlanFlow := newFlow("192.168.0.1", "172.16.0.1", 1000, "8.8.8.8", 443)
+	vlanFlow.InputInterface = 2
+	assert.False(t, filter.Keep(vlanFlow))
+	vlanFlow.AdditionalFields = common.AdditionalFields{"vlan": uint64(10)}
+	assert.True(t, filter.Keep(vlanFlow))
+	vlanFlow.AdditionalFields = common.AdditionalFields{"vlan": uint64(20)}
+	assert.False(t, filter.Keep(vlanFlow))
+
+	assert.Equal(t, []RuleDropCount{
+		{Rule: "not-dns-server", Dropped: 1},
+		{Rule: NoIncludeRuleMatched, Dropped: 2},
+	}, filter.DropCounts())
+}
@@ -0,0 +1,305 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package geoip enriches flows with the location and the autonomous system
+// of their endpoints, from local MaxMind-format (.mmdb) databases.
+package geoip
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/prometheus/client_golang/prometheus"
+
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+	"github.com/DataDog/datadog-agent/comp/netflow/payload"
+)
+
+// Prometheus metrics exposed on the NetFlow Prometheus listener, to break
+// down the traffic by country and autonomous system.
+var (
+	flowBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "flow_geoip_bytes",
+		Help: "Bytes of the flushed flows by endpoint country and autonomous system",
+	}, []string{"endpoint", "country", "asn"})
+	flowPackets = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "flow_geoip_packets",
+		Help: "Packets of the flushed flows by endpoint country and autonomous system",
+	}, []string{"endpoint", "country", "asn"})
+)
+
+// maxASNLabels caps the number of autonomous systems with their own label in
+// the Prometheus metrics, the traffic of the other ones is counted as "other".
+const maxASNLabels = 100
+
+// asnLabels holds the autonomous systems which have their own label
+var asnLabels = struct {
+	sync.Mutex
+	values map[uint32]string
+}{values: make(map[uint32]string)}
+
+func init() {
+	prometheus.MustRegister(flowBytes, flowPackets)
+}
+
+// locationRecord is the subset of the City and Country databases we use
+type locationRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+// asnRecord is the subset of the ASN database we use
+type asnRecord struct {
+	ASN            uint32 `maxminddb:"autonomous_system_number"`
+	ASOrganization string `maxminddb:"autonomous_system_organization"`
+}
+
+// Enricher looks up the endpoints of flows in GeoIP databases
+type Enricher struct {
+	locationDB     *database
+	asnDB          *database
+	reloadInterval time.Duration
+	stopChan       chan struct{}
+	stopOnce       sync.Once
+	logger         log.Component
+}
+
+// NewEnricher opens the configured databases
+func NewEnricher(conf config.GeoIPEnrichmentConfig, logger log.Component) (*Enricher, error) {
+	e := &Enricher{
+		reloadInterval: time.Duration(conf.ReloadInterval) * time.Second,
+		stopChan:       make(chan struct{}),
+		logger:         logger,
+	}
+	if conf.LocationDatabasePath != "" {
+		db, err := openDatabase(conf.LocationDatabasePath)
+		if err != nil {
+			return nil, err
+		}
+		e.locationDB = db
+	}
+	if conf.ASNDatabasePath != "" {
+		db, err := openDatabase(conf.ASNDatabasePath)
+		if err != nil {
+			e.Close()
+			return nil, err
+		}
+		e.asnDB = db
+	}
+	return e, nil
+}
+
+// Start reloads the databases when they change, until Stop is called
+func (e *Enricher) Start() {
+	if e.reloadInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(e.reloadInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-e.stopChan:
+			return
+		case <-ticker.C:
+			e.reloadIfChanged()
+		}
+	}
+}
+
+// Stop stops reloading the databases
+func (e *Enricher) Stop() {
+	e.stopOnce.Do(func() {
+		close(e.stopChan)
+	})
+}
+
+// Close stops reloading and closes the databases
+func (e *Enricher) Close() {
+	e.Stop()
+	for _, db := range e.databases() {
+		db.close()
+	}
+}
+
+func (e *Enricher) databases() []*database {
+	var dbs []*database
+	if e.locationDB != nil {
+		dbs = append(dbs, e.locationDB)
+	}
+	if e.asnDB != nil {
+		dbs = append(dbs, e.asnDB)
+	}
+	return dbs
+}
+
+func (e *Enricher) reloadIfChanged() {
+	for _, db := range e.databases() {
+		reloaded, err := db.reloadIfChanged()
+		if err != nil {
+			// keep using the previous version of the database
+			e.logger.Warnf("Error reloading GeoIP database `%s`: %s", db.path, err)
+			continue
+		}
+		if reloaded {
+			e.logger.Infof("GeoIP database `%s` reloaded", db.path)
+		}
+	}
+}
+
+// Lookup returns the location and autonomous system of an IP address, or nil
+// if it's not in the databases.
+func (e *Enricher) Lookup(ipAddr []byte) *payload.GeoIP {
+	ip := net.IP(ipAddr)
+	if len(ip) != net.IPv4len && len(ip) != net.IPv6len {
+		return nil
+	}
+
+	var result payload.GeoIP
+	found := false
+	if e.locationDB != nil {
+		var record locationRecord
+		ok, err := e.locationDB.lookup(ip, &record)
+		if err != nil {
+			e.logger.Tracef("GeoIP location lookup failed for %s: %s", ip, err)
+		} else if ok {
+			found = true
+			result.CountryISOCode = record.Country.ISOCode
+			result.City = record.City.Names["en"]
+		}
+	}
+	if e.asnDB != nil {
+		var record asnRecord
+		ok, err := e.asnDB.lookup(ip, &record)
+		if err != nil {
+			e.logger.Tracef("GeoIP ASN lookup failed for %s: %s", ip, err)
+		} else if ok {
+			found = true
+			result.ASN = record.ASN
+			result.ASOrganization = record.ASOrganization
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &result
+}
+
+// ObserveTraffic counts the traffic of an endpoint in the Prometheus metrics
+func ObserveTraffic(endpoint string, geoIP *payload.GeoIP, bytes uint64, packets uint64) {
+	country, asn := "unknown", "unknown"
+	if geoIP != nil {
+		if geoIP.CountryISOCode != "" {
+			country = geoIP.CountryISOCode
+		}
+		if geoIP.ASN != 0 {
+			asn = asnLabel(geoIP.ASN)
+		}
+	}
+	flowBytes.WithLabelValues(endpoint, country, asn).Add(float64(bytes))
+	flowPackets.WithLabelValues(endpoint, country, asn).Add(float64(packets))
+}
+
+// asnLabel returns the label of an autonomous system, the first maxASNLabels
+// autonomous systems seen have their own label as the series never expire.
+func asnLabel(asn uint32) string {
+	asnLabels.Lock()
+	defer asnLabels.Unlock()
+	if label, ok := asnLabels.values[asn]; ok {
+		return label
+	}
+	if len(asnLabels.values) >= maxASNLabels {
+		return "other"
+	}
+	label := strconv.FormatUint(uint64(asn), 10)
+	asnLabels.values[asn] = label
+	return label
+}
+
+// database is a MaxMind-format database, reloaded when the file changes
+type database struct {
+	path string
+
+	mu      sync.RWMutex
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+func openDatabase(path string) (*database, error) {
+	db := &database{path: path}
+	if _, err := db.reloadIfChanged(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func (db *database) lookup(ip net.IP, result any) (bool, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.reader == nil {
+		return false, nil
+	}
+	_, ok, err := db.reader.LookupNetwork(ip, result)
+	return ok, err
+}
+
+func (db *database) reloadIfChanged() (bool, error) {
+	info, err := os.Stat(db.path)
+	if err != nil {
+		return false, err
+	}
+
+	db.mu.RLock()
+	unchanged := db.reader != nil && info.ModTime().Equal(db.modTime) && info.Size() == db.size
+	db.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+
+	// the database is read in memory rather than mapped, so that it can be
+	// overwritten in place while being used
+	content, err := os.ReadFile(db.path)
+	if err != nil {
+		return false, err
+	}
+	reader, err := maxminddb.FromBytes(content)
+	if err != nil {
+		return false, err
+	}
+	if err := reader.Verify(); err != nil {
+		reader.Close()
+		return false, err
+	}
+
+	db.mu.Lock()
+	previous := db.reader
+	db.reader = reader
+	db.modTime = info.ModTime()
+	db.size = info.Size()
+	db.mu.Unlock()
+
+	if previous != nil {
+		previous.Close()
+	}
+	return true, nil
+}
+
+func (db *database) close() {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.reader != nil {
+		db.reader.Close()
+		db.reader = nil
+	}
+}
@@ -0,0 +1,296 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package geoip
+
+import (
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+	"github.com/DataDog/datadog-agent/comp/netflow/payload"
+)
+
+// mmdbEntry maps an IPv4 network to the record returned for its addresses
+type mmdbEntry struct {
+	network string
+	record  []any
+}
+
+// writeMMDB writes a minimal IPv4 MaxMind database (24 bits records). Records
+// are lists of alternating keys and values, values being strings, uint32 or
+// nested records.
+func writeMMDB(t *testing.T, path string, entries ...mmdbEntry) {
+	t.Helper()
+
+	type node struct {
+		children [2]*node
+		data     [2]int
+	}
+	newNode := func() *node { return &node{data: [2]int{-1, -1}} }
+
+	root := newNode()
+	var dataSection []byte
+	for _, entry := range entries {
+		_, network, err := net.ParseCIDR(entry.network)
+		require.NoError(t, err)
+		ones, _ := network.Mask.Size()
+		require.Positive(t, ones)
+
+		offset := len(dataSection)
+		dataSection = append(dataSection, encodeMMDBMap(entry.record)...)
+
+		current := root
+		ip := network.IP.To4()
+		for i := 0; i < ones; i++ {
+			bit := (ip[i/8] >> (7 - i%8)) & 1
+			if i == ones-1 {
+				current.data[bit] = offset
+				break
+			}
+			if current.children[bit] == nil {
+				current.children[bit] = newNode()
+			}
+			current = current.children[bit]
+		}
+	}
+
+	// number the nodes breadth first, the root being 0
+	var nodes []*node
+	ids := map[*node]int{}
+	for queue := []*node{root}; len(queue) > 0; queue = queue[1:] {
+		ids[queue[0]] = len(nodes)
+		nodes = append(nodes, queue[0])
+		for _, child := range queue[0].children {
+			if child != nil {
+				queue = append(queue, child)
+			}
+		}
+	}
+	nodeCount := len(nodes)
+
+	var buf []byte
+	for _, n := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			value := nodeCount
+			if n.children[bit] != nil {
+				value = ids[n.children[bit]]
+			} else if n.data[bit] >= 0 {
+				value = nodeCount + 16 + n.data[bit]
+			}
+			buf = append(buf, byte(value>>16), byte(value>>8), byte(value))
+		}
+	}
+	buf = append(buf, make([]byte, 16)...)
+	buf = append(buf, dataSection...)
+	buf = append(buf, "\xAB\xCD\xEFMaxMind.com"...)
+	buf = append(buf, encodeMMDBMap([]any{
+		"binary_format_major_version", uint16(2),
+		"binary_format_minor_version", uint16(0),
+		"build_epoch", uint64(time.Now().Unix()),
+		"database_type", "Test",
+		"description", []any{"en", "Test database"},
+		"ip_version", uint16(4),
+		"languages", []string{"en"},
+		"node_count", uint32(nodeCount),
+		"record_size", uint16(24),
+	})...)
+
+	require.NoError(t, os.WriteFile(path, buf, 0o644))
+}
+
+func encodeMMDBControl(typ byte, size int) []byte {
+	var control []byte
+	if typ < 8 {
+		control = []byte{typ << 5}
+	} else {
+		control = []byte{0, typ - 7}
+	}
+	if size < 29 {
+		control[0] |= byte(size)
+		return control
+	}
+	control[0] |= 29
+	// sizes of the test records are small enough for a single extra byte
+	return append(control[:1], append([]byte{byte(size - 29)}, control[1:]...)...)
+}
+
+func encodeMMDBValue(value any) []byte {
+	switch v := value.(type) {
+	case string:
+		return append(encodeMMDBControl(2, len(v)), v...)
+	case uint16:
+		return encodeMMDBUint(5, uint64(v))
+	case uint32:
+		return encodeMMDBUint(6, uint64(v))
+	case uint64:
+		return encodeMMDBUint(9, v)
+	case []string:
+		buf := encodeMMDBControl(11, len(v))
+		for _, s := range v {
+			buf = append(buf, encodeMMDBValue(s)...)
+		}
+		return buf
+	case []any:
+		return encodeMMDBMap(v)
+	}
+	panic("unsupported type")
+}
+
+func encodeMMDBUint(typ byte, value uint64) []byte {
+	var raw [8]byte
+	binary.BigEndian.PutUint64(raw[:], value)
+	trimmed := raw[:]
+	for len(trimmed) > 0 && trimmed[0] == 0 {
+		trimmed = trimmed[1:]
+	}
+	return append(encodeMMDBControl(typ, len(trimmed)), trimmed...)
+}
+
+func encodeMMDBMap(keyValues []any) []byte {
+	buf := encodeMMDBControl(7, len(keyValues)/2)
+	for _, kv := range keyValues {
+		buf = append(buf, encodeMMDBValue(kv)...)
+	}
+	return buf
+}
+
+func locationRecordFor(country string, city string) []any {
+	return []any{
+		"city", []any{"names", []any{"en", city}},
+		"country", []any{"iso_code", country},
+	}
+}
+
+func asnRecordFor(asn uint32, org string) []any {
+	return []any{
+		"autonomous_system_number", asn,
+		"autonomous_system_organization", org,
+	}
+}
+
+func newTestEnricher(t *testing.T, conf config.GeoIPEnrichmentConfig) *Enricher {
+	t.Helper()
+	enricher, err := NewEnricher(conf, logmock.New(t))
+	require.NoError(t, err)
+	t.Cleanup(enricher.Close)
+	return enricher
+}
+
+func TestEnricherLookup(t *testing.T) {
+	dir := t.TempDir()
+	locationPath := filepath.Join(dir, "city.mmdb")
+	asnPath := filepath.Join(dir, "asn.mmdb")
+	writeMMDB(t, locationPath,
+		mmdbEntry{"8.8.8.0/24", locationRecordFor("US", "Mountain View")},
+		mmdbEntry{"81.0.0.0/8", locationRecordFor("FR", "Paris")},
+	)
+	writeMMDB(t, asnPath,
+		mmdbEntry{"8.8.0.0/16", asnRecordFor(15169, "GOOGLE")},
+	)
+
+	enricher := newTestEnricher(t, config.GeoIPEnrichmentConfig{
+		Enabled:              true,
+		LocationDatabasePath: locationPath,
+		ASNDatabasePath:      asnPath,
+	})
+
+	assert.Equal(t, &payload.GeoIP{
+		CountryISOCode: "US",
+		City:           "Mountain View",
+		ASN:            15169,
+		ASOrganization: "GOOGLE",
+	}, enricher.Lookup(net.ParseIP("8.8.8.8").To4()))
+	assert.Equal(t, &payload.GeoIP{
+		ASN:            15169,
+		ASOrganization: "GOOGLE",
+	}, enricher.Lookup(net.ParseIP("8.8.4.4").To4()))
+	assert.Equal(t, &payload.GeoIP{
+		CountryISOCode: "FR",
+		City:           "Paris",
+	}, enricher.Lookup(net.ParseIP("81.2.3.4").To4()))
+
+	// unknown addresses and invalid addresses are not enriched
+	assert.Nil(t, enricher.Lookup(net.ParseIP("10.0.0.1").To4()))
+	assert.Nil(t, enricher.Lookup([]byte{1, 2}))
+}
+
+func TestEnricherReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "asn.mmdb")
+	writeMMDB(t, path, mmdbEntry{"8.8.0.0/16", asnRecordFor(15169, "GOOGLE")})
+
+	enricher := newTestEnricher(t, config.GeoIPEnrichmentConfig{
+		Enabled:         true,
+		ASNDatabasePath: path,
+	})
+	ip := net.ParseIP("1.1.1.1").To4()
+	assert.Nil(t, enricher.Lookup(ip))
+
+	writeMMDB(t, path,
+		mmdbEntry{"8.8.0.0/16", asnRecordFor(15169, "GOOGLE")},
+		mmdbEntry{"1.1.1.0/24", asnRecordFor(13335, "CLOUDFLARENET")},
+	)
+	enricher.reloadIfChanged()
+	assert.Equal(t, &payload.GeoIP{ASN: 13335, ASOrganization: "CLOUDFLARENET"}, enricher.Lookup(ip))
+
+	// an invalid database doesn't replace the current one
+	require.NoError(t, os.WriteFile(path, []byte("not a database"), 0o644))
+	enricher.reloadIfChanged()
+	assert.Equal(t, &payload.GeoIP{ASN: 13335, ASOrganization: "CLOUDFLARENET"}, enricher.Lookup(ip))
+}
+
+func TestNewEnricherInvalidDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "city.mmdb")
+	require.NoError(t, os.WriteFile(path, []byte("not a database"), 0o644))
+
+	_, err := NewEnricher(config.GeoIPEnrichmentConfig{Enabled: true, LocationDatabasePath: path}, logmock.New(t))
+	assert.Error(t, err)
+
+	_, err = NewEnricher(config.GeoIPEnrichmentConfig{Enabled: true, ASNDatabasePath: filepath.Join(t.TempDir(), "missing.mmdb")}, logmock.New(t))
+	assert.Error(t, err)
+}
+
+func TestObserveTraffic(t *testing.T) {
+	ObserveTraffic("source", &payload.GeoIP{CountryISOCode: "US", ASN: 15169}, 100, 2)
+	ObserveTraffic("source", &payload.GeoIP{CountryISOCode: "US", ASN: 15169}, 50, 1)
+	ObserveTraffic("destination", nil, 10, 1)
+
+	assert.Equal(t, float64(150), testutil.ToFloat64(flowBytes.WithLabelValues("source", "US", "15169")))
+	assert.Equal(t, float64(3), testutil.ToFloat64(flowPackets.WithLabelValues("source", "US", "15169")))
+	assert.Equal(t, float64(10), testutil.ToFloat64(flowBytes.WithLabelValues("destination", "unknown", "unknown")))
+}
+
+func resetASNLabels() {
+	asnLabels.Lock()
+	asnLabels.values = make(map[uint32]string)
+	asnLabels.Unlock()
+}
+
+func TestObserveTrafficASNCap(t *testing.T) {
+	resetASNLabels()
+	t.Cleanup(resetASNLabels)
+
+	for asn := uint32(1); asn <= maxASNLabels; asn++ {
+		ObserveTraffic("source", &payload.GeoIP{CountryISOCode: "FR", ASN: asn}, 1, 1)
+	}
+	ObserveTraffic("source", &payload.GeoIP{CountryISOCode: "FR", ASN: 64512}, 20, 2)
+	ObserveTraffic("source", &payload.GeoIP{CountryISOCode: "FR", ASN: 64513}, 30, 3)
+	ObserveTraffic("source", &payload.GeoIP{CountryISOCode: "FR", ASN: 1}, 5, 1)
+
+	// The autonomous systems seen first keep their label, the other ones share the same series
+	assert.Equal(t, float64(6), testutil.ToFloat64(flowBytes.WithLabelValues("source", "FR", "1")))
+	assert.Equal(t, float64(50), testutil.ToFloat64(flowBytes.WithLabelValues("source", "FR", "other")))
+	assert.Equal(t, float64(5), testutil.ToFloat64(flowPackets.WithLabelValues("source", "FR", "other")))
+	assert.Len(t, asnLabels.values, maxASNLabels)
+}
@@ -10,7 +10,9 @@ import (
 	"fmt"
 
 	"github.com/DataDog/datadog-agent/comp/netflow/config"
+	"github.com/DataDog/datadog-agent/comp/netflow/flowfilter"
 	"github.com/DataDog/datadog-agent/comp/netflow/goflowlib/netflowstate"
+	"github.com/DataDog/datadog-agent/comp/netflow/replicator"
 
 	"github.com/netsampler/goflow2/decoders/netflow/templates"
 	"go.uber.org/atomic"
@@ -51,13 +53,16 @@ func StartFlowRoutine(
 	workers int,
 	namespace string,
 	fieldMappings []config.Mapping,
+	filter *flowfilter.Filter,
+	samplingRates *SamplingRateOverrides,
+	replicator *replicator.Replicator,
 	flowInChan chan *common.Flow,
 	logger log.Component,
 	atomicErr *atomic.String,
 	listenerFlowCount *atomic.Int64) (*FlowStateWrapper, error) {
 	var flowState FlowRunnableState
 
-	formatDriver := NewAggregatorFormatDriver(flowInChan, namespace, listenerFlowCount)
+	formatDriver := NewAggregatorFormatDriver(flowInChan, namespace, listenerFlowCount, filter, samplingRates)
 	logrusLogger := GetLogrusLevel(logger)
 	ctx := context.Background()
 
@@ -74,16 +79,25 @@ func StartFlowRoutine(
 		state.Logger = logrusLogger
 		state.TemplateSystem = templateSystem
 		flowState = state
+		if replicator != nil {
+			flowState = newReplicatingState("NetFlow", state.DecodeFlow, replicator, logrusLogger)
+		}
 	case common.TypeSFlow5:
 		state := utils.NewStateSFlow()
 		state.Format = formatDriver
 		state.Logger = logrusLogger
 		flowState = state
+		if replicator != nil {
+			flowState = newReplicatingState("sFlow", state.DecodeFlow, replicator, logrusLogger)
+		}
 	case common.TypeNetFlow5:
 		state := utils.NewStateNFLegacy()
 		state.Format = formatDriver
 		state.Logger = logrusLogger
 		flowState = state
+		if replicator != nil {
+			flowState = newReplicatingState("NetFlowV5", state.DecodeFlow, replicator, logrusLogger)
+		}
 	default:
 		return nil, fmt.Errorf("unknown flow type: %s", flowType)
 	}
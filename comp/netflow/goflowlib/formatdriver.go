@@ -11,6 +11,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/flowfilter"
 	flowpb "github.com/netsampler/goflow2/pb"
 )
 
@@ -19,14 +20,19 @@ type AggregatorFormatDriver struct {
 	namespace         string
 	flowAggIn         chan *common.Flow
 	listenerFlowCount *atomic.Int64
+	filter            *flowfilter.Filter
+	samplingRates     *SamplingRateOverrides
 }
 
-// NewAggregatorFormatDriver returns a new AggregatorFormatDriver
-func NewAggregatorFormatDriver(flowAgg chan *common.Flow, namespace string, listenerFlowCount *atomic.Int64) *AggregatorFormatDriver {
+// NewAggregatorFormatDriver returns a new AggregatorFormatDriver, filter and samplingRates can be nil
+// if the flows are not filtered and keep the sampling rate reported by their exporter
+func NewAggregatorFormatDriver(flowAgg chan *common.Flow, namespace string, listenerFlowCount *atomic.Int64, filter *flowfilter.Filter, samplingRates *SamplingRateOverrides) *AggregatorFormatDriver {
 	return &AggregatorFormatDriver{
 		namespace:         namespace,
 		flowAggIn:         flowAgg,
 		listenerFlowCount: listenerFlowCount,
+		filter:            filter,
+		samplingRates:     samplingRates,
 	}
 }
 
@@ -42,16 +48,24 @@ func (d *AggregatorFormatDriver) Init(context.Context) error {
 
 // Format desc
 func (d *AggregatorFormatDriver) Format(data interface{}) ([]byte, []byte, error) {
-	switch flow := data.(type) {
+	var flow *common.Flow
+	switch msg := data.(type) {
 	case *flowpb.FlowMessage:
-		d.listenerFlowCount.Add(1)
-		d.flowAggIn <- ConvertFlow(flow, d.namespace)
+		flow = ConvertFlow(msg, d.namespace)
 	case *common.FlowMessageWithAdditionalFields:
-		d.listenerFlowCount.Add(1)
-		d.flowAggIn <- ConvertFlowWithAdditionalFields(flow, d.namespace)
+		flow = ConvertFlowWithAdditionalFields(msg, d.namespace)
 	default:
 		return nil, nil, fmt.Errorf("message is not flowpb.FlowMessage or common.FlowMessageWithAdditionalFields")
 	}
 
+	d.listenerFlowCount.Add(1)
+	if d.samplingRates != nil {
+		d.samplingRates.Apply(flow)
+	}
+	if d.filter != nil && !d.filter.Keep(flow) {
+		return nil, nil, nil
+	}
+	d.flowAggIn <- flow
+
 	return nil, nil, nil
 }
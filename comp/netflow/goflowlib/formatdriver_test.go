@@ -0,0 +1,46 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package goflowlib
+
+import (
+	"testing"
+
+	flowpb "github.com/netsampler/goflow2/pb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+	"github.com/DataDog/datadog-agent/comp/netflow/flowfilter"
+)
+
+func TestAggregatorFormatDriver_Filter(t *testing.T) {
+	filter, err := flowfilter.New([]config.FilterRule{
+		{Name: "https", Action: common.FilterExclude, DestinationPorts: []string{"443"}},
+	})
+	require.NoError(t, err)
+
+	flowChan := make(chan *common.Flow, 10)
+	flowCount := atomic.NewInt64(0)
+	driver := NewAggregatorFormatDriver(flowChan, "my-ns", flowCount, filter, nil)
+
+	_, _, err = driver.Format(&flowpb.FlowMessage{DstPort: 443})
+	require.NoError(t, err)
+	_, _, err = driver.Format(&flowpb.FlowMessage{DstPort: 80})
+	require.NoError(t, err)
+
+	// dropped flows are counted as received, but not aggregated
+	assert.Equal(t, int64(2), flowCount.Load())
+	require.Len(t, flowChan, 1)
+	flow := <-flowChan
+	assert.Equal(t, int32(80), flow.DstPort)
+	assert.Equal(t, "my-ns", flow.Namespace)
+	assert.Equal(t, []flowfilter.RuleDropCount{{Rule: "https", Dropped: 1}}, filter.DropCounts())
+
+	_, _, err = driver.Format("not a flow")
+	assert.Error(t, err)
+}
@@ -132,6 +132,20 @@ var metricNameMapping = map[string]mappedMetric{
 		},
 		extraTags: []string{"flow_protocol:sflow"},
 	},
+	"flow_replication_datagrams": {
+		name:           "replication.datagrams",
+		allowedTagKeys: []string{"local_port", "target"},
+		keyRemapper: map[string]string{
+			"local_port": "listener_port",
+		},
+	},
+	"flow_replication_dropped": {
+		name:           "replication.dropped",
+		allowedTagKeys: []string{"local_port", "target", "reason"},
+		keyRemapper: map[string]string{
+			"local_port": "listener_port",
+		},
+	},
 }
 
 func remapCollectorType(goflowType string) string {
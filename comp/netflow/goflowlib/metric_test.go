@@ -470,6 +470,45 @@ func TestConvertMetric(t *testing.T) {
 			expectedTags:       []string{"exporter_ip:1.2.3.4", "error:some-error", "flow_protocol:sflow"},
 			expectedErr:        "",
 		},
+		{
+			name: "METRIC flow_replication_datagrams",
+			metricFamily: &promClient.MetricFamily{
+				Name: proto.String("flow_replication_datagrams"),
+				Type: promClient.MetricType_COUNTER.Enum(),
+			},
+			metric: &promClient.Metric{
+				Counter: &promClient.Counter{Value: proto.Float64(10)},
+				Label: []*promClient.LabelPair{
+					{Name: proto.String("local_port"), Value: proto.String("2055")},
+					{Name: proto.String("target"), Value: proto.String("10.0.0.2:2055")},
+				},
+			},
+			expectedMetricType: metrics.MonotonicCountType,
+			expectedName:       "replication.datagrams",
+			expectedValue:      10.0,
+			expectedTags:       []string{"listener_port:2055", "target:10.0.0.2:2055"},
+			expectedErr:        "",
+		},
+		{
+			name: "METRIC flow_replication_dropped",
+			metricFamily: &promClient.MetricFamily{
+				Name: proto.String("flow_replication_dropped"),
+				Type: promClient.MetricType_COUNTER.Enum(),
+			},
+			metric: &promClient.Metric{
+				Counter: &promClient.Counter{Value: proto.Float64(10)},
+				Label: []*promClient.LabelPair{
+					{Name: proto.String("local_port"), Value: proto.String("2055")},
+					{Name: proto.String("target"), Value: proto.String("10.0.0.2:2055")},
+					{Name: proto.String("reason"), Value: proto.String("queue_full")},
+				},
+			},
+			expectedMetricType: metrics.MonotonicCountType,
+			expectedName:       "replication.dropped",
+			expectedValue:      10.0,
+			expectedTags:       []string{"listener_port:2055", "target:10.0.0.2:2055", "reason:queue_full"},
+			expectedErr:        "",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
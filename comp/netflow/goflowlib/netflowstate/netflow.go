@@ -117,9 +117,14 @@ func (s *StateNetFlow) DecodeFlow(msg interface{}) error {
 		s.Logger.Errorf("failed to process additional fields %s", err)
 	}
 
+	samplingRates := recordsSamplingRates(msgDec)
+
 	for i, fmsg := range flowMessageSet {
 		fmsg.TimeReceived = ts
 		fmsg.SamplerAddress = samplerAddress
+		if i < len(samplingRates) && samplingRates[i] > 0 {
+			fmsg.SamplingRate = samplingRates[i]
+		}
 		timeDiff := fmsg.TimeReceived - fmsg.TimeFlowEnd
 
 		message := common.FlowMessageWithAdditionalFields{
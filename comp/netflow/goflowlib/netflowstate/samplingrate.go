@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package netflowstate
+
+import (
+	"github.com/netsampler/goflow2/decoders/netflow"
+	"github.com/netsampler/goflow2/producer"
+)
+
+// samplingRateFields are the fields carrying a sampling rate, by order of precedence:
+// samplingPacketInterval, samplerRandomInterval and samplingInterval
+var samplingRateFields = []uint16{
+	netflow.IPFIX_FIELD_samplingPacketInterval,
+	netflow.IPFIX_FIELD_samplerRandomInterval,
+	netflow.IPFIX_FIELD_samplingInterval,
+}
+
+// recordsSamplingRates returns the sampling rate carried by each data record of a
+// NetflowV9/IPFIX packet, or 0 for the records without one. goflow only reads the
+// sampling rate from options data records, some exporters set it in each flow record.
+// Records are returned in the same order as the flow messages built by goflow.
+func recordsSamplingRates(msgDec interface{}) []uint64 {
+	var dataFlowSets []netflow.DataFlowSet
+	switch msgDecConv := msgDec.(type) {
+	case netflow.NFv9Packet:
+		dataFlowSets, _, _, _ = producer.SplitNetFlowSets(msgDecConv)
+	case netflow.IPFIXPacket:
+		dataFlowSets, _, _, _ = producer.SplitIPFIXSets(msgDecConv)
+	default:
+		return nil
+	}
+
+	var samplingRates []uint64
+	for _, dataFlowSet := range dataFlowSets {
+		for _, record := range dataFlowSet.Records {
+			samplingRates = append(samplingRates, recordSamplingRate(record.Values))
+		}
+	}
+	return samplingRates
+}
+
+func recordSamplingRate(fields []netflow.DataField) uint64 {
+	for _, fieldType := range samplingRateFields {
+		found, value := producer.NetFlowLookFor(fields, fieldType)
+		valueBytes, ok := value.([]byte)
+		if !found || !ok {
+			continue
+		}
+		// exporters can use reduced-size encoding for these fields (RFC 7011 6.2)
+		var samplingRate uint64
+		if err := producer.DecodeUNumber(valueBytes, &samplingRate); err == nil && samplingRate > 0 {
+			return samplingRate
+		}
+	}
+	return 0
+}
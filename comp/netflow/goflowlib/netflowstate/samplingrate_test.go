@@ -0,0 +1,50 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package netflowstate
+
+import (
+	"testing"
+
+	"github.com/netsampler/goflow2/decoders/netflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordsSamplingRates(t *testing.T) {
+	record := func(fields ...netflow.DataField) netflow.DataRecord {
+		return netflow.DataRecord{Values: append([]netflow.DataField{
+			{Type: netflow.IPFIX_FIELD_octetDeltaCount, Value: []byte{0, 0, 0, 100}},
+		}, fields...)}
+	}
+
+	packet := netflow.IPFIXPacket{
+		FlowSets: []interface{}{
+			netflow.DataFlowSet{Records: []netflow.DataRecord{
+				record(netflow.DataField{Type: netflow.IPFIX_FIELD_samplingPacketInterval, Value: []byte{0x03, 0xe8}}),
+				record(),
+			}},
+			netflow.OptionsDataFlowSet{},
+			netflow.DataFlowSet{Records: []netflow.DataRecord{
+				record(
+					netflow.DataField{Type: netflow.IPFIX_FIELD_samplingInterval, Value: []byte{0, 0, 0, 10}},
+					netflow.DataField{Type: netflow.IPFIX_FIELD_samplerRandomInterval, Value: []byte{0, 0, 0, 20}},
+				),
+				record(netflow.DataField{Type: netflow.IPFIX_FIELD_samplingInterval, Value: []byte{0, 0, 0, 0}}),
+			}},
+		},
+	}
+	assert.Equal(t, []uint64{1000, 0, 20, 0}, recordsSamplingRates(packet))
+
+	nfv9Packet := netflow.NFv9Packet{
+		FlowSets: []interface{}{
+			netflow.DataFlowSet{Records: []netflow.DataRecord{
+				record(netflow.DataField{Type: netflow.NFV9_FIELD_SAMPLING_INTERVAL, Value: []byte{0, 0, 0, 64}}),
+			}},
+		},
+	}
+	assert.Equal(t, []uint64{64}, recordsSamplingRates(nfv9Packet))
+
+	assert.Nil(t, recordsSamplingRates("not a packet"))
+}
@@ -0,0 +1,62 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package goflowlib
+
+import (
+	"github.com/netsampler/goflow2/decoders"
+	"github.com/netsampler/goflow2/utils"
+
+	"github.com/DataDog/datadog-agent/comp/netflow/goflowlib/netflowstate"
+	"github.com/DataDog/datadog-agent/comp/netflow/replicator"
+)
+
+// replicatingState runs the UDP routine of a flow state itself, to forward the received
+// datagrams to the replication targets of the listener before decoding them.
+type replicatingState struct {
+	// name is the goflow decoder name, used as collector type in goflow metrics
+	name       string
+	decodeFunc decoder.DecoderFunc
+	replicator *replicator.Replicator
+	logger     utils.Logger
+	stopCh     chan struct{}
+}
+
+func newReplicatingState(name string, decodeFunc decoder.DecoderFunc, replicator *replicator.Replicator, logger utils.Logger) *replicatingState {
+	return &replicatingState{
+		name:       name,
+		decodeFunc: decodeFunc,
+		replicator: replicator,
+		logger:     logger,
+	}
+}
+
+// FlowRoutine starts a goflow flow routine
+func (s *replicatingState) FlowRoutine(workers int, addr string, port int, reuseport bool) error {
+	if s.stopCh != nil {
+		return netflowstate.ErrAlreadyStarted
+	}
+	s.stopCh = make(chan struct{})
+	return utils.UDPStoppableRoutine(s.stopCh, s.name, s.decode, workers, addr, port, reuseport, s.logger)
+}
+
+func (s *replicatingState) decode(msg interface{}) error {
+	if pkt, ok := msg.(utils.BaseMessage); ok {
+		s.replicator.Replicate(pkt.Src, pkt.Port, pkt.Payload)
+	}
+	return s.decodeFunc(msg)
+}
+
+// Shutdown stops the flow routine, the replicator is stopped by its owner
+func (s *replicatingState) Shutdown() {
+	if s.stopCh != nil {
+		select {
+		case <-s.stopCh:
+		default:
+			close(s.stopCh)
+		}
+		s.stopCh = nil
+	}
+}
@@ -0,0 +1,53 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package goflowlib
+
+import (
+	"net"
+
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+)
+
+// SamplingRateOverrides replaces the sampling rate of the flows of the configured exporters
+type SamplingRateOverrides struct {
+	overrides []samplingRateOverride
+}
+
+type samplingRateOverride struct {
+	exporters    *net.IPNet
+	samplingRate uint64
+}
+
+// NewSamplingRateOverrides builds the sampling rate overrides of a listener, it returns nil if there are none
+func NewSamplingRateOverrides(confs []config.SamplingRateOverride) (*SamplingRateOverrides, error) {
+	if len(confs) == 0 {
+		return nil, nil
+	}
+	overrides := &SamplingRateOverrides{}
+	for _, conf := range confs {
+		exporters, err := common.ParseIPNetwork(conf.ExporterIP)
+		if err != nil {
+			return nil, err
+		}
+		overrides.overrides = append(overrides.overrides, samplingRateOverride{
+			exporters:    exporters,
+			samplingRate: conf.SamplingRate,
+		})
+	}
+	return overrides, nil
+}
+
+// Apply sets the sampling rate of the flow using the first override matching its exporter
+func (o *SamplingRateOverrides) Apply(flow *common.Flow) {
+	exporterIP := net.IP(flow.ExporterAddr)
+	for _, override := range o.overrides {
+		if override.exporters.Contains(exporterIP) {
+			flow.SamplingRate = override.samplingRate
+			return
+		}
+	}
+}
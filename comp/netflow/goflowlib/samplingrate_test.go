@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package goflowlib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+)
+
+func TestSamplingRateOverrides(t *testing.T) {
+	overrides, err := NewSamplingRateOverrides(nil)
+	require.NoError(t, err)
+	assert.Nil(t, overrides)
+
+	_, err = NewSamplingRateOverrides([]config.SamplingRateOverride{{ExporterIP: "router", SamplingRate: 10}})
+	assert.Error(t, err)
+
+	overrides, err = NewSamplingRateOverrides([]config.SamplingRateOverride{
+		{ExporterIP: "10.0.0.1", SamplingRate: 1000},
+		{ExporterIP: "10.0.0.0/24", SamplingRate: 100},
+	})
+	require.NoError(t, err)
+
+	flow := &common.Flow{ExporterAddr: []byte{10, 0, 0, 1}, SamplingRate: 1}
+	overrides.Apply(flow)
+	assert.Equal(t, uint64(1000), flow.SamplingRate)
+
+	flow = &common.Flow{ExporterAddr: []byte{10, 0, 0, 2}}
+	overrides.Apply(flow)
+	assert.Equal(t, uint64(100), flow.SamplingRate)
+
+	flow = &common.Flow{ExporterAddr: []byte{10, 0, 1, 1}, SamplingRate: 512}
+	overrides.Apply(flow)
+	assert.Equal(t, uint64(512), flow.SamplingRate)
+}
@@ -25,6 +25,15 @@ type Endpoint struct {
 	Mac                string `json:"mac"`
 	Mask               string `json:"mask"`
 	ReverseDNSHostname string `json:"reverse_dns_hostname,omitempty"`
+	GeoIP              *GeoIP `json:"geoip,omitempty"`
+}
+
+// GeoIP contains the location and autonomous system of an endpoint
+type GeoIP struct {
+	CountryISOCode string `json:"country_iso_code,omitempty"`
+	City           string `json:"city,omitempty"`
+	ASN            uint32 `json:"as_number,omitempty"`
+	ASOrganization string `json:"as_organization,omitempty"`
 }
 
 // NextHop contains next hop details
@@ -55,6 +64,8 @@ type FlowPayload struct {
 	End              uint64           `json:"end"`   // in seconds
 	Bytes            uint64           `json:"bytes"`
 	Packets          uint64           `json:"packets"`
+	EstimatedBytes   uint64           `json:"estimated_bytes"`   // Bytes upscaled by the sampling rate
+	EstimatedPackets uint64           `json:"estimated_packets"` // Packets upscaled by the sampling rate
 	EtherType        string           `json:"ether_type,omitempty"`
 	IPProtocol       string           `json:"ip_protocol"`
 	Device           Device           `json:"device"`
@@ -72,23 +83,25 @@ type FlowPayload struct {
 // MarshalJSON Custom marshaller that moves AdditionalFields to the root of the payload
 func (p FlowPayload) MarshalJSON() ([]byte, error) {
 	fields := map[string]any{
-		"flush_timestamp": p.FlushTimestamp,
-		"type":            p.FlowType,
-		"sampling_rate":   p.SamplingRate,
-		"direction":       p.Direction,
-		"start":           p.Start,
-		"end":             p.End,
-		"bytes":           p.Bytes,
-		"packets":         p.Packets,
-		"ip_protocol":     p.IPProtocol,
-		"device":          p.Device,
-		"exporter":        p.Exporter,
-		"source":          p.Source,
-		"destination":     p.Destination,
-		"ingress":         p.Ingress,
-		"egress":          p.Egress,
-		"host":            p.Host,
-		"next_hop":        p.NextHop,
+		"flush_timestamp":   p.FlushTimestamp,
+		"type":              p.FlowType,
+		"sampling_rate":     p.SamplingRate,
+		"direction":         p.Direction,
+		"start":             p.Start,
+		"end":               p.End,
+		"bytes":             p.Bytes,
+		"packets":           p.Packets,
+		"estimated_bytes":   p.EstimatedBytes,
+		"estimated_packets": p.EstimatedPackets,
+		"ip_protocol":       p.IPProtocol,
+		"device":            p.Device,
+		"exporter":          p.Exporter,
+		"source":            p.Source,
+		"destination":       p.Destination,
+		"ingress":           p.Ingress,
+		"egress":            p.Egress,
+		"host":              p.Host,
+		"next_hop":          p.NextHop,
 	}
 
 	// omit empty
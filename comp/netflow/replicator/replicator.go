@@ -0,0 +1,247 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package replicator forwards the raw datagrams received by a flow listener to downstream collectors.
+package replicator
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/atomic"
+
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+)
+
+const (
+	dropReasonQueueFull = "queue_full"
+	dropReasonSendError = "send_error"
+)
+
+// Prometheus metrics exposed on the NetFlow Prometheus listener and submitted as NetFlow telemetry
+var (
+	replicatedDatagrams = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "flow_replication_datagrams",
+		Help: "Datagrams forwarded to replication targets",
+	}, []string{"local_port", "target"})
+	droppedDatagrams = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "flow_replication_dropped",
+		Help: "Datagrams not forwarded to replication targets",
+	}, []string{"local_port", "target", "reason"})
+)
+
+func init() {
+	prometheus.MustRegister(replicatedDatagrams, droppedDatagrams)
+}
+
+// proxyV2Signature is the signature starting PROXY protocol v2 headers
+var proxyV2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+const (
+	// proxyV2Command is the protocol version 2 and the PROXY command
+	proxyV2Command = 0x21
+	// proxyV2UDPv4 and proxyV2UDPv6 are the address families over UDP
+	proxyV2UDPv4 = 0x12
+	proxyV2UDPv6 = 0x22
+)
+
+// TargetStats contains the number of datagrams forwarded to a replication target
+type TargetStats struct {
+	Target  string
+	Sent    int64
+	Dropped int64
+}
+
+// Replicator forwards the datagrams received by a listener to its replication targets.
+// Each target has its own bounded queue and sender, so that a slow or unreachable target
+// holds back neither the listener nor the other targets.
+type Replicator struct {
+	targets  []*target
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+	logger   log.Component
+}
+
+type target struct {
+	address       string
+	encapsulation common.ReplicationEncapsulation
+	// listenerIP and listenerPort are the destination address of PROXY protocol headers
+	listenerIP   net.IP
+	listenerPort uint16
+	conn         net.Conn
+	queue        chan datagram
+
+	sent    *atomic.Int64
+	dropped *atomic.Int64
+
+	sentCounter      prometheus.Counter
+	queueFullCounter prometheus.Counter
+	sendErrorCounter prometheus.Counter
+}
+
+type datagram struct {
+	exporterIP   net.IP
+	exporterPort int
+	payload      []byte
+}
+
+// New starts forwarding to the replication targets of the listener, it returns nil if there are none
+func New(listenerConfig config.ListenerConfig, logger log.Component) (*Replicator, error) {
+	r, err := newReplicator(listenerConfig, logger)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	r.start()
+	return r, nil
+}
+
+func newReplicator(listenerConfig config.ListenerConfig, logger log.Component) (*Replicator, error) {
+	if len(listenerConfig.Replication.Targets) == 0 {
+		return nil, nil
+	}
+	r := &Replicator{
+		stopCh: make(chan struct{}),
+		logger: logger,
+	}
+	localPort := strconv.Itoa(int(listenerConfig.Port))
+	for _, conf := range listenerConfig.Replication.Targets {
+		conn, err := net.Dial("udp", conf.Address)
+		if err != nil {
+			r.closeConns()
+			return nil, err
+		}
+		r.targets = append(r.targets, &target{
+			address:          conf.Address,
+			encapsulation:    conf.Encapsulation,
+			listenerIP:       net.ParseIP(listenerConfig.BindHost),
+			listenerPort:     listenerConfig.Port,
+			conn:             conn,
+			queue:            make(chan datagram, listenerConfig.Replication.QueueSize),
+			sent:             atomic.NewInt64(0),
+			dropped:          atomic.NewInt64(0),
+			sentCounter:      replicatedDatagrams.WithLabelValues(localPort, conf.Address),
+			queueFullCounter: droppedDatagrams.WithLabelValues(localPort, conf.Address, dropReasonQueueFull),
+			sendErrorCounter: droppedDatagrams.WithLabelValues(localPort, conf.Address, dropReasonSendError),
+		})
+	}
+	return r, nil
+}
+
+func (r *Replicator) start() {
+	for _, t := range r.targets {
+		r.wg.Add(1)
+		go func(t *target) {
+			defer r.wg.Done()
+			t.run(r.stopCh, r.logger)
+		}(t)
+	}
+}
+
+// Replicate queues a datagram received from an exporter for all the targets, without blocking.
+// Datagrams are dropped for the targets whose queue is full. The payload is shared between the
+// targets and must not be modified afterwards.
+func (r *Replicator) Replicate(exporterIP net.IP, exporterPort int, payload []byte) {
+	d := datagram{exporterIP: exporterIP, exporterPort: exporterPort, payload: payload}
+	for _, t := range r.targets {
+		select {
+		case t.queue <- d:
+		default:
+			t.dropped.Inc()
+			t.queueFullCounter.Inc()
+		}
+	}
+}
+
+// Stats returns the number of datagrams forwarded to and dropped for each target
+func (r *Replicator) Stats() []TargetStats {
+	var stats []TargetStats
+	for _, t := range r.targets {
+		stats = append(stats, TargetStats{
+			Target:  t.address,
+			Sent:    t.sent.Load(),
+			Dropped: t.dropped.Load(),
+		})
+	}
+	return stats
+}
+
+// Stop stops forwarding, datagrams still queued are discarded
+func (r *Replicator) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopCh)
+		r.wg.Wait()
+		r.closeConns()
+	})
+}
+
+func (r *Replicator) closeConns() {
+	for _, t := range r.targets {
+		t.conn.Close()
+	}
+}
+
+func (t *target) run(stopCh <-chan struct{}, logger log.Component) {
+	for {
+		select {
+		case <-stopCh:
+			return
+		case d := <-t.queue:
+			if _, err := t.conn.Write(t.encode(d)); err != nil {
+				logger.Debugf("Error forwarding datagram from %s to replication target %s: %s", d.exporterIP, t.address, err)
+				t.dropped.Inc()
+				t.sendErrorCounter.Inc()
+				continue
+			}
+			t.sent.Inc()
+			t.sentCounter.Inc()
+		}
+	}
+}
+
+func (t *target) encode(d datagram) []byte {
+	if t.encapsulation != common.ReplicationEncapsulationProxyV2 {
+		return d.payload
+	}
+	return append(proxyV2Header(d.exporterIP, uint16(d.exporterPort), t.listenerIP, t.listenerPort), d.payload...)
+}
+
+// proxyV2Header builds a PROXY protocol v2 header, carrying the exporter address as source so that
+// the target doesn't see the Agent as the exporter. The destination address falls back to the
+// unspecified address of the exporter family when the listener isn't bound to an address of that family.
+func proxyV2Header(srcIP net.IP, srcPort uint16, dstIP net.IP, dstPort uint16) []byte {
+	family := byte(proxyV2UDPv6)
+	src := srcIP.To16()
+	dst := dstIP.To16()
+	if src4 := srcIP.To4(); src4 != nil {
+		family = proxyV2UDPv4
+		src = src4
+		dst = dstIP.To4()
+	}
+	if dst == nil || (family == proxyV2UDPv6 && dstIP.To4() != nil) {
+		dst = make(net.IP, len(src))
+	}
+	if src == nil {
+		// unknown exporter address, it is sent as the unspecified IPv6 address
+		src = make(net.IP, net.IPv6len)
+		dst = make(net.IP, net.IPv6len)
+	}
+
+	addrLen := 2*len(src) + 4
+	header := make([]byte, 0, len(proxyV2Signature)+4+addrLen)
+	header = append(header, proxyV2Signature...)
+	header = append(header, proxyV2Command, family)
+	header = binary.BigEndian.AppendUint16(header, uint16(addrLen))
+	header = append(header, src...)
+	header = append(header, dst...)
+	header = binary.BigEndian.AppendUint16(header, srcPort)
+	header = binary.BigEndian.AppendUint16(header, dstPort)
+	return header
+}
@@ -0,0 +1,113 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package replicator
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
+	"github.com/DataDog/datadog-agent/comp/netflow/config"
+)
+
+func listenUDP(t *testing.T) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	return conn
+}
+
+func readDatagram(t *testing.T, conn *net.UDPConn) []byte {
+	buf := make([]byte, 9000)
+	n, err := conn.Read(buf)
+	require.NoError(t, err)
+	return buf[:n]
+}
+
+func TestReplicator(t *testing.T) {
+	plainTarget := listenUDP(t)
+	proxyTarget := listenUDP(t)
+
+	r, err := New(config.ListenerConfig{
+		BindHost: "10.0.0.1",
+		Port:     2055,
+		Replication: config.ReplicationConfig{
+			Targets: []config.ReplicationTarget{
+				{Address: plainTarget.LocalAddr().String(), Encapsulation: common.ReplicationEncapsulationNone},
+				{Address: proxyTarget.LocalAddr().String(), Encapsulation: common.ReplicationEncapsulationProxyV2},
+			},
+			QueueSize: 10,
+		},
+	}, logmock.New(t))
+	require.NoError(t, err)
+	defer r.Stop()
+
+	payload := []byte("flow datagram")
+	r.Replicate(net.ParseIP("192.168.1.10"), 12345, payload)
+
+	assert.Equal(t, payload, readDatagram(t, plainTarget))
+
+	expected := append([]byte{}, proxyV2Signature...)
+	expected = append(expected,
+		0x21, 0x12, 0x00, 0x0C, // PROXY command, UDP over IPv4, 12 address bytes
+		192, 168, 1, 10, // exporter IP
+		10, 0, 0, 1, // listener IP
+		0x30, 0x39, // exporter port
+		0x08, 0x07, // listener port
+	)
+	expected = append(expected, payload...)
+	assert.Equal(t, expected, readDatagram(t, proxyTarget))
+
+	assert.Eventually(t, func() bool {
+		stats := r.Stats()
+		return stats[0].Sent == 1 && stats[1].Sent == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestReplicatorQueueFull(t *testing.T) {
+	target := listenUDP(t)
+
+	// the senders aren't started, so that the queue isn't consumed
+	r, err := newReplicator(config.ListenerConfig{
+		Port: 2055,
+		Replication: config.ReplicationConfig{
+			Targets:   []config.ReplicationTarget{{Address: target.LocalAddr().String()}},
+			QueueSize: 2,
+		},
+	}, logmock.New(t))
+	require.NoError(t, err)
+	defer r.Stop()
+
+	for i := 0; i < 5; i++ {
+		r.Replicate(net.ParseIP("192.168.1.10"), 12345, []byte("flow datagram"))
+	}
+
+	assert.Equal(t, []TargetStats{{Target: target.LocalAddr().String(), Sent: 0, Dropped: 3}}, r.Stats())
+}
+
+func TestNewReplicatorWithoutTargets(t *testing.T) {
+	r, err := New(config.ListenerConfig{Port: 2055}, logmock.New(t))
+	require.NoError(t, err)
+	assert.Nil(t, r)
+}
+
+func TestProxyV2Header(t *testing.T) {
+	header := proxyV2Header(net.ParseIP("2001:db8::1"), 53, net.ParseIP("0.0.0.0"), 6343)
+
+	expected := append([]byte{}, proxyV2Signature...)
+	expected = append(expected, 0x21, 0x22, 0x00, 0x24)
+	expected = append(expected, net.ParseIP("2001:db8::1")...)
+	// the listener isn't bound to an IPv6 address
+	expected = append(expected, net.IPv6unspecified...)
+	expected = append(expected, 0x00, 0x35, 0x18, 0xC7)
+	assert.Equal(t, expected, header)
+}
@@ -190,6 +190,42 @@ func TestNetFlow_IntegrationTest_AdditionalFields(t *testing.T) {
 	assertFlowEventsCount(t, port, srv, flowData, 29)
 }
 
+func TestNetFlow_IntegrationTest_Replication(t *testing.T) {
+	port, err := ndmtestutils.GetFreePort()
+	require.NoError(t, err)
+	target, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	require.NoError(t, err)
+	defer target.Close()
+
+	conf := singleListenerConfig("netflow5", port)
+	conf.Listeners[0].Replication = nfconfig.ReplicationConfig{
+		Targets:   []nfconfig.ReplicationTarget{{Address: target.LocalAddr().String(), Encapsulation: common.ReplicationEncapsulationNone}},
+		QueueSize: 10,
+	}
+	var epForwarder forwarder.MockComponent
+	srv := fxutil.Test[Component](t, fx.Options(
+		testOptions,
+		fx.Populate(&epForwarder),
+		fx.Replace(conf),
+		setTimeNow,
+	)).(*Server)
+
+	testutil.ExpectNetflow5Payloads(t, epForwarder)
+	epForwarder.EXPECT().SendEventPlatformEventBlocking(gomock.Any(), "network-devices-metadata").Return(nil).Times(1)
+
+	packetData, err := testutil.GetNetFlow5Packet()
+	require.NoError(t, err, "error getting packet")
+
+	assertFlowEventsCount(t, port, srv, packetData, 2)
+
+	// the datagrams are forwarded as received, in addition to being decoded
+	require.NoError(t, target.SetReadDeadline(time.Now().Add(5*time.Second)))
+	buf := make([]byte, 9000)
+	n, err := target.Read(buf)
+	require.NoError(t, err)
+	assert.Equal(t, packetData, buf[:n])
+}
+
 func BenchmarkNetflowAdditionalFields(b *testing.B) {
 	flowChan := make(chan *common.Flow, 10)
 	listenerFlowCount := atomic.NewInt64(0)
@@ -201,7 +237,7 @@ func BenchmarkNetflowAdditionalFields(b *testing.B) {
 		}
 	}()
 
-	formatDriver := goflowlib.NewAggregatorFormatDriver(flowChan, "bench", listenerFlowCount)
+	formatDriver := goflowlib.NewAggregatorFormatDriver(flowChan, "bench", listenerFlowCount, nil, nil)
 	logrusLogger := logrus.StandardLogger()
 	ctx := context.Background()
 
@@ -9,7 +9,9 @@ import (
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	"github.com/DataDog/datadog-agent/comp/netflow/config"
 	"github.com/DataDog/datadog-agent/comp/netflow/flowaggregator"
+	"github.com/DataDog/datadog-agent/comp/netflow/flowfilter"
 	"github.com/DataDog/datadog-agent/comp/netflow/goflowlib"
+	"github.com/DataDog/datadog-agent/comp/netflow/replicator"
 	"go.uber.org/atomic"
 )
 
@@ -20,12 +22,29 @@ type netflowListener struct {
 	config    config.ListenerConfig
 	error     *atomic.String
 	flowCount *atomic.Int64
+	// filter is nil if the listener has no filter rules
+	filter *flowfilter.Filter
+	// replicator is nil if the listener has no replication targets
+	replicator *replicator.Replicator
 }
 
 func startFlowListener(listenerConfig config.ListenerConfig, flowAgg *flowaggregator.FlowAggregator, logger log.Component) (*netflowListener, error) {
 	listenerAtomicErr := atomic.NewString("")
 	listenerFlowCount := atomic.NewInt64(0)
 
+	filter, err := flowfilter.New(listenerConfig.Filters)
+	if err != nil {
+		return nil, err
+	}
+	samplingRates, err := goflowlib.NewSamplingRateOverrides(listenerConfig.SamplingRateOverrides)
+	if err != nil {
+		return nil, err
+	}
+	flowReplicator, err := replicator.New(listenerConfig, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	flowState, err := goflowlib.StartFlowRoutine(
 		listenerConfig.FlowType,
 		listenerConfig.BindHost,
@@ -33,17 +52,33 @@ func startFlowListener(listenerConfig config.ListenerConfig, flowAgg *flowaggreg
 		listenerConfig.Workers,
 		listenerConfig.Namespace,
 		listenerConfig.Mapping,
+		filter,
+		samplingRates,
+		flowReplicator,
 		flowAgg.GetFlowInChan(),
 		logger,
 		listenerAtomicErr,
 		listenerFlowCount)
 
 	listener := &netflowListener{
-		flowState: flowState,
-		config:    listenerConfig,
-		error:     listenerAtomicErr,
-		flowCount: listenerFlowCount,
+		flowState:  flowState,
+		config:     listenerConfig,
+		error:      listenerAtomicErr,
+		flowCount:  listenerFlowCount,
+		filter:     filter,
+		replicator: flowReplicator,
+	}
+	if err != nil && flowReplicator != nil {
+		flowReplicator.Stop()
 	}
 
 	return listener, err
 }
+
+// shutdown stops receiving flows and forwarding them to the replication targets
+func (l *netflowListener) shutdown() {
+	l.flowState.Shutdown()
+	if l.replicator != nil {
+		l.replicator.Stop()
+	}
+}
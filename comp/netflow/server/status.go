@@ -11,6 +11,8 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/core/status"
 	nfconfig "github.com/DataDog/datadog-agent/comp/netflow/config"
+	"github.com/DataDog/datadog-agent/comp/netflow/flowfilter"
+	"github.com/DataDog/datadog-agent/comp/netflow/replicator"
 )
 
 //go:embed status_templates
@@ -31,6 +33,10 @@ type netflowListenerStatus struct {
 	Config    nfconfig.ListenerConfig
 	Error     string
 	FlowCount int64
+	// DroppedFlows contains the number of flows dropped by each filter rule
+	DroppedFlows []flowfilter.RuleDropCount
+	// Replication contains the number of datagrams forwarded to each replication target
+	Replication []replicator.TargetStats
 }
 
 // Provider provides the functionality to populate the status output
@@ -77,10 +83,17 @@ func (p Provider) getStatus(stats map[string]interface{}) {
 				Error:  errorString,
 			})
 		} else {
-			workingListeners = append(workingListeners, netflowListenerStatus{
+			listenerStatus := netflowListenerStatus{
 				Config:    listener.config,
 				FlowCount: listener.flowCount.Load(),
-			})
+			}
+			if listener.filter != nil {
+				listenerStatus.DroppedFlows = listener.filter.DropCounts()
+			}
+			if listener.replicator != nil {
+				listenerStatus.Replication = listener.replicator.Stats()
+			}
+			workingListeners = append(workingListeners, listenerStatus)
 		}
 	}
 
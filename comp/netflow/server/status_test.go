@@ -12,8 +12,13 @@ import (
 	"strings"
 	"testing"
 
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/netflow/common"
 	nfconfig "github.com/DataDog/datadog-agent/comp/netflow/config"
+	"github.com/DataDog/datadog-agent/comp/netflow/flowfilter"
+	"github.com/DataDog/datadog-agent/comp/netflow/replicator"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 )
 
@@ -141,3 +146,108 @@ func TestStatusProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestStatusProviderWithFilters(t *testing.T) {
+	filter, err := flowfilter.New([]nfconfig.FilterRule{
+		{Name: "multicast", Action: common.FilterExclude, DestinationCIDRs: []string{"224.0.0.0/4"}},
+		{Name: "dns", Action: common.FilterInclude, DestinationPorts: []string{"53"}},
+	})
+	require.NoError(t, err)
+	filter.Keep(&common.Flow{DstAddr: []byte{224, 0, 0, 1}, DstPort: 53})
+	filter.Keep(&common.Flow{DstAddr: []byte{10, 0, 0, 1}, DstPort: 443})
+	filter.Keep(&common.Flow{DstAddr: []byte{10, 0, 0, 1}, DstPort: 53})
+
+	statusProvider := Provider{
+		server: &Server{
+			listeners: []*netflowListener{
+				{
+					config: nfconfig.ListenerConfig{
+						BindHost:  "hello",
+						FlowType:  "netflow5",
+						Namespace: "foo",
+					},
+					error:     atomic.NewString(""),
+					flowCount: atomic.NewInt64(3),
+					filter:    filter,
+				},
+			},
+		},
+	}
+
+	b := new(bytes.Buffer)
+	require.NoError(t, statusProvider.Text(false, b))
+
+	expectedTextOutput := `
+  Total Listeners: 1
+  Open Listeners: 1
+  Closed Listeners: 0
+
+  === Open Listener Details ===
+  ---------
+  BindHost: hello
+  FlowType: netflow5
+  Port: 0
+  Workers: 0
+  Namespace: foo
+  Flows Received: 3
+  Flows Dropped by Filters:
+    multicast: 1
+    no include rule matched: 1
+  ---------
+`
+	expectedResult := strings.Replace(expectedTextOutput, "\r\n", "\n", -1)
+	output := strings.Replace(b.String(), "\r\n", "\n", -1)
+	assert.Equal(t, expectedResult, output)
+}
+
+func TestStatusProviderWithReplication(t *testing.T) {
+	listenerConfig := nfconfig.ListenerConfig{
+		BindHost:  "hello",
+		FlowType:  "netflow5",
+		Namespace: "foo",
+		Replication: nfconfig.ReplicationConfig{
+			Targets:   []nfconfig.ReplicationTarget{{Address: "127.0.0.1:2055", Encapsulation: common.ReplicationEncapsulationNone}},
+			QueueSize: 10,
+		},
+	}
+	flowReplicator, err := replicator.New(listenerConfig, logmock.New(t))
+	require.NoError(t, err)
+	defer flowReplicator.Stop()
+
+	statusProvider := Provider{
+		server: &Server{
+			listeners: []*netflowListener{
+				{
+					config:     listenerConfig,
+					error:      atomic.NewString(""),
+					flowCount:  atomic.NewInt64(3),
+					replicator: flowReplicator,
+				},
+			},
+		},
+	}
+
+	b := new(bytes.Buffer)
+	require.NoError(t, statusProvider.Text(false, b))
+
+	expectedTextOutput := `
+  Total Listeners: 1
+  Open Listeners: 1
+  Closed Listeners: 0
+
+  === Open Listener Details ===
+  ---------
+  BindHost: hello
+  FlowType: netflow5
+  Port: 0
+  Workers: 0
+  Namespace: foo
+  Flows Received: 3
+  Replication Targets:
+    127.0.0.1:2055: 0 datagrams sent, 0 dropped
+  ---------
+`
+	expectedResult := strings.Replace(expectedTextOutput, "\r\n", "\n", -1)
+	output := strings.Replace(b.String(), "\r\n", "\n", -1)
+	assert.Equal(t, expectedResult, output)
+}
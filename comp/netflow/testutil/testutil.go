@@ -64,6 +64,8 @@ func ExpectNetflow5Payloads(t *testing.T, mockEpForwarder forwarder.MockComponen
     "end": 1683712725,
     "bytes": 10,
     "packets": 1,
+    "estimated_bytes": 10,
+    "estimated_packets": 1,
     "ether_type": "IPv4",
     "ip_protocol": "TCP",
     "device": {
@@ -111,6 +113,8 @@ func ExpectNetflow5Payloads(t *testing.T, mockEpForwarder forwarder.MockComponen
     "end": 1683712725,
     "bytes": 10,
     "packets": 1,
+    "estimated_bytes": 10,
+    "estimated_packets": 1,
     "ether_type": "IPv4",
     "ip_protocol": "TCP",
     "device": {
@@ -187,6 +191,8 @@ func ExpectPayloadWithAdditionalFields(t *testing.T, mockEpForwarder forwarder.M
     }
   },
   "end": 1675541179,
+  "estimated_bytes": 114702,
+  "estimated_packets": 840155153,
   "ether_type": "IPv4",
   "exporter": {
     "ip": "127.0.0.1"
@@ -12,12 +12,22 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/networkpath/payload"
 )
 
+// PathtestMetadata contains the data used to prioritize a Pathtest, it isn't part of its hash
+type PathtestMetadata struct {
+	// Score ranks the Pathtest against the others when the number of pathtests run per interval is capped
+	Score float64
+	// Retest requests the Pathtest to be run at the next flush, e.g. after an RTT regression
+	Retest bool
+}
+
 // Pathtest details of information necessary to run a traceroute (pathtrace)
 type Pathtest struct {
 	Hostname          string
 	Port              uint16
 	Protocol          payload.Protocol
 	SourceContainerID string
+
+	Metadata PathtestMetadata
 }
 
 // GetHash returns the hash of the Pathtest
@@ -42,10 +42,18 @@ func TestPathtest_GetHash(t *testing.T) {
 		Protocol:          "TCP",
 		SourceContainerID: "containerID2",
 	}
+	p6 := Pathtest{
+		Hostname:          "aaa1",
+		Port:              80,
+		Protocol:          "TCP",
+		SourceContainerID: "containerID1",
+		Metadata:          PathtestMetadata{Score: 1000, Retest: true},
+	}
 
 	assert.NotEqual(t, p1.GetHash(), p2.GetHash())
 	assert.NotEqual(t, p1.GetHash(), p3.GetHash())
 	assert.NotEqual(t, p2.GetHash(), p3.GetHash())
 	assert.NotEqual(t, p1.GetHash(), p4.GetHash())
 	assert.NotEqual(t, p1.GetHash(), p5.GetHash())
+	assert.Equal(t, p1.GetHash(), p6.GetHash())
 }
@@ -23,6 +23,15 @@ type collectorConfigs struct {
 	pathtestInterval             time.Duration
 	flushInterval                time.Duration
 	networkDevicesNamespace      string
+
+	// target selection
+	rankBy                 string
+	maxTestsPerInterval    int
+	includeCIDRs           []string
+	excludeCIDRs           []string
+	includeDomains         []string
+	excludeDomains         []string
+	rttRegressionThreshold float64
 }
 
 func newConfig(agentConfig config.Component) *collectorConfigs {
@@ -39,6 +48,14 @@ func newConfig(agentConfig config.Component) *collectorConfigs {
 		pathtestInterval:             agentConfig.GetDuration("network_path.collector.pathtest_interval"),
 		flushInterval:                agentConfig.GetDuration("network_path.collector.flush_interval"),
 		networkDevicesNamespace:      agentConfig.GetString("network_devices.namespace"),
+
+		rankBy:                 agentConfig.GetString("network_path.collector.target_selection.rank_by"),
+		maxTestsPerInterval:    agentConfig.GetInt("network_path.collector.target_selection.max_tests_per_interval"),
+		includeCIDRs:           agentConfig.GetStringSlice("network_path.collector.target_selection.include_cidrs"),
+		excludeCIDRs:           agentConfig.GetStringSlice("network_path.collector.target_selection.exclude_cidrs"),
+		includeDomains:         agentConfig.GetStringSlice("network_path.collector.target_selection.include_domains"),
+		excludeDomains:         agentConfig.GetStringSlice("network_path.collector.target_selection.exclude_domains"),
+		rttRegressionThreshold: agentConfig.GetFloat64("network_path.collector.target_selection.rtt_regression_threshold"),
 	}
 }
 
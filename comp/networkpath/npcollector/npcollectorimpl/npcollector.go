@@ -11,6 +11,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"time"
 
 	model "github.com/DataDog/agent-payload/v5/process"
@@ -48,6 +49,9 @@ type npCollectorImpl struct {
 	pathtestInputChan      chan *common.Pathtest
 	pathtestProcessingChan chan *pathteststore.PathtestContext
 
+	// Target selection
+	targetSelector *targetSelector
+
 	// Scheduling related
 	running       bool
 	workers       int
@@ -91,11 +95,12 @@ func newNpCollectorImpl(epForwarder eventplatform.Forwarder, collectorConfigs *c
 		collectorConfigs: collectorConfigs,
 		logger:           logger,
 
-		pathtestStore:          pathteststore.NewPathtestStore(collectorConfigs.pathtestTTL, collectorConfigs.pathtestInterval, collectorConfigs.pathtestContextsLimit, logger),
+		pathtestStore:          pathteststore.NewPathtestStore(collectorConfigs.pathtestTTL, collectorConfigs.pathtestInterval, collectorConfigs.pathtestContextsLimit, collectorConfigs.maxTestsPerInterval, logger),
 		pathtestInputChan:      make(chan *common.Pathtest, collectorConfigs.pathtestInputChanSize),
 		pathtestProcessingChan: make(chan *pathteststore.PathtestContext, collectorConfigs.pathtestProcessingChanSize),
 		flushInterval:          collectorConfigs.flushInterval,
 		workers:                collectorConfigs.workers,
+		targetSelector:         newTargetSelector(collectorConfigs, logger),
 
 		networkDevicesNamespace: collectorConfigs.networkDevicesNamespace,
 
@@ -113,11 +118,15 @@ func newNpCollectorImpl(epForwarder eventplatform.Forwarder, collectorConfigs *c
 	}
 }
 
-func (s *npCollectorImpl) ScheduleConns(conns []*model.Connection) {
+func (s *npCollectorImpl) ScheduleConns(conns []*model.Connection, dns map[string]*model.DNSEntry) {
 	if !s.collectorConfigs.connectionsMonitoringEnabled {
 		return
 	}
 	startTime := s.TimeNowFn()
+
+	// aggregate the connections stats by pathtest, to rank them
+	var targets []*target
+	targetsByHash := make(map[uint64]*target)
 	for _, conn := range conns {
 		remoteAddr := conn.Raddr
 		protocol := convertProtocol(conn.GetType())
@@ -131,12 +140,42 @@ func (s *npCollectorImpl) ScheduleConns(conns []*model.Connection) {
 			s.logger.Tracef("Skipped connection: addr=%s, port=%d, protocol=%s", remoteAddr, remotePort, protocol)
 			continue
 		}
-		sourceContainer := conn.Laddr.GetContainerId()
-		err := s.scheduleOne(remoteAddr.GetIp(), remotePort, protocol, sourceContainer)
+		if !s.targetSelector.isAllowed(net.ParseIP(remoteAddr.GetIp()), connNames(conn, dns)) {
+			s.logger.Tracef("Filtered connection: addr=%s, port=%d, protocol=%s", remoteAddr, remotePort, protocol)
+			continue
+		}
+		ptest := &common.Pathtest{
+			Hostname:          remoteAddr.GetIp(),
+			Port:              remotePort,
+			Protocol:          protocol,
+			SourceContainerID: conn.Laddr.GetContainerId(),
+		}
+		hash := ptest.GetHash()
+		t, ok := targetsByHash[hash]
+		if !ok {
+			t = &target{pathtest: ptest}
+			targetsByHash[hash] = t
+			targets = append(targets, t)
+		}
+		t.bytes += conn.LastBytesSent + conn.LastBytesReceived
+		t.retransmits += uint64(conn.LastRetransmits)
+		t.rtt = max(t.rtt, conn.Rtt)
+	}
+
+	var retests int
+	for _, ptest := range s.targetSelector.selectTargets(targets, startTime) {
+		if ptest.Metadata.Retest {
+			s.logger.Debugf("RTT regression detected, retesting: hostname=%s port=%d", ptest.Hostname, ptest.Port)
+			retests++
+		}
+		err := s.scheduleOne(ptest)
 		if err != nil {
 			s.logger.Errorf("Error scheduling pathtests: %s", err)
 		}
 	}
+	if retests > 0 {
+		s.statsdClient.Count("datadog.network_path.collector.rtt_regression_retests", int64(retests), nil, 1) //nolint:errcheck
+	}
 
 	scheduleDuration := s.TimeNowFn().Sub(startTime)
 	s.statsdClient.Gauge("datadog.network_path.collector.schedule_duration", scheduleDuration.Seconds(), nil, 1) //nolint:errcheck
@@ -144,18 +183,12 @@ func (s *npCollectorImpl) ScheduleConns(conns []*model.Connection) {
 
 // scheduleOne schedules pathtests.
 // It shouldn't block, if the input channel is full, an error is returned.
-func (s *npCollectorImpl) scheduleOne(hostname string, port uint16, protocol payload.Protocol, sourceContainerID string) error {
+func (s *npCollectorImpl) scheduleOne(ptest *common.Pathtest) error {
 	if s.pathtestInputChan == nil {
 		return errors.New("no input channel, please check that network path is enabled")
 	}
-	s.logger.Debugf("Schedule traceroute for: hostname=%s port=%d", hostname, port)
+	s.logger.Debugf("Schedule traceroute for: hostname=%s port=%d", ptest.Hostname, ptest.Port)
 
-	ptest := &common.Pathtest{
-		Hostname:          hostname,
-		Port:              port,
-		Protocol:          protocol,
-		SourceContainerID: sourceContainerID,
-	}
 	select {
 	case s.pathtestInputChan <- ptest:
 		return nil
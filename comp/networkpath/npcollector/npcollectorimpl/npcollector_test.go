@@ -222,7 +222,7 @@ func Test_NpCollector_runningAndProcessing(t *testing.T) {
 			Type:      model.ConnectionType_udp,
 		},
 	}
-	npCollector.ScheduleConns(conns)
+	npCollector.ScheduleConns(conns, nil)
 
 	waitForProcessedPathtests(npCollector, 5*time.Second, 2)
 
@@ -277,7 +277,7 @@ func Test_NpCollector_ScheduleConns_ScheduleDurationMetric(t *testing.T) {
 	}
 
 	// WHEN
-	npCollector.ScheduleConns(conns)
+	npCollector.ScheduleConns(conns, nil)
 
 	// THEN
 	calls := stats.GaugeCalls
@@ -336,6 +336,7 @@ func Test_npCollectorImpl_ScheduleConns(t *testing.T) {
 	tests := []struct {
 		name              string
 		conns             []*model.Connection
+		dns               map[string]*model.DNSEntry
 		noInputChan       bool
 		agentConfigs      map[string]any
 		expectedPathtests []*common.Pathtest
@@ -476,6 +477,119 @@ func Test_npCollectorImpl_ScheduleConns(t *testing.T) {
 			},
 			expectedLogs: []logCount{},
 		},
+		{
+			name: "ranked by bytes",
+			agentConfigs: map[string]any{
+				"network_path.connections_monitoring.enabled": true,
+			},
+			conns: []*model.Connection{
+				{
+					Laddr:         &model.Addr{Ip: "10.0.0.3", Port: int32(30000)},
+					Raddr:         &model.Addr{Ip: "10.0.0.4", Port: int32(80)},
+					Direction:     model.ConnectionDirection_outgoing,
+					Type:          model.ConnectionType_tcp,
+					LastBytesSent: 100,
+				},
+				{
+					Laddr:         &model.Addr{Ip: "10.0.0.3", Port: int32(30001)},
+					Raddr:         &model.Addr{Ip: "10.0.0.5", Port: int32(80)},
+					Direction:     model.ConnectionDirection_outgoing,
+					Type:          model.ConnectionType_tcp,
+					LastBytesSent: 150,
+				},
+				{
+					Laddr:             &model.Addr{Ip: "10.0.0.3", Port: int32(30002)},
+					Raddr:             &model.Addr{Ip: "10.0.0.4", Port: int32(80)},
+					Direction:         model.ConnectionDirection_outgoing,
+					Type:              model.ConnectionType_tcp,
+					LastBytesReceived: 100,
+				},
+			},
+			expectedPathtests: []*common.Pathtest{
+				{Hostname: "10.0.0.4", Port: uint16(80), Protocol: payload.ProtocolTCP, Metadata: common.PathtestMetadata{Score: 200}},
+				{Hostname: "10.0.0.5", Port: uint16(80), Protocol: payload.ProtocolTCP, Metadata: common.PathtestMetadata{Score: 150}},
+			},
+		},
+		{
+			name: "ranked by retransmits",
+			agentConfigs: map[string]any{
+				"network_path.connections_monitoring.enabled":     true,
+				"network_path.collector.target_selection.rank_by": "retransmits",
+			},
+			conns: []*model.Connection{
+				{
+					Laddr:           &model.Addr{Ip: "10.0.0.3", Port: int32(30000)},
+					Raddr:           &model.Addr{Ip: "10.0.0.4", Port: int32(80)},
+					Direction:       model.ConnectionDirection_outgoing,
+					Type:            model.ConnectionType_tcp,
+					LastBytesSent:   1000,
+					LastRetransmits: 1,
+				},
+				{
+					Laddr:           &model.Addr{Ip: "10.0.0.3", Port: int32(30001)},
+					Raddr:           &model.Addr{Ip: "10.0.0.5", Port: int32(80)},
+					Direction:       model.ConnectionDirection_outgoing,
+					Type:            model.ConnectionType_tcp,
+					LastBytesSent:   10,
+					LastRetransmits: 5,
+				},
+			},
+			expectedPathtests: []*common.Pathtest{
+				{Hostname: "10.0.0.5", Port: uint16(80), Protocol: payload.ProtocolTCP, Metadata: common.PathtestMetadata{Score: 5}},
+				{Hostname: "10.0.0.4", Port: uint16(80), Protocol: payload.ProtocolTCP, Metadata: common.PathtestMetadata{Score: 1}},
+			},
+		},
+		{
+			name: "include and exclude filters",
+			agentConfigs: map[string]any{
+				"network_path.connections_monitoring.enabled":             true,
+				"network_path.collector.target_selection.include_cidrs":   []string{"10.0.0.0/24"},
+				"network_path.collector.target_selection.exclude_cidrs":   []string{"10.0.0.6/32"},
+				"network_path.collector.target_selection.include_domains": []string{"example.com"},
+				"network_path.collector.target_selection.exclude_domains": []string{"*.internal.example.com"},
+			},
+			conns: []*model.Connection{
+				{
+					Laddr:     &model.Addr{Ip: "10.0.0.3", Port: int32(30000)},
+					Raddr:     &model.Addr{Ip: "10.0.0.4", Port: int32(80)},
+					Direction: model.ConnectionDirection_outgoing,
+					Type:      model.ConnectionType_tcp,
+				},
+				{
+					Laddr:     &model.Addr{Ip: "10.0.0.3", Port: int32(30001)},
+					Raddr:     &model.Addr{Ip: "10.0.0.6", Port: int32(80)},
+					Direction: model.ConnectionDirection_outgoing,
+					Type:      model.ConnectionType_tcp,
+				},
+				{
+					Laddr:     &model.Addr{Ip: "10.0.0.3", Port: int32(30002)},
+					Raddr:     &model.Addr{Ip: "10.0.1.1", Port: int32(443)},
+					Direction: model.ConnectionDirection_outgoing,
+					Type:      model.ConnectionType_tcp,
+				},
+				{
+					Laddr:     &model.Addr{Ip: "10.0.0.3", Port: int32(30003)},
+					Raddr:     &model.Addr{Ip: "10.0.1.2", Port: int32(443)},
+					Direction: model.ConnectionDirection_outgoing,
+					Type:      model.ConnectionType_tcp,
+				},
+				{
+					Laddr:     &model.Addr{Ip: "10.0.0.3", Port: int32(30004)},
+					Raddr:     &model.Addr{Ip: "10.0.1.3", Port: int32(443)},
+					Direction: model.ConnectionDirection_outgoing,
+					Type:      model.ConnectionType_tcp,
+				},
+			},
+			dns: map[string]*model.DNSEntry{
+				"10.0.1.1": {Names: []string{"api.example.com"}},
+				"10.0.1.2": {Names: []string{"db.internal.example.com"}},
+				"10.0.1.3": {Names: []string{"example.org"}},
+			},
+			expectedPathtests: []*common.Pathtest{
+				{Hostname: "10.0.0.4", Port: uint16(80), Protocol: payload.ProtocolTCP},
+				{Hostname: "10.0.1.1", Port: uint16(443), Protocol: payload.ProtocolTCP},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -493,7 +607,7 @@ func Test_npCollectorImpl_ScheduleConns(t *testing.T) {
 			stats := &teststatsd.Client{}
 			npCollector.statsdClient = stats
 
-			npCollector.ScheduleConns(tt.conns)
+			npCollector.ScheduleConns(tt.conns, tt.dns)
 
 			actualPathtests := []*common.Pathtest{}
 			for i := 0; i < len(tt.expectedPathtests); i++ {
@@ -754,7 +868,7 @@ func Benchmark_npCollectorImpl_ScheduleConns(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		// add line to avoid linter error
 		_ = i
-		npCollector.ScheduleConns(connections)
+		npCollector.ScheduleConns(connections, nil)
 
 		waitForProcessedPathtests(npCollector, 60*time.Second, 50)
 	}
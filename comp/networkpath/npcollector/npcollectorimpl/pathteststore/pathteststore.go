@@ -7,6 +7,7 @@
 package pathteststore
 
 import (
+	"sort"
 	"sync"
 	time "time"
 
@@ -24,6 +25,10 @@ type PathtestContext struct {
 	runUntil          time.Time
 	lastFlushTime     time.Time
 	lastFlushInterval time.Duration
+
+	// score and retest are updated from the Pathtest metadata every time it is added
+	score  float64
+	retest bool
 }
 
 // LastFlushInterval returns last flush interval
@@ -58,6 +63,15 @@ type Store struct {
 
 	// lastContextWarning is the last time a warning was logged about the store being full
 	lastContextWarning time.Time
+
+	// maxPerInterval is the maximum number of pathtests flushed per interval, 0 means no limit.
+	// When the limit is reached, the pathtests with the highest score are flushed first and the
+	// others are postponed to the next interval.
+	maxPerInterval int
+
+	// windowStart and windowCount track the pathtests flushed during the current interval
+	windowStart time.Time
+	windowCount int
 }
 
 func newPathtestContext(pt *common.Pathtest, runUntilDuration time.Duration) *PathtestContext {
@@ -66,17 +80,20 @@ func newPathtestContext(pt *common.Pathtest, runUntilDuration time.Duration) *Pa
 		Pathtest: pt,
 		nextRun:  now,
 		runUntil: now.Add(runUntilDuration),
+		score:    pt.Metadata.Score,
+		retest:   pt.Metadata.Retest,
 	}
 }
 
 // NewPathtestStore creates a new Store
-func NewPathtestStore(pathtestTTL time.Duration, pathtestInterval time.Duration, contextsLimit int, logger log.Component) *Store {
+func NewPathtestStore(pathtestTTL time.Duration, pathtestInterval time.Duration, contextsLimit int, maxPerInterval int, logger log.Component) *Store {
 	return &Store{
-		contexts:      make(map[uint64]*PathtestContext),
-		ttl:           pathtestTTL,
-		interval:      pathtestInterval,
-		contextsLimit: contextsLimit,
-		logger:        logger,
+		contexts:       make(map[uint64]*PathtestContext),
+		ttl:            pathtestTTL,
+		interval:       pathtestInterval,
+		contextsLimit:  contextsLimit,
+		maxPerInterval: maxPerInterval,
+		logger:         logger,
 	}
 }
 
@@ -90,16 +107,18 @@ func NewPathtestStore(pathtestTTL time.Duration, pathtestInterval time.Duration,
 // We need to keep PathtestContext (contains `nextRun` and `lastSuccessfulFlush`) after flush
 // to be able to flush at regular interval (`flushInterval`).
 // Example, after a flush, PathtestContext will have a new nextRun, that will be the next flush time for new contexts being added.
+//
+// Pathtests due are flushed by descending priority: pathtests to retest first, then by score.
+// If maxPerInterval is set, pathtests exceeding it stay due and are flushed during the next interval.
 func (f *Store) Flush() []*PathtestContext {
 	f.contextsMutex.Lock()
 	defer f.contextsMutex.Unlock()
 
 	f.logger.Tracef("f.contexts: %+v", f.contexts)
 
-	var pathtestsToFlush []*PathtestContext
+	now := timeNow()
+	var pathtestsDue []*PathtestContext
 	for key, ptConfigCtx := range f.contexts {
-		now := timeNow()
-
 		if ptConfigCtx.runUntil.Before(now) {
 			f.logger.Tracef("Delete Pathtest context (key=%d, runUntil=%s, nextRun=%s)", key, ptConfigCtx.runUntil, ptConfigCtx.nextRun)
 			// delete ptConfigCtx wrapper if it reaches runUntil
@@ -109,12 +128,36 @@ func (f *Store) Flush() []*PathtestContext {
 		if ptConfigCtx.nextRun.After(now) {
 			continue
 		}
+		pathtestsDue = append(pathtestsDue, ptConfigCtx)
+	}
+	sort.SliceStable(pathtestsDue, func(i, j int) bool {
+		if pathtestsDue[i].retest != pathtestsDue[j].retest {
+			return pathtestsDue[i].retest
+		}
+		return pathtestsDue[i].score > pathtestsDue[j].score
+	})
+
+	pathtestsToFlush := pathtestsDue
+	if f.maxPerInterval > 0 {
+		if now.Sub(f.windowStart) >= f.interval {
+			f.windowStart = now
+			f.windowCount = 0
+		}
+		remaining := f.maxPerInterval - f.windowCount
+		if remaining < len(pathtestsToFlush) {
+			f.logger.Debugf("Maximum number of pathtests per interval reached (%d), postponing %d pathtests", f.maxPerInterval, len(pathtestsToFlush)-remaining)
+			pathtestsToFlush = pathtestsToFlush[:remaining]
+		}
+		f.windowCount += len(pathtestsToFlush)
+	}
+
+	for _, ptConfigCtx := range pathtestsToFlush {
 		if !ptConfigCtx.lastFlushTime.IsZero() {
 			ptConfigCtx.lastFlushInterval = now.Sub(ptConfigCtx.lastFlushTime)
 		}
 		ptConfigCtx.lastFlushTime = now
-		pathtestsToFlush = append(pathtestsToFlush, ptConfigCtx)
 		ptConfigCtx.nextRun = ptConfigCtx.nextRun.Add(f.interval)
+		ptConfigCtx.retest = false
 	}
 	return pathtestsToFlush
 }
@@ -141,7 +184,13 @@ func (f *Store) Add(pathtestToAdd *common.Pathtest) {
 		f.contexts[hash] = newPathtestContext(pathtestToAdd, f.ttl)
 		return
 	}
-	pathtestCtx.runUntil = timeNow().Add(f.ttl)
+	now := timeNow()
+	pathtestCtx.runUntil = now.Add(f.ttl)
+	pathtestCtx.score = pathtestToAdd.Metadata.Score
+	if pathtestToAdd.Metadata.Retest && !pathtestCtx.retest {
+		pathtestCtx.retest = true
+		pathtestCtx.nextRun = now
+	}
 }
 
 // GetContextsCount returns pathtest contexts count
@@ -91,7 +91,7 @@ func Test_pathtestStore_add(t *testing.T) {
 			assert.Nil(t, err)
 			utillog.SetupLogger(l, "debug")
 
-			store := NewPathtestStore(10*time.Minute, 1*time.Minute, tc.initialSize, l)
+			store := NewPathtestStore(10*time.Minute, 1*time.Minute, tc.initialSize, 0, l)
 
 			for _, pt := range tc.pathtests {
 				store.Add(pt)
@@ -117,7 +117,7 @@ func Test_pathtestStore_add_when_full(t *testing.T) {
 	logger := logmock.New(t)
 
 	// GIVEN
-	store := NewPathtestStore(10*time.Minute, 1*time.Minute, 2, logger)
+	store := NewPathtestStore(10*time.Minute, 1*time.Minute, 2, 0, logger)
 
 	// WHEN
 	pt1 := &common.Pathtest{Hostname: "host1", Port: 53}
@@ -143,7 +143,7 @@ func Test_pathtestStore_flush(t *testing.T) {
 	runInterval := 1 * time.Minute
 
 	// GIVEN
-	store := NewPathtestStore(runDurationFromDisc, runInterval, 10, logger)
+	store := NewPathtestStore(runDurationFromDisc, runInterval, 10, 0, logger)
 
 	// WHEN
 	pt := &common.Pathtest{Hostname: "host1", Port: 53}
@@ -218,3 +218,71 @@ func Test_pathtestStore_flush(t *testing.T) {
 	store.Flush()
 	assert.Equal(t, 0, len(store.contexts))
 }
+
+func Test_pathtestStore_flush_maxPerInterval(t *testing.T) {
+	logger := logmock.New(t)
+	timeNow = MockTimeNow
+	runInterval := 1 * time.Minute
+
+	// GIVEN
+	store := NewPathtestStore(10*time.Minute, runInterval, 10, 2, logger)
+	pt1 := &common.Pathtest{Hostname: "host1", Port: 53, Metadata: common.PathtestMetadata{Score: 10}}
+	pt2 := &common.Pathtest{Hostname: "host2", Port: 53, Metadata: common.PathtestMetadata{Score: 30}}
+	pt3 := &common.Pathtest{Hostname: "host3", Port: 53, Metadata: common.PathtestMetadata{Score: 20}}
+	store.Add(pt1)
+	store.Add(pt2)
+	store.Add(pt3)
+
+	// WHEN the limit is reached, THEN the highest scores are flushed first
+	flushed := store.Flush()
+	assert.Equal(t, []*common.Pathtest{pt2, pt3}, pathtests(flushed))
+
+	// the others are postponed to the next interval
+	setMockTimeNow(MockTimeNow().Add(10 * time.Second))
+	assert.Empty(t, store.Flush())
+
+	setMockTimeNow(MockTimeNow().Add(runInterval))
+	flushed = store.Flush()
+	assert.Equal(t, []*common.Pathtest{pt2, pt3}, pathtests(flushed))
+
+	// retests are flushed first, even when their score is lower
+	setMockTimeNow(MockTimeNow().Add(2 * runInterval))
+	store.Add(&common.Pathtest{Hostname: "host1", Port: 53, Metadata: common.PathtestMetadata{Score: 5, Retest: true}})
+	flushed = store.Flush()
+	assert.Equal(t, []*common.Pathtest{pt1, pt2}, pathtests(flushed))
+	assert.False(t, flushed[0].retest)
+	assert.Equal(t, float64(5), flushed[0].score)
+	assert.Equal(t, MockTimeNow().Add(3*runInterval), flushed[0].nextRun)
+}
+
+func Test_pathtestStore_add_retest(t *testing.T) {
+	logger := logmock.New(t)
+	timeNow = MockTimeNow
+
+	// GIVEN
+	store := NewPathtestStore(10*time.Minute, 1*time.Minute, 10, 0, logger)
+	pt := &common.Pathtest{Hostname: "host1", Port: 53}
+	store.Add(pt)
+	store.Flush()
+	ptCtx := store.contexts[pt.GetHash()]
+	assert.Equal(t, MockTimeNow().Add(time.Minute), ptCtx.nextRun)
+
+	// WHEN
+	retestTime := MockTimeNow().Add(10 * time.Second)
+	setMockTimeNow(retestTime)
+	store.Add(&common.Pathtest{Hostname: "host1", Port: 53, Metadata: common.PathtestMetadata{Retest: true}})
+
+	// THEN
+	assert.Equal(t, retestTime, ptCtx.nextRun)
+	assert.True(t, ptCtx.retest)
+	assert.Len(t, store.Flush(), 1)
+	assert.Equal(t, retestTime.Add(time.Minute), ptCtx.nextRun)
+}
+
+func pathtests(contexts []*PathtestContext) []*common.Pathtest {
+	var ptests []*common.Pathtest
+	for _, ptCtx := range contexts {
+		ptests = append(ptests, ptCtx.Pathtest)
+	}
+	return ptests
+}
@@ -0,0 +1,201 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package npcollectorimpl
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/networkpath/npcollector/npcollectorimpl/common"
+)
+
+const (
+	rankByBytes       = "bytes"
+	rankByRTT         = "rtt"
+	rankByRetransmits = "retransmits"
+)
+
+// targetSelector decides which connections destinations are path tested: destinations are
+// filtered by CIDR and domain, scored from the connections stats to rank them, and flagged
+// to be retested when their RTT regresses.
+//
+// It isn't thread-safe, it's only used from ScheduleConns which is called by the connections check.
+type targetSelector struct {
+	rankBy                 string
+	includeCIDRs           []*net.IPNet
+	excludeCIDRs           []*net.IPNet
+	includeDomains         []string
+	excludeDomains         []string
+	rttRegressionThreshold float64
+
+	// rttBaselines contains the lowest RTT seen for each pathtest since its last RTT regression,
+	// entries not seen for baselineTTL are removed.
+	rttBaselines map[uint64]*rttBaseline
+	baselineTTL  time.Duration
+}
+
+type rttBaseline struct {
+	rtt      uint32
+	lastSeen time.Time
+}
+
+// target is a pathtest candidate, along with the stats of the connections to it
+type target struct {
+	pathtest    *common.Pathtest
+	bytes       uint64
+	retransmits uint64
+	rtt         uint32
+}
+
+func newTargetSelector(configs *collectorConfigs, logger log.Component) *targetSelector {
+	ts := &targetSelector{
+		rankBy:                 configs.rankBy,
+		includeCIDRs:           parseCIDRs(configs.includeCIDRs, logger),
+		excludeCIDRs:           parseCIDRs(configs.excludeCIDRs, logger),
+		includeDomains:         normalizeDomains(configs.includeDomains),
+		excludeDomains:         normalizeDomains(configs.excludeDomains),
+		rttRegressionThreshold: configs.rttRegressionThreshold,
+		rttBaselines:           make(map[uint64]*rttBaseline),
+		baselineTTL:            configs.pathtestTTL,
+	}
+	switch ts.rankBy {
+	case rankByBytes, rankByRTT, rankByRetransmits:
+	default:
+		logger.Warnf("Invalid network path target selection rank_by `%s`, using `%s` instead", ts.rankBy, rankByBytes)
+		ts.rankBy = rankByBytes
+	}
+	return ts
+}
+
+func parseCIDRs(cidrs []string, logger log.Component) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			logger.Warnf("Ignoring invalid network path target selection CIDR `%s`: %s", cidr, err)
+			continue
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+func normalizeDomains(domains []string) []string {
+	normalized := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		normalized = append(normalized, strings.TrimSuffix(strings.ToLower(domain), "."))
+	}
+	return normalized
+}
+
+// isAllowed returns whether a destination passes the include and exclude filters, excludes
+// taking precedence. Destinations match a domain filter if any of their names resolved from DNS does.
+func (ts *targetSelector) isAllowed(ip net.IP, names []string) bool {
+	if matchesCIDRs(ip, ts.excludeCIDRs) || matchesDomains(names, ts.excludeDomains) {
+		return false
+	}
+	if len(ts.includeCIDRs) == 0 && len(ts.includeDomains) == 0 {
+		return true
+	}
+	return matchesCIDRs(ip, ts.includeCIDRs) || matchesDomains(names, ts.includeDomains)
+}
+
+func matchesCIDRs(ip net.IP, networks []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, network := range networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDomains returns whether a name matches one of the domains: `example.com` matches
+// example.com and its subdomains, `*.example.com` only matches its subdomains.
+func matchesDomains(names []string, domains []string) bool {
+	for _, name := range names {
+		name = strings.TrimSuffix(strings.ToLower(name), ".")
+		for _, domain := range domains {
+			if suffix, ok := strings.CutPrefix(domain, "*"); ok {
+				if strings.HasSuffix(name, suffix) {
+					return true
+				}
+			} else if name == domain || strings.HasSuffix(name, "."+domain) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// score returns the rank of a target, the higher the better
+func (ts *targetSelector) score(t *target) float64 {
+	switch ts.rankBy {
+	case rankByRTT:
+		return float64(t.rtt)
+	case rankByRetransmits:
+		return float64(t.retransmits)
+	default:
+		return float64(t.bytes)
+	}
+}
+
+// rttRegressed returns whether the RTT of a target increased by more than the regression
+// threshold over its baseline, in which case the baseline is reset to the current RTT.
+func (ts *targetSelector) rttRegressed(hash uint64, rtt uint32, now time.Time) bool {
+	if ts.rttRegressionThreshold <= 0 || rtt == 0 {
+		return false
+	}
+	baseline, ok := ts.rttBaselines[hash]
+	if !ok {
+		ts.rttBaselines[hash] = &rttBaseline{rtt: rtt, lastSeen: now}
+		return false
+	}
+	baseline.lastSeen = now
+	if float64(rtt) > float64(baseline.rtt)*(1+ts.rttRegressionThreshold) {
+		baseline.rtt = rtt
+		return true
+	}
+	if rtt < baseline.rtt {
+		baseline.rtt = rtt
+	}
+	return false
+}
+
+// selectTargets returns the pathtests to schedule for the given targets, by descending score
+func (ts *targetSelector) selectTargets(targets []*target, now time.Time) []*common.Pathtest {
+	pathtests := make([]*common.Pathtest, 0, len(targets))
+	for _, t := range targets {
+		t.pathtest.Metadata.Score = ts.score(t)
+		t.pathtest.Metadata.Retest = ts.rttRegressed(t.pathtest.GetHash(), t.rtt, now)
+		pathtests = append(pathtests, t.pathtest)
+	}
+	sort.SliceStable(pathtests, func(i, j int) bool {
+		return pathtests[i].Metadata.Score > pathtests[j].Metadata.Score
+	})
+
+	for hash, baseline := range ts.rttBaselines {
+		if now.Sub(baseline.lastSeen) > ts.baselineTTL {
+			delete(ts.rttBaselines, hash)
+		}
+	}
+	return pathtests
+}
+
+// connNames returns the names the remote address of a connection resolves to
+func connNames(conn *model.Connection, dns map[string]*model.DNSEntry) []string {
+	if entry, ok := dns[conn.Raddr.GetIp()]; ok && entry != nil {
+		return entry.Names
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package npcollectorimpl
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/networkpath/npcollector/npcollectorimpl/common"
+)
+
+func Test_targetSelector_isAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		configs  collectorConfigs
+		ip       string
+		names    []string
+		expected bool
+	}{
+		{
+			name:     "no filters",
+			ip:       "10.0.0.1",
+			expected: true,
+		},
+		{
+			name:     "excluded CIDR",
+			configs:  collectorConfigs{excludeCIDRs: []string{"10.0.0.0/8"}},
+			ip:       "10.0.0.1",
+			expected: false,
+		},
+		{
+			name:     "invalid CIDRs are ignored",
+			configs:  collectorConfigs{excludeCIDRs: []string{"10.0.0.0/33"}},
+			ip:       "10.0.0.1",
+			expected: true,
+		},
+		{
+			name:     "included CIDR",
+			configs:  collectorConfigs{includeCIDRs: []string{"10.0.0.0/24"}},
+			ip:       "10.0.0.1",
+			expected: true,
+		},
+		{
+			name:     "not included CIDR",
+			configs:  collectorConfigs{includeCIDRs: []string{"10.0.0.0/24"}},
+			ip:       "10.0.1.1",
+			expected: false,
+		},
+		{
+			name:     "exclude takes precedence",
+			configs:  collectorConfigs{includeCIDRs: []string{"10.0.0.0/24"}, excludeDomains: []string{"example.com"}},
+			ip:       "10.0.0.1",
+			names:    []string{"www.example.com"},
+			expected: false,
+		},
+		{
+			name:     "included domain",
+			configs:  collectorConfigs{includeDomains: []string{"Example.com."}},
+			ip:       "10.0.0.1",
+			names:    []string{"example.com"},
+			expected: true,
+		},
+		{
+			name:     "included subdomain",
+			configs:  collectorConfigs{includeDomains: []string{"example.com"}},
+			ip:       "10.0.0.1",
+			names:    []string{"api.example.com."},
+			expected: true,
+		},
+		{
+			name:     "domain suffix isn't a subdomain",
+			configs:  collectorConfigs{includeDomains: []string{"example.com"}},
+			ip:       "10.0.0.1",
+			names:    []string{"notexample.com"},
+			expected: false,
+		},
+		{
+			name:     "wildcard only matches subdomains",
+			configs:  collectorConfigs{includeDomains: []string{"*.example.com"}},
+			ip:       "10.0.0.1",
+			names:    []string{"example.com"},
+			expected: false,
+		},
+		{
+			name:     "domain filters without names",
+			configs:  collectorConfigs{includeDomains: []string{"example.com"}},
+			ip:       "10.0.0.1",
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTargetSelector(&tt.configs, logmock.New(t))
+			assert.Equal(t, tt.expected, ts.isAllowed(net.ParseIP(tt.ip), tt.names))
+		})
+	}
+}
+
+func Test_targetSelector_invalidRankBy(t *testing.T) {
+	ts := newTargetSelector(&collectorConfigs{rankBy: "latency"}, logmock.New(t))
+	assert.Equal(t, rankByBytes, ts.rankBy)
+}
+
+func Test_targetSelector_rttRegression(t *testing.T) {
+	ts := newTargetSelector(&collectorConfigs{
+		rankBy:                 rankByRTT,
+		rttRegressionThreshold: 0.5,
+		pathtestTTL:            10 * time.Minute,
+	}, logmock.New(t))
+	now := MockTimeNow()
+
+	selectTarget := func(rtt uint32) common.PathtestMetadata {
+		ptests := ts.selectTargets([]*target{{
+			pathtest: &common.Pathtest{Hostname: "10.0.0.1", Port: 443},
+			rtt:      rtt,
+		}}, now)
+		return ptests[0].Metadata
+	}
+
+	// the first RTT seen is the baseline
+	assert.Equal(t, common.PathtestMetadata{Score: 1000}, selectTarget(1000))
+	// the baseline is lowered by lower RTTs
+	assert.Equal(t, common.PathtestMetadata{Score: 800}, selectTarget(800))
+	// below the threshold
+	assert.Equal(t, common.PathtestMetadata{Score: 1200}, selectTarget(1200))
+	// above the threshold, the baseline is reset
+	assert.Equal(t, common.PathtestMetadata{Score: 1300, Retest: true}, selectTarget(1300))
+	assert.Equal(t, common.PathtestMetadata{Score: 1400}, selectTarget(1400))
+	// connections without RTT are ignored
+	assert.Equal(t, common.PathtestMetadata{}, selectTarget(0))
+	assert.Len(t, ts.rttBaselines, 1)
+
+	// baselines expire
+	now = now.Add(11 * time.Minute)
+	ts.selectTargets(nil, now)
+	assert.Empty(t, ts.rttBaselines)
+}
@@ -7,6 +7,8 @@
 package snmpscan
 
 import (
+	"io"
+
 	"github.com/gosnmp/gosnmp"
 )
 
@@ -17,4 +19,6 @@ type Component interface {
 	// Triggers a device scan
 	RunDeviceScan(snmpConection *gosnmp.GoSNMP, deviceNamespace string, deviceIPAddress string) error
 	RunSnmpWalk(snmpConection *gosnmp.GoSNMP, firstOid string) error
+	// Walks a device and writes every value to w in the .snmprec format, to be served by `agent snmp simulate`
+	RecordSnmpWalk(snmpConection *gosnmp.GoSNMP, firstOid string, w io.Writer) error
 }
@@ -8,9 +8,11 @@ package snmpscanimpl
 import (
 	"encoding/hex"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
+	"github.com/DataDog/datadog-agent/pkg/snmp/snmpsim"
 	"github.com/gosnmp/gosnmp"
 )
 
@@ -24,6 +26,25 @@ func (s snmpScannerImpl) RunSnmpWalk(snmpConnection *gosnmp.GoSNMP, firstOid str
 	return nil
 }
 
+// RecordSnmpWalk writes every SNMP value to w in the .snmprec format.
+func (s snmpScannerImpl) RecordSnmpWalk(snmpConnection *gosnmp.GoSNMP, firstOid string, w io.Writer) error {
+	record := func(pdu gosnmp.SnmpPDU) error {
+		line, err := snmpsim.FormatSnmprec(pdu)
+		if err != nil {
+			// keep recording the rest of the device
+			s.log.Warnf("Skipping OID: %s", err)
+			return nil
+		}
+		_, err = fmt.Fprintln(w, line)
+		return err
+	}
+	if err := snmpConnection.Walk(firstOid, record); err != nil {
+		return fmt.Errorf("unable to walk SNMP agent on %s:%d: %w", snmpConnection.Target, snmpConnection.Port, err)
+	}
+
+	return nil
+}
+
 // printValue prints a PDU in a similar style to snmpwalk -Ont
 func printValue(pdu gosnmp.SnmpPDU) error {
 	fmt.Printf("%s = ", pdu.Name)
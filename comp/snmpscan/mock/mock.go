@@ -9,6 +9,7 @@
 package mock
 
 import (
+	"io"
 	"testing"
 
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
@@ -38,3 +39,6 @@ func (m mock) RunDeviceScan(_ *gosnmp.GoSNMP, _ string, _ string) error {
 func (m mock) RunSnmpWalk(_ *gosnmp.GoSNMP, _ string) error {
 	return nil
 }
+func (m mock) RecordSnmpWalk(_ *gosnmp.GoSNMP, _ string, _ io.Writer) error {
+	return nil
+}
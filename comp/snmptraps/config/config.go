@@ -6,8 +6,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"hash/fnv"
+	"regexp"
+	"strings"
+	"text/template"
 
 	"github.com/gosnmp/gosnmp"
 
@@ -15,12 +19,15 @@ import (
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/snmplog"
 	"github.com/DataDog/datadog-agent/pkg/config/structure"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
 	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
 	"github.com/DataDog/datadog-agent/pkg/snmp/utils"
 )
 
 const (
 	defaultPort        = uint16(9162) // Standard UDP port for traps.
+	defaultManagerPort = uint16(162)
 	defaultStopTimeout = 5
 	packetsChanSize    = 100
 )
@@ -47,6 +54,60 @@ type TrapsConfig struct {
 	StopTimeout           int      `mapstructure:"stop_timeout" yaml:"stop_timeout"`
 	Namespace             string   `mapstructure:"namespace" yaml:"namespace"`
 	authoritativeEngineID string   `mapstructure:"-" yaml:"-"`
+
+	// Managers are the downstream SNMP managers received traps are re-emitted to
+	Managers []ManagerConfig `mapstructure:"managers" yaml:"managers"`
+	// Rules convert the traps matching them into Datadog events or service checks
+	Rules []TrapRule `mapstructure:"rules" yaml:"rules"`
+}
+
+// ManagerConfig contains the definition of a downstream SNMP manager. Traps are
+// re-encoded with the SNMP version of the manager, v1 traps being converted from
+// and to v2c/v3 notifications as described in RFC 3584.
+type ManagerConfig struct {
+	Host            string `mapstructure:"host" yaml:"host"`
+	Port            uint16 `mapstructure:"port" yaml:"port"`
+	SNMPVersion     string `mapstructure:"snmp_version" yaml:"snmp_version"`
+	CommunityString string `mapstructure:"community_string" yaml:"community_string"`
+	// User, AuthKey, AuthProtocol, PrivKey and PrivProtocol define the SNMPv3 user traps are sent as
+	User         string `mapstructure:"user" yaml:"user"`
+	AuthKey      string `mapstructure:"authKey" yaml:"authKey"`
+	AuthProtocol string `mapstructure:"authProtocol" yaml:"authProtocol"`
+	PrivKey      string `mapstructure:"privKey" yaml:"privKey"`
+	PrivProtocol string `mapstructure:"privProtocol" yaml:"privProtocol"`
+}
+
+// TrapRule contains the definition of a rule converting the traps with a given OID, and
+// optionally some variable values, into a Datadog event and/or service check.
+type TrapRule struct {
+	Name      string          `mapstructure:"name" yaml:"name"`
+	TrapOID   string          `mapstructure:"trap_oid" yaml:"trap_oid"`
+	Variables []VariableMatch `mapstructure:"variables" yaml:"variables"`
+	// Event and ServiceCheck fields are Go templates rendered with the formatted trap
+	Event        EventTemplate        `mapstructure:"event" yaml:"event"`
+	ServiceCheck ServiceCheckTemplate `mapstructure:"service_check" yaml:"service_check"`
+}
+
+// VariableMatch matches the traps with a variable whose value matches a regular expression
+type VariableMatch struct {
+	// Name is the MIB name or the OID of the variable
+	Name  string `mapstructure:"name" yaml:"name"`
+	Value string `mapstructure:"value" yaml:"value"`
+}
+
+// EventTemplate defines the event sent for the traps matching a rule, no event is sent if Title is empty
+type EventTemplate struct {
+	Title     string `mapstructure:"title" yaml:"title"`
+	Text      string `mapstructure:"text" yaml:"text"`
+	AlertType string `mapstructure:"alert_type" yaml:"alert_type"`
+	Priority  string `mapstructure:"priority" yaml:"priority"`
+}
+
+// ServiceCheckTemplate defines the service check sent for the traps matching a rule, no service check is sent if Name is empty
+type ServiceCheckTemplate struct {
+	Name    string `mapstructure:"name" yaml:"name"`
+	Status  string `mapstructure:"status" yaml:"status"`
+	Message string `mapstructure:"message" yaml:"message"`
 }
 
 // ReadConfig builds the traps configuration from the Agent configuration.
@@ -98,6 +159,28 @@ func (c *TrapsConfig) SetDefaults(host string, namespace string) error {
 		return fmt.Errorf("invalid config: %w", err)
 	}
 
+	for i := range c.Managers {
+		manager := &c.Managers[i]
+		if manager.Port == 0 {
+			manager.Port = defaultManagerPort
+		}
+		if manager.SNMPVersion == "" {
+			manager.SNMPVersion = "2"
+		}
+		if _, err := manager.version(); err != nil {
+			return fmt.Errorf("invalid config: manager %s: %w", manager.Host, err)
+		}
+	}
+	for i := range c.Rules {
+		rule := &c.Rules[i]
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule_%d", i+1)
+		}
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("invalid config: trap rule %s: %w", rule.Name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -160,6 +243,135 @@ func (c *TrapsConfig) BuildSNMPParams(logger log.Component) (*gosnmp.GoSNMP, err
 	}, nil
 }
 
+// BuildManagerSNMPParams returns a GoSNMP params structure to send traps to a manager.
+func (c *TrapsConfig) BuildManagerSNMPParams(manager ManagerConfig, logger log.Component) (*gosnmp.GoSNMP, error) {
+	version, err := manager.version()
+	if err != nil {
+		return nil, err
+	}
+	var snmpLogger gosnmp.Logger
+	if logger != nil {
+		snmpLogger = gosnmp.NewLogger(snmplog.New(logger))
+	}
+	params := &gosnmp.GoSNMP{
+		Target:    manager.Host,
+		Port:      manager.Port,
+		Transport: "udp",
+		Version:   version,
+		Community: manager.CommunityString,
+		Timeout:   gosnmp.Default.Timeout,
+		Logger:    snmpLogger,
+	}
+	if version != gosnmp.Version3 {
+		return params, nil
+	}
+
+	authProtocol, err := gosnmplib.GetAuthProtocol(manager.AuthProtocol)
+	if err != nil {
+		return nil, err
+	}
+	privProtocol, err := gosnmplib.GetPrivProtocol(manager.PrivProtocol)
+	if err != nil {
+		return nil, err
+	}
+	msgFlags := gosnmp.NoAuthNoPriv
+	if privProtocol != gosnmp.NoPriv {
+		msgFlags = gosnmp.AuthPriv
+	} else if authProtocol != gosnmp.NoAuth {
+		msgFlags = gosnmp.AuthNoPriv
+	}
+	params.MsgFlags = msgFlags
+	params.SecurityModel = gosnmp.UserSecurityModel
+	// The Agent is the authoritative engine of the traps it sends
+	params.SecurityParameters = &gosnmp.UsmSecurityParameters{
+		UserName:                 manager.User,
+		AuthoritativeEngineID:    c.authoritativeEngineID,
+		AuthenticationProtocol:   authProtocol,
+		AuthenticationPassphrase: manager.AuthKey,
+		PrivacyProtocol:          privProtocol,
+		PrivacyPassphrase:        manager.PrivKey,
+	}
+	return params, nil
+}
+
+func (m *ManagerConfig) version() (gosnmp.SnmpVersion, error) {
+	if m.Host == "" {
+		return 0, errors.New("host is required")
+	}
+	var version gosnmp.SnmpVersion
+	switch m.SNMPVersion {
+	case "1":
+		version = gosnmp.Version1
+	case "2", "2c":
+		version = gosnmp.Version2c
+	case "3":
+		version = gosnmp.Version3
+	default:
+		return 0, fmt.Errorf("SNMP version not supported: %s", m.SNMPVersion)
+	}
+	if version == gosnmp.Version3 && m.User == "" {
+		return 0, errors.New("user is required with SNMP v3")
+	}
+	if version != gosnmp.Version3 && m.CommunityString == "" {
+		return 0, errors.New("community_string is required with SNMP v1 and v2c")
+	}
+	return version, nil
+}
+
+func (r *TrapRule) validate() error {
+	if r.TrapOID == "" {
+		return errors.New("trap_oid is required")
+	}
+	if r.Event.Title == "" && r.ServiceCheck.Name == "" {
+		return errors.New("an event title or a service check name is required")
+	}
+	for _, variable := range r.Variables {
+		if variable.Name == "" {
+			return errors.New("variable name is required")
+		}
+		if _, err := regexp.Compile(variable.Value); err != nil {
+			return fmt.Errorf("invalid variable value pattern %q: %w", variable.Value, err)
+		}
+	}
+	if r.Event.AlertType != "" {
+		if _, err := event.GetAlertTypeFromString(r.Event.AlertType); err != nil {
+			return err
+		}
+	}
+	if r.Event.Priority != "" {
+		if _, err := event.GetEventPriorityFromString(r.Event.Priority); err != nil {
+			return err
+		}
+	}
+	if r.ServiceCheck.Name != "" {
+		if _, err := ParseServiceCheckStatus(r.ServiceCheck.Status); err != nil {
+			return err
+		}
+	}
+	for _, text := range []string{r.Event.Title, r.Event.Text, r.ServiceCheck.Name, r.ServiceCheck.Message} {
+		if _, err := template.New("").Parse(text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseServiceCheckStatus returns the service check status of a trap rule, unknown if it isn't set
+func ParseServiceCheckStatus(status string) (servicecheck.ServiceCheckStatus, error) {
+	switch strings.ToLower(status) {
+	case "ok":
+		return servicecheck.ServiceCheckOK, nil
+	case "warning":
+		return servicecheck.ServiceCheckWarning, nil
+	case "critical":
+		return servicecheck.ServiceCheckCritical, nil
+	case "unknown", "":
+		return servicecheck.ServiceCheckUnknown, nil
+	default:
+		return servicecheck.ServiceCheckUnknown, fmt.Errorf("invalid service check status: %s", status)
+	}
+}
+
 // GetPacketChannelSize returns the default size for the packets channel
 func (c *TrapsConfig) GetPacketChannelSize() int {
 	return packetsChanSize
@@ -290,3 +290,96 @@ func TestNamespaceSetBothGloballyAndLocally(t *testing.T) {
 
 	assert.Equal(t, "bar", config.Namespace)
 }
+
+func TestManagers(t *testing.T) {
+	deps := fxutil.Test[struct {
+		fx.In
+		Config *TrapsConfig
+		Logger log.Component
+	}](t,
+		testOptions(t),
+		withConfig(t, &TrapsConfig{
+			Managers: []ManagerConfig{
+				{Host: "10.0.0.1", CommunityString: "public"},
+				{Host: "10.0.0.2", Port: 1162, SNMPVersion: "3", User: "user", AuthKey: "password", AuthProtocol: "SHA"},
+			},
+		}, ""),
+	)
+	config := deps.Config
+	require.Len(t, config.Managers, 2)
+	assert.Equal(t, uint16(162), config.Managers[0].Port)
+	assert.Equal(t, "2", config.Managers[0].SNMPVersion)
+
+	params, err := config.BuildManagerSNMPParams(config.Managers[0], deps.Logger)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.1", params.Target)
+	assert.Equal(t, uint16(162), params.Port)
+	assert.Equal(t, gosnmp.Version2c, params.Version)
+	assert.Equal(t, "public", params.Community)
+
+	params, err = config.BuildManagerSNMPParams(config.Managers[1], deps.Logger)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(1162), params.Port)
+	assert.Equal(t, gosnmp.Version3, params.Version)
+	assert.Equal(t, gosnmp.AuthNoPriv, params.MsgFlags)
+	assert.Equal(t, &gosnmp.UsmSecurityParameters{
+		UserName:                 "user",
+		AuthoritativeEngineID:    expectedEngineID,
+		AuthenticationProtocol:   gosnmp.SHA,
+		AuthenticationPassphrase: "password",
+		PrivacyProtocol:          gosnmp.NoPriv,
+	}, params.SecurityParameters)
+}
+
+func TestInvalidManager(t *testing.T) {
+	ddConfig := fxutil.Test[config.Component](t,
+		withConfig(t, &TrapsConfig{
+			Managers: []ManagerConfig{{Host: "10.0.0.1"}},
+		}, ""))
+	_, err := ReadConfig("", ddConfig)
+	assert.EqualError(t, err, "invalid config: manager 10.0.0.1: community_string is required with SNMP v1 and v2c")
+}
+
+func TestRules(t *testing.T) {
+	config := fxutil.Test[*TrapsConfig](t,
+		testOptions(t),
+		withConfig(t, &TrapsConfig{
+			Rules: []TrapRule{
+				{TrapOID: "1.3.6.1.6.3.1.1.5.3", Event: EventTemplate{Title: "{{.ifDescr}} is down"}},
+				{Name: "link-up", TrapOID: "1.3.6.1.6.3.1.1.5.4", ServiceCheck: ServiceCheckTemplate{Name: "snmp.link", Status: "ok"}},
+			},
+		}, ""),
+	)
+	require.Len(t, config.Rules, 2)
+	assert.Equal(t, "rule_1", config.Rules[0].Name)
+	assert.Equal(t, "link-up", config.Rules[1].Name)
+}
+
+func TestInvalidRules(t *testing.T) {
+	for _, tc := range []struct {
+		rule        TrapRule
+		expectedErr string
+	}{
+		{
+			rule:        TrapRule{Event: EventTemplate{Title: "title"}},
+			expectedErr: "invalid config: trap rule rule_1: trap_oid is required",
+		},
+		{
+			rule:        TrapRule{TrapOID: "1.3.6.1.6.3.1.1.5.3"},
+			expectedErr: "invalid config: trap rule rule_1: an event title or a service check name is required",
+		},
+		{
+			rule:        TrapRule{TrapOID: "1.3.6.1.6.3.1.1.5.3", Event: EventTemplate{Title: "title", AlertType: "bad"}},
+			expectedErr: "invalid config: trap rule rule_1: Invalid alert type: 'bad'",
+		},
+		{
+			rule:        TrapRule{TrapOID: "1.3.6.1.6.3.1.1.5.3", ServiceCheck: ServiceCheckTemplate{Name: "check", Status: "bad"}},
+			expectedErr: "invalid config: trap rule rule_1: invalid service check status: bad",
+		},
+	} {
+		ddConfig := fxutil.Test[config.Component](t,
+			withConfig(t, &TrapsConfig{Rules: []TrapRule{tc.rule}}, ""))
+		_, err := ReadConfig("", ddConfig)
+		assert.EqualError(t, err, tc.expectedErr)
+	}
+}
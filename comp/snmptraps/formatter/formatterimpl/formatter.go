@@ -16,6 +16,7 @@ import (
 
 	"github.com/DataDog/datadog-agent/comp/aggregator/demultiplexer"
 	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/config"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/formatter"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/oidresolver"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/packet"
@@ -40,6 +41,7 @@ type JSONFormatter struct {
 	oidResolver oidresolver.Component
 	sender      sender.Sender
 	logger      log.Component
+	rules       []trapRule
 }
 
 type trapVariable struct {
@@ -58,15 +60,20 @@ const (
 )
 
 // newJSONFormatter creates a new JSONFormatter instance with an optional OIDResolver variable.
-func newJSONFormatter(oidResolver oidresolver.Component, demux demultiplexer.Component, logger log.Component) (formatter.Component, error) {
+func newJSONFormatter(oidResolver oidresolver.Component, demux demultiplexer.Component, conf config.Component, logger log.Component) (formatter.Component, error) {
 	sender, err := demux.GetDefaultSender()
 	if err != nil {
 		return nil, err
 	}
-	return JSONFormatter{oidResolver, sender, logger}, nil
+	rules, err := newTrapRules(conf.Get().Rules)
+	if err != nil {
+		return nil, err
+	}
+	return JSONFormatter{oidResolver, sender, logger, rules}, nil
 }
 
-// FormatPacket converts a raw SNMP trap packet to a FormattedSnmpPacket containing the JSON data and the tags to attach
+// FormatPacket converts a raw SNMP trap packet to a FormattedSnmpPacket containing the JSON data and the tags to attach.
+// The traps matching a trap rule are also sent as events and/or service checks.
 //
 //	{
 //	  "trap": {
@@ -101,6 +108,7 @@ func (f JSONFormatter) FormatPacket(packet *packet.SnmpPacket) ([]byte, error) {
 			return nil, err
 		}
 	}
+	f.applyRules(packet, formattedTrap)
 	formattedTrap["ddsource"] = ddsource
 	formattedTrap["ddtags"] = strings.Join(packet.GetTags(), ",")
 	formattedTrap["timestamp"] = packet.Timestamp
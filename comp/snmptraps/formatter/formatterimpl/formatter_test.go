@@ -14,6 +14,7 @@ import (
 	"go.uber.org/fx"
 
 	logmock "github.com/DataDog/datadog-agent/comp/core/log/mock"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/config/configimpl"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/formatter"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/oidresolver"
 	"github.com/DataDog/datadog-agent/comp/snmptraps/oidresolver/oidresolverimpl"
@@ -217,6 +218,7 @@ var (
 
 var testOptions = fx.Options(
 	senderhelper.Opts,
+	configimpl.MockModule(),
 	oidresolverimpl.MockModule(),
 	Module(),
 )
@@ -0,0 +1,175 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package formatterimpl
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/DataDog/datadog-agent/comp/snmptraps/config"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/oidresolver"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/packet"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+const telemetryRuleMatches = "datadog.snmp_traps.rule_matches"
+
+// trapRule converts the traps matching it into an event and/or a service check
+type trapRule struct {
+	name      string
+	trapOID   string
+	variables []variableMatcher
+
+	eventTitle *template.Template
+	eventText  *template.Template
+	alertType  event.AlertType
+	priority   event.Priority
+
+	serviceCheckName    *template.Template
+	serviceCheckMessage *template.Template
+	serviceCheckStatus  servicecheck.ServiceCheckStatus
+}
+
+type variableMatcher struct {
+	name    string
+	pattern *regexp.Regexp
+}
+
+func newTrapRules(confs []config.TrapRule) ([]trapRule, error) {
+	var rules []trapRule
+	for _, conf := range confs {
+		rule := trapRule{
+			name:      conf.Name,
+			trapOID:   oidresolver.NormalizeOID(conf.TrapOID),
+			alertType: event.AlertTypeInfo,
+			priority:  event.PriorityNormal,
+		}
+		for _, variable := range conf.Variables {
+			pattern, err := regexp.Compile(variable.Value)
+			if err != nil {
+				return nil, fmt.Errorf("trap rule %s: %w", conf.Name, err)
+			}
+			rule.variables = append(rule.variables, variableMatcher{name: oidresolver.NormalizeOID(variable.Name), pattern: pattern})
+		}
+
+		var err error
+		if conf.Event.Title != "" {
+			if rule.eventTitle, err = parseTemplate(conf.Name, conf.Event.Title); err != nil {
+				return nil, err
+			}
+			if rule.eventText, err = parseTemplate(conf.Name, conf.Event.Text); err != nil {
+				return nil, err
+			}
+			if conf.Event.AlertType != "" {
+				if rule.alertType, err = event.GetAlertTypeFromString(conf.Event.AlertType); err != nil {
+					return nil, err
+				}
+			}
+			if conf.Event.Priority != "" {
+				if rule.priority, err = event.GetEventPriorityFromString(conf.Event.Priority); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if conf.ServiceCheck.Name != "" {
+			if rule.serviceCheckName, err = parseTemplate(conf.Name, conf.ServiceCheck.Name); err != nil {
+				return nil, err
+			}
+			if rule.serviceCheckMessage, err = parseTemplate(conf.Name, conf.ServiceCheck.Message); err != nil {
+				return nil, err
+			}
+			if rule.serviceCheckStatus, err = config.ParseServiceCheckStatus(conf.ServiceCheck.Status); err != nil {
+				return nil, err
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseTemplate(name string, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("trap rule %s: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+// matches returns whether the rule applies to a trap, given its fields
+func (r *trapRule) matches(fields map[string]interface{}) bool {
+	if fields["snmpTrapOID"] != r.trapOID {
+		return false
+	}
+	for _, variable := range r.variables {
+		value, ok := fields[variable.name]
+		if !ok || !variable.pattern.MatchString(fmt.Sprint(value)) {
+			return false
+		}
+	}
+	return true
+}
+
+// ruleFields returns the fields rules are matched against and rendered with: the fields
+// of the formatted trap, including the variables resolved by name, the variables by OID,
+// and the device address and namespace.
+func ruleFields(packet *packet.SnmpPacket, formattedTrap map[string]interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, len(formattedTrap)+4)
+	for key, value := range formattedTrap {
+		fields[key] = value
+	}
+	if variables, ok := formattedTrap["variables"].([]trapVariable); ok {
+		for _, variable := range variables {
+			if _, exists := fields[variable.OID]; !exists {
+				fields[variable.OID] = variable.Value
+			}
+		}
+	}
+	fields["snmp_device"] = packet.Addr.IP.String()
+	fields["device_namespace"] = packet.Namespace
+	return fields
+}
+
+// applyRules sends the events and service checks of the rules matching a formatted trap
+func (f JSONFormatter) applyRules(packet *packet.SnmpPacket, formattedTrap map[string]interface{}) {
+	if len(f.rules) == 0 {
+		return
+	}
+	fields := ruleFields(packet, formattedTrap)
+	for i := range f.rules {
+		rule := &f.rules[i]
+		if !rule.matches(fields) {
+			continue
+		}
+		tags := append(packet.GetTags(), "snmp_trap_rule:"+rule.name)
+		f.sender.Count(telemetryRuleMatches, 1, "", tags)
+
+		if rule.eventTitle != nil {
+			f.sender.Event(event.Event{
+				Title:          f.render(rule.eventTitle, fields),
+				Text:           f.render(rule.eventText, fields),
+				Ts:             packet.Timestamp / 1000,
+				Priority:       rule.priority,
+				Tags:           tags,
+				AlertType:      rule.alertType,
+				SourceTypeName: ddsource,
+			})
+		}
+		if rule.serviceCheckName != nil {
+			f.sender.ServiceCheck(f.render(rule.serviceCheckName, fields), rule.serviceCheckStatus, "", tags, f.render(rule.serviceCheckMessage, fields))
+		}
+	}
+}
+
+func (f JSONFormatter) render(tmpl *template.Template, fields map[string]interface{}) string {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, fields); err != nil {
+		f.logger.Debugf("unable to render trap rule %s template: %s", tmpl.Name(), err)
+	}
+	return b.String()
+}
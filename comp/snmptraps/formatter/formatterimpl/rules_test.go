@@ -0,0 +1,78 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package formatterimpl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/comp/snmptraps/config"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/formatter"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/packet"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestTrapRules(t *testing.T) {
+	var mockSender *mocksender.MockSender
+	formatter := fxutil.Test[formatter.Component](t,
+		testOptions,
+		fx.Replace(&config.TrapsConfig{Enabled: true, Rules: []config.TrapRule{
+			{
+				Name:    "link-up",
+				TrapOID: ".1.3.6.1.6.3.1.1.5.4",
+				Variables: []config.VariableMatch{
+					{Name: "ifIndex", Value: "^9001$"},
+					{Name: "ifAdminStatus", Value: "down"},
+					// variables can also be matched by OID, against their raw value
+					{Name: ".1.3.6.1.2.1.2.2.1.8", Value: "^7$"},
+				},
+				Event: config.EventTemplate{
+					Title:     "{{.snmpTrapName}} on {{.snmp_device}}",
+					Text:      "Interface {{.ifIndex}} is {{.ifOperStatus}}",
+					AlertType: "success",
+				},
+				ServiceCheck: config.ServiceCheckTemplate{
+					Name:    "snmp_traps.interface",
+					Status:  "ok",
+					Message: "{{.ifIndex}} up",
+				},
+			},
+			{
+				Name:    "other-interface",
+				TrapOID: "1.3.6.1.6.3.1.1.5.4",
+				Variables: []config.VariableMatch{
+					{Name: "ifIndex", Value: "^1$"},
+				},
+				ServiceCheck: config.ServiceCheckTemplate{Name: "snmp_traps.other", Status: "critical"},
+			},
+		}}),
+		fx.Populate(&mockSender),
+	)
+
+	p := packet.CreateTestPacket(LinkUpExampleV2Trap)
+	p.Timestamp = 1700000000000
+	_, err := formatter.FormatPacket(p)
+	require.NoError(t, err)
+
+	tags := []string{"snmp_version:2", "device_namespace:totoro", "snmp_device:127.0.0.1", "snmp_trap_rule:link-up"}
+	mockSender.AssertEvent(t, event.Event{
+		Title:          "linkUp on 127.0.0.1",
+		Text:           "Interface 9001 is lowerLayerDown",
+		Ts:             1700000000,
+		Priority:       event.PriorityNormal,
+		Tags:           tags,
+		AlertType:      event.AlertTypeSuccess,
+		SourceTypeName: "snmp-traps",
+	}, 0)
+	mockSender.AssertServiceCheck(t, "snmp_traps.interface", servicecheck.ServiceCheckOK, "", tags, "9001 up")
+	mockSender.AssertMetric(t, "Count", "datadog.snmp_traps.rule_matches", 1, "", tags)
+	mockSender.AssertNotCalled(t, "ServiceCheck", "snmp_traps.other", servicecheck.ServiceCheckCritical, "", mocksender.MatchTagsContains([]string{"snmp_trap_rule:other-interface"}), "")
+}
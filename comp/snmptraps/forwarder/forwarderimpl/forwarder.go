@@ -34,13 +34,15 @@ func Module() fxutil.Module {
 // trapForwarder consumes SNMP packets, formats traps and send them as EventPlatformEvents
 // The trapForwarder is an intermediate step between the listener and the epforwarder in order to limit the processing of the listener
 // to the minimum. The forwarder process payloads received by the listener via the trapsIn channel, formats them and finally
-// give them to the epforwarder for sending it to Datadog.
+// give them to the epforwarder for sending it to Datadog. Traps are also handed to the relay, if downstream managers are configured.
 type trapForwarder struct {
 	trapsIn   packet.PacketsChannel
 	formatter formatter.Component
 	sender    sender.Sender
 	stopChan  chan struct{}
 	logger    log.Component
+	// relay is nil if no downstream manager is configured
+	relay *trapRelay
 }
 
 type dependencies struct {
@@ -58,14 +60,19 @@ func newTrapForwarder(lc fx.Lifecycle, dep dependencies) (forwarder.Component, e
 	if err != nil {
 		return nil, err
 	}
+	conf := dep.Config.Get()
+	relay, err := newTrapRelay(conf, sender, dep.Logger)
+	if err != nil {
+		return nil, err
+	}
 	tf := &trapForwarder{
 		trapsIn:   dep.Listener.Packets(),
 		formatter: dep.Formatter,
 		sender:    sender,
 		stopChan:  make(chan struct{}, 1),
 		logger:    dep.Logger,
+		relay:     relay,
 	}
-	conf := dep.Config.Get()
 	if conf.Enabled {
 		lc.Append(fx.Hook{
 			OnStart: func(_ context.Context) error {
@@ -85,6 +92,9 @@ func newTrapForwarder(lc fx.Lifecycle, dep dependencies) (forwarder.Component, e
 // Start the TrapForwarder instance. Need to Stop it manually.
 func (tf *trapForwarder) Start() {
 	tf.logger.Info("Starting TrapForwarder")
+	if tf.relay != nil {
+		tf.relay.Start()
+	}
 	go tf.run()
 }
 
@@ -92,6 +102,9 @@ func (tf *trapForwarder) Start() {
 func (tf *trapForwarder) Stop() {
 	select {
 	case tf.stopChan <- struct{}{}:
+		if tf.relay != nil {
+			tf.relay.Stop()
+		}
 	default:
 		tf.logger.Warn("TrapForwarder stopped twice.")
 	}
@@ -114,6 +127,9 @@ func (tf *trapForwarder) run() {
 }
 
 func (tf *trapForwarder) sendTrap(packet *packet.SnmpPacket) {
+	if tf.relay != nil {
+		tf.relay.Relay(packet)
+	}
 	data, err := tf.formatter.FormatPacket(packet)
 	if err != nil {
 		tf.logger.Errorf("failed to format packet: %s", err)
@@ -0,0 +1,251 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package forwarderimpl
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gosnmp/gosnmp"
+
+	log "github.com/DataDog/datadog-agent/comp/core/log/def"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/config"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/oidresolver"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/packet"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+)
+
+const (
+	sysUpTimeInstanceOID  = "1.3.6.1.2.1.1.3.0"
+	snmpTrapOID           = "1.3.6.1.6.3.1.1.4.1.0"
+	snmpTrapEnterpriseOID = "1.3.6.1.6.3.1.1.4.3.0"
+	snmpTrapAddressOID    = "1.3.6.1.6.3.18.1.3.0"
+	snmpTrapCommunityOID  = "1.3.6.1.6.3.18.1.4.0"
+	// snmpTrapsOID is the prefix of the generic traps OIDs
+	snmpTrapsOID = "1.3.6.1.6.3.1.1.5"
+
+	telemetryRelayed      = "datadog.snmp_traps.relayed"
+	telemetryRelayErrors  = "datadog.snmp_traps.relay_errors"
+	telemetryRelayDropped = "datadog.snmp_traps.relay_dropped"
+)
+
+// trapRelay re-emits the received traps to downstream SNMP managers. Traps are sent from
+// a dedicated goroutine so that slow or unreachable managers don't hold back the forwarder;
+// traps are dropped when the relay queue is full.
+type trapRelay struct {
+	managers []*manager
+	trapsIn  packet.PacketsChannel
+	stopChan chan struct{}
+	stopOnce sync.Once
+	sender   sender.Sender
+	logger   log.Component
+}
+
+type manager struct {
+	address string
+	params  *gosnmp.GoSNMP
+}
+
+// newTrapRelay returns a relay to the configured managers, or nil if there are none
+func newTrapRelay(conf *config.TrapsConfig, sender sender.Sender, logger log.Component) (*trapRelay, error) {
+	if len(conf.Managers) == 0 {
+		return nil, nil
+	}
+	relay := &trapRelay{
+		trapsIn:  make(packet.PacketsChannel, conf.GetPacketChannelSize()),
+		stopChan: make(chan struct{}),
+		sender:   sender,
+		logger:   logger,
+	}
+	for _, managerConf := range conf.Managers {
+		params, err := conf.BuildManagerSNMPParams(managerConf, logger)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manager %s: %w", managerConf.Host, err)
+		}
+		relay.managers = append(relay.managers, &manager{
+			address: net.JoinHostPort(managerConf.Host, strconv.Itoa(int(managerConf.Port))),
+			params:  params,
+		})
+	}
+	return relay, nil
+}
+
+// Start connects to the managers and starts relaying traps
+func (r *trapRelay) Start() {
+	for _, m := range r.managers {
+		if err := m.params.Connect(); err != nil {
+			r.logger.Errorf("Failed to connect to SNMP manager %s, traps won't be relayed to it: %s", m.address, err)
+		}
+	}
+	go r.run()
+}
+
+// Stop stops relaying traps
+func (r *trapRelay) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+		for _, m := range r.managers {
+			if m.params.Conn != nil {
+				m.params.Conn.Close()
+			}
+		}
+	})
+}
+
+// Relay queues a trap to be re-emitted to the managers, without blocking
+func (r *trapRelay) Relay(packet *packet.SnmpPacket) {
+	select {
+	case r.trapsIn <- packet:
+	default:
+		r.sender.Count(telemetryRelayDropped, 1, "", packet.GetTags())
+	}
+}
+
+func (r *trapRelay) run() {
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case packet := <-r.trapsIn:
+			for _, m := range r.managers {
+				r.send(m, packet)
+			}
+		}
+	}
+}
+
+func (r *trapRelay) send(m *manager, packet *packet.SnmpPacket) {
+	tags := append(packet.GetTags(), "snmp_manager:"+m.address)
+	if m.params.Conn == nil {
+		r.sender.Count(telemetryRelayErrors, 1, "", tags)
+		return
+	}
+
+	var trap gosnmp.SnmpTrap
+	var err error
+	if m.params.Version == gosnmp.Version1 {
+		trap, err = v1Trap(packet)
+	} else {
+		trap = v2Trap(packet)
+	}
+	if err == nil {
+		_, err = m.params.SendTrap(trap)
+	}
+	if err != nil {
+		r.logger.Debugf("Failed to relay trap from %s to SNMP manager %s: %s", packet.Addr.IP, m.address, err)
+		r.sender.Count(telemetryRelayErrors, 1, "", tags)
+		return
+	}
+	r.sender.Count(telemetryRelayed, 1, "", tags)
+}
+
+// v2Trap returns the variables of a trap as an SNMPv2 notification, translating
+// SNMPv1 traps as described in RFC 3584 section 3.1.
+func v2Trap(packet *packet.SnmpPacket) gosnmp.SnmpTrap {
+	content := packet.Content
+	if content.Version != gosnmp.Version1 {
+		return gosnmp.SnmpTrap{Variables: content.Variables}
+	}
+
+	enterprise := oidresolver.NormalizeOID(content.Enterprise)
+	var trapOID string
+	if content.GenericTrap == 6 {
+		trapOID = fmt.Sprintf("%s.0.%d", enterprise, content.SpecificTrap)
+	} else {
+		trapOID = fmt.Sprintf("%s.%d", snmpTrapsOID, content.GenericTrap+1)
+	}
+
+	variables := make([]gosnmp.SnmpPDU, 0, len(content.Variables)+4)
+	variables = append(variables,
+		gosnmp.SnmpPDU{Name: sysUpTimeInstanceOID, Type: gosnmp.TimeTicks, Value: uint32(content.Timestamp)},
+		gosnmp.SnmpPDU{Name: snmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: trapOID},
+	)
+	variables = append(variables, content.Variables...)
+	if content.AgentAddress != "" {
+		variables = append(variables, gosnmp.SnmpPDU{Name: snmpTrapAddressOID, Type: gosnmp.IPAddress, Value: content.AgentAddress})
+	}
+	variables = append(variables, gosnmp.SnmpPDU{Name: snmpTrapEnterpriseOID, Type: gosnmp.ObjectIdentifier, Value: enterprise})
+	return gosnmp.SnmpTrap{Variables: variables}
+}
+
+// v1Trap returns a trap as an SNMPv1 trap, translating SNMPv2 notifications as described
+// in RFC 3584 section 3.2. Counter64 variables, which can't be represented in SNMPv1, are dropped.
+func v1Trap(packet *packet.SnmpPacket) (gosnmp.SnmpTrap, error) {
+	content := packet.Content
+	if content.Version == gosnmp.Version1 {
+		trap := content.SnmpTrap
+		trap.Variables = content.Variables
+		return trap, nil
+	}
+
+	if len(content.Variables) < 2 {
+		return gosnmp.SnmpTrap{}, fmt.Errorf("expected at least 2 variables, got %d", len(content.Variables))
+	}
+	uptime, ok := content.Variables[0].Value.(uint32)
+	if !ok || oidresolver.NormalizeOID(content.Variables[0].Name) != sysUpTimeInstanceOID {
+		return gosnmp.SnmpTrap{}, fmt.Errorf("expected %s as first variable", sysUpTimeInstanceOID)
+	}
+	if oidresolver.NormalizeOID(content.Variables[1].Name) != snmpTrapOID {
+		return gosnmp.SnmpTrap{}, fmt.Errorf("expected %s as second variable", snmpTrapOID)
+	}
+	var trapOID string
+	switch value := content.Variables[1].Value.(type) {
+	case string:
+		trapOID = oidresolver.NormalizeOID(value)
+	case []byte:
+		trapOID = oidresolver.NormalizeOID(string(value))
+	default:
+		return gosnmp.SnmpTrap{}, fmt.Errorf("expected snmpTrapOID to be a string (got %v of type %T)", value, value)
+	}
+
+	trap := gosnmp.SnmpTrap{Timestamp: uint(uptime)}
+	var enterprise string
+	for _, variable := range content.Variables[2:] {
+		switch oidresolver.NormalizeOID(variable.Name) {
+		case snmpTrapAddressOID:
+			trap.AgentAddress = fmt.Sprint(variable.Value)
+		case snmpTrapEnterpriseOID:
+			enterprise = oidresolver.NormalizeOID(fmt.Sprint(variable.Value))
+		case snmpTrapCommunityOID:
+		default:
+			if variable.Type != gosnmp.Counter64 {
+				trap.Variables = append(trap.Variables, variable)
+			}
+		}
+	}
+
+	lastDot := strings.LastIndex(trapOID, ".")
+	if lastDot < 0 {
+		return gosnmp.SnmpTrap{}, fmt.Errorf("invalid snmpTrapOID %s", trapOID)
+	}
+	subID, err := strconv.Atoi(trapOID[lastDot+1:])
+	if err != nil {
+		return gosnmp.SnmpTrap{}, fmt.Errorf("invalid snmpTrapOID %s: %w", trapOID, err)
+	}
+	if trapOID[:lastDot] == snmpTrapsOID && subID >= 1 && subID <= 6 {
+		// generic trap
+		trap.GenericTrap = subID - 1
+		trap.Enterprise = enterprise
+		if trap.Enterprise == "" {
+			trap.Enterprise = snmpTrapsOID
+		}
+	} else {
+		trap.GenericTrap = 6
+		trap.SpecificTrap = subID
+		trap.Enterprise = strings.TrimSuffix(trapOID[:lastDot], ".0")
+	}
+
+	if trap.AgentAddress == "" {
+		trap.AgentAddress = "0.0.0.0"
+		if ip := packet.Addr.IP.To4(); ip != nil {
+			trap.AgentAddress = ip.String()
+		}
+	}
+	return trap, nil
+}
@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+package forwarderimpl
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/fx"
+
+	"github.com/DataDog/datadog-agent/comp/snmptraps/config"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/config/configimpl"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/formatter/formatterimpl"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/forwarder"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/listener"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/listener/listenerimpl"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/packet"
+	"github.com/DataDog/datadog-agent/comp/snmptraps/senderhelper"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	ndmtestutils "github.com/DataDog/datadog-agent/pkg/networkdevice/testutils"
+	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
+)
+
+func TestV2TrapFromV1GenericTrap(t *testing.T) {
+	trap := v2Trap(packet.CreateTestV1GenericPacket())
+
+	require.Len(t, trap.Variables, 8)
+	assert.Equal(t, gosnmp.SnmpPDU{Name: sysUpTimeInstanceOID, Type: gosnmp.TimeTicks, Value: uint32(1000)}, trap.Variables[0])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: snmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: "1.3.6.1.6.3.1.1.5.3"}, trap.Variables[1])
+	assert.Equal(t, packet.LinkDownv1GenericTrap.Variables, trap.Variables[2:6])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: snmpTrapAddressOID, Type: gosnmp.IPAddress, Value: "127.0.0.1"}, trap.Variables[6])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: snmpTrapEnterpriseOID, Type: gosnmp.ObjectIdentifier, Value: "1.3.6.1.6.3.1.1.5"}, trap.Variables[7])
+}
+
+func TestV2TrapFromV1SpecificTrap(t *testing.T) {
+	trap := v2Trap(packet.CreateTestV1SpecificPacket())
+
+	assert.Equal(t, gosnmp.SnmpPDU{Name: snmpTrapOID, Type: gosnmp.ObjectIdentifier, Value: "1.3.6.1.2.1.118.0.2"}, trap.Variables[1])
+}
+
+func TestV1TrapFromV2GenericNotification(t *testing.T) {
+	trap, err := v1Trap(packet.CreateTestPacket(gosnmp.SnmpTrap{
+		Variables: []gosnmp.SnmpPDU{
+			{Name: "1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1000)},
+			{Name: "1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.6.3.1.1.5.4"},
+			{Name: "1.3.6.1.2.1.2.2.1.1", Type: gosnmp.Integer, Value: 9001},
+			{Name: "1.3.6.1.6.3.1.1.4.3.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.9"},
+		},
+	}))
+	require.NoError(t, err)
+
+	assert.Equal(t, gosnmp.SnmpTrap{
+		AgentAddress: "127.0.0.1",
+		Enterprise:   "1.3.6.1.4.1.9",
+		GenericTrap:  3,
+		Timestamp:    1000,
+		Variables:    []gosnmp.SnmpPDU{{Name: "1.3.6.1.2.1.2.2.1.1", Type: gosnmp.Integer, Value: 9001}},
+	}, trap)
+}
+
+func TestV1TrapFromV2SpecificNotification(t *testing.T) {
+	notification := packet.NetSNMPExampleHeartbeatNotification
+	notification.Variables = append(append([]gosnmp.SnmpPDU{}, notification.Variables...),
+		gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.31.1.1.1.6.1", Type: gosnmp.Counter64, Value: uint64(1)},
+		gosnmp.SnmpPDU{Name: "1.3.6.1.6.3.18.1.3.0", Type: gosnmp.IPAddress, Value: "10.0.0.1"},
+	)
+	trap, err := v1Trap(packet.CreateTestPacket(notification))
+	require.NoError(t, err)
+
+	assert.Equal(t, gosnmp.SnmpTrap{
+		AgentAddress: "10.0.0.1",
+		Enterprise:   "1.3.6.1.4.1.8072.2.3",
+		GenericTrap:  6,
+		SpecificTrap: 1,
+		Timestamp:    1000,
+		// the Counter64 variable is dropped
+		Variables: packet.NetSNMPExampleHeartbeatNotification.Variables[2:],
+	}, trap)
+}
+
+func TestV1TrapFromInvalidNotification(t *testing.T) {
+	_, err := v1Trap(packet.CreateTestPacket(gosnmp.SnmpTrap{
+		Variables: []gosnmp.SnmpPDU{{Name: "1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1000)}},
+	}))
+	assert.EqualError(t, err, "expected at least 2 variables, got 1")
+}
+
+func TestTrapsAreRelayed(t *testing.T) {
+	port, err := ndmtestutils.GetFreePort()
+	require.NoError(t, err)
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(int(port)))
+
+	received := make(chan *gosnmp.SnmpPacket, 1)
+	trapListener := gosnmp.NewTrapListener()
+	trapListener.Params = &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"}
+	trapListener.OnNewTrap = func(p *gosnmp.SnmpPacket, _ *net.UDPAddr) { received <- p }
+	go trapListener.Listen(address) //nolint:errcheck
+	defer trapListener.Close()
+	select {
+	case <-trapListener.Listening():
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "trap listener didn't start")
+	}
+
+	s := fxutil.Test[struct {
+		fx.In
+		Sender    *mocksender.MockSender
+		Listener  listener.MockComponent
+		Forwarder forwarder.Component
+	}](t,
+		configimpl.MockModule(),
+		fx.Replace(&config.TrapsConfig{Enabled: true, Managers: []config.ManagerConfig{
+			{Host: "127.0.0.1", Port: port, CommunityString: "public"},
+		}}),
+		senderhelper.Opts,
+		formatterimpl.MockModule(),
+		listenerimpl.MockModule(),
+		Module(),
+	)
+
+	s.Listener.Send(packet.CreateTestV1GenericPacket())
+
+	select {
+	case p := <-received:
+		assert.Equal(t, gosnmp.Version2c, p.Version)
+		require.Len(t, p.Variables, 8)
+		assert.Equal(t, ".1.3.6.1.6.3.1.1.5.3", p.Variables[1].Value)
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "trap wasn't relayed")
+	}
+	time.Sleep(100 * time.Millisecond)
+	s.Sender.AssertMetric(t, "Count", telemetryRelayed, 1, "", []string{"snmp_version:1", "device_namespace:the_baron", "snmp_device:127.0.0.1", "snmp_manager:" + address})
+}
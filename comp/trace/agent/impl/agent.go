@@ -31,6 +31,8 @@ import (
 	compression "github.com/DataDog/datadog-agent/comp/trace/compression/def"
 	"github.com/DataDog/datadog-agent/comp/trace/config"
 	"github.com/DataDog/datadog-agent/pkg/config/env"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/flare/selfflare"
 	"github.com/DataDog/datadog-agent/pkg/pidfile"
 	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
 	agentrt "github.com/DataDog/datadog-agent/pkg/runtime"
@@ -123,6 +125,7 @@ func NewAgent(deps dependencies) (traceagent.Component, error) {
 	setupShutdown(ctx, deps.Shutdowner, statsdCl)
 
 	prepGoRuntime(tracecfg)
+	setupSelfFlares()
 
 	c.Agent = pkgagent.NewAgent(
 		ctx,
@@ -186,6 +189,15 @@ func prepGoRuntime(tracecfg *tracecfg.AgentConfig) {
 	}
 }
 
+// setupSelfFlares captures a self flare when a panic is caught by the trace-agent
+func setupSelfFlares() {
+	cfg := selfflare.ReadConfig(pkgconfigsetup.Datadog())
+	if !cfg.TriggerEnabled(selfflare.TriggerPanic) {
+		return
+	}
+	watchdog.SetPanicHook(selfflare.PanicHook(selfflare.NewStore(cfg.Path, cfg.MaxCount, cfg.MaxSize)))
+}
+
 func start(ag component) error {
 	if ag.params.CPUProfile != "" {
 		f, err := os.Create(ag.params.CPUProfile)
@@ -2595,3 +2595,35 @@ func TestOnUpdateAPIKeyCallback(t *testing.T) {
 	configC.updateAPIKey("foo", "bar")
 	assert.Equal(t, 1, n)
 }
+
+func TestAdditionalEndpointsRouting(t *testing.T) {
+	overrides := map[string]interface{}{
+		"apm_config.additional_endpoints": map[string][]string{
+			"https://prod.example.com":    {"key1"},
+			"https://billing.example.com": {"key2", "key3"},
+		},
+		"apm_config.additional_endpoints_routing": map[string][]string{
+			"https://billing.example.com": {"service:payments", "env:prod"},
+			"https://unknown.example.com": {"env:prod"},
+		},
+	}
+
+	config := fxutil.Test[Component](t, fx.Options(
+		corecomp.MockModule(),
+		fx.Replace(corecomp.MockParams{Overrides: overrides}),
+		MockModule(),
+	))
+	cfg := config.Object()
+	require.NotNil(t, cfg)
+
+	require.Len(t, cfg.Endpoints, 4)
+	assert.Empty(t, cfg.Endpoints[0].RoutingRules)
+	for _, e := range cfg.Endpoints[1:] {
+		switch e.Host {
+		case "https://billing.example.com":
+			assert.Equal(t, []string{"service:payments", "env:prod"}, e.RoutingRules)
+		default:
+			assert.Empty(t, e.RoutingRules)
+		}
+	}
+}
@@ -145,6 +145,28 @@ func appendEndpoints(endpoints []*config.Endpoint, cfgKey string) []*config.Endp
 	return endpoints
 }
 
+// applyRoutingRules sets the routing rules found at the given cfgKey on the
+// additional endpoints. The format for cfgKey should be a map which has the URL
+// of an additional endpoint as a key and one or more rules as an array value.
+func applyRoutingRules(endpoints []*config.Endpoint, cfgKey string) {
+	if !pkgconfigsetup.Datadog().IsSet(cfgKey) {
+		return
+	}
+	for url, rules := range pkgconfigsetup.Datadog().GetStringMapStringSlice(cfgKey) {
+		found := false
+		// the main endpoint is never routed
+		for _, e := range endpoints[1:] {
+			if e.Host == url {
+				e.RoutingRules = rules
+				found = true
+			}
+		}
+		if !found {
+			log.Warnf("'%s' contains %s which is not an additional endpoint, ignoring its rules", cfgKey, url)
+		}
+	}
+}
+
 func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error {
 	if len(c.Endpoints) == 0 {
 		c.Endpoints = []*config.Endpoint{{}}
@@ -170,6 +192,7 @@ func applyDatadogConfig(c *config.AgentConfig, core corecompcfg.Component) error
 		c.Endpoints[0].Host = utils.GetMainEndpoint(pkgconfigsetup.Datadog(), apiEndpointPrefix, "apm_config.apm_dd_url")
 	}
 	c.Endpoints = appendEndpoints(c.Endpoints, "apm_config.additional_endpoints")
+	applyRoutingRules(c.Endpoints, "apm_config.additional_endpoints_routing")
 
 	if core.IsSet("proxy.no_proxy") {
 		proxyList := core.GetStringSlice("proxy.no_proxy")
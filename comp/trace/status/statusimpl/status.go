@@ -91,12 +91,17 @@ func (s statusProvider) getStatusInfo() map[string]interface{} {
 	return stats
 }
 
+// getDebugVars returns the expvars of the trace-agent
+func (s statusProvider) getDebugVars() ([]byte, error) {
+	c := client()
+	url := fmt.Sprintf("http://localhost:%d/debug/vars", s.Config.GetInt("apm_config.debug.port"))
+	return apiutil.DoGet(c, url, apiutil.CloseConnection)
+}
+
 func (s statusProvider) populateStatus() map[string]interface{} {
 	port := s.Config.GetInt("apm_config.debug.port")
 
-	c := client()
-	url := fmt.Sprintf("http://localhost:%d/debug/vars", port)
-	resp, err := apiutil.DoGet(c, url, apiutil.CloseConnection)
+	resp, err := s.getDebugVars()
 	if err != nil {
 		return map[string]interface{}{
 			"port":  port,
@@ -132,3 +137,82 @@ func (s statusProvider) Text(_ bool, buffer io.Writer) error {
 func (s statusProvider) HTML(_ bool, buffer io.Writer) error {
 	return status.RenderHTML(templatesFS, "traceagentHTML.tmpl", buffer, s.getStatusInfo())
 }
+
+// receiverStats is the part of the trace-agent expvars exposed as OpenMetrics
+type receiverStats struct {
+	Receiver []struct {
+		Lang            string
+		LangVersion     string
+		Interpreter     string
+		TracerVersion   string
+		EndpointVersion string
+		Service         string
+		TracesReceived  float64
+		TracesFiltered  float64
+		TracesBytes     float64
+		SpansReceived   float64
+		SpansDropped    float64
+		SpansFiltered   float64
+		PayloadAccepted float64
+		PayloadRefused  float64
+	} `json:"receiver"`
+}
+
+// OpenMetrics returns the receiver stats of the trace-agent for the previous
+// minute, as gauges since they are reset every minute
+func (s statusProvider) OpenMetrics() ([]*status.MetricFamily, error) {
+	running := status.NewGauge("apm_running", "Whether the trace-agent is running and reachable.")
+	resp, err := s.getDebugVars()
+	if err != nil {
+		running.Add(0)
+		return []*status.MetricFamily{running}, nil
+	}
+	running.Add(1)
+	return receiverMetricFamilies(running, resp)
+}
+
+func receiverMetricFamilies(running *status.MetricFamily, debugVars []byte) ([]*status.MetricFamily, error) {
+	var stats receiverStats
+	if err := json.Unmarshal(debugVars, &stats); err != nil {
+		return nil, err
+	}
+
+	tracesReceived := status.NewGauge("apm_receiver_traces_received", "Number of traces received in the previous minute.")
+	tracesFiltered := status.NewGauge("apm_receiver_traces_filtered", "Number of traces filtered in the previous minute.")
+	tracesBytes := status.NewGauge("apm_receiver_traces_bytes", "Number of bytes of traces received in the previous minute.")
+	spansReceived := status.NewGauge("apm_receiver_spans_received", "Number of spans received in the previous minute.")
+	spansDropped := status.NewGauge("apm_receiver_spans_dropped", "Number of spans dropped in the previous minute.")
+	spansFiltered := status.NewGauge("apm_receiver_spans_filtered", "Number of spans filtered in the previous minute.")
+	payloadsAccepted := status.NewGauge("apm_receiver_payloads_accepted", "Number of payloads accepted in the previous minute.")
+	payloadsRefused := status.NewGauge("apm_receiver_payloads_refused", "Number of payloads refused in the previous minute.")
+	for _, ts := range stats.Receiver {
+		labels := []string{
+			"lang", ts.Lang,
+			"lang_version", ts.LangVersion,
+			"interpreter", ts.Interpreter,
+			"tracer_version", ts.TracerVersion,
+			"endpoint_version", ts.EndpointVersion,
+			"service", ts.Service,
+		}
+		tracesReceived.Add(ts.TracesReceived, labels...)
+		tracesFiltered.Add(ts.TracesFiltered, labels...)
+		tracesBytes.Add(ts.TracesBytes, labels...)
+		spansReceived.Add(ts.SpansReceived, labels...)
+		spansDropped.Add(ts.SpansDropped, labels...)
+		spansFiltered.Add(ts.SpansFiltered, labels...)
+		payloadsAccepted.Add(ts.PayloadAccepted, labels...)
+		payloadsRefused.Add(ts.PayloadRefused, labels...)
+	}
+
+	return []*status.MetricFamily{
+		running,
+		tracesReceived,
+		tracesFiltered,
+		tracesBytes,
+		spansReceived,
+		spansDropped,
+		spansFiltered,
+		payloadsAccepted,
+		payloadsRefused,
+	}, nil
+}
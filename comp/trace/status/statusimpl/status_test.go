@@ -13,6 +13,7 @@ import (
 	"go.uber.org/fx"
 
 	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/core/status"
 	"github.com/DataDog/datadog-agent/pkg/util/fxutil"
 )
 
@@ -51,6 +52,16 @@ func TestStatusOut(t *testing.T) {
 
 			assert.NotEmpty(t, b.String())
 		}},
+		{"OpenMetrics", func(t *testing.T) {
+			families, err := headerProvider.(status.OpenMetricsProvider).OpenMetrics()
+			assert.NoError(t, err)
+
+			b := new(bytes.Buffer)
+			assert.NoError(t, status.RenderOpenMetrics(b, families))
+
+			// the trace-agent is not running
+			assert.Contains(t, b.String(), "datadog_agent_apm_running 0\n")
+		}},
 	}
 
 	for _, test := range tests {
@@ -59,3 +70,28 @@ func TestStatusOut(t *testing.T) {
 		})
 	}
 }
+
+func TestReceiverMetricFamilies(t *testing.T) {
+	debugVars := `{
+		"pid": 1234,
+		"receiver": [
+			{"Lang": "python", "LangVersion": "3.12", "Interpreter": "CPython", "TracerVersion": "2.9.0", "EndpointVersion": "v0.4", "Service": "web", "TracesReceived": 10, "TracesBytes": 2048, "SpansReceived": 42, "PayloadAccepted": 3},
+			{"Lang": "go", "EndpointVersion": "v0.5", "Service": "api", "TracesReceived": 1, "SpansDropped": 2, "PayloadRefused": 1}
+		]
+	}`
+	families, err := receiverMetricFamilies(status.NewGauge("apm_running", ""), []byte(debugVars))
+	assert.NoError(t, err)
+
+	b := new(bytes.Buffer)
+	assert.NoError(t, status.RenderOpenMetrics(b, families))
+
+	for _, line := range []string{
+		`datadog_agent_apm_receiver_traces_received{endpoint_version="v0.4",interpreter="CPython",lang="python",lang_version="3.12",service="web",tracer_version="2.9.0"} 10`,
+		`datadog_agent_apm_receiver_traces_bytes{endpoint_version="v0.4",interpreter="CPython",lang="python",lang_version="3.12",service="web",tracer_version="2.9.0"} 2048`,
+		`datadog_agent_apm_receiver_spans_received{endpoint_version="v0.4",interpreter="CPython",lang="python",lang_version="3.12",service="web",tracer_version="2.9.0"} 42`,
+		`datadog_agent_apm_receiver_spans_dropped{endpoint_version="v0.5",interpreter="",lang="go",lang_version="",service="api",tracer_version=""} 2`,
+		`datadog_agent_apm_receiver_payloads_refused{endpoint_version="v0.5",interpreter="",lang="go",lang_version="",service="api",tracer_version=""} 1`,
+	} {
+		assert.Contains(t, b.String(), line+"\n")
+	}
+}
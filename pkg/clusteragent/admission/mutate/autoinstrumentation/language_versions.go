@@ -291,3 +291,10 @@ func (i libInfo) libRequirement(v version) (libRequirement, bool) {
 		volumes:        []volume{sourceVolume},
 	}, true
 }
+
+// IsSupportedLanguage returns whether libraries can be injected for the given
+// language, as used in the admission.datadoghq.com/<language>-lib.version
+// annotation.
+func IsSupportedLanguage(lang string) bool {
+	return language(lang).isSupported()
+}
@@ -0,0 +1,335 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+// Package podconfig implements the webhook that validates the Datadog
+// configuration of a pod (autodiscovery annotations, library injection
+// annotations and unified service tags labels) at creation time.
+package podconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	admiv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/common/utils"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/common"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/metrics"
+	"github.com/DataDog/datadog-agent/pkg/clusteragent/admission/mutate/autoinstrumentation"
+	validatecommon "github.com/DataDog/datadog-agent/pkg/clusteragent/admission/validate/common"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	webhookName = "pod_config_validation"
+
+	// ModeWarn admits the misconfigured pods and returns the issues found
+	// as admission warnings.
+	ModeWarn = "warn"
+	// ModeReject refuses the misconfigured pods.
+	ModeReject = "reject"
+
+	checkNamesSuffix = ".check_names"
+
+	admissionAnnotationPrefix = "admission.datadoghq.com/"
+	tagsLabelPrefix           = "tags.datadoghq.com/"
+
+	libVersionSuffix     = "-lib.version"
+	libConfigV1Suffix    = "-lib.config.v1"
+	libCustomImageSuffix = "-lib.custom-image"
+
+	// allLanguages is used in place of a language to inject all libraries
+	allLanguages = "all"
+)
+
+// unifiedServiceTags are the tags that can be set with the
+// tags.datadoghq.com/<tag> and tags.datadoghq.com/<container>.<tag> labels.
+var unifiedServiceTags = []string{"env", "service", "version"}
+
+// libVersionRegex matches the valid library versions, which are used as image
+// tags. See https://docs.docker.com/reference/cli/docker/image/tag/
+var libVersionRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127}$`)
+
+// Webhook is the webhook that validates the Datadog configuration of pods
+type Webhook struct {
+	name       string
+	isEnabled  bool
+	endpoint   string
+	resources  []string
+	operations []admissionregistrationv1.OperationType
+	mode       string
+}
+
+// NewWebhook returns a new Webhook
+func NewWebhook() *Webhook {
+	mode := strings.ToLower(pkgconfigsetup.Datadog().GetString("admission_controller.pod_config_validation.mode"))
+	if mode != ModeWarn && mode != ModeReject {
+		log.Warnf("Invalid value %q for admission_controller.pod_config_validation.mode, defaulting to %q", mode, ModeWarn)
+		mode = ModeWarn
+	}
+
+	return &Webhook{
+		name:       webhookName,
+		isEnabled:  pkgconfigsetup.Datadog().GetBool("admission_controller.pod_config_validation.enabled"),
+		endpoint:   pkgconfigsetup.Datadog().GetString("admission_controller.pod_config_validation.endpoint"),
+		resources:  []string{"pods"},
+		operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create},
+		mode:       mode,
+	}
+}
+
+// Name returns the name of the webhook
+func (w *Webhook) Name() string {
+	return w.name
+}
+
+// WebhookType returns the type of the webhook
+func (w *Webhook) WebhookType() common.WebhookType {
+	return common.ValidatingWebhook
+}
+
+// IsEnabled returns whether the webhook is enabled
+func (w *Webhook) IsEnabled() bool {
+	return w.isEnabled
+}
+
+// Endpoint returns the endpoint of the webhook
+func (w *Webhook) Endpoint() string {
+	return w.endpoint
+}
+
+// Resources returns the kubernetes resources for which the webhook should
+// be invoked
+func (w *Webhook) Resources() []string {
+	return w.resources
+}
+
+// Operations returns the operations on the resources specified for which
+// the webhook should be invoked
+func (w *Webhook) Operations() []admissionregistrationv1.OperationType {
+	return w.operations
+}
+
+// LabelSelectors returns the label selectors that specify when the webhook
+// should be invoked
+func (w *Webhook) LabelSelectors(useNamespaceSelector bool) (namespaceSelector *metav1.LabelSelector, objectSelector *metav1.LabelSelector) {
+	return common.DefaultLabelSelectors(useNamespaceSelector)
+}
+
+// WebhookFunc returns the function that validates the resources
+func (w *Webhook) WebhookFunc() admission.WebhookFunc {
+	return func(request *admission.Request) *admiv1.AdmissionResponse {
+		var issues []string
+
+		validated, err := validatecommon.Validate(request.Raw, request.Namespace, w.Name(), func(pod *corev1.Pod, _ string, _ dynamic.Interface) (bool, error) {
+			if pod == nil {
+				return false, errors.New(metrics.InvalidInput)
+			}
+
+			issues = validatePod(pod)
+			return len(issues) == 0 || w.mode == ModeWarn, nil
+		}, request.DynamicClient)
+
+		response := common.ValidationResponse(validated, err)
+		if err != nil || len(issues) == 0 {
+			return response
+		}
+
+		response.Warnings = issues
+		if !validated {
+			response.Result = &metav1.Status{
+				Message: fmt.Sprintf("invalid Datadog configuration: %s", strings.Join(issues, "; ")),
+			}
+		}
+
+		return response
+	}
+}
+
+// validatePod returns the list of issues found in the Datadog configuration
+// of the pod, sorted to return stable responses.
+func validatePod(pod *corev1.Pod) []string {
+	containers := containerNames(pod)
+
+	var issues []string
+	for _, err := range validateADAnnotations(pod, containers) {
+		issues = append(issues, err.Error())
+	}
+	for _, err := range validateLibAnnotations(pod.GetAnnotations(), containers) {
+		issues = append(issues, err.Error())
+	}
+	for _, err := range validateTagsLabels(pod.GetLabels(), containers) {
+		issues = append(issues, err.Error())
+	}
+
+	sort.Strings(issues)
+	return issues
+}
+
+// validateADAnnotations parses the autodiscovery annotations the same way the
+// node agent does, and checks that they reference existing containers.
+func validateADAnnotations(pod *corev1.Pod, containers map[string]struct{}) []error {
+	annotations := pod.GetAnnotations()
+	identifiers := make(map[string]struct{}, len(containers))
+
+	var errs []error
+	for name := range containers {
+		adIdentifier := name
+		if customADID, found := utils.ExtractCheckIDFromPodAnnotations(annotations, name); found {
+			adIdentifier = customADID
+		}
+		identifiers[adIdentifier] = struct{}{}
+
+		configs, extractErrs := utils.ExtractTemplatesFromAnnotations(name, annotations, adIdentifier)
+		for _, err := range extractErrs {
+			errs = append(errs, fmt.Errorf("annotations %s%s.* are invalid: %w", utils.KubeAnnotationPrefix, adIdentifier, err))
+		}
+
+		// Templates whose check names, init configs and instances don't have
+		// the same length are discarded without error by the node agent.
+		if _, found := annotations[utils.KubeAnnotationPrefix+adIdentifier+checkNamesSuffix]; found && len(extractErrs) == 0 && !hasCheckConfig(configs) {
+			errs = append(errs, fmt.Errorf("annotations %s%s.* are invalid: check_names, init_configs and instances must have the same number of entries", utils.KubeAnnotationPrefix, adIdentifier))
+		}
+	}
+
+	return append(errs, utils.ValidateAnnotationsMatching(annotations, identifiers, containers)...)
+}
+
+// validateLibAnnotations checks the library injection annotations:
+//   - admission.datadoghq.com/[<container>.]<language>-lib.version
+//   - admission.datadoghq.com/[<container>.]<language>-lib.custom-image
+//   - admission.datadoghq.com/<language>-lib.config.v1
+func validateLibAnnotations(annotations map[string]string, containers map[string]struct{}) []error {
+	var errs []error
+	for key, value := range annotations {
+		if !strings.HasPrefix(key, admissionAnnotationPrefix) {
+			continue
+		}
+
+		name := strings.TrimPrefix(key, admissionAnnotationPrefix)
+
+		var suffix string
+		for _, s := range []string{libVersionSuffix, libConfigV1Suffix, libCustomImageSuffix} {
+			if strings.HasSuffix(name, s) {
+				suffix = s
+				break
+			}
+		}
+		if suffix == "" {
+			continue
+		}
+
+		lang := strings.TrimSuffix(name, suffix)
+		if ctr, l, found := strings.Cut(lang, "."); found && suffix != libConfigV1Suffix {
+			if _, exists := containers[ctr]; !exists {
+				errs = append(errs, fmt.Errorf("annotation %s is invalid: %s doesn't match a container name %v", key, ctr, sortedKeys(containers)))
+			}
+			lang = l
+		}
+
+		if lang == allLanguages {
+			if suffix == libVersionSuffix && value != "latest" {
+				errs = append(errs, fmt.Errorf("annotation %s is invalid: only the latest version is supported when injecting all libraries", key))
+			}
+		} else if !autoinstrumentation.IsSupportedLanguage(lang) {
+			errs = append(errs, fmt.Errorf("annotation %s is invalid: language %q is not supported", key, lang))
+			continue
+		}
+
+		switch suffix {
+		case libVersionSuffix:
+			if !libVersionRegex.MatchString(value) {
+				errs = append(errs, fmt.Errorf("annotation %s is invalid: %q is not a valid library version", key, value))
+			}
+		case libCustomImageSuffix:
+			if value == "" {
+				errs = append(errs, fmt.Errorf("annotation %s is invalid: the image is empty", key))
+			}
+		case libConfigV1Suffix:
+			if !json.Valid([]byte(value)) {
+				errs = append(errs, fmt.Errorf("annotation %s is invalid: the value is not valid JSON", key))
+			}
+		}
+	}
+
+	return errs
+}
+
+// validateTagsLabels checks that the tags.datadoghq.com labels are unified
+// service tags, optionally scoped to an existing container.
+func validateTagsLabels(labels map[string]string, containers map[string]struct{}) []error {
+	var errs []error
+	for key := range labels {
+		if !strings.HasPrefix(key, tagsLabelPrefix) {
+			continue
+		}
+
+		tag := strings.TrimPrefix(key, tagsLabelPrefix)
+		if idx := strings.LastIndex(tag, "."); idx >= 0 {
+			ctr := tag[:idx]
+			if _, exists := containers[ctr]; !exists {
+				errs = append(errs, fmt.Errorf("label %s is invalid: %s doesn't match a container name %v", key, ctr, sortedKeys(containers)))
+			}
+			tag = tag[idx+1:]
+		}
+
+		if !isUnifiedServiceTag(tag) {
+			errs = append(errs, fmt.Errorf("label %s is invalid: %q is not a unified service tag %v", key, tag, unifiedServiceTags))
+		}
+	}
+
+	return errs
+}
+
+func hasCheckConfig(configs []integration.Config) bool {
+	for _, config := range configs {
+		if len(config.Instances) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func isUnifiedServiceTag(tag string) bool {
+	for _, t := range unifiedServiceTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+func containerNames(pod *corev1.Pod) map[string]struct{} {
+	names := make(map[string]struct{}, len(pod.Spec.InitContainers)+len(pod.Spec.Containers))
+	for _, c := range pod.Spec.InitContainers {
+		names[c.Name] = struct{}{}
+	}
+	for _, c := range pod.Spec.Containers {
+		names[c.Name] = struct{}{}
+	}
+	return names
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
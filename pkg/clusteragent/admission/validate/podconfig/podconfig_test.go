@@ -0,0 +1,212 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package podconfig
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/DataDog/datadog-agent/cmd/cluster-agent/admission"
+)
+
+func fakePod(annotations, labels map[string]string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo-pod",
+			Annotations: annotations,
+			Labels:      labels,
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{Name: "init"}},
+			Containers:     []corev1.Container{{Name: "redis"}, {Name: "app"}},
+		},
+	}
+}
+
+func Test_validatePod(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+		wantIssues  int
+		wantContain string
+	}{
+		{
+			name: "valid configuration",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.checks":              `{"redisdb": {"instances": [{"host": "%%host%%"}]}}`,
+				"ad.datadoghq.com/app.logs":                  `[{"source": "java"}]`,
+				"ad.datadoghq.com/tags":                      `{"team": "foo"}`,
+				"admission.datadoghq.com/java-lib.version":   "v1.31.0",
+				"admission.datadoghq.com/app.js-lib.version": "latest",
+				"admission.datadoghq.com/all-lib.version":    "latest",
+				"admission.datadoghq.com/enabled":            "true",
+			},
+			labels: map[string]string{
+				"tags.datadoghq.com/env":         "prod",
+				"tags.datadoghq.com/service":     "foo",
+				"tags.datadoghq.com/app.version": "1.2.3",
+				"app":                            "foo",
+			},
+		},
+		{
+			name: "valid custom check id",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check.id":     "cache",
+				"ad.datadoghq.com/cache.check_names":  `["redisdb"]`,
+				"ad.datadoghq.com/cache.init_configs": `[{}]`,
+				"ad.datadoghq.com/cache.instances":    `[{"host": "%%host%%"}]`,
+			},
+		},
+		{
+			name: "malformed check annotation",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.checks": `{"redisdb": {"instances": [{"host": }]}}`,
+			},
+			wantIssues:  1,
+			wantContain: "ad.datadoghq.com/redis.*",
+		},
+		{
+			name: "mismatching check names and instances",
+			annotations: map[string]string{
+				"ad.datadoghq.com/redis.check_names":  `["redisdb", "tcp_check"]`,
+				"ad.datadoghq.com/redis.init_configs": `[{}]`,
+				"ad.datadoghq.com/redis.instances":    `[{"host": "%%host%%"}]`,
+			},
+			wantIssues:  1,
+			wantContain: "same number of entries",
+		},
+		{
+			name: "unknown container in AD annotation",
+			annotations: map[string]string{
+				"ad.datadoghq.com/nginx.logs": `[{"source": "nginx"}]`,
+			},
+			wantIssues:  1,
+			wantContain: "nginx doesn't match a container identifier",
+		},
+		{
+			name: "unsupported language",
+			annotations: map[string]string{
+				"admission.datadoghq.com/cobol-lib.version": "v1",
+			},
+			wantIssues:  1,
+			wantContain: `language "cobol" is not supported`,
+		},
+		{
+			name: "invalid library version",
+			annotations: map[string]string{
+				"admission.datadoghq.com/python-lib.version": "v2:latest",
+			},
+			wantIssues:  1,
+			wantContain: "is not a valid library version",
+		},
+		{
+			name: "unknown container in library annotation",
+			annotations: map[string]string{
+				"admission.datadoghq.com/nginx.python-lib.version": "v2",
+			},
+			wantIssues:  1,
+			wantContain: "nginx doesn't match a container name",
+		},
+		{
+			name: "inject all libraries with a version",
+			annotations: map[string]string{
+				"admission.datadoghq.com/all-lib.version": "v1",
+			},
+			wantIssues: 1,
+		},
+		{
+			name: "invalid library config",
+			annotations: map[string]string{
+				"admission.datadoghq.com/java-lib.config.v1": `{"version":`,
+			},
+			wantIssues:  1,
+			wantContain: "not valid JSON",
+		},
+		{
+			name: "unknown unified service tag",
+			labels: map[string]string{
+				"tags.datadoghq.com/team": "foo",
+			},
+			wantIssues:  1,
+			wantContain: `"team" is not a unified service tag`,
+		},
+		{
+			name: "unknown container in unified service tag",
+			labels: map[string]string{
+				"tags.datadoghq.com/nginx.env": "prod",
+			},
+			wantIssues:  1,
+			wantContain: "nginx doesn't match a container name",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := validatePod(fakePod(tt.annotations, tt.labels))
+			assert.Len(t, issues, tt.wantIssues, "issues: %v", issues)
+			if tt.wantContain != "" && len(issues) > 0 {
+				assert.Contains(t, issues[0], tt.wantContain)
+			}
+		})
+	}
+}
+
+func TestWebhookFunc(t *testing.T) {
+	invalidPod := fakePod(nil, map[string]string{"tags.datadoghq.com/team": "foo"})
+	validPod := fakePod(nil, map[string]string{"tags.datadoghq.com/env": "prod"})
+
+	tests := []struct {
+		name         string
+		mode         string
+		pod          *corev1.Pod
+		wantAllowed  bool
+		wantWarnings int
+	}{
+		{
+			name:        "valid pod",
+			mode:        ModeReject,
+			pod:         validPod,
+			wantAllowed: true,
+		},
+		{
+			name:         "invalid pod in warn mode",
+			mode:         ModeWarn,
+			pod:          invalidPod,
+			wantAllowed:  true,
+			wantWarnings: 1,
+		},
+		{
+			name:         "invalid pod in reject mode",
+			mode:         ModeReject,
+			pod:          invalidPod,
+			wantAllowed:  false,
+			wantWarnings: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := json.Marshal(tt.pod)
+			require.NoError(t, err)
+
+			w := &Webhook{name: webhookName, mode: tt.mode}
+			response := w.WebhookFunc()(&admission.Request{Raw: raw, Namespace: "default"})
+
+			assert.Equal(t, tt.wantAllowed, response.Allowed)
+			assert.Len(t, response.Warnings, tt.wantWarnings)
+			if !tt.wantAllowed {
+				require.NotNil(t, response.Result)
+				assert.Contains(t, response.Result.Message, "tags.datadoghq.com/team")
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@
 package check
 
 import (
+	"context"
 	"errors"
 	"time"
 
@@ -53,6 +54,27 @@ type Check interface {
 	GetDiagnoses() ([]diagnosis.Diagnosis, error)
 }
 
+// TimeoutCheck is implemented by the checks whose runs can be limited with the
+// `run_timeout` instance setting.
+type TimeoutCheck interface {
+	// RunTimeout returns the maximum duration of a run, 0 if it isn't set
+	RunTimeout() time.Duration
+}
+
+// ContextCheck is implemented by the checks which stop running when the context
+// of their run is cancelled, for instance when they exceed their run timeout.
+type ContextCheck interface {
+	// RunWithContext runs the check until ctx is cancelled
+	RunWithContext(ctx context.Context) error
+}
+
+// InterruptibleCheck is implemented by the checks which can be interrupted while
+// running, like the Python checks.
+type InterruptibleCheck interface {
+	// Interrupt interrupts the current run of the check, if any
+	Interrupt()
+}
+
 // Info is an interface to pull information from types capable to run checks. This is a subsection from the Check
 // interface with only read only method.
 type Info interface {
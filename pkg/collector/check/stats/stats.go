@@ -7,6 +7,7 @@
 package stats
 
 import (
+	"errors"
 	"sync"
 	"time"
 
@@ -24,6 +25,9 @@ const (
 	runCheckSuccessTag = "ok"
 )
 
+// ErrRunTimeout is the error of the check runs which exceeded their run timeout
+var ErrRunTimeout = errors.New("the check run exceeded its run timeout")
+
 // EventPlatformNameTranslations contains human readable translations for event platform event types
 var EventPlatformNameTranslations = map[string]string{
 	"dbm-samples":                "Database Monitoring Query Samples",
@@ -41,6 +45,8 @@ var (
 		[]string{"check_name", "state"}, "Check runs")
 	tlmWarnings = telemetry.NewCounter("checks", "warnings",
 		[]string{"check_name"}, "Check warnings")
+	tlmRunTimeouts = telemetry.NewCounter("checks", "run_timeouts",
+		[]string{"check_name"}, "Check runs which exceeded their run timeout")
 	tlmMetricsSamples = telemetry.NewCounter("checks", "metrics_samples",
 		[]string{"check_name"}, "Metrics count")
 	tlmEvents = telemetry.NewCounter("checks", "events",
@@ -98,8 +104,10 @@ type Stats struct {
 	// converted to a normal check
 	LongRunning              bool
 	Cancelling               bool
+	Stuck                    bool // still running after exceeding its run timeout
 	TotalRuns                uint64
 	TotalErrors              uint64
+	TotalRunTimeouts         uint64
 	TotalWarnings            uint64
 	MetricSamples            int64
 	Events                   int64
@@ -194,6 +202,12 @@ func (cs *Stats) Add(t time.Duration, err error, warnings []error, metricStats S
 			tlmRuns.Inc(cs.CheckName, runCheckFailureTag)
 		}
 		cs.LastError = err.Error()
+		if errors.Is(err, ErrRunTimeout) {
+			cs.TotalRunTimeouts++
+			if cs.Telemetry {
+				tlmRunTimeouts.Inc(cs.CheckName)
+			}
+		}
 	} else {
 		if cs.Telemetry {
 			tlmRuns.Inc(cs.CheckName, runCheckSuccessTag)
@@ -258,6 +272,13 @@ func (cs *Stats) SetStateCancelling() {
 	cs.Cancelling = true
 }
 
+// SetStuck sets whether the check is still running after exceeding its run timeout
+func (cs *Stats) SetStuck(stuck bool) {
+	cs.m.Lock()
+	defer cs.m.Unlock()
+	cs.Stuck = stuck
+}
+
 type aggStats struct {
 	EventPlatformEvents       map[string]interface{}
 	EventPlatformEventsErrors map[string]interface{}
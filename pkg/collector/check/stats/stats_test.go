@@ -6,6 +6,8 @@
 package stats
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -100,6 +102,20 @@ func TestNewStatsStateTelemetryInitialized(t *testing.T) {
 	)
 }
 
+func TestStatsRunTimeout(t *testing.T) {
+	stats := NewStats(newMockCheck())
+
+	stats.Add(time.Second, errors.New("myerror"), nil, NewSenderStats())
+	stats.Add(time.Second, fmt.Errorf("%w of 1s", ErrRunTimeout), nil, NewSenderStats())
+
+	assert.Equal(t, uint64(2), stats.TotalErrors)
+	assert.Equal(t, uint64(1), stats.TotalRunTimeouts)
+	assert.Equal(t, "the check run exceeded its run timeout of 1s", stats.LastError)
+
+	stats.SetStuck(true)
+	assert.True(t, stats.Stuck)
+}
+
 func TestTranslateEventPlatformEventTypes(t *testing.T) {
 	original := map[string]interface{}{
 		"EventPlatformEvents": map[string]interface{}{
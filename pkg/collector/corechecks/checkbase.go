@@ -47,6 +47,7 @@ type CheckBase struct {
 	checkID        checkid.ID
 	latestWarnings []error
 	checkInterval  time.Duration
+	runTimeout     time.Duration
 	source         string
 	telemetry      bool
 	initConfig     string
@@ -111,6 +112,11 @@ func (c *CheckBase) CommonConfigure(senderManager sender.SenderManager, initConf
 			c.checkInterval = time.Duration(commonOptions.MinCollectionInterval) * time.Second
 		}
 
+		// See if a run timeout was specified
+		if commonOptions.RunTimeout > 0 {
+			c.runTimeout = time.Duration(commonOptions.RunTimeout) * time.Second
+		}
+
 		// Disable default hostname if specified
 		if commonOptions.EmptyDefaultHostname {
 			s, err := c.GetSender()
@@ -197,6 +203,13 @@ func (c *CheckBase) Interval() time.Duration {
 	return c.checkInterval
 }
 
+// RunTimeout returns the maximum duration of a run set with `run_timeout`,
+// 0 if it isn't set. The checks implementing check.ContextCheck are cancelled
+// when they exceed it.
+func (c *CheckBase) RunTimeout() time.Duration {
+	return c.runTimeout
+}
+
 // String returns the name of the check, the same for every instance
 func (c *CheckBase) String() string {
 	return c.checkName
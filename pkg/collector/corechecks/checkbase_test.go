@@ -40,6 +40,7 @@ func TestCommonConfigure(t *testing.T) {
 	err := mycheck.CommonConfigure(mockSender.GetSenderManager(), nil, []byte(defaultsInstance), "test")
 	assert.NoError(t, err)
 	assert.Equal(t, defaults.DefaultCheckInterval, mycheck.Interval())
+	assert.Equal(t, time.Duration(0), mycheck.RunTimeout())
 	mockSender.AssertNumberOfCalls(t, "DisableDefaultHostname", 0)
 
 	mockSender.On("DisableDefaultHostname", true).Return().Once()
@@ -49,6 +50,10 @@ func TestCommonConfigure(t *testing.T) {
 	mycheck.BuildID(1, []byte(customInstance), []byte(initConfig))
 	assert.Equal(t, string(mycheck.ID()), "test:foobar:a934df33209f45f4")
 	mockSender.AssertExpectations(t)
+
+	err = mycheck.CommonConfigure(mockSender.GetSenderManager(), nil, []byte("run_timeout: 30"), "test")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, mycheck.RunTimeout())
 }
 
 func TestCommonConfigureCustomID(t *testing.T) {
@@ -0,0 +1,142 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package customresources
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+// MetricTypeConditions is a helper metric type, on top of the ones supported
+// by kube-state-metrics, that generates a StateSet metric for each entry of
+// the `status.conditions` list of a custom resource. The condition type is
+// exposed as the `condition` label and its status as the `status` label.
+const MetricTypeConditions customresourcestate.MetricType = "Conditions"
+
+var conditionStatuses = []string{"True", "False", "Unknown"}
+
+// NewCustomResourceStateFactories returns metric family generator factories
+// for the custom resources declared in the check configuration, following the
+// kube-state-metrics CustomResourceStateMetrics format.
+//
+// The factories use the given dynamic client instead of building their own
+// from a REST config.
+func NewCustomResourceStateFactories(client dynamic.Interface, metrics customresourcestate.Metrics) ([]customresource.RegistryFactory, error) {
+	factories := make([]customresource.RegistryFactory, 0, len(metrics.Spec.Resources))
+	seen := make(map[string]struct{}, len(metrics.Spec.Resources))
+
+	for _, resource := range metrics.Spec.Resources {
+		resource = expandConditionsMetrics(resource)
+
+		factory, err := customresourcestate.NewCustomResourceMetrics(resource)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metrics factory for %v: %w", resource.GroupVersionKind, err)
+		}
+
+		if _, found := seen[factory.Name()]; found {
+			return nil, fmt.Errorf("found multiple custom resource configurations for the same resource %s", factory.Name())
+		}
+		seen[factory.Name()] = struct{}{}
+
+		gvr := CustomResourceStateGroupVersionResource(resource)
+		factories = append(factories, &customResourceStateFactory{
+			RegistryFactory: factory,
+			client:          client.Resource(gvr),
+		})
+	}
+
+	return factories, nil
+}
+
+// CustomResourceStateGroupVersionResource returns the group version resource
+// targeted by a custom resource state configuration.
+func CustomResourceStateGroupVersionResource(resource customresourcestate.Resource) schema.GroupVersionResource {
+	return schema.GroupVersionResource{
+		Group:    resource.GroupVersionKind.Group,
+		Version:  resource.GroupVersionKind.Version,
+		Resource: resource.GetResourceName(),
+	}
+}
+
+// CustomResourceStateMetricNames returns the mapping between the names of the
+// metric families generated for the given custom resources and the names of
+// the corresponding Datadog metrics, without the check prefix.
+//
+// Example: kube_customresource_replicas => customresource.replicas
+func CustomResourceStateMetricNames(metrics customresourcestate.Metrics) map[string]string {
+	names := make(map[string]string)
+
+	for _, resource := range metrics.Spec.Resources {
+		prefix := resource.GetMetricNamePrefix()
+		ddPrefix := strings.TrimPrefix(prefix, "kube_")
+
+		for _, generator := range resource.Metrics {
+			if prefix == "" {
+				names[generator.Name] = generator.Name
+				continue
+			}
+			names[prefix+"_"+generator.Name] = ddPrefix + "." + generator.Name
+		}
+	}
+
+	return names
+}
+
+// expandConditionsMetrics replaces the metrics using the Conditions helper
+// type by their StateSet equivalent.
+func expandConditionsMetrics(resource customresourcestate.Resource) customresourcestate.Resource {
+	generators := make([]customresourcestate.Generator, 0, len(resource.Metrics))
+
+	for _, generator := range resource.Metrics {
+		if generator.Each.Type != MetricTypeConditions {
+			generators = append(generators, generator)
+			continue
+		}
+
+		path := []string{"status", "conditions"}
+		if generator.Each.StateSet != nil && len(generator.Each.StateSet.Path) > 0 {
+			path = generator.Each.StateSet.Path
+		}
+
+		generator.Each = customresourcestate.Metric{
+			Type: customresourcestate.MetricTypeStateSet,
+			StateSet: &customresourcestate.MetricStateSet{
+				MetricMeta: customresourcestate.MetricMeta{
+					Path: path,
+					LabelsFromPath: map[string][]string{
+						"condition": {"type"},
+					},
+				},
+				List:      conditionStatuses,
+				LabelName: "status",
+				ValueFrom: []string{"status"},
+			},
+		}
+		generators = append(generators, generator)
+	}
+
+	resource.Metrics = generators
+	return resource
+}
+
+// customResourceStateFactory wraps a kube-state-metrics custom resource
+// state factory to reuse the dynamic client of the agent.
+type customResourceStateFactory struct {
+	customresource.RegistryFactory
+	client interface{}
+}
+
+func (f *customResourceStateFactory) CreateClient(_ *rest.Config) (interface{}, error) {
+	return f.client, nil
+}
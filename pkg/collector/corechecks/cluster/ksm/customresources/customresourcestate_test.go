@@ -0,0 +1,126 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver
+
+package customresources
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
+)
+
+const rolloutConfig = `
+spec:
+  resources:
+    - groupVersionKind:
+        group: argoproj.io
+        version: v1alpha1
+        kind: Rollout
+      labelsFromPath:
+        kube_namespace: [metadata, namespace]
+      metrics:
+        - name: replicas
+          each:
+            type: Gauge
+            gauge:
+              path: [status, replicas]
+        - name: condition
+          each:
+            type: Conditions
+    - groupVersionKind:
+        group: cert-manager.io
+        version: v1
+        kind: Certificate
+      metricNamePrefix: certmanager_certificate
+      metrics:
+        - name: expiration
+          each:
+            type: Gauge
+            gauge:
+              path: [status, notAfter]
+`
+
+func parseMetrics(t *testing.T, config string) customresourcestate.Metrics {
+	var metrics customresourcestate.Metrics
+	require.NoError(t, yaml.Unmarshal([]byte(config), &metrics))
+	return metrics
+}
+
+func TestNewCustomResourceStateFactories(t *testing.T) {
+	metrics := parseMetrics(t, rolloutConfig)
+	client := fake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	factories, err := NewCustomResourceStateFactories(client, metrics)
+	require.NoError(t, err)
+	require.Len(t, factories, 2)
+
+	assert.Equal(t, "rollouts", factories[0].Name())
+	assert.Equal(t, "certificates", factories[1].Name())
+
+	for _, f := range factories {
+		c, err := f.CreateClient(nil)
+		assert.NoError(t, err)
+		assert.NotNil(t, c)
+	}
+
+	names := []string{}
+	for _, g := range factories[0].MetricFamilyGenerators(nil, nil) {
+		names = append(names, g.Name)
+	}
+	assert.ElementsMatch(t, []string{"kube_customresource_replicas", "kube_customresource_condition"}, names)
+}
+
+func TestNewCustomResourceStateFactoriesDuplicate(t *testing.T) {
+	metrics := parseMetrics(t, rolloutConfig)
+	metrics.Spec.Resources = append(metrics.Spec.Resources, metrics.Spec.Resources[0])
+
+	_, err := NewCustomResourceStateFactories(fake.NewSimpleDynamicClient(runtime.NewScheme()), metrics)
+	assert.Error(t, err)
+}
+
+func TestCustomResourceStateMetricNames(t *testing.T) {
+	metrics := parseMetrics(t, rolloutConfig)
+
+	assert.Equal(t, map[string]string{
+		"kube_customresource_replicas":       "customresource.replicas",
+		"kube_customresource_condition":      "customresource.condition",
+		"certmanager_certificate_expiration": "certmanager_certificate.expiration",
+	}, CustomResourceStateMetricNames(metrics))
+}
+
+func TestCustomResourceStateGroupVersionResource(t *testing.T) {
+	metrics := parseMetrics(t, rolloutConfig)
+
+	assert.Equal(t, schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "rollouts"}, CustomResourceStateGroupVersionResource(metrics.Spec.Resources[0]))
+}
+
+func TestExpandConditionsMetrics(t *testing.T) {
+	metrics := parseMetrics(t, rolloutConfig)
+
+	resource := expandConditionsMetrics(metrics.Spec.Resources[0])
+	require.Len(t, resource.Metrics, 2)
+
+	assert.Equal(t, customresourcestate.MetricTypeGauge, resource.Metrics[0].Each.Type)
+
+	condition := resource.Metrics[1].Each
+	assert.Equal(t, customresourcestate.MetricTypeStateSet, condition.Type)
+	require.NotNil(t, condition.StateSet)
+	assert.Equal(t, []string{"status", "conditions"}, condition.StateSet.Path)
+	assert.Equal(t, map[string][]string{"condition": {"type"}}, condition.StateSet.LabelsFromPath)
+	assert.Equal(t, []string{"True", "False", "Unknown"}, condition.StateSet.List)
+	assert.Equal(t, "status", condition.StateSet.LabelName)
+	assert.Equal(t, []string{"status"}, condition.StateSet.ValueFrom)
+
+	// the original configuration is left untouched
+	assert.Equal(t, MetricTypeConditions, metrics.Spec.Resources[0].Metrics[1].Each.Type)
+}
@@ -39,10 +39,12 @@ import (
 
 	"gopkg.in/yaml.v2"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/kube-state-metrics/v2/pkg/allowdenylist"
 	"k8s.io/kube-state-metrics/v2/pkg/customresource"
+	"k8s.io/kube-state-metrics/v2/pkg/customresourcestate"
 	"k8s.io/kube-state-metrics/v2/pkg/options"
 )
 
@@ -186,6 +188,33 @@ type KSMConfig struct {
 	// PodCollectionMode defines how pods are collected.
 	// Accepted values are: "default", "node_kubelet", and "cluster_unassigned".
 	PodCollectionMode podCollectionMode `yaml:"pod_collection_mode"`
+
+	// CustomResource defines metrics to generate from arbitrary custom resources.
+	// It follows the kube-state-metrics CustomResourceStateMetrics format, with the
+	// additional `Conditions` metric type generating one metric per status condition.
+	// Metrics are sent as kubernetes_state.<prefix>.<name>, the default prefix being
+	// `customresource`.
+	// Example:
+	// custom_resource:
+	//   spec:
+	//     resources:
+	//       - groupVersionKind:
+	//           group: argoproj.io
+	//           version: v1alpha1
+	//           kind: Rollout
+	//         labelsFromPath:
+	//           kube_namespace: [metadata, namespace]
+	//           kube_argo_rollout: [metadata, name]
+	//         metrics:
+	//           - name: rollout.replicas.available
+	//             each:
+	//               type: Gauge
+	//               gauge:
+	//                 path: [status, availableReplicas]
+	//           - name: rollout.condition
+	//             each:
+	//               type: Conditions
+	CustomResource customresourcestate.Metrics `yaml:"custom_resource"`
 }
 
 // KSMCheck wraps the config and the metric stores needed to run the check
@@ -354,7 +383,10 @@ func (k *KSMCheck) Configure(senderManager sender.SenderManager, integrationConf
 
 	// configure custom resources required for extended features and
 	// compatibility across deprecated/removed versions of APIs
-	cr := k.discoverCustomResources(c, collectors, resources)
+	cr, err := k.discoverCustomResources(c, collectors, resources)
+	if err != nil {
+		return err
+	}
 	builder.WithGenerateCustomResourceStoresFunc(builder.GenerateCustomResourceStoresFunc)
 	builder.WithCustomResourceStoreFactories(cr.factories...)
 	builder.WithCustomResourceClients(cr.clients)
@@ -426,7 +458,7 @@ type customResources struct {
 	clients    map[string]interface{}
 }
 
-func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []string, resources []*v1.APIResourceList) customResources {
+func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []string, resources []*v1.APIResourceList) (customResources, error) {
 	// automatically add extended collectors if their standard ones are
 	// enabled
 	for _, c := range collectors {
@@ -446,6 +478,16 @@ func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []
 
 	factories = manageResourcesReplacement(c, factories, resources)
 
+	// custom resource state metrics declared in the check configuration
+	crsFactories, err := k.customResourceStateFactories(c, resources)
+	if err != nil {
+		return customResources{}, err
+	}
+	for _, f := range crsFactories {
+		collectors = append(collectors, f.Name())
+	}
+	factories = append(factories, crsFactories...)
+
 	clients := make(map[string]interface{}, len(factories))
 	for _, f := range factories {
 		client, _ := f.CreateClient(nil)
@@ -456,7 +498,46 @@ func (k *KSMCheck) discoverCustomResources(c *apiserver.APIClient, collectors []
 		collectors: collectors,
 		clients:    clients,
 		factories:  factories,
+	}, nil
+}
+
+// customResourceStateFactories builds the factories for the custom resources
+// declared in the check configuration. Resources that are not served by the
+// API server are skipped.
+func (k *KSMCheck) customResourceStateFactories(c *apiserver.APIClient, resources []*v1.APIResourceList) ([]customresource.RegistryFactory, error) {
+	if len(k.instance.CustomResource.Spec.Resources) == 0 {
+		return nil, nil
 	}
+
+	available := make(map[schema.GroupVersionResource]struct{})
+	for _, resourceList := range resources {
+		gv, err := schema.ParseGroupVersion(resourceList.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range resourceList.APIResources {
+			available[gv.WithResource(resource.Name)] = struct{}{}
+		}
+	}
+
+	metrics := customresourcestate.Metrics{}
+	for _, resource := range k.instance.CustomResource.Spec.Resources {
+		gvr := customresources.CustomResourceStateGroupVersionResource(resource)
+		if _, found := available[gvr]; !found {
+			log.Warnf("custom resource %s is unknown and will not be collected", gvr.String())
+			continue
+		}
+		metrics.Spec.Resources = append(metrics.Spec.Resources, resource)
+	}
+
+	factories, err := customresources.NewCustomResourceStateFactories(c.DynamicInformerCl, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom_resource configuration: %w", err)
+	}
+
+	maps.Copy(k.metricNamesMapper, customresources.CustomResourceStateMetricNames(metrics))
+
+	return factories, nil
 }
 
 func manageResourcesReplacement(c *apiserver.APIClient, factories []customresource.RegistryFactory, resources []*v1.APIResourceList) []customresource.RegistryFactory {
@@ -34,6 +34,9 @@ func NewCollectorInventory(cfg config.Component, store workloadmeta.Component) *
 			k8sCollectors.NewCronJobCollectorVersions(),
 			k8sCollectors.NewDaemonSetCollectorVersions(),
 			k8sCollectors.NewDeploymentCollectorVersions(),
+			k8sCollectors.NewEndpointSliceCollectorVersions(),
+			k8sCollectors.NewGatewayCollectorVersions(),
+			k8sCollectors.NewHTTPRouteCollectorVersions(),
 			k8sCollectors.NewIngressCollectorVersions(),
 			k8sCollectors.NewJobCollectorVersions(),
 			k8sCollectors.NewLimitRangeCollectorVersions(),
@@ -41,7 +44,9 @@ func NewCollectorInventory(cfg config.Component, store workloadmeta.Component) *
 			k8sCollectors.NewNodeCollectorVersions(),
 			k8sCollectors.NewPersistentVolumeCollectorVersions(),
 			k8sCollectors.NewPersistentVolumeClaimCollectorVersions(),
+			k8sCollectors.NewPodDisruptionBudgetCollectorVersions(),
 			k8sCollectors.NewReplicaSetCollectorVersions(),
+			k8sCollectors.NewResourceQuotaCollectorVersions(),
 			k8sCollectors.NewRoleCollectorVersions(),
 			k8sCollectors.NewRoleBindingCollectorVersions(),
 			k8sCollectors.NewServiceCollectorVersions(),
@@ -0,0 +1,98 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+
+package k8s
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	k8sProcessors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/k8s"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// gatewayAPIGroupVersion is the group version of the Gateway API resources
+// collected by the orchestrator check.
+var gatewayAPIGroupVersion = schema.GroupVersion{Group: "gateway.networking.k8s.io", Version: "v1"}
+
+// NewGatewayCollectorVersions builds the group of collector versions.
+func NewGatewayCollectorVersions() collectors.CollectorVersions {
+	return collectors.NewCollectorVersions(
+		NewGatewayCollector(),
+	)
+}
+
+// GatewayCollector is a collector for Kubernetes Gateway API Gateways.
+type GatewayCollector struct {
+	informer  informers.GenericInformer
+	lister    cache.GenericLister
+	metadata  *collectors.CollectorMetadata
+	processor *processors.Processor
+}
+
+// NewGatewayCollector creates a new collector for the Kubernetes Gateway API
+// Gateway resource. It is not stable as the Gateway API CRDs are not installed
+// on every cluster, so it needs to be enabled explicitly.
+func NewGatewayCollector() *GatewayCollector {
+	return &GatewayCollector{
+		metadata: &collectors.CollectorMetadata{
+			IsDefaultVersion:          true,
+			IsStable:                  false,
+			IsManifestProducer:        true,
+			IsMetadataProducer:        false,
+			SupportsManifestBuffering: true,
+			Name:                      "gateways",
+			NodeType:                  orchestrator.K8sGateway,
+			Version:                   gatewayAPIGroupVersion.String(),
+		},
+		processor: processors.NewProcessor(new(k8sProcessors.GatewayHandlers)),
+	}
+}
+
+// Informer returns the shared informer.
+func (c *GatewayCollector) Informer() cache.SharedInformer {
+	return c.informer.Informer()
+}
+
+// Init is used to initialize the collector.
+func (c *GatewayCollector) Init(rcfg *collectors.CollectorRunConfig) {
+	c.informer = rcfg.OrchestratorInformerFactory.DynamicInformerFactory.ForResource(gatewayAPIGroupVersion.WithResource(c.metadata.Name))
+	c.lister = c.informer.Lister()
+}
+
+// Metadata is used to access information about the collector.
+func (c *GatewayCollector) Metadata() *collectors.CollectorMetadata {
+	return c.metadata
+}
+
+// Run triggers the collection process.
+func (c *GatewayCollector) Run(rcfg *collectors.CollectorRunConfig) (*collectors.CollectorRunResult, error) {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, collectors.NewListingError(err)
+	}
+
+	ctx := collectors.NewK8sProcessorContext(rcfg, c.metadata)
+
+	processResult, processed := c.processor.Process(ctx, list)
+
+	if processed == -1 {
+		return nil, collectors.ErrProcessingPanic
+	}
+
+	result := &collectors.CollectorRunResult{
+		Result:             processResult,
+		ResourcesListed:    len(list),
+		ResourcesProcessed: processed,
+	}
+
+	return result, nil
+}
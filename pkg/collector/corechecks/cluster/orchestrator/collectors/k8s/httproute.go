@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build kubeapiserver && orchestrator
+
+package k8s
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/collectors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	k8sProcessors "github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/k8s"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewHTTPRouteCollectorVersions builds the group of collector versions.
+func NewHTTPRouteCollectorVersions() collectors.CollectorVersions {
+	return collectors.NewCollectorVersions(
+		NewHTTPRouteCollector(),
+	)
+}
+
+// HTTPRouteCollector is a collector for Kubernetes Gateway API HTTPRoutes.
+type HTTPRouteCollector struct {
+	informer  informers.GenericInformer
+	lister    cache.GenericLister
+	metadata  *collectors.CollectorMetadata
+	processor *processors.Processor
+}
+
+// NewHTTPRouteCollector creates a new collector for the Kubernetes Gateway API
+// HTTPRoute resource. It is not stable as the Gateway API CRDs are not installed
+// on every cluster, so it needs to be enabled explicitly.
+func NewHTTPRouteCollector() *HTTPRouteCollector {
+	return &HTTPRouteCollector{
+		metadata: &collectors.CollectorMetadata{
+			IsDefaultVersion:          true,
+			IsStable:                  false,
+			IsManifestProducer:        true,
+			IsMetadataProducer:        false,
+			SupportsManifestBuffering: true,
+			Name:                      "httproutes",
+			NodeType:                  orchestrator.K8sHTTPRoute,
+			Version:                   gatewayAPIGroupVersion.String(),
+		},
+		processor: processors.NewProcessor(new(k8sProcessors.HTTPRouteHandlers)),
+	}
+}
+
+// Informer returns the shared informer.
+func (c *HTTPRouteCollector) Informer() cache.SharedInformer {
+	return c.informer.Informer()
+}
+
+// Init is used to initialize the collector.
+func (c *HTTPRouteCollector) Init(rcfg *collectors.CollectorRunConfig) {
+	c.informer = rcfg.OrchestratorInformerFactory.DynamicInformerFactory.ForResource(gatewayAPIGroupVersion.WithResource(c.metadata.Name))
+	c.lister = c.informer.Lister()
+}
+
+// Metadata is used to access information about the collector.
+func (c *HTTPRouteCollector) Metadata() *collectors.CollectorMetadata {
+	return c.metadata
+}
+
+// Run triggers the collection process.
+func (c *HTTPRouteCollector) Run(rcfg *collectors.CollectorRunConfig) (*collectors.CollectorRunResult, error) {
+	list, err := c.lister.List(labels.Everything())
+	if err != nil {
+		return nil, collectors.NewListingError(err)
+	}
+
+	ctx := collectors.NewK8sProcessorContext(rcfg, c.metadata)
+
+	processResult, processed := c.processor.Process(ctx, list)
+
+	if processed == -1 {
+		return nil, collectors.ErrProcessingPanic
+	}
+
+	result := &collectors.CollectorRunResult{
+		Result:             processResult,
+		ResourcesListed:    len(list),
+		ResourcesProcessed: processed,
+	}
+
+	return result, nil
+}
@@ -0,0 +1,93 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build orchestrator
+
+package k8s
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	model "github.com/DataDog/agent-payload/v5/process"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/cluster/orchestrator/processors/common"
+	"github.com/DataDog/datadog-agent/pkg/orchestrator/redact"
+)
+
+// GatewayHandlers implements the Handlers interface for Kubernetes Gateway API
+// Gateways.
+//
+// Gateways are read through the dynamic client as unstructured objects and there
+// is no dedicated model in the agent payload, so only manifests are produced
+// for this resource.
+type GatewayHandlers struct {
+	common.BaseHandlers
+}
+
+// AfterMarshalling is a handler called after resource marshalling.
+//
+//nolint:revive
+func (h *GatewayHandlers) AfterMarshalling(ctx processors.ProcessorContext, resource, resourceModel interface{}, yaml []byte) (skip bool) {
+	return
+}
+
+// BuildMessageBody is a handler called to build a message body out of a list of
+// extracted resources.
+//
+//nolint:revive
+func (h *GatewayHandlers) BuildMessageBody(ctx processors.ProcessorContext, resourceModels []interface{}, groupSize int) model.MessageBody {
+	return nil
+}
+
+// ExtractResource is a handler called to extract the resource model out of a raw resource.
+//
+//nolint:revive
+func (h *GatewayHandlers) ExtractResource(ctx processors.ProcessorContext, resource interface{}) (resourceModel interface{}) {
+	return
+}
+
+// ResourceList is a handler called to convert a list passed as a generic
+// interface to a list of generic interfaces.
+//
+//nolint:revive
+func (h *GatewayHandlers) ResourceList(ctx processors.ProcessorContext, list interface{}) (resources []interface{}) {
+	resourceList := list.([]runtime.Object)
+	resources = make([]interface{}, 0, len(resourceList))
+
+	for _, resource := range resourceList {
+		resources = append(resources, resource)
+	}
+
+	return resources
+}
+
+// ResourceUID is a handler called to retrieve the resource UID.
+//
+//nolint:revive
+func (h *GatewayHandlers) ResourceUID(ctx processors.ProcessorContext, resource interface{}) types.UID {
+	return resource.(*unstructured.Unstructured).GetUID()
+}
+
+// ResourceVersion is a handler called to retrieve the resource version.
+//
+//nolint:revive
+func (h *GatewayHandlers) ResourceVersion(ctx processors.ProcessorContext, resource, resourceModel interface{}) string {
+	return resource.(*unstructured.Unstructured).GetResourceVersion()
+}
+
+// ScrubBeforeExtraction is a handler called to redact the raw resource before
+// it is extracted as an internal resource model.
+//
+//nolint:revive
+func (h *GatewayHandlers) ScrubBeforeExtraction(ctx processors.ProcessorContext, resource interface{}) {
+	r := resource.(*unstructured.Unstructured)
+	annotations := r.GetAnnotations()
+	labels := r.GetLabels()
+	redact.RemoveSensitiveAnnotationsAndLabels(annotations, labels)
+	r.SetAnnotations(annotations)
+	r.SetLabels(labels)
+}
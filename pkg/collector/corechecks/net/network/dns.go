@@ -0,0 +1,95 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux
+
+package network
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/DataDog/sketches-go/ddsketch/pb/sketchpb"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/network/model"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const dnsLatencyMetric = "system.net.dns.resolver.latency"
+
+var dnsRcodeNames = map[uint32]string{
+	0: "noerror",
+	1: "formerr",
+	2: "servfail",
+	3: "nxdomain",
+	4: "notimp",
+	5: "refused",
+}
+
+func dnsRcodeName(rcode uint32) string {
+	if name, ok := dnsRcodeNames[rcode]; ok {
+		return name
+	}
+	return strconv.FormatUint(uint64(rcode), 10)
+}
+
+func submitDNSResolverMetrics(sender sender.Sender, stats *model.DNSResolverStats) {
+	for _, resolver := range stats.Resolvers {
+		tags := []string{fmt.Sprintf("resolver:%s", resolver.Address)}
+		sender.Count("system.net.dns.resolver.timeouts", float64(resolver.Timeouts), "", tags)
+		for rcode, count := range resolver.CountByRcode {
+			sender.Count("system.net.dns.resolver.responses", float64(count), "", append([]string{fmt.Sprintf("rcode:%s", dnsRcodeName(rcode))}, tags...))
+		}
+		submitDNSLatencyMetrics(sender, resolver.SuccessLatencies, append([]string{"status:success"}, tags...))
+		submitDNSLatencyMetrics(sender, resolver.FailureLatencies, append([]string{"status:failure"}, tags...))
+	}
+
+	for _, domain := range stats.FailingDomains {
+		tags := []string{fmt.Sprintf("domain:%s", domain.Domain)}
+		if domain.NXDomain > 0 {
+			sender.Count("system.net.dns.failing_domain.responses", float64(domain.NXDomain), "", append([]string{"rcode:nxdomain"}, tags...))
+		}
+		if domain.ServFail > 0 {
+			sender.Count("system.net.dns.failing_domain.responses", float64(domain.ServFail), "", append([]string{"rcode:servfail"}, tags...))
+		}
+	}
+}
+
+// submitDNSLatencyMetrics submits the bins of the encoded latency sketch as the
+// buckets of a distribution, converted from microseconds to seconds
+func submitDNSLatencyMetrics(sender sender.Sender, encoded []byte, tags []string) {
+	if len(encoded) == 0 {
+		return
+	}
+
+	sketch, err := decodeSketch(encoded)
+	if err != nil {
+		log.Debugf("could not decode DNS latency sketch: %s", err)
+		return
+	}
+
+	// The sketches are reset at each report, so the buckets are not monotonic.
+	if count := sketch.GetZeroCount(); count > 0 {
+		sender.HistogramBucket(dnsLatencyMetric, int64(math.Round(count)), 0, 0, false, "", tags, false)
+	}
+	sketch.GetPositiveValueStore().ForEach(func(index int, count float64) bool {
+		lowerBound := sketch.LowerBound(index) / 1e6
+		upperBound := sketch.LowerBound(index+1) / 1e6
+		sender.HistogramBucket(dnsLatencyMetric, int64(math.Round(count)), lowerBound, upperBound, false, "", tags, false)
+		return false
+	})
+}
+
+func decodeSketch(data []byte) (*ddsketch.DDSketch, error) {
+	var sketch sketchpb.DDSketch
+	if err := proto.Unmarshal(data, &sketch); err != nil {
+		return nil, err
+	}
+	return ddsketch.FromProto(&sketch)
+}
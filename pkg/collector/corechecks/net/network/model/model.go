@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package model contains the model for the DNS resolver statistics, with types shared between the
+// system-probe network tracer and the network core agent check
+package model
+
+// ResolverStats contains the statistics collected for a single DNS resolver
+type ResolverStats struct {
+	// Address is the IP address of the resolver
+	Address string `json:"address"`
+
+	// Timeouts is the number of queries sent to the resolver which did not get any response
+	Timeouts uint32 `json:"timeouts"`
+
+	// CountByRcode is the number of responses received from the resolver, by response code
+	CountByRcode map[uint32]uint32 `json:"count_by_rcode"`
+
+	// SuccessLatencies is the protobuf encoded DDSketch of the latencies of successful responses, in microseconds
+	SuccessLatencies []byte `json:"success_latencies,omitempty"`
+
+	// FailureLatencies is the protobuf encoded DDSketch of the latencies of failed responses, in microseconds
+	FailureLatencies []byte `json:"failure_latencies,omitempty"`
+}
+
+// FailingDomain contains the number of failed responses received for a domain
+type FailingDomain struct {
+	Domain   string `json:"domain"`
+	NXDomain uint32 `json:"nxdomain"`
+	ServFail uint32 `json:"servfail"`
+}
+
+// DNSResolverStats is the data structure that is sent to the agent. It contains the statistics of all
+// the resolvers seen since the last check run, and the top failing domains when enabled
+type DNSResolverStats struct {
+	Resolvers      []ResolverStats `json:"resolvers"`
+	FailingDomains []FailingDomain `json:"failing_domains,omitempty"`
+}
@@ -20,10 +20,14 @@ import (
 	"github.com/shirou/gopsutil/v3/net"
 	yaml "gopkg.in/yaml.v2"
 
+	sysconfig "github.com/DataDog/datadog-agent/cmd/system-probe/config"
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/network/model"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	processnet "github.com/DataDog/datadog-agent/pkg/process/net"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 	"github.com/DataDog/datadog-agent/pkg/util/optional"
 )
@@ -82,6 +86,7 @@ type NetworkCheck struct {
 
 type networkInstanceConfig struct {
 	CollectConnectionState   bool     `yaml:"collect_connection_state"`
+	CollectDNSResolverStats  bool     `yaml:"collect_dns_resolver_stats"`
 	ExcludedInterfaces       []string `yaml:"excluded_interfaces"`
 	ExcludedInterfaceRe      string   `yaml:"excluded_interface_re"`
 	ExcludedInterfacePattern *regexp.Regexp
@@ -99,6 +104,7 @@ type networkStats interface {
 	ProtoCounters(protocols []string) ([]net.ProtoCountersStat, error)
 	Connections(kind string) ([]net.ConnectionStat, error)
 	NetstatTCPExtCounters() (map[string]int64, error)
+	DNSResolverStats() (*model.DNSResolverStats, error)
 }
 
 type defaultNetworkStats struct{}
@@ -119,6 +125,25 @@ func (n defaultNetworkStats) NetstatTCPExtCounters() (map[string]int64, error) {
 	return netstatTCPExtCounters()
 }
 
+func (n defaultNetworkStats) DNSResolverStats() (*model.DNSResolverStats, error) {
+	sysProbeUtil, err := processnet.GetRemoteSystemProbeUtil(
+		pkgconfigsetup.SystemProbe().GetString("system_probe_config.sysprobe_socket"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sysprobe connection: %w", err)
+	}
+
+	data, err := sysProbeUtil.GetCheck(sysconfig.NetworkTracerModule)
+	if err != nil {
+		return nil, err
+	}
+	stats, ok := data.(model.DNSResolverStats)
+	if !ok {
+		return nil, fmt.Errorf("unexpected DNS resolver stats type %T", data)
+	}
+	return &stats, nil
+}
+
 // Run executes the check
 func (c *NetworkCheck) Run() error {
 	sender, err := c.GetSender()
@@ -182,6 +207,17 @@ func (c *NetworkCheck) Run() error {
 		submitConnectionsMetrics(sender, "tcp6", tcpStateMetricsSuffixMapping, connectionsStats)
 	}
 
+	if c.config.instance.CollectDNSResolverStats {
+		// The DNS stats are collected by system-probe, which may not be running:
+		// don't fail the whole check if they can't be retrieved
+		dnsStats, err := c.net.DNSResolverStats()
+		if err != nil {
+			log.Debugf("could not get DNS resolver stats from system-probe: %s", err)
+		} else {
+			submitDNSResolverMetrics(sender, dnsStats)
+		}
+	}
+
 	sender.Commit()
 	return nil
 }
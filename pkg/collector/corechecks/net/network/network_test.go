@@ -10,13 +10,17 @@ package network
 import (
 	"testing"
 
+	"github.com/DataDog/sketches-go/ddsketch"
 	"github.com/shirou/gopsutil/v3/net"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
 	"github.com/DataDog/datadog-agent/pkg/aggregator"
 	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/network/model"
 )
 
 type fakeNetworkStats struct {
@@ -34,6 +38,8 @@ type fakeNetworkStats struct {
 	connectionStatsTCP6Error    error
 	netstatTCPExtCountersValues map[string]int64
 	netstatTCPExtCountersError  error
+	dnsResolverStats            *model.DNSResolverStats
+	dnsResolverStatsError       error
 }
 
 // IOCounters returns the inner values of counterStats and counterStatsError
@@ -69,6 +75,10 @@ func (n *fakeNetworkStats) NetstatTCPExtCounters() (map[string]int64, error) {
 	return n.netstatTCPExtCountersValues, n.netstatTCPExtCountersError
 }
 
+func (n *fakeNetworkStats) DNSResolverStats() (*model.DNSResolverStats, error) {
+	return n.dnsResolverStats, n.dnsResolverStatsError
+}
+
 func TestDefaultConfiguration(t *testing.T) {
 	check := NetworkCheck{}
 	check.Configure(aggregator.NewNoOpSenderManager(), integration.FakeConfigHash, []byte(``), []byte(``), "test")
@@ -516,3 +526,65 @@ excluded_interface_re: "eth[0-9]"
 	mockSender.AssertCalled(t, "Rate", "system.net.packets_out.drop", float64(32), "", lo0Tags)
 	mockSender.AssertCalled(t, "Rate", "system.net.packets_out.error", float64(33), "", lo0Tags)
 }
+
+func TestDNSResolverStats(t *testing.T) {
+	sketch, err := ddsketch.NewDefaultDDSketch(0.01)
+	require.NoError(t, err)
+	require.NoError(t, sketch.AddWithCount(2000, 3))
+	require.NoError(t, sketch.Add(50000))
+	latencies, err := proto.Marshal(sketch.ToProto())
+	require.NoError(t, err)
+
+	net := &fakeNetworkStats{
+		dnsResolverStats: &model.DNSResolverStats{
+			Resolvers: []model.ResolverStats{
+				{
+					Address:          "8.8.8.8",
+					Timeouts:         3,
+					CountByRcode:     map[uint32]uint32{0: 10, 3: 2},
+					SuccessLatencies: latencies,
+				},
+			},
+			FailingDomains: []model.FailingDomain{
+				{Domain: "missing.example", NXDomain: 2},
+			},
+		},
+	}
+
+	networkCheck := NetworkCheck{
+		net: net,
+	}
+
+	rawInstanceConfig := []byte(`
+collect_dns_resolver_stats: true
+`)
+
+	mockSender := mocksender.NewMockSender(networkCheck.ID())
+	networkCheck.Configure(mockSender.GetSenderManager(), integration.FakeConfigHash, rawInstanceConfig, []byte(``), "test")
+
+	mockSender.On("Gauge", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("Count", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("HistogramBucket", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	mockSender.On("Commit").Return()
+
+	err = networkCheck.Run()
+	assert.Nil(t, err)
+
+	resolverTags := []string{"resolver:8.8.8.8"}
+	mockSender.AssertCalled(t, "Count", "system.net.dns.resolver.timeouts", float64(3), "", resolverTags)
+	mockSender.AssertCalled(t, "Count", "system.net.dns.resolver.responses", float64(10), "", []string{"rcode:noerror", "resolver:8.8.8.8"})
+	mockSender.AssertCalled(t, "Count", "system.net.dns.resolver.responses", float64(2), "", []string{"rcode:nxdomain", "resolver:8.8.8.8"})
+	// Each latency is submitted in the bucket of its sketch bin, in seconds
+	for latency, count := range map[float64]int64{0.002: 3, 0.05: 1} {
+		mockSender.AssertCalled(t, "HistogramBucket", "system.net.dns.resolver.latency", count,
+			mock.MatchedBy(func(lowerBound float64) bool { return lowerBound <= latency && lowerBound > latency*0.97 }),
+			mock.MatchedBy(func(upperBound float64) bool { return upperBound > latency && upperBound < latency*1.03 }),
+			false, "", []string{"status:success", "resolver:8.8.8.8"}, false)
+	}
+	mockSender.AssertNumberOfCalls(t, "HistogramBucket", 2)
+	mockSender.AssertNotCalled(t, "Gauge", mock.Anything, mock.Anything, "", []string{"status:success", "resolver:8.8.8.8"})
+
+	mockSender.AssertCalled(t, "Count", "system.net.dns.failing_domain.responses", float64(2), "", []string{"rcode:nxdomain", "domain:missing.example"})
+	mockSender.AssertNotCalled(t, "Count", "system.net.dns.failing_domain.responses", mock.Anything, "", []string{"rcode:servfail", "domain:missing.example"})
+	mockSender.AssertCalled(t, "Commit")
+}
@@ -0,0 +1,246 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+)
+
+const (
+	defaultTimeout         = 10 * time.Second
+	defaultBearerTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// instanceConfig contains the fields of the openmetrics v2 check instances
+// supported by the core check. The v1 instances, whose metrics, counters and
+// service check follow different semantics, are not supported.
+type instanceConfig struct {
+	OpenMetricsEndpoint             string            `yaml:"openmetrics_endpoint"`
+	Namespace                       string            `yaml:"namespace"`
+	Metrics                         []interface{}     `yaml:"metrics"`
+	ExcludeMetrics                  []string          `yaml:"exclude_metrics"`
+	RawMetricPrefix                 string            `yaml:"raw_metric_prefix"`
+	IncludeLabels                   []string          `yaml:"include_labels"`
+	ExcludeLabels                   []string          `yaml:"exclude_labels"`
+	RenameLabels                    map[string]string `yaml:"rename_labels"`
+	HostnameLabel                   string            `yaml:"hostname_label"`
+	EnableHealthServiceCheck        *bool             `yaml:"enable_health_service_check"`
+	CollectHistogramBuckets         *bool             `yaml:"collect_histogram_buckets"`
+	NonCumulativeHistogramBuckets   bool              `yaml:"non_cumulative_histogram_buckets"`
+	HistogramBucketsAsDistributions bool              `yaml:"histogram_buckets_as_distributions"`
+	CollectCountersWithDistribution bool              `yaml:"collect_counters_with_distributions"`
+	TagByEndpoint                   *bool             `yaml:"tag_by_endpoint"`
+	Tags                            []string          `yaml:"tags"`
+
+	Timeout         float64           `yaml:"timeout"`
+	Headers         map[string]string `yaml:"headers"`
+	ExtraHeaders    map[string]string `yaml:"extra_headers"`
+	BearerTokenAuth bool              `yaml:"bearer_token_auth"`
+	BearerTokenPath string            `yaml:"bearer_token_path"`
+	Username        string            `yaml:"username"`
+	Password        string            `yaml:"password"`
+	TLSVerify       *bool             `yaml:"tls_verify"`
+	TLSCACert       string            `yaml:"tls_ca_cert"`
+	TLSCert         string            `yaml:"tls_cert"`
+	TLSPrivateKey   string            `yaml:"tls_private_key"`
+
+	// PrometheusURL is the endpoint of the openmetrics v1 instances
+	PrometheusURL string `yaml:"prometheus_url"`
+}
+
+// supportedOptions contains the options of the instances handled by the core
+// check, including the ones common to all the checks.
+var supportedOptions = yamlFields(instanceConfig{}, integration.CommonInstanceConfig{}, struct {
+	Loader string `yaml:"loader"`
+}{})
+
+// config is the parsed configuration of an instance.
+type config struct {
+	instanceConfig
+
+	endpoint   string
+	timeout    time.Duration
+	includes   *regexp.Regexp
+	renames    map[string]string
+	excludes   *regexp.Regexp
+	labels     map[string]struct{}
+	baseTags   []string
+	healthTags []string
+
+	// unsupported contains the options of the instance ignored by the check
+	unsupported []string
+}
+
+func (c *config) parse(data []byte) error {
+	if err := yaml.Unmarshal(data, &c.instanceConfig); err != nil {
+		return err
+	}
+	if c.PrometheusURL != "" {
+		return errors.New("openmetrics v1 instances are not supported by the core check, use the openmetrics_endpoint setting of the v2 instances")
+	}
+
+	options := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &options); err != nil {
+		return err
+	}
+	for option := range options {
+		if _, found := supportedOptions[option]; !found {
+			c.unsupported = append(c.unsupported, option)
+		}
+	}
+	sort.Strings(c.unsupported)
+
+	if c.endpoint = c.OpenMetricsEndpoint; c.endpoint == "" {
+		return errors.New("the openmetrics_endpoint setting is required")
+	}
+
+	c.timeout = defaultTimeout
+	if c.Timeout > 0 {
+		c.timeout = time.Duration(c.Timeout * float64(time.Second))
+	}
+
+	if err := c.parseMetrics(); err != nil {
+		return err
+	}
+	if len(c.ExcludeMetrics) > 0 {
+		excludes, err := compileAnyOf(c.ExcludeMetrics)
+		if err != nil {
+			return fmt.Errorf("invalid exclude_metrics: %w", err)
+		}
+		c.excludes = excludes
+	}
+
+	if len(c.IncludeLabels) > 0 {
+		c.labels = make(map[string]struct{}, len(c.IncludeLabels))
+		for _, label := range c.IncludeLabels {
+			c.labels[label] = struct{}{}
+		}
+	}
+
+	c.baseTags = append(c.baseTags, c.Tags...)
+	if c.TagByEndpoint == nil || *c.TagByEndpoint {
+		c.baseTags = append(c.baseTags, "endpoint:"+c.endpoint)
+	}
+	c.healthTags = append(append([]string{}, c.Tags...), "endpoint:"+c.endpoint)
+	return nil
+}
+
+// parseMetrics parses the `metrics` setting, made of regular expressions
+// matching the metrics to collect and of mappings renaming metrics.
+func (c *config) parseMetrics() error {
+	var patterns []string
+	c.renames = make(map[string]string)
+	for _, item := range c.Metrics {
+		switch v := item.(type) {
+		case string:
+			patterns = append(patterns, v)
+		case map[interface{}]interface{}:
+			for rawName, value := range v {
+				name, err := parseRename(value)
+				if err != nil {
+					return fmt.Errorf("invalid metrics mapping for %v: %w", rawName, err)
+				}
+				c.renames[fmt.Sprint(rawName)] = name
+			}
+		default:
+			return fmt.Errorf("invalid metrics item %v, expected a string or a mapping", item)
+		}
+	}
+	if len(patterns) == 0 && len(c.renames) == 0 {
+		return errors.New("the metrics setting is required")
+	}
+	if len(patterns) > 0 {
+		includes, err := compileAnyOf(patterns)
+		if err != nil {
+			return fmt.Errorf("invalid metrics: %w", err)
+		}
+		c.includes = includes
+	}
+	return nil
+}
+
+func parseRename(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case map[interface{}]interface{}:
+		if name, ok := v["name"].(string); ok {
+			return name, nil
+		}
+	}
+	return "", errors.New("expected a name")
+}
+
+// compileAnyOf returns a regular expression fully matching any of the patterns.
+func compileAnyOf(patterns []string) (*regexp.Regexp, error) {
+	return regexp.Compile("^(?:" + strings.Join(patterns, "|") + ")$")
+}
+
+// yamlFields returns the yaml names of the fields of the structs.
+func yamlFields(structs ...interface{}) map[string]struct{} {
+	fields := make(map[string]struct{})
+	for _, s := range structs {
+		t := reflect.TypeOf(s)
+		for i := 0; i < t.NumField(); i++ {
+			if name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ","); name != "" {
+				fields[name] = struct{}{}
+			}
+		}
+	}
+	return fields
+}
+
+// metricName returns the name the raw metric is submitted with, without
+// namespace, and false if it must not be collected.
+func (c *config) metricName(rawName string) (string, bool) {
+	name := strings.TrimPrefix(rawName, c.RawMetricPrefix)
+	if c.excludes != nil && c.excludes.MatchString(name) {
+		return "", false
+	}
+	if renamed, found := c.renames[name]; found {
+		return renamed, true
+	}
+	if c.includes != nil && c.includes.MatchString(name) {
+		return name, true
+	}
+	return "", false
+}
+
+// labelTag returns the tag of a label and false if it must be ignored.
+func (c *config) labelTag(name, value string) (string, bool) {
+	if c.labels != nil {
+		if _, found := c.labels[name]; !found {
+			return "", false
+		}
+	}
+	for _, excluded := range c.ExcludeLabels {
+		if excluded == name {
+			return "", false
+		}
+	}
+	if renamed, found := c.RenameLabels[name]; found {
+		name = renamed
+	}
+	return name + ":" + value, true
+}
+
+func (c *config) healthServiceCheckEnabled() bool {
+	return c.EnableHealthServiceCheck == nil || *c.EnableHealthServiceCheck
+}
+
+func (c *config) collectHistogramBuckets() bool {
+	return c.CollectHistogramBuckets == nil || *c.CollectHistogramBuckets
+}
@@ -0,0 +1,241 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package openmetrics implements a core check scraping OpenMetrics and
+// Prometheus endpoints. It accepts the configuration of the openmetrics
+// Python check and is used in its place by instances setting `loader: core`.
+package openmetrics
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const (
+	// CheckName is the name of the check
+	CheckName = "openmetrics"
+
+	healthServiceCheck = "openmetrics.health"
+)
+
+// Check scrapes an OpenMetrics or Prometheus endpoint.
+type Check struct {
+	core.CheckBase
+	config  *config
+	scraper *scraper
+	// names caches the resolution of the raw metric names
+	names map[string]metricName
+}
+
+type metricName struct {
+	name    string
+	collect bool
+}
+
+// Configure parses the check configuration and initializes the check.
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfig integration.Data, source string) error {
+	cfg := &config{}
+	if err := cfg.parse(data); err != nil {
+		log.Errorf("Error parsing configuration file: %s", err)
+		return err
+	}
+	if len(cfg.unsupported) > 0 {
+		log.Warnf("The openmetrics core check ignores the unsupported options: %s", strings.Join(cfg.unsupported, ", "))
+	}
+
+	c.BuildID(integrationConfigDigest, data, initConfig)
+	if err := c.CommonConfigure(senderManager, initConfig, data, source); err != nil {
+		return err
+	}
+
+	scraper, err := newScraper(cfg)
+	if err != nil {
+		return err
+	}
+	c.config = cfg
+	c.scraper = scraper
+	c.names = make(map[string]metricName)
+	return nil
+}
+
+// Run scrapes the endpoint and submits the metrics.
+func (c *Check) Run() error {
+	return c.RunWithContext(context.Background())
+}
+
+// RunWithContext scrapes the endpoint, until ctx is cancelled, and submits the metrics.
+func (c *Check) RunWithContext(ctx context.Context) error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+
+	err = c.scraper.scrape(ctx, func(family *dto.MetricFamily) {
+		c.submitFamily(sender, family)
+	})
+	if c.config.healthServiceCheckEnabled() {
+		status, message := servicecheck.ServiceCheckOK, ""
+		if err != nil {
+			status, message = servicecheck.ServiceCheckCritical, err.Error()
+		}
+		sender.ServiceCheck(c.prefixed(healthServiceCheck), status, "", c.config.healthTags, message)
+	}
+	sender.Commit()
+	return err
+}
+
+// resolveName returns the name the metrics of a family are submitted with
+// and false if the family must not be collected.
+func (c *Check) resolveName(family *dto.MetricFamily) (string, bool) {
+	rawName := family.GetName()
+	if resolved, found := c.names[rawName]; found {
+		return resolved.name, resolved.collect
+	}
+
+	name := rawName
+	if family.GetType() == dto.MetricType_COUNTER {
+		name = strings.TrimSuffix(name, "_total")
+	}
+	name, collect := c.config.metricName(name)
+	if collect {
+		name = c.prefixed(name)
+	}
+	c.names[rawName] = metricName{name: name, collect: collect}
+	return name, collect
+}
+
+func (c *Check) prefixed(name string) string {
+	if c.config.Namespace == "" {
+		return name
+	}
+	return c.config.Namespace + "." + name
+}
+
+// tags returns the hostname and the tags of a metric.
+func (c *Check) tags(metric *dto.Metric) (string, []string) {
+	var hostname string
+	tags := make([]string, 0, len(c.config.baseTags)+len(metric.GetLabel()))
+	tags = append(tags, c.config.baseTags...)
+	for _, label := range metric.GetLabel() {
+		if c.config.HostnameLabel != "" && label.GetName() == c.config.HostnameLabel {
+			hostname = label.GetValue()
+		}
+		if tag, ok := c.config.labelTag(label.GetName(), label.GetValue()); ok {
+			tags = append(tags, tag)
+		}
+	}
+	// the histogram and summary tags are appended to copies of the slice
+	return hostname, tags[:len(tags):len(tags)]
+}
+
+func (c *Check) submitFamily(sender sender.Sender, family *dto.MetricFamily) {
+	name, collect := c.resolveName(family)
+	if !collect {
+		return
+	}
+
+	for _, metric := range family.GetMetric() {
+		hostname, tags := c.tags(metric)
+		switch family.GetType() {
+		case dto.MetricType_GAUGE:
+			sender.Gauge(name, metric.GetGauge().GetValue(), hostname, tags)
+		case dto.MetricType_UNTYPED:
+			sender.Gauge(name, metric.GetUntyped().GetValue(), hostname, tags)
+		case dto.MetricType_COUNTER:
+			sender.MonotonicCount(name+".count", metric.GetCounter().GetValue(), hostname, tags)
+		case dto.MetricType_SUMMARY:
+			c.submitSummary(sender, name, metric.GetSummary(), hostname, tags)
+		case dto.MetricType_HISTOGRAM, dto.MetricType_GAUGE_HISTOGRAM:
+			c.submitHistogram(sender, name, metric.GetHistogram(), hostname, tags)
+		}
+	}
+}
+
+func (c *Check) submitSummary(sender sender.Sender, name string, summary *dto.Summary, hostname string, tags []string) {
+	sender.MonotonicCount(name+".sum", summary.GetSampleSum(), hostname, tags)
+	sender.MonotonicCount(name+".count", float64(summary.GetSampleCount()), hostname, tags)
+	for _, quantile := range summary.GetQuantile() {
+		if math.IsNaN(quantile.GetValue()) {
+			continue
+		}
+		sender.Gauge(name+".quantile", quantile.GetValue(), hostname, append(tags, "quantile:"+formatFloat(quantile.GetQuantile())))
+	}
+}
+
+func (c *Check) submitHistogram(sender sender.Sender, name string, histogram *dto.Histogram, hostname string, tags []string) {
+	if !c.config.HistogramBucketsAsDistributions || c.config.CollectCountersWithDistribution {
+		sender.MonotonicCount(name+".sum", histogram.GetSampleSum(), hostname, tags)
+		sender.MonotonicCount(name+".count", float64(histogram.GetSampleCount()), hostname, tags)
+	}
+	if !c.config.collectHistogramBuckets() && !c.config.HistogramBucketsAsDistributions {
+		return
+	}
+
+	buckets := histogram.GetBucket()
+	// The +Inf bucket is implicit in the protobuf format
+	if len(buckets) == 0 || !math.IsInf(buckets[len(buckets)-1].GetUpperBound(), 1) {
+		count, upperBound := histogram.GetSampleCount(), math.Inf(1)
+		buckets = append(buckets[:len(buckets):len(buckets)], &dto.Bucket{CumulativeCount: &count, UpperBound: &upperBound})
+	}
+
+	// Like the Python check, the first bucket starts at 0 unless it holds negative values
+	lowerBound, previousCount := 0.0, uint64(0)
+	if buckets[0].GetUpperBound() <= 0 {
+		lowerBound = math.Inf(-1)
+	}
+	for _, bucket := range buckets {
+		upperBound, count := bucket.GetUpperBound(), bucket.GetCumulativeCount()
+		// The cumulative counts of a malformed histogram can decrease, the
+		// difference between the buckets must not wrap around
+		count = max(count, previousCount)
+		switch {
+		case c.config.HistogramBucketsAsDistributions:
+			sender.HistogramBucket(name, int64(count-previousCount), lowerBound, upperBound, true, hostname, tags, false)
+		case c.config.NonCumulativeHistogramBuckets:
+			bucketTags := append(tags, "lower_bound:"+formatBound(lowerBound), "upper_bound:"+formatBound(upperBound))
+			sender.MonotonicCount(name+".bucket", float64(count-previousCount), hostname, bucketTags)
+		default:
+			sender.MonotonicCount(name+".bucket", float64(count), hostname, append(tags, "upper_bound:"+formatBound(upperBound)))
+		}
+		lowerBound, previousCount = upperBound, count
+	}
+}
+
+func formatBound(bound float64) string {
+	switch {
+	case math.IsInf(bound, 1):
+		return "inf"
+	case math.IsInf(bound, -1):
+		return "-inf"
+	}
+	return formatFloat(bound)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Factory creates a new check factory
+func Factory() optional.Option[func() check.Check] {
+	return optional.NewOption(newCheck)
+}
+
+func newCheck() check.Check {
+	return &Check{
+		CheckBase: core.NewCheckBase(CheckName),
+	}
+}
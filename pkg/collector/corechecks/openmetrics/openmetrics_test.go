@@ -0,0 +1,312 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+const textPayload = `# HELP go_goroutines Number of goroutines that currently exist.
+# TYPE go_goroutines gauge
+go_goroutines 42
+# HELP http_requests_total The total number of HTTP requests.
+# TYPE http_requests_total counter
+http_requests_total{method="post",code="200",pod="web-1"} 1027
+http_requests_total{method="post",code="400",pod="web-1"} 3
+# HELP rpc_duration_seconds A summary of the RPC duration in seconds.
+# TYPE rpc_duration_seconds summary
+rpc_duration_seconds{quantile="0.5"} 4773
+rpc_duration_seconds{quantile="0.99"} 76656
+rpc_duration_seconds_sum 1.7560473e+07
+rpc_duration_seconds_count 2693
+# HELP request_duration_seconds A histogram of the request duration.
+# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 10
+request_duration_seconds_bucket{le="0.5"} 25
+request_duration_seconds_bucket{le="+Inf"} 30
+request_duration_seconds_sum 8.5
+request_duration_seconds_count 30
+# TYPE ignored_metric gauge
+ignored_metric 1
+`
+
+func newTestServer(t *testing.T, contentType string, body []byte) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/metrics" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runCheck(t *testing.T, instance string) *mocksender.MockSender {
+	check := newCheck().(*Check)
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	err := check.Configure(senderManager, integration.FakeConfigHash, []byte(instance), nil, "test")
+	require.NoError(t, err)
+
+	mockSender := mocksender.NewMockSenderWithSenderManager(check.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	check.Run()
+	return mockSender
+}
+
+func TestTextFormat(t *testing.T) {
+	server := newTestServer(t, "text/plain; version=0.0.4", []byte(textPayload))
+	endpoint := server.URL + "/metrics"
+
+	mockSender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s
+namespace: app
+metrics:
+  - go_goroutines
+  - http_requests
+  - rpc_duration_seconds
+  - request_duration_seconds: request.duration
+rename_labels:
+  code: status_code
+exclude_labels:
+  - pod
+tags:
+  - team:web
+`, endpoint))
+
+	endpointTag := "endpoint:" + endpoint
+	mockSender.AssertMetric(t, "Gauge", "app.go_goroutines", 42, "", []string{"team:web", endpointTag})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.http_requests.count", 1027, "", []string{"team:web", endpointTag, "method:post", "status_code:200"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.http_requests.count", 3, "", []string{"method:post", "status_code:400"})
+	mockSender.AssertMetricNotTaggedWith(t, "MonotonicCount", "app.http_requests.count", []string{"pod:web-1"})
+
+	mockSender.AssertMetric(t, "Gauge", "app.rpc_duration_seconds.quantile", 4773, "", []string{"quantile:0.5"})
+	mockSender.AssertMetric(t, "Gauge", "app.rpc_duration_seconds.quantile", 76656, "", []string{"quantile:0.99"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.rpc_duration_seconds.sum", 1.7560473e+07, "", nil)
+	mockSender.AssertMetric(t, "MonotonicCount", "app.rpc_duration_seconds.count", 2693, "", nil)
+
+	mockSender.AssertMetric(t, "MonotonicCount", "app.request.duration.bucket", 10, "", []string{"upper_bound:0.1"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.request.duration.bucket", 25, "", []string{"upper_bound:0.5"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.request.duration.bucket", 30, "", []string{"upper_bound:inf"})
+	mockSender.AssertMetric(t, "MonotonicCount", "app.request.duration.sum", 8.5, "", nil)
+	mockSender.AssertMetric(t, "MonotonicCount", "app.request.duration.count", 30, "", nil)
+
+	mockSender.AssertNotCalled(t, "Gauge", "app.ignored_metric", 1.0, "", mocksender.MatchTagsContains(nil))
+	mockSender.AssertServiceCheck(t, "app.openmetrics.health", servicecheck.ServiceCheckOK, "", []string{"team:web", endpointTag}, "")
+	mockSender.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestProtobufFormat(t *testing.T) {
+	families := []*dto.MetricFamily{
+		{
+			Name: proto.String("queue_depth"),
+			Type: dto.MetricType_GAUGE.Enum(),
+			Metric: []*dto.Metric{{
+				Label: []*dto.LabelPair{{Name: proto.String("node"), Value: proto.String("node-1")}},
+				Gauge: &dto.Gauge{Value: proto.Float64(7)},
+			}},
+		},
+		{
+			Name: proto.String("latency_seconds"),
+			Type: dto.MetricType_HISTOGRAM.Enum(),
+			Metric: []*dto.Metric{{
+				Histogram: &dto.Histogram{
+					SampleCount: proto.Uint64(12),
+					SampleSum:   proto.Float64(3),
+					// The +Inf bucket is implicit
+					Bucket: []*dto.Bucket{
+						{UpperBound: proto.Float64(0.25), CumulativeCount: proto.Uint64(4)},
+						{UpperBound: proto.Float64(1), CumulativeCount: proto.Uint64(10)},
+					},
+				},
+			}},
+		},
+	}
+	var body strings.Builder
+	encoder := expfmt.NewEncoder(&body, expfmt.FmtProtoDelim)
+	for _, family := range families {
+		require.NoError(t, encoder.Encode(family))
+	}
+	server := newTestServer(t, string(expfmt.FmtProtoDelim), []byte(body.String()))
+
+	mockSender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+metrics:
+  - .*
+hostname_label: node
+histogram_buckets_as_distributions: true
+tag_by_endpoint: false
+`, server.URL))
+
+	mockSender.AssertMetric(t, "Gauge", "queue_depth", 7, "node-1", []string{"node:node-1"})
+	mockSender.AssertHistogramBucket(t, "HistogramBucket", "latency_seconds", 4, 0, 0.25, true, "", []string{}, false)
+	mockSender.AssertHistogramBucket(t, "HistogramBucket", "latency_seconds", 6, 0.25, 1, true, "", []string{}, false)
+	mockSender.AssertHistogramBucket(t, "HistogramBucket", "latency_seconds", 2, 1, math.Inf(1), true, "", []string{}, false)
+	mockSender.AssertNotCalled(t, "MonotonicCount", "latency_seconds.count", 12.0, "", mocksender.MatchTagsContains(nil))
+	mockSender.AssertServiceCheck(t, "openmetrics.health", servicecheck.ServiceCheckOK, "", nil, "")
+}
+
+func TestNonCumulativeBuckets(t *testing.T) {
+	server := newTestServer(t, "text/plain; version=0.0.4", []byte(textPayload))
+
+	mockSender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+metrics:
+  - request_duration_seconds
+non_cumulative_histogram_buckets: true
+`, server.URL))
+
+	mockSender.AssertMetric(t, "MonotonicCount", "request_duration_seconds.bucket", 10, "", []string{"lower_bound:0", "upper_bound:0.1"})
+	mockSender.AssertMetric(t, "MonotonicCount", "request_duration_seconds.bucket", 15, "", []string{"lower_bound:0.1", "upper_bound:0.5"})
+	mockSender.AssertMetric(t, "MonotonicCount", "request_duration_seconds.bucket", 5, "", []string{"lower_bound:0.5", "upper_bound:inf"})
+}
+
+func TestEndpointDown(t *testing.T) {
+	server := newTestServer(t, "text/plain", nil)
+
+	mockSender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/not-found
+namespace: app
+metrics:
+  - .*
+`, server.URL))
+
+	mockSender.AssertServiceCheck(t, "app.openmetrics.health", servicecheck.ServiceCheckCritical, "", nil, "unexpected status code 404")
+	mockSender.AssertNotCalled(t, "Gauge", mocksender.AnythingBut(""), 0.0, "", mocksender.MatchTagsContains(nil))
+}
+
+func TestConfigParse(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		instance string
+		err      string
+		check    func(t *testing.T, c *config)
+	}{
+		{
+			name:     "endpoint required",
+			instance: "metrics: [foo]",
+			err:      "openmetrics_endpoint",
+		},
+		{
+			name:     "metrics required",
+			instance: "openmetrics_endpoint: http://localhost/metrics",
+			err:      "metrics setting is required",
+		},
+		{
+			name:     "invalid regexp",
+			instance: "openmetrics_endpoint: http://localhost/metrics\nmetrics: ['foo(']",
+			err:      "invalid metrics",
+		},
+		{
+			name: "v2",
+			instance: `
+openmetrics_endpoint: http://localhost/metrics
+raw_metric_prefix: envoy_
+metrics:
+  - cluster_.*
+  - server_uptime: uptime
+  - server_live:
+      name: live
+exclude_metrics:
+  - cluster_internal_.*
+include_labels: [cluster]
+`,
+			check: func(t *testing.T, c *config) {
+				assert.Equal(t, defaultTimeout, c.timeout)
+				assert.Equal(t, []string{"endpoint:http://localhost/metrics"}, c.baseTags)
+
+				for raw, expected := range map[string]string{
+					"envoy_cluster_upstream_rq": "cluster_upstream_rq",
+					"envoy_server_uptime":       "uptime",
+					"envoy_server_live":         "live",
+					"envoy_cluster_internal_rq": "",
+					"envoy_listener_rq":         "",
+				} {
+					name, collect := c.metricName(raw)
+					assert.Equal(t, expected, name, raw)
+					assert.Equal(t, expected != "", collect, raw)
+				}
+
+				tag, ok := c.labelTag("cluster", "backend")
+				assert.True(t, ok)
+				assert.Equal(t, "cluster:backend", tag)
+				_, ok = c.labelTag("pod", "web-1")
+				assert.False(t, ok)
+			},
+		},
+		{
+			name: "v1 instance",
+			instance: `
+prometheus_url: http://localhost/metrics
+metrics: ['*']
+`,
+			err: "openmetrics v1 instances are not supported",
+		},
+		{
+			name: "unsupported options",
+			instance: `
+openmetrics_endpoint: http://localhost/metrics
+metrics: ['.*']
+min_collection_interval: 30
+loader: core
+share_labels:
+  build_info: true
+ignore_tags: ['pod:.*']
+`,
+			check: func(t *testing.T, c *config) {
+				assert.Equal(t, []string{"ignore_tags", "share_labels"}, c.unsupported)
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &config{}
+			err := c.parse([]byte(tc.instance))
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, c)
+		})
+	}
+}
+
+func TestNonMonotonicBuckets(t *testing.T) {
+	server := newTestServer(t, "text/plain; version=0.0.4", []byte(`# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{le="0.1"} 10
+request_duration_seconds_bucket{le="0.5"} 8
+request_duration_seconds_bucket{le="+Inf"} 12
+request_duration_seconds_sum 3
+request_duration_seconds_count 12
+`))
+
+	mockSender := runCheck(t, fmt.Sprintf(`
+openmetrics_endpoint: %s/metrics
+metrics:
+  - request_duration_seconds
+non_cumulative_histogram_buckets: true
+`, server.URL))
+
+	mockSender.AssertMetric(t, "MonotonicCount", "request_duration_seconds.bucket", 10, "", []string{"lower_bound:0", "upper_bound:0.1"})
+	mockSender.AssertMetric(t, "MonotonicCount", "request_duration_seconds.bucket", 0, "", []string{"lower_bound:0.1", "upper_bound:0.5"})
+	mockSender.AssertMetric(t, "MonotonicCount", "request_duration_seconds.bucket", 2, "", []string{"lower_bound:0.5", "upper_bound:inf"})
+}
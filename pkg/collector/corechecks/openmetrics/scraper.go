@@ -0,0 +1,141 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package openmetrics
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// acceptHeader prefers the protobuf exposition format and falls back to the
+// text format, like the Prometheus server does.
+const acceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,text/plain;version=0.0.4;q=0.3,*/*;q=0.1`
+
+// scraper fetches and decodes the metrics exposed by an endpoint.
+type scraper struct {
+	endpoint        string
+	client          *http.Client
+	headers         map[string]string
+	username        string
+	password        string
+	bearerTokenPath string
+}
+
+func newScraper(c *config) (*scraper, error) {
+	tlsConfig, err := buildTLSConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	s := &scraper{
+		endpoint: c.endpoint,
+		client:   &http.Client{Transport: transport, Timeout: c.timeout},
+		headers:  make(map[string]string, len(c.Headers)+len(c.ExtraHeaders)),
+		username: c.Username,
+		password: c.Password,
+	}
+	for k, v := range c.Headers {
+		s.headers[k] = v
+	}
+	for k, v := range c.ExtraHeaders {
+		s.headers[k] = v
+	}
+	if c.BearerTokenAuth {
+		s.bearerTokenPath = c.BearerTokenPath
+		if s.bearerTokenPath == "" {
+			s.bearerTokenPath = defaultBearerTokenPath
+		}
+	}
+	return s, nil
+}
+
+func buildTLSConfig(c *config) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: c.TLSVerify != nil && !*c.TLSVerify, //nolint:gosec // explicitly configured by the user
+	}
+	if c.TLSCACert != "" {
+		pem, err := os.ReadFile(c.TLSCACert)
+		if err != nil {
+			return nil, fmt.Errorf("can't read tls_ca_cert: %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in tls_ca_cert %s", c.TLSCACert)
+		}
+	}
+	if c.TLSCert != "" {
+		keyPath := c.TLSPrivateKey
+		if keyPath == "" {
+			// the private key may be bundled with the certificate
+			keyPath = c.TLSCert
+		}
+		cert, err := tls.LoadX509KeyPair(c.TLSCert, keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("can't load tls_cert: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// scrape calls fn with every metric family exposed by the endpoint.
+func (s *scraper) scrape(ctx context.Context, fn func(*dto.MetricFamily)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", acceptHeader)
+	for k, v := range s.headers {
+		req.Header.Set(k, v)
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+	if s.bearerTokenPath != "" {
+		// the token is read on every scrape as it may be rotated
+		token, err := os.ReadFile(s.bearerTokenPath)
+		if err != nil {
+			return fmt.Errorf("can't read the bearer token: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	// the protobuf decoder wraps its reader in a bufio.Reader on every call,
+	// which is a no-op when it is already buffered
+	decoder := expfmt.NewDecoder(bufio.NewReader(resp.Body), expfmt.ResponseFormat(resp.Header))
+	for {
+		family := &dto.MetricFamily{}
+		if err := decoder.Decode(family); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("can't decode the metrics: %w", err)
+		}
+		fn(family)
+	}
+}
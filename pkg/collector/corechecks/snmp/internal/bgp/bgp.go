@@ -0,0 +1,25 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+// Package bgp contains mappings for BGP4-MIB values
+package bgp
+
+// PeerStateMap mapping to translate into human-readable value for bgpPeerState
+// Ref https://www.rfc-editor.org/rfc/rfc4273#section-5 (bgpPeerState)
+var PeerStateMap = map[string]string{
+	"1": "idle",
+	"2": "connect",
+	"3": "active",
+	"4": "opensent",
+	"5": "openconfirm",
+	"6": "established",
+}
+
+// AdminStatusMap mapping to translate into human-readable value for bgpPeerAdminStatus
+// Ref https://www.rfc-editor.org/rfc/rfc4273#section-5 (bgpPeerAdminStatus)
+var AdminStatusMap = map[string]string{
+	"1": "stop",
+	"2": "start",
+}
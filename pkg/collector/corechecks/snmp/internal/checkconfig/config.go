@@ -87,6 +87,7 @@ type InitConfig struct {
 	BulkMaxRepetitions           Number                            `yaml:"bulk_max_repetitions"`
 	CollectDeviceMetadata        Boolean                           `yaml:"collect_device_metadata"`
 	CollectTopology              Boolean                           `yaml:"collect_topology"`
+	CollectBGPPeers              Boolean                           `yaml:"collect_bgp_peers"`
 	UseDeviceIDAsHostname        Boolean                           `yaml:"use_device_id_as_hostname"`
 	MinCollectionInterval        int                               `yaml:"min_collection_interval"`
 	Namespace                    string                            `yaml:"namespace"`
@@ -116,6 +117,7 @@ type InstanceConfig struct {
 	UseGlobalMetrics      bool                                `yaml:"use_global_metrics"`
 	CollectDeviceMetadata *Boolean                            `yaml:"collect_device_metadata"`
 	CollectTopology       *Boolean                            `yaml:"collect_topology"`
+	CollectBGPPeers       *Boolean                            `yaml:"collect_bgp_peers"`
 	UseDeviceIDAsHostname *Boolean                            `yaml:"use_device_id_as_hostname"`
 	PingConfig            snmpintegration.PackedPingConfig    `yaml:"ping"`
 
@@ -193,6 +195,7 @@ type CheckConfig struct {
 	InstanceTags          []string
 	CollectDeviceMetadata bool
 	CollectTopology       bool
+	CollectBGPPeers       bool
 	UseDeviceIDAsHostname bool
 	DeviceID              string
 	DeviceIDTags          []string
@@ -261,11 +264,11 @@ func (c *CheckConfig) RebuildMetadataMetricsAndTags() {
 	c.Metrics = c.RequestedMetrics
 	c.MetricTags = c.RequestedMetricTags
 	if c.ProfileDef != nil {
-		c.Metadata = updateMetadataDefinitionWithDefaults(c.ProfileDef.Metadata, c.CollectTopology)
+		c.Metadata = updateMetadataDefinitionWithDefaults(c.ProfileDef.Metadata, c.CollectTopology, c.CollectBGPPeers)
 		c.Metrics = append(c.Metrics, c.ProfileDef.Metrics...)
 		c.MetricTags = append(c.MetricTags, c.ProfileDef.MetricTags...)
 	} else {
-		c.Metadata = updateMetadataDefinitionWithDefaults(nil, c.CollectTopology)
+		c.Metadata = updateMetadataDefinitionWithDefaults(nil, c.CollectTopology, c.CollectBGPPeers)
 	}
 	c.OidConfig.clean()
 	c.OidConfig.addScalarOids(c.parseScalarOids(c.Metrics, c.MetricTags, c.Metadata))
@@ -395,6 +398,12 @@ func NewCheckConfig(rawInstance integration.Data, rawInitConfig integration.Data
 		c.CollectTopology = bool(initConfig.CollectTopology)
 	}
 
+	if instance.CollectBGPPeers != nil {
+		c.CollectBGPPeers = bool(*instance.CollectBGPPeers)
+	} else {
+		c.CollectBGPPeers = bool(initConfig.CollectBGPPeers)
+	}
+
 	if instance.DetectMetricsEnabled != nil {
 		c.DetectMetricsEnabled = bool(*instance.DetectMetricsEnabled)
 	} else {
@@ -697,6 +706,7 @@ func (c *CheckConfig) Copy() *CheckConfig {
 	newConfig.InstanceTags = netutils.CopyStrings(c.InstanceTags)
 	newConfig.CollectDeviceMetadata = c.CollectDeviceMetadata
 	newConfig.CollectTopology = c.CollectTopology
+	newConfig.CollectBGPPeers = c.CollectBGPPeers
 	newConfig.UseDeviceIDAsHostname = c.UseDeviceIDAsHostname
 	newConfig.DeviceID = c.DeviceID
 
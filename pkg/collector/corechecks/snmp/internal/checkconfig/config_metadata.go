@@ -227,15 +227,93 @@ var TopologyMetadataConfig = profiledefinition.MetadataConfig{
 	},
 }
 
+// BGPMetadataConfig represent the metadata needed for BGP peers
+// Ref https://www.rfc-editor.org/rfc/rfc4273 (BGP4-MIB bgpPeerTable)
+var BGPMetadataConfig = profiledefinition.MetadataConfig{
+	"bgp_peer": {
+		Fields: map[string]profiledefinition.MetadataField{
+			"identifier": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.1",
+					Name: "bgpPeerIdentifier",
+				},
+			},
+			"state": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.2",
+					Name: "bgpPeerState",
+				},
+			},
+			"admin_status": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.3",
+					Name: "bgpPeerAdminStatus",
+				},
+			},
+			"negotiated_version": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.4",
+					Name: "bgpPeerNegotiatedVersion",
+				},
+			},
+			"local_address": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.5",
+					Name: "bgpPeerLocalAddr",
+				},
+			},
+			"local_port": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.6",
+					Name: "bgpPeerLocalPort",
+				},
+			},
+			"remote_address": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.7",
+					Name: "bgpPeerRemoteAddr",
+				},
+			},
+			"remote_port": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.8",
+					Name: "bgpPeerRemotePort",
+				},
+			},
+			"remote_as": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.9",
+					Name: "bgpPeerRemoteAs",
+				},
+			},
+			"last_error": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.14",
+					Name: "bgpPeerLastError",
+				},
+			},
+			"established_time": {
+				Symbol: profiledefinition.SymbolConfig{
+					OID:  "1.3.6.1.2.1.15.3.1.16",
+					Name: "bgpPeerFsmEstablishedTime",
+				},
+			},
+		},
+	},
+}
+
 // updateMetadataDefinitionWithDefaults will add metadata config for resources
 // that does not have metadata definitions
-func updateMetadataDefinitionWithDefaults(metadataConfig profiledefinition.MetadataConfig, collectTopology bool) profiledefinition.MetadataConfig {
+func updateMetadataDefinitionWithDefaults(metadataConfig profiledefinition.MetadataConfig, collectTopology bool, collectBGPPeers bool) profiledefinition.MetadataConfig {
 	newConfig := make(profiledefinition.MetadataConfig)
 	mergeMetadata(newConfig, metadataConfig)
 	mergeMetadata(newConfig, LegacyMetadataConfig)
 	if collectTopology {
 		mergeMetadata(newConfig, TopologyMetadataConfig)
 	}
+	if collectBGPPeers {
+		mergeMetadata(newConfig, BGPMetadataConfig)
+	}
 	return newConfig
 }
 
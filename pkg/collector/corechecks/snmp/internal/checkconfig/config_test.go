@@ -1429,6 +1429,43 @@ collect_topology: true
 	assert.Equal(t, false, config.CollectTopology)
 }
 
+func Test_buildConfig_collectBGPPeers(t *testing.T) {
+	// language=yaml
+	rawInstanceConfig := []byte(`
+ip_address: 1.2.3.4
+community_string: "abc"
+`)
+	// language=yaml
+	rawInitConfig := []byte(`
+oid_batch_size: 10
+`)
+	config, err := NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, false, config.CollectBGPPeers)
+	assert.NotContains(t, config.Metadata, "bgp_peer")
+
+	// language=yaml
+	rawInitConfig = []byte(`
+oid_batch_size: 10
+collect_bgp_peers: true
+`)
+	config, err = NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, true, config.CollectBGPPeers)
+	assert.Contains(t, config.Metadata, "bgp_peer")
+	assert.Contains(t, config.OidConfig.ColumnOids, "1.3.6.1.2.1.15.3.1.2")
+
+	// language=yaml
+	rawInstanceConfig = []byte(`
+ip_address: 1.2.3.4
+community_string: "abc"
+collect_bgp_peers: false
+`)
+	config, err = NewCheckConfig(rawInstanceConfig, rawInitConfig)
+	assert.Nil(t, err)
+	assert.Equal(t, false, config.CollectBGPPeers)
+}
+
 func Test_buildConfig_namespace(t *testing.T) {
 	defer pkgconfigsetup.Datadog().SetWithoutSource("network_devices.namespace", "default")
 
@@ -21,6 +21,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/networkdevice/profile/profiledefinition"
 	"github.com/DataDog/datadog-agent/pkg/networkdevice/utils"
 
+	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/bgp"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/common"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/lldp"
@@ -62,8 +63,9 @@ func (ms *MetricSender) ReportNetworkDeviceMetadata(config *checkconfig.CheckCon
 	interfaces := buildNetworkInterfacesMetadata(config.DeviceID, metadataStore)
 	ipAddresses := buildNetworkIPAddressesMetadata(config.DeviceID, metadataStore)
 	topologyLinks := buildNetworkTopologyMetadata(config.DeviceID, metadataStore, interfaces)
+	bgpPeers := buildNetworkBGPPeersMetadata(config.DeviceID, metadataStore)
 
-	metadataPayloads := devicemetadata.BatchPayloads(config.Namespace, config.ResolvedSubnetName, collectTime, devicemetadata.PayloadMetadataBatchSize, devices, interfaces, ipAddresses, topologyLinks, nil, diagnoses)
+	metadataPayloads := devicemetadata.BatchPayloads(config.Namespace, config.ResolvedSubnetName, collectTime, devicemetadata.PayloadMetadataBatchSize, devices, interfaces, ipAddresses, topologyLinks, bgpPeers, nil, diagnoses)
 
 	for _, payload := range metadataPayloads {
 		payloadBytes, err := json.Marshal(payload)
@@ -335,8 +337,27 @@ func buildNetworkTopologyMetadata(deviceID string, store *metadata.Store, interf
 	}
 
 	links := buildNetworkTopologyMetadataWithLLDP(deviceID, store, interfaces)
-	if len(links) == 0 {
-		links = buildNetworkTopologyMetadataWithCDP(deviceID, store, interfaces)
+
+	// LLDP is preferred, CDP links are only added for the local interfaces without LLDP neighbors,
+	// so that devices running both protocols don't report the same links twice.
+	localInterfacesWithLLDP := make(map[string]struct{}, len(links))
+	for _, link := range links {
+		if link.Local.Interface.DDID != "" {
+			localInterfacesWithLLDP[link.Local.Interface.DDID] = struct{}{}
+		}
+	}
+	hasUnresolvedLLDPLinks := len(localInterfacesWithLLDP) < len(links)
+	for _, link := range buildNetworkTopologyMetadataWithCDP(deviceID, store, interfaces) {
+		if _, ok := localInterfacesWithLLDP[link.Local.Interface.DDID]; ok {
+			continue
+		}
+		if hasUnresolvedLLDPLinks {
+			// we can't tell which local interfaces the unresolved LLDP links belong to,
+			// keep the previous behavior of using only LLDP in that case
+			log.Debugf("Skipping CDP link %s: some LLDP links have no resolved local interface", link.ID)
+			continue
+		}
+		links = append(links, link)
 	}
 	return links
 }
@@ -476,6 +497,51 @@ func buildNetworkTopologyMetadataWithCDP(deviceID string, store *metadata.Store,
 	return links
 }
 
+func buildNetworkBGPPeersMetadata(deviceID string, store *metadata.Store) []devicemetadata.BGPPeerMetadata {
+	if store == nil {
+		// it's expected that the value store is nil if we can't reach the device
+		// in that case, we just return a nil slice.
+		return nil
+	}
+	indexes := store.GetColumnIndexes("bgp_peer.state") // using `bgp_peer.state` to get indexes since it's expected to be always present
+	if len(indexes) == 0 {
+		log.Debugf("Unable to build BGP peers metadata: no bgp_peer indexes found")
+		return nil
+	}
+	sort.Strings(indexes)
+	var peers []devicemetadata.BGPPeerMetadata
+	for _, strIndex := range indexes {
+		// The bgpPeerEntry index is bgpPeerRemoteAddr
+		remoteAddress := store.GetColumnAsString("bgp_peer.remote_address", strIndex)
+		if remoteAddress == "" {
+			remoteAddress = strIndex
+		}
+
+		peer := devicemetadata.BGPPeerMetadata{
+			ID:                deviceID + ":" + strIndex,
+			DeviceID:          deviceID,
+			RemoteAddress:     remoteAddress,
+			RemoteAS:          uint32(store.GetColumnAsFloat("bgp_peer.remote_as", strIndex)),
+			RemotePort:        int32(store.GetColumnAsFloat("bgp_peer.remote_port", strIndex)),
+			LocalAddress:      store.GetColumnAsString("bgp_peer.local_address", strIndex),
+			LocalPort:         int32(store.GetColumnAsFloat("bgp_peer.local_port", strIndex)),
+			Identifier:        store.GetColumnAsString("bgp_peer.identifier", strIndex),
+			State:             bgp.PeerStateMap[store.GetColumnAsString("bgp_peer.state", strIndex)],
+			AdminStatus:       bgp.AdminStatusMap[store.GetColumnAsString("bgp_peer.admin_status", strIndex)],
+			NegotiatedVersion: int32(store.GetColumnAsFloat("bgp_peer.negotiated_version", strIndex)),
+			EstablishedTime:   uint32(store.GetColumnAsFloat("bgp_peer.established_time", strIndex)),
+		}
+
+		// bgpPeerLastError is composed of the error code and subcode of the last NOTIFICATION message
+		if lastError := store.GetColumnAsByteArray("bgp_peer.last_error", strIndex); len(lastError) == 2 {
+			peer.LastErrorCode = int32(lastError[0])
+			peer.LastErrorSubcode = int32(lastError[1])
+		}
+		peers = append(peers, peer)
+	}
+	return peers
+}
+
 func getRemDeviceAddressByCDPRemIndex(store *metadata.Store, strIndex string) string {
 	remoteDeviceAddressType := store.GetColumnAsString("cdp_remote.device_address_type", strIndex)
 	if remoteDeviceAddressType == ciscoNetworkProtocolIPv4 || remoteDeviceAddressType == ciscoNetworkProtocolIPv6 {
@@ -24,6 +24,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/snmp/snmpintegration"
 
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/checkconfig"
+	snmpmetadata "github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/metadata"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/profile"
 	"github.com/DataDog/datadog-agent/pkg/collector/corechecks/snmp/internal/valuestore"
 )
@@ -990,3 +991,93 @@ func Test_getProfileVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildNetworkTopologyMetadata_LLDPAndCDP(t *testing.T) {
+	store := snmpmetadata.NewMetadataStore()
+	// LLDP neighbor on interface 1 (resolved by interface name)
+	store.AddColumnValue("lldp_remote.interface_id", "0.101.1", valuestore.ResultValue{Value: []byte("Gi0/1")})
+	store.AddColumnValue("lldp_remote.device_name", "0.101.1", valuestore.ResultValue{Value: []byte("lldp-neighbor")})
+	store.AddColumnValue("lldp_local.interface_id_type", "101", valuestore.ResultValue{Value: float64(5)}) // 5->interface_name
+	store.AddColumnValue("lldp_local.interface_id", "101", valuestore.ResultValue{Value: []byte("eth1")})
+	// CDP neighbors on interfaces 1 (also seen with LLDP) and 2 (CDP only)
+	for _, index := range []string{"1.5", "2.3"} {
+		store.AddColumnValue("cdp_remote.interface_id", index, valuestore.ResultValue{Value: []byte("Gi0/" + index)})
+		store.AddColumnValue("cdp_remote.device_name", index, valuestore.ResultValue{Value: []byte("cdp-neighbor-" + index)})
+		store.AddColumnValue("cdp_remote.device_address_type", index, valuestore.ResultValue{Value: float64(1)})
+		store.AddColumnValue("cdp_remote.device_address", index, valuestore.ResultValue{Value: []byte{10, 0, 0, 1}})
+	}
+	interfaces := []metadata.InterfaceMetadata{
+		{DeviceID: "default:1.2.3.4", Index: 1, Name: "eth1"},
+		{DeviceID: "default:1.2.3.4", Index: 2, Name: "eth2"},
+	}
+
+	links := buildNetworkTopologyMetadata("default:1.2.3.4", store, interfaces)
+
+	assert.Len(t, links, 2)
+	assert.Equal(t, "lldp", links[0].SourceType)
+	assert.Equal(t, "default:1.2.3.4:1", links[0].Local.Interface.DDID)
+	assert.Equal(t, "lldp-neighbor", links[0].Remote.Device.Name)
+	assert.Equal(t, "cdp", links[1].SourceType)
+	assert.Equal(t, "default:1.2.3.4:2.3", links[1].ID)
+	assert.Equal(t, "default:1.2.3.4:2", links[1].Local.Interface.DDID)
+	assert.Equal(t, "cdp-neighbor-2.3", links[1].Remote.Device.Name)
+	assert.Equal(t, "10.0.0.1", links[1].Remote.Device.IPAddress)
+
+	// CDP links are skipped when the local interface of some LLDP links is unknown
+	store.AddColumnValue("lldp_remote.interface_id", "0.102.2", valuestore.ResultValue{Value: []byte("Gi0/2")})
+	links = buildNetworkTopologyMetadata("default:1.2.3.4", store, interfaces)
+	assert.Len(t, links, 2)
+	assert.Equal(t, "lldp", links[0].SourceType)
+	assert.Equal(t, "lldp", links[1].SourceType)
+}
+
+func Test_buildNetworkBGPPeersMetadata(t *testing.T) {
+	store := snmpmetadata.NewMetadataStore()
+	assert.Nil(t, buildNetworkBGPPeersMetadata("default:1.2.3.4", store))
+
+	store.AddColumnValue("bgp_peer.state", "10.0.0.2", valuestore.ResultValue{Value: float64(6)})
+	store.AddColumnValue("bgp_peer.admin_status", "10.0.0.2", valuestore.ResultValue{Value: float64(2)})
+	store.AddColumnValue("bgp_peer.identifier", "10.0.0.2", valuestore.ResultValue{Value: "192.168.0.2"})
+	store.AddColumnValue("bgp_peer.negotiated_version", "10.0.0.2", valuestore.ResultValue{Value: float64(4)})
+	store.AddColumnValue("bgp_peer.local_address", "10.0.0.2", valuestore.ResultValue{Value: "10.0.0.1"})
+	store.AddColumnValue("bgp_peer.local_port", "10.0.0.2", valuestore.ResultValue{Value: float64(179)})
+	store.AddColumnValue("bgp_peer.remote_address", "10.0.0.2", valuestore.ResultValue{Value: "10.0.0.2"})
+	store.AddColumnValue("bgp_peer.remote_port", "10.0.0.2", valuestore.ResultValue{Value: float64(51234)})
+	store.AddColumnValue("bgp_peer.remote_as", "10.0.0.2", valuestore.ResultValue{Value: float64(65002)})
+	store.AddColumnValue("bgp_peer.established_time", "10.0.0.2", valuestore.ResultValue{Value: float64(3600)})
+	store.AddColumnValue("bgp_peer.last_error", "10.0.0.2", valuestore.ResultValue{Value: []byte{0, 0}})
+
+	store.AddColumnValue("bgp_peer.state", "10.0.0.3", valuestore.ResultValue{Value: float64(3)})
+	store.AddColumnValue("bgp_peer.admin_status", "10.0.0.3", valuestore.ResultValue{Value: float64(2)})
+	store.AddColumnValue("bgp_peer.remote_as", "10.0.0.3", valuestore.ResultValue{Value: float64(65003)})
+	store.AddColumnValue("bgp_peer.last_error", "10.0.0.3", valuestore.ResultValue{Value: []byte{6, 2}}) // Cease, Administrative Shutdown
+
+	peers := buildNetworkBGPPeersMetadata("default:1.2.3.4", store)
+
+	assert.Equal(t, []metadata.BGPPeerMetadata{
+		{
+			ID:                "default:1.2.3.4:10.0.0.2",
+			DeviceID:          "default:1.2.3.4",
+			RemoteAddress:     "10.0.0.2",
+			RemoteAS:          65002,
+			RemotePort:        51234,
+			LocalAddress:      "10.0.0.1",
+			LocalPort:         179,
+			Identifier:        "192.168.0.2",
+			State:             "established",
+			AdminStatus:       "start",
+			NegotiatedVersion: 4,
+			EstablishedTime:   3600,
+		},
+		{
+			ID:               "default:1.2.3.4:10.0.0.3",
+			DeviceID:         "default:1.2.3.4",
+			RemoteAddress:    "10.0.0.3",
+			RemoteAS:         65003,
+			State:            "active",
+			AdminStatus:      "start",
+			LastErrorCode:    6,
+			LastErrorSubcode: 2,
+		},
+	}, peers)
+}
@@ -0,0 +1,212 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package execcheck
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	// waitDelay bounds the wait for the processes started by the command
+	// which keep its output open after it was killed
+	waitDelay = time.Second
+)
+
+// exitCodeStatus maps the exit codes of the Nagios plugins to service check statuses.
+var exitCodeStatus = map[int]servicecheck.ServiceCheckStatus{
+	0: servicecheck.ServiceCheckOK,
+	1: servicecheck.ServiceCheckWarning,
+	2: servicecheck.ServiceCheckCritical,
+	3: servicecheck.ServiceCheckUnknown,
+}
+
+type instanceConfig struct {
+	Command          string            `yaml:"command"`
+	Args             []string          `yaml:"args"`
+	Timeout          int               `yaml:"timeout"`
+	Env              map[string]string `yaml:"env"`
+	PassEnv          []string          `yaml:"pass_env"`
+	ServiceCheckName string            `yaml:"service_check_name"`
+	MetricPrefix     string            `yaml:"metric_prefix"`
+	Tags             []string          `yaml:"tags"`
+}
+
+// Check runs an executable following the Nagios plugins conventions: its exit
+// code is submitted as a service check and its performance data as gauges.
+type Check struct {
+	core.CheckBase
+	config         instanceConfig
+	timeout        time.Duration
+	env            []string
+	maxOutputSize  int
+	allowGroupExec bool
+}
+
+func newCheck(name string, maxOutputSize int, allowGroupExec bool) *Check {
+	return &Check{
+		CheckBase:      core.NewCheckBase(name),
+		maxOutputSize:  maxOutputSize,
+		allowGroupExec: allowGroupExec,
+	}
+}
+
+// Configure parses the check configuration and initializes the check.
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfig integration.Data, source string) error {
+	if err := yaml.Unmarshal(data, &c.config); err != nil {
+		return err
+	}
+	if c.config.Command == "" {
+		return errors.New("the command setting is required")
+	}
+
+	c.timeout = defaultTimeout
+	if c.config.Timeout > 0 {
+		c.timeout = time.Duration(c.config.Timeout) * time.Second
+	}
+	if c.config.ServiceCheckName == "" {
+		c.config.ServiceCheckName = c.String() + ".status"
+	}
+	if c.config.MetricPrefix == "" {
+		c.config.MetricPrefix = c.String()
+	}
+
+	// The command only gets the PATH and the variables it is given
+	c.env = []string{"PATH=" + os.Getenv("PATH")}
+	for _, name := range c.config.PassEnv {
+		if value, found := os.LookupEnv(name); found {
+			c.env = append(c.env, name+"="+value)
+		}
+	}
+	for name, value := range c.config.Env {
+		c.env = append(c.env, name+"="+value)
+	}
+
+	c.BuildID(integrationConfigDigest, data, initConfig)
+	return c.CommonConfigure(senderManager, initConfig, data, source)
+}
+
+// Run runs the command and submits its results.
+func (c *Check) Run() error {
+	return c.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the command, killing it when ctx is cancelled, and
+// submits its results.
+func (c *Check) RunWithContext(ctx context.Context) error {
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+
+	output, exitCode, err := c.execCommand(ctx)
+	if err != nil {
+		sender.ServiceCheck(c.config.ServiceCheckName, servicecheck.ServiceCheckUnknown, "", c.config.Tags, err.Error())
+		return err
+	}
+
+	text, items := parseOutput(output)
+	status, found := exitCodeStatus[exitCode]
+	if !found {
+		status = servicecheck.ServiceCheckUnknown
+	}
+	sender.ServiceCheck(c.config.ServiceCheckName, status, "", c.config.Tags, text)
+
+	for _, item := range items {
+		name := metricName(c.config.MetricPrefix, item.label)
+		tags := c.config.Tags
+		if item.unit != "" {
+			tags = append(tags[:len(tags):len(tags)], "unit:"+item.unit)
+		}
+		sender.Gauge(name, item.value, "", tags)
+		for _, threshold := range thresholdNames {
+			if value, found := item.thresholds[threshold]; found {
+				sender.Gauge(name+"."+threshold, value, "", tags)
+			}
+		}
+	}
+	return nil
+}
+
+// execCommand runs the command and returns its output and its exit code.
+func (c *Check) execCommand(parent context.Context) (string, int, error) {
+	ctx, cancel := context.WithTimeout(parent, c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.config.Command, c.config.Args...)
+	if cmd.Err != nil {
+		return "", 0, cmd.Err
+	}
+	// The rights are checked on every run as the executable may be replaced
+	if err := checkRights(cmd.Path, c.allowGroupExec); err != nil {
+		return "", 0, err
+	}
+	cmd.Env = c.env
+	cmd.WaitDelay = waitDelay
+
+	stdout := limitBuffer{
+		buf: &bytes.Buffer{},
+		max: c.maxOutputSize,
+	}
+	stderr := limitBuffer{
+		buf: &bytes.Buffer{},
+		max: c.maxOutputSize,
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if stderr.buf.Len() > 0 {
+		log.Debugf("%s: command stderr: %s", c.ID(), stderr.buf.String())
+	}
+	if parent.Err() != nil {
+		return "", 0, fmt.Errorf("command interrupted: %w", parent.Err())
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", 0, fmt.Errorf("command timed out after %s", c.timeout)
+	}
+	if stdout.err != nil {
+		return "", 0, stdout.err
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.Exited() {
+		return stdout.buf.String(), exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("error while running '%s': %s", c.config.Command, err)
+	}
+	return stdout.buf.String(), 0, nil
+}
+
+type limitBuffer struct {
+	max int
+	buf *bytes.Buffer
+	err error
+}
+
+func (b *limitBuffer) Write(p []byte) (n int, err error) {
+	if len(p)+b.buf.Len() > b.max {
+		b.err = fmt.Errorf("command output was too long: exceeded %d bytes", b.max)
+		return 0, b.err
+	}
+	return b.buf.Write(p)
+}
@@ -0,0 +1,140 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+
+package execcheck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func writeScript(t *testing.T, content string, mode os.FileMode) string {
+	path := filepath.Join(t.TempDir(), "check.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+content), mode))
+	require.NoError(t, os.Chmod(path, mode))
+	return path
+}
+
+func runCheck(t *testing.T, loader *ExecCheckLoader, instance string) (*mocksender.MockSender, error) {
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	config := integration.Config{Name: "nagios_disk", Provider: names.File}
+	c, err := loader.Load(senderManager, config, integration.Data(instance))
+	require.NoError(t, err)
+
+	mockSender := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	return mockSender, c.Run()
+}
+
+func newTestLoader() *ExecCheckLoader {
+	return &ExecCheckLoader{enabled: true, maxOutputSize: 1024}
+}
+
+func TestCheckRun(t *testing.T) {
+	script := writeScript(t, `echo "DISK WARNING - $MOUNT at 80% | used=80%;75;90;0;100 'free bytes'=2048B"
+echo "stderr output" >&2
+exit 1
+`, 0700)
+
+	mockSender, err := runCheck(t, newTestLoader(), fmt.Sprintf(`
+command: %s
+env:
+  MOUNT: /home
+tags:
+  - team:storage
+`, script))
+	require.NoError(t, err)
+
+	mockSender.AssertServiceCheck(t, "nagios_disk.status", servicecheck.ServiceCheckWarning, "", []string{"team:storage"}, "DISK WARNING - /home at 80%")
+	mockSender.AssertMetric(t, "Gauge", "nagios_disk.used", 80, "", []string{"team:storage", "unit:%"})
+	mockSender.AssertMetric(t, "Gauge", "nagios_disk.used.warn", 75, "", []string{"team:storage", "unit:%"})
+	mockSender.AssertMetric(t, "Gauge", "nagios_disk.used.crit", 90, "", []string{"team:storage", "unit:%"})
+	mockSender.AssertMetric(t, "Gauge", "nagios_disk.used.min", 0, "", []string{"team:storage", "unit:%"})
+	mockSender.AssertMetric(t, "Gauge", "nagios_disk.used.max", 100, "", []string{"team:storage", "unit:%"})
+	mockSender.AssertMetric(t, "Gauge", "nagios_disk.free_bytes", 2048, "", []string{"team:storage", "unit:B"})
+	mockSender.AssertNumberOfCalls(t, "Commit", 1)
+}
+
+func TestCheckExitCodes(t *testing.T) {
+	for exitCode, status := range map[int]servicecheck.ServiceCheckStatus{
+		0:  servicecheck.ServiceCheckOK,
+		2:  servicecheck.ServiceCheckCritical,
+		3:  servicecheck.ServiceCheckUnknown,
+		42: servicecheck.ServiceCheckUnknown,
+	} {
+		script := writeScript(t, fmt.Sprintf("echo output\nexit %d\n", exitCode), 0700)
+		mockSender, err := runCheck(t, newTestLoader(), fmt.Sprintf("command: %s\nservice_check_name: disk.can_write", script))
+		require.NoError(t, err)
+		mockSender.AssertServiceCheck(t, "disk.can_write", status, "", nil, "output")
+	}
+}
+
+func TestCheckEnvironment(t *testing.T) {
+	t.Setenv("EXEC_CHECK_PASSED", "passed")
+	t.Setenv("EXEC_CHECK_SECRET", "secret")
+	script := writeScript(t, `echo "env $EXEC_CHECK_PASSED-$EXEC_CHECK_SECRET"`, 0700)
+
+	mockSender, err := runCheck(t, newTestLoader(), fmt.Sprintf("command: %s\npass_env: [EXEC_CHECK_PASSED]", script))
+	require.NoError(t, err)
+	mockSender.AssertServiceCheck(t, "nagios_disk.status", servicecheck.ServiceCheckOK, "", nil, "env passed-")
+}
+
+func TestCheckFailures(t *testing.T) {
+	t.Run("timeout", func(t *testing.T) {
+		script := writeScript(t, "sleep 10\n", 0700)
+		mockSender, err := runCheck(t, newTestLoader(), fmt.Sprintf("command: %s\ntimeout: 1", script))
+		require.ErrorContains(t, err, "timed out")
+		mockSender.AssertServiceCheck(t, "nagios_disk.status", servicecheck.ServiceCheckUnknown, "", nil, "command timed out after 1s")
+	})
+
+	t.Run("output too long", func(t *testing.T) {
+		script := writeScript(t, fmt.Sprintf("echo %s\n", strings.Repeat("a", 2048)), 0700)
+		_, err := runCheck(t, newTestLoader(), "command: "+script)
+		require.ErrorContains(t, err, "command output was too long")
+	})
+
+	t.Run("rights", func(t *testing.T) {
+		script := writeScript(t, "echo OK\n", 0750)
+		_, err := runCheck(t, newTestLoader(), "command: "+script)
+		require.ErrorContains(t, err, "'group' or 'others' have rights on it")
+
+		loader := newTestLoader()
+		loader.allowGroupExec = true
+		_, err = runCheck(t, loader, "command: "+script)
+		require.NoError(t, err)
+
+		require.NoError(t, os.Chmod(script, 0770))
+		_, err = runCheck(t, loader, "command: "+script)
+		require.ErrorContains(t, err, "'group' has write permissions on it")
+	})
+}
+
+func TestLoaderRejects(t *testing.T) {
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	script := writeScript(t, "echo OK\n", 0700)
+	instance := integration.Data("command: " + script)
+
+	_, err := (&ExecCheckLoader{}).Load(senderManager, integration.Config{Name: "nagios_disk", Provider: names.File}, instance)
+	assert.ErrorContains(t, err, "exec checks are disabled")
+
+	_, err = newTestLoader().Load(senderManager, integration.Config{Name: "nagios_disk", Provider: names.Container}, instance)
+	assert.ErrorContains(t, err, "can only be configured in files")
+
+	_, err = newTestLoader().Load(senderManager, integration.Config{Name: "nagios_disk", Provider: names.File}, integration.Data("tags: [a:b]"))
+	assert.ErrorContains(t, err, "the command setting is required")
+}
@@ -0,0 +1,19 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+
+package execcheck
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/util/filesystem"
+)
+
+// checkRights checks the executable follows the same rules as the
+// secret_backend_command: only its owner, and optionally its group, can
+// access it.
+func checkRights(path string, allowGroupExec bool) error {
+	return filesystem.CheckExecutableRights(path, allowGroupExec)
+}
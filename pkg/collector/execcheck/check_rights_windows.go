@@ -0,0 +1,15 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build windows
+
+package execcheck
+
+import "errors"
+
+// checkRights rejects all the executables, the exec checks are not supported on Windows yet.
+func checkRights(_ string, _ bool) error {
+	return errors.New("exec checks are not supported on Windows")
+}
@@ -0,0 +1,75 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package execcheck implements a check loader running external executables,
+// like Nagios or Sensu check scripts, as checks.
+package execcheck
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/providers/names"
+	integrations "github.com/DataDog/datadog-agent/comp/logs/integrations/def"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/loaders"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+// ExecCheckLoader loads the checks running an executable
+type ExecCheckLoader struct {
+	enabled        bool
+	maxOutputSize  int
+	allowGroupExec bool
+}
+
+// NewExecCheckLoader creates a loader for the exec checks
+func NewExecCheckLoader() (*ExecCheckLoader, error) {
+	return &ExecCheckLoader{
+		enabled:        pkgconfigsetup.Datadog().GetBool("exec_checks.enabled"),
+		maxOutputSize:  pkgconfigsetup.Datadog().GetInt("exec_checks.output_max_size"),
+		allowGroupExec: pkgconfigsetup.Datadog().GetBool("exec_checks.allow_group_exec_perm"),
+	}, nil
+}
+
+// Name returns the exec loader name
+func (l *ExecCheckLoader) Name() string {
+	return "exec"
+}
+
+// Load returns a check running the command of the instance
+func (l *ExecCheckLoader) Load(senderManager sender.SenderManager, config integration.Config, instance integration.Data) (check.Check, error) {
+	if !l.enabled {
+		return nil, errors.New("exec checks are disabled, set exec_checks.enabled to enable them")
+	}
+	// Running commands from the configurations found in the container labels
+	// or in the cluster would let their authors run anything on the host.
+	if config.Provider != names.File {
+		return nil, fmt.Errorf("exec checks can only be configured in files, not by the %s provider", config.Provider)
+	}
+
+	c := newCheck(config.Name, l.maxOutputSize, l.allowGroupExec)
+	if err := c.Configure(senderManager, config.FastDigest(), instance, config.InitConfig, config.Source); err != nil {
+		log.Errorf("exec.loader: could not configure check %s: %s", c, err)
+		return c, fmt.Errorf("Could not configure check %s: %s", c, err)
+	}
+	return c, nil
+}
+
+func (l *ExecCheckLoader) String() string {
+	return "Exec Check Loader"
+}
+
+func init() {
+	factory := func(sender.SenderManager, optional.Option[integrations.Component]) (check.Loader, error) {
+		return NewExecCheckLoader()
+	}
+
+	loaders.RegisterLoader(40, factory)
+}
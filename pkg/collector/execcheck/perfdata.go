@@ -0,0 +1,144 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package execcheck
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// perfData is a Nagios performance data item: `'label'=value[UOM];[warn];[crit];[min];[max]`
+type perfData struct {
+	label string
+	value float64
+	unit  string
+	// thresholds holds the warn, crit, min and max values which are plain numbers
+	thresholds map[string]float64
+}
+
+var thresholdNames = []string{"warn", "crit", "min", "max"}
+
+// parseOutput splits the output of a Nagios plugin into its text, which is
+// the first line without performance data, and its performance data, which
+// follows the first `|` of the first line and of the long text.
+func parseOutput(output string) (string, []perfData) {
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	text, perf, _ := strings.Cut(lines[0], "|")
+
+	var rawPerf []string
+	rawPerf = append(rawPerf, perf)
+	inPerf := false
+	for _, line := range lines[1:] {
+		if !inPerf {
+			if _, after, found := strings.Cut(line, "|"); found {
+				inPerf = true
+				rawPerf = append(rawPerf, after)
+			}
+			continue
+		}
+		rawPerf = append(rawPerf, line)
+	}
+
+	var items []perfData
+	for _, raw := range rawPerf {
+		items = append(items, parsePerfData(raw)...)
+	}
+	return strings.TrimSpace(text), items
+}
+
+// parsePerfData parses space separated performance data items, the invalid
+// ones and the ones whose value is undetermined are skipped.
+func parsePerfData(raw string) []perfData {
+	var items []perfData
+	for _, token := range splitPerfData(raw) {
+		label, data, found := strings.Cut(token, "=")
+		if !found || label == "" {
+			continue
+		}
+		if strings.HasPrefix(label, "'") && strings.HasSuffix(label, "'") && len(label) >= 2 {
+			label = strings.ReplaceAll(label[1:len(label)-1], "''", "'")
+		}
+
+		fields := strings.Split(data, ";")
+		value, unit, ok := parseValue(fields[0])
+		if !ok {
+			continue
+		}
+		item := perfData{label: label, value: value, unit: unit, thresholds: make(map[string]float64)}
+		for i, field := range fields[1:] {
+			if i >= len(thresholdNames) {
+				break
+			}
+			// ranges like `10:20` or `@5:` are not submitted
+			if threshold, err := strconv.ParseFloat(field, 64); err == nil {
+				item.thresholds[thresholdNames[i]] = threshold
+			}
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// splitPerfData splits performance data on spaces which are not quoted.
+func splitPerfData(raw string) []string {
+	var tokens []string
+	var current strings.Builder
+	quoted := false
+	for _, r := range raw {
+		switch {
+		case r == '\'':
+			quoted = !quoted
+			current.WriteRune(r)
+		case unicode.IsSpace(r) && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// parseValue parses a value followed by its unit of measure.
+func parseValue(raw string) (float64, string, bool) {
+	end := strings.IndexFunc(raw, func(r rune) bool {
+		return !(unicode.IsDigit(r) || r == '.' || r == '-' || r == '+' || r == 'e' || r == 'E')
+	})
+	if end == -1 {
+		end = len(raw)
+	}
+	value, err := strconv.ParseFloat(raw[:end], 64)
+	if err != nil {
+		// `U` means the value could not be determined
+		return 0, "", false
+	}
+	return value, raw[end:], true
+}
+
+// metricName turns a performance data label into a metric name.
+func metricName(prefix, label string) string {
+	var b strings.Builder
+	b.WriteString(prefix)
+	b.WriteByte('.')
+	// leading invalid characters are dropped
+	underscore := true
+	for _, r := range strings.ToLower(label) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore {
+			b.WriteByte('_')
+			underscore = true
+		}
+	}
+	return strings.TrimRight(b.String(), "_.")
+}
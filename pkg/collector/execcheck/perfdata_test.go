@@ -0,0 +1,74 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package execcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOutput(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		output string
+		text   string
+		items  []perfData
+	}{
+		{
+			name:   "text only",
+			output: "DISK OK\n",
+			text:   "DISK OK",
+		},
+		{
+			name:   "single line",
+			output: "DISK OK - free space: / 3326 MB (56%); | /=2643MB;5948;5958;0;5968\n",
+			text:   "DISK OK - free space: / 3326 MB (56%);",
+			items: []perfData{
+				{label: "/", value: 2643, unit: "MB", thresholds: map[string]float64{"warn": 5948, "crit": 5958, "min": 0, "max": 5968}},
+			},
+		},
+		{
+			name: "long text",
+			output: `DISK OK - free space: / 3326 MB (56%); | /=2643MB;5948;5958;0;5968
+/ 15272 MB (77%);
+/boot 68 MB (69%);
+/home 69357 MB (27%); | /boot=68MB;88;93;0;98
+/home=69357MB;253404;253409;0;253414
+'time taken'=1.5s;;;;
+`,
+			text: "DISK OK - free space: / 3326 MB (56%);",
+			items: []perfData{
+				{label: "/", value: 2643, unit: "MB", thresholds: map[string]float64{"warn": 5948, "crit": 5958, "min": 0, "max": 5968}},
+				{label: "/boot", value: 68, unit: "MB", thresholds: map[string]float64{"warn": 88, "crit": 93, "min": 0, "max": 98}},
+				{label: "/home", value: 69357, unit: "MB", thresholds: map[string]float64{"warn": 253404, "crit": 253409, "min": 0, "max": 253414}},
+				{label: "time taken", value: 1.5, unit: "s", thresholds: map[string]float64{}},
+			},
+		},
+		{
+			name:   "ranges and undetermined values",
+			output: "PING WARNING | rta=-0.5ms;@10:20;~:30 loss=U;5;10 'it''s'=3% invalid\n",
+			text:   "PING WARNING",
+			items: []perfData{
+				{label: "rta", value: -0.5, unit: "ms", thresholds: map[string]float64{}},
+				{label: "it's", value: 3, unit: "%", thresholds: map[string]float64{}},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			text, items := parseOutput(tc.output)
+			assert.Equal(t, tc.text, text)
+			assert.Equal(t, tc.items, items)
+		})
+	}
+}
+
+func TestMetricName(t *testing.T) {
+	assert.Equal(t, "nagios.disk.used", metricName("nagios", "disk.used"))
+	assert.Equal(t, "nagios.time_taken", metricName("nagios", "Time Taken"))
+	assert.Equal(t, "nagios.home", metricName("nagios", "/home"))
+	assert.Equal(t, "nagios.it_s", metricName("nagios", "it's"))
+}
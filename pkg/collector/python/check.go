@@ -18,6 +18,7 @@ import (
 	"time"
 	"unsafe"
 
+	"go.uber.org/atomic"
 	yaml "gopkg.in/yaml.v2"
 
 	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
@@ -58,11 +59,14 @@ type PythonCheck struct {
 	class          *C.rtloader_pyobject_t
 	ModuleName     string
 	interval       time.Duration
+	runTimeout     time.Duration
 	lastWarnings   []error
 	source         string
 	telemetry      bool // whether or not the telemetry is enabled for this check
 	initConfig     string
 	instanceConfig string
+	// threadID is the Python thread running the check, 0 when it isn't running
+	threadID atomic.Uint64
 }
 
 // NewPythonCheck conveniently creates a PythonCheck instance
@@ -98,6 +102,10 @@ func (c *PythonCheck) runCheckImpl(commitMetrics bool) error {
 
 	log.Debugf("Running python check %s (version: '%s', id: '%s')", c.ModuleName, c.version, c.id)
 
+	// The thread is reset before releasing the GIL, so Interrupt can't interrupt another check
+	c.threadID.Store(uint64(C.get_thread_id(rtloader)))
+	defer c.threadID.Store(0)
+
 	cResult := C.run_check(rtloader, c.instance)
 	if cResult == nil {
 		if err := getRtLoaderError(); err != nil {
@@ -164,6 +172,31 @@ func (c *PythonCheck) Cancel() {
 	}
 }
 
+// Interrupt interrupts the current run of the check by raising a TimeoutError in
+// its thread, once it runs Python code again.
+func (c *PythonCheck) Interrupt() {
+	gstate, err := newStickyLock()
+	if err != nil {
+		log.Warnf("failed to interrupt check %s: %s", c.id, err)
+		return
+	}
+	defer gstate.unlock()
+
+	// The check may have completed while waiting for the GIL
+	threadID := c.threadID.Load()
+	if threadID == 0 {
+		return
+	}
+	if C.interrupt_thread(rtloader, C.ulong(threadID)) == 0 {
+		log.Warnf("failed to interrupt check %s: its thread was not found", c.id)
+	}
+}
+
+// RunTimeout returns the maximum duration of a run set with `run_timeout`
+func (c *PythonCheck) RunTimeout() time.Duration {
+	return c.runTimeout
+}
+
 // String representation (for debug and logging)
 func (c *PythonCheck) String() string {
 	return c.ModuleName
@@ -267,6 +300,11 @@ func (c *PythonCheck) Configure(senderManager sender.SenderManager, integrationC
 		c.interval = time.Duration(commonOptions.MinCollectionInterval) * time.Second
 	}
 
+	// See if a run timeout was specified
+	if commonOptions.RunTimeout > 0 {
+		c.runTimeout = time.Duration(commonOptions.RunTimeout) * time.Second
+	}
+
 	// Disable default hostname if specified
 	if commonOptions.EmptyDefaultHostname {
 		s, err := c.senderManager.GetSender(c.id)
@@ -96,6 +96,19 @@ void cancel_check(rtloader_t *s, rtloader_pyobject_t *check) {
 	return;
 }
 
+unsigned long get_thread_id_return = 0;
+unsigned long get_thread_id(rtloader_t *s) {
+	return get_thread_id_return;
+}
+
+int interrupt_thread_calls = 0;
+unsigned long interrupt_thread_id = 0;
+int interrupt_thread(rtloader_t *s, unsigned long thread_id) {
+	interrupt_thread_id = thread_id;
+	interrupt_thread_calls++;
+	return 1;
+}
+
 char *get_check_diagnoses_return = NULL;
 int get_check_diagnoses_calls = 0;
 char *get_check_diagnoses(rtloader_t *s, rtloader_pyobject_t *check) {
@@ -183,6 +196,9 @@ void reset_check_mock() {
 	get_check_check = NULL;
 	cancel_check_calls = 0;
 	cancel_check_instance = NULL;
+	get_thread_id_return = 0;
+	interrupt_thread_calls = 0;
+	interrupt_thread_id = 0;
 
 	get_check_deprecated_calls = 0;
 	get_check_deprecated_return = 0;
@@ -310,6 +326,37 @@ func testCheckCancel(t *testing.T) {
 	assert.Equal(t, check.instance, C.cancel_check_instance)
 }
 
+func testCheckInterrupt(t *testing.T) {
+	rtloader = newMockRtLoaderPtr()
+	defer func() { rtloader = nil }()
+	check, err := NewPythonFakeCheck(aggregator.NewNoOpSenderManager())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	C.reset_check_mock()
+	check.instance = newMockPyObjectPtr()
+	C.run_check_return = C.CString("")
+	C.get_thread_id_return = 42
+
+	err = check.runCheck(false)
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	// The check isn't running anymore, there is nothing to interrupt
+	check.Interrupt()
+	assert.Equal(t, C.int(0), C.interrupt_thread_calls)
+	assert.Equal(t, C.int(2), C.gil_locked_calls)
+	assert.Equal(t, C.int(2), C.gil_unlocked_calls)
+
+	// Simulate a running check
+	check.threadID.Store(42)
+	check.Interrupt()
+	assert.Equal(t, C.int(1), C.interrupt_thread_calls)
+	assert.Equal(t, C.ulong(42), C.interrupt_thread_id)
+}
+
 func testCheckCancelWhenRuntimeUnloaded(t *testing.T) {
 	rtloader = newMockRtLoaderPtr()
 	defer func() { rtloader = nil }()
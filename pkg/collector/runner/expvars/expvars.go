@@ -29,6 +29,7 @@ const (
 	runningChecksExpvarKey = "RunningChecks"
 	runsExpvarKey          = "Runs"
 	runningExpvarKey       = "Running"
+	stuckChecksExpvarKey   = "StuckChecks"
 	warningsExpvarKey      = "Warnings"
 )
 
@@ -84,6 +85,7 @@ func Reset() {
 		errorsExpvarKey,
 		runsExpvarKey,
 		runningChecksExpvarKey,
+		stuckChecksExpvarKey,
 		warningsExpvarKey,
 	} {
 		runnerStats.Delete(key)
@@ -178,6 +180,13 @@ func CheckStats(id checkid.ID) (*checkstats.Stats, bool) {
 	return check, true
 }
 
+// SetCheckStuck marks a check as still running, or not, after exceeding its run timeout
+func SetCheckStuck(id checkid.ID, stuck bool) {
+	if s, found := CheckStats(id); found {
+		s.SetStuck(stuck)
+	}
+}
+
 // Functions relating to running checks state map (`runningChecksStats`)
 
 // SetRunningStats sets the start time of a running check
@@ -256,3 +265,17 @@ func GetErrorsCount() int64 {
 	}
 	return count.(*expvar.Int).Value()
 }
+
+// AddStuckChecksCount is used to increment and decrement the 'StuckChecks' expvar
+func AddStuckChecksCount(amount int) {
+	runnerStats.Add(stuckChecksExpvarKey, int64(amount))
+}
+
+// GetStuckChecksCount is used to get the value of 'StuckChecks' expvar
+func GetStuckChecksCount() int64 {
+	count := runnerStats.Get(stuckChecksExpvarKey)
+	if count == nil {
+		return 0
+	}
+	return count.(*expvar.Int).Value()
+}
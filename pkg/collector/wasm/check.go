@@ -0,0 +1,238 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/imports/wasi_snapshot_preview1"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	core "github.com/DataDog/datadog-agent/pkg/collector/corechecks"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// checkFunction is the function the modules export to run the check, it
+// returns 0 on success.
+const checkFunction = "check"
+
+// wasmPageSize is the size of a WebAssembly memory page
+const wasmPageSize = 64 * 1024
+
+type initConfig struct {
+	AllowedHosts []string `yaml:"allowed_hosts"`
+}
+
+type instanceConfig struct {
+	// Timeout can lower the maximum run time of the instance
+	Timeout int `yaml:"timeout"`
+}
+
+// Check runs a check compiled to WebAssembly in a sandbox: it can only use
+// the host API and the memory and the time it is given.
+type Check struct {
+	core.CheckBase
+	code       []byte
+	cache      wazero.CompilationCache
+	maxMemory  int
+	maxRunTime time.Duration
+
+	mu      sync.Mutex
+	host    *hostAPI
+	runtime wazero.Runtime
+	module  api.Module
+}
+
+func newCheck(name string, code []byte, cache wazero.CompilationCache, maxMemory int, maxRunTime time.Duration) *Check {
+	return &Check{
+		CheckBase:  core.NewCheckBase(name),
+		code:       code,
+		cache:      cache,
+		maxMemory:  maxMemory,
+		maxRunTime: maxRunTime,
+	}
+}
+
+// Configure parses the check configuration and compiles its module.
+func (c *Check) Configure(senderManager sender.SenderManager, integrationConfigDigest uint64, data integration.Data, initConfigData integration.Data, source string) error {
+	var initConf initConfig
+	if err := yaml.Unmarshal(initConfigData, &initConf); err != nil {
+		return err
+	}
+	var instance instanceConfig
+	if err := yaml.Unmarshal(data, &instance); err != nil {
+		return err
+	}
+	if timeout := time.Duration(instance.Timeout) * time.Second; timeout > 0 && timeout < c.maxRunTime {
+		c.maxRunTime = timeout
+	}
+
+	// The module gets the instance as JSON, which is easier to parse than YAML
+	var rawInstance map[string]interface{}
+	if err := yaml.Unmarshal(data, &rawInstance); err != nil {
+		return err
+	}
+	instanceJSON, err := json.Marshal(convertMap(rawInstance))
+	if err != nil {
+		return err
+	}
+
+	c.BuildID(integrationConfigDigest, data, initConfigData)
+	if err := c.CommonConfigure(senderManager, initConfigData, data, source); err != nil {
+		return err
+	}
+
+	c.host = &hostAPI{
+		checkName:    c.String(),
+		instance:     instanceJSON,
+		allowedHosts: initConf.AllowedHosts,
+		client:       &http.Client{},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.maxRunTime)
+	defer cancel()
+	return c.instantiate(ctx)
+}
+
+// instantiate creates a runtime limited to the memory of the check and
+// instantiates the module in it.
+func (c *Check) instantiate(ctx context.Context) error {
+	config := wazero.NewRuntimeConfig().
+		WithMemoryLimitPages(uint32(c.maxMemory / wasmPageSize)).
+		WithCloseOnContextDone(true).
+		WithCompilationCache(c.cache)
+	r := wazero.NewRuntimeWithConfig(ctx, config)
+
+	if _, err := wasi_snapshot_preview1.Instantiate(ctx, r); err != nil {
+		r.Close(ctx)
+		return err
+	}
+	if err := c.host.instantiate(ctx, r); err != nil {
+		r.Close(ctx)
+		return err
+	}
+	compiled, err := r.CompileModule(ctx, c.code)
+	if err != nil {
+		r.Close(ctx)
+		return fmt.Errorf("invalid WebAssembly module: %w", err)
+	}
+	if compiled.ExportedFunctions()[checkFunction] == nil {
+		r.Close(ctx)
+		return fmt.Errorf("the WebAssembly module does not export a %q function", checkFunction)
+	}
+
+	// The modules built as WASI reactors are initialized by `_initialize`,
+	// they get no arguments, environment or filesystem.
+	moduleConfig := wazero.NewModuleConfig().WithStartFunctions("_initialize").WithName(c.String())
+	module, err := r.InstantiateModule(ctx, compiled, moduleConfig)
+	if err != nil {
+		r.Close(ctx)
+		return fmt.Errorf("could not instantiate the WebAssembly module: %w", err)
+	}
+	c.runtime = r
+	c.module = module
+	return nil
+}
+
+// Run runs the check function of the module.
+func (c *Check) Run() error {
+	return c.RunWithContext(context.Background())
+}
+
+// RunWithContext runs the check function of the module, which is interrupted
+// when ctx is cancelled.
+func (c *Check) RunWithContext(parent context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sender, err := c.GetSender()
+	if err != nil {
+		return err
+	}
+	defer sender.Commit()
+	c.host.sender = sender
+
+	ctx, cancel := context.WithTimeout(parent, c.maxRunTime)
+	defer cancel()
+
+	// The module is closed when it exceeds its run time, its state is lost
+	if c.module == nil || c.module.IsClosed() {
+		c.closeRuntime()
+		if err := c.instantiate(ctx); err != nil {
+			return err
+		}
+	}
+
+	c.host.lastError = ""
+	results, err := c.module.ExportedFunction(checkFunction).Call(ctx)
+	if err != nil {
+		if parent.Err() != nil {
+			return fmt.Errorf("the check was interrupted: %w", parent.Err())
+		}
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("the check exceeded its maximum run time of %s", c.maxRunTime)
+		}
+		return err
+	}
+	if len(results) > 0 && api.DecodeI32(results[0]) != 0 {
+		if c.host.lastError != "" {
+			return fmt.Errorf("the check failed: %s", c.host.lastError)
+		}
+		return fmt.Errorf("the check failed with code %d", api.DecodeI32(results[0]))
+	}
+	return nil
+}
+
+// Cancel releases the runtime of the check.
+func (c *Check) Cancel() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeRuntime()
+}
+
+func (c *Check) closeRuntime() {
+	if c.runtime == nil {
+		return
+	}
+	if err := c.runtime.Close(context.Background()); err != nil {
+		log.Debugf("%s: error closing the WebAssembly runtime: %v", c.ID(), err)
+	}
+	c.runtime = nil
+	c.module = nil
+}
+
+// convertMap converts the maps decoded from YAML so they can be encoded as JSON.
+func convertMap(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = convertMap(item)
+		}
+		return m
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = convertMap(item)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = convertMap(item)
+		}
+		return v
+	}
+	return value
+}
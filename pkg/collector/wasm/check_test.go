@@ -0,0 +1,112 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package wasm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tetratelabs/wazero"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	"github.com/DataDog/datadog-agent/comp/core/tagger"
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/mocksender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+)
+
+func newTestLoader() *WasmCheckLoader {
+	return &WasmCheckLoader{
+		enabled:    true,
+		checksPath: "testdata",
+		maxMemory:  1024 * 1024,
+		maxRunTime: time.Second,
+		cache:      wazero.NewCompilationCache(),
+	}
+}
+
+func loadCheck(t *testing.T, loader *WasmCheckLoader, name string, instance string) (*Check, *mocksender.MockSender) {
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	config := integration.Config{Name: name}
+	c, err := loader.Load(senderManager, config, integration.Data(instance))
+	require.NoError(t, err)
+	t.Cleanup(c.Cancel)
+
+	mockSender := mocksender.NewMockSenderWithSenderManager(c.ID(), senderManager)
+	mockSender.SetupAcceptAll()
+	return c.(*Check), mockSender
+}
+
+func TestCheckRun(t *testing.T) {
+	tagsFunc = func(entity string, cardinality types.TagCardinality) ([]string, error) {
+		if entity != "container_id://abc" || cardinality != types.HighCardinality {
+			return nil, fmt.Errorf("unexpected lookup of %s", entity)
+		}
+		return []string{"kube_namespace:default", "pod_name:web"}, nil
+	}
+	t.Cleanup(func() { tagsFunc = tagger.Tag })
+
+	c, mockSender := loadCheck(t, newTestLoader(), "check", "url: http://localhost\nport: 8080")
+	for i := 0; i < 2; i++ {
+		require.NoError(t, c.Run())
+	}
+
+	mockSender.AssertMetric(t, "Gauge", "wasm.gauge", 42, "", []string{"env:test", "role:db"})
+	mockSender.AssertServiceCheck(t, "wasm.can_connect", servicecheck.ServiceCheckWarning, "", []string{"env:test", "role:db"}, "all good")
+	// The instance is passed as JSON
+	mockSender.AssertMetric(t, "Gauge", "wasm.instance_size", float64(len(`{"port":8080,"url":"http://localhost"}`)), "", nil)
+	mockSender.AssertMetric(t, "Gauge", "wasm.tags_size", float64(len("kube_namespace:default\npod_name:web")), "", nil)
+	mockSender.AssertCalled(t, "Event", event.Event{
+		Title:     "hello",
+		Text:      "world",
+		AlertType: event.AlertTypeWarning,
+		Tags:      []string{"env:test"},
+	})
+	mockSender.AssertNumberOfCalls(t, "Gauge", 6)
+	mockSender.AssertNumberOfCalls(t, "Commit", 2)
+}
+
+func TestCheckMaxRunTime(t *testing.T) {
+	c, _ := loadCheck(t, newTestLoader(), "loop", "timeout: 1")
+
+	start := time.Now()
+	err := c.Run()
+	require.ErrorContains(t, err, "the check exceeded its maximum run time of 1s")
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	// The module is instantiated again on the next run
+	assert.True(t, c.module.IsClosed())
+	require.Error(t, c.Run())
+}
+
+func TestCheckMaxMemory(t *testing.T) {
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+	_, err := newTestLoader().Load(senderManager, integration.Config{Name: "memory"}, integration.Data("{}"))
+	require.ErrorContains(t, err, "over limit of 16 pages")
+
+	loader := newTestLoader()
+	loader.maxMemory = 4 * 1024 * 1024
+	_, err = loader.Load(senderManager, integration.Config{Name: "memory"}, integration.Data("{}"))
+	require.NoError(t, err)
+}
+
+func TestLoaderRejects(t *testing.T) {
+	senderManager := mocksender.CreateDefaultDemultiplexer()
+
+	_, err := (&WasmCheckLoader{}).Load(senderManager, integration.Config{Name: "check"}, integration.Data("{}"))
+	assert.ErrorContains(t, err, "WebAssembly checks are disabled")
+
+	_, err = newTestLoader().Load(senderManager, integration.Config{Name: "missing"}, integration.Data("{}"))
+	assert.ErrorContains(t, err, "could not read the WebAssembly module of missing")
+
+	// The module can be set in the init_config
+	_, err = newTestLoader().Load(senderManager, integration.Config{Name: "check", InitConfig: integration.Data("module: testdata/check.wat")}, integration.Data("{}"))
+	assert.ErrorContains(t, err, "invalid WebAssembly module")
+}
@@ -0,0 +1,332 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+
+	"github.com/DataDog/datadog-agent/comp/core/tagger"
+	"github.com/DataDog/datadog-agent/comp/core/tagger/types"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/metrics/event"
+	"github.com/DataDog/datadog-agent/pkg/metrics/servicecheck"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// hostModuleName is the name of the module the checks import the host API from.
+//
+// The strings are passed as a pointer and a length in the memory of the check,
+// the tags as a string separated by new lines. The functions returning data
+// write it to a buffer of the check and return its length; when the buffer is
+// too small, nothing is written and the check can retry with a larger one.
+const hostModuleName = "datadog"
+
+const (
+	maxHTTPResponseSize = 1024 * 1024
+	maxHTTPRedirects    = 10
+)
+
+// Metric types of submit_metric
+const (
+	metricGauge int32 = iota
+	metricRate
+	metricCount
+	metricMonotonicCount
+	metricHistogram
+	metricHistorate
+)
+
+// Log levels of log
+const (
+	logDebug int32 = iota
+	logInfo
+	logWarn
+	logError
+)
+
+// tagsFunc is used to lookup the tags of an entity, for testing purpose
+var tagsFunc = tagger.Tag
+
+// wasmEvent is the JSON representation of the events submitted by the checks.
+type wasmEvent struct {
+	Title          string   `json:"title"`
+	Text           string   `json:"text"`
+	Timestamp      int64    `json:"timestamp"`
+	Priority       string   `json:"priority"`
+	Host           string   `json:"host"`
+	Tags           []string `json:"tags"`
+	AlertType      string   `json:"alert_type"`
+	AggregationKey string   `json:"aggregation_key"`
+	SourceTypeName string   `json:"source_type_name"`
+	EventType      string   `json:"event_type"`
+}
+
+// hostAPI implements the functions the checks can call. It only gives them
+// access to their sender, their configuration, the tagger and the HTTP
+// endpoints they are allowed to reach.
+type hostAPI struct {
+	checkName    string
+	instance     []byte
+	sender       sender.Sender
+	allowedHosts []string
+	client       *http.Client
+
+	response  []byte
+	lastError string
+}
+
+func (h *hostAPI) submitMetric(metricType int32, name string, value float64, tags []string) {
+	switch metricType {
+	case metricGauge:
+		h.sender.Gauge(name, value, "", tags)
+	case metricRate:
+		h.sender.Rate(name, value, "", tags)
+	case metricCount:
+		h.sender.Count(name, value, "", tags)
+	case metricMonotonicCount:
+		h.sender.MonotonicCount(name, value, "", tags)
+	case metricHistogram:
+		h.sender.Histogram(name, value, "", tags)
+	case metricHistorate:
+		h.sender.Historate(name, value, "", tags)
+	default:
+		log.Warnf("%s: invalid metric type %d for %s", h.checkName, metricType, name)
+	}
+}
+
+func (h *hostAPI) submitServiceCheck(name string, status int32, tags []string, message string) {
+	h.sender.ServiceCheck(name, servicecheck.ServiceCheckStatus(status), "", tags, message)
+}
+
+func (h *hostAPI) submitEvent(data []byte) error {
+	var e wasmEvent
+	if err := json.Unmarshal(data, &e); err != nil {
+		return fmt.Errorf("invalid event: %w", err)
+	}
+	ev := event.Event{
+		Title:          e.Title,
+		Text:           e.Text,
+		Ts:             e.Timestamp,
+		Host:           e.Host,
+		Tags:           e.Tags,
+		AggregationKey: e.AggregationKey,
+		SourceTypeName: e.SourceTypeName,
+		EventType:      e.EventType,
+	}
+	if e.Priority != "" {
+		priority, err := event.GetEventPriorityFromString(e.Priority)
+		if err != nil {
+			return err
+		}
+		ev.Priority = priority
+	}
+	if e.AlertType != "" {
+		alertType, err := event.GetAlertTypeFromString(e.AlertType)
+		if err != nil {
+			return err
+		}
+		ev.AlertType = alertType
+	}
+	h.sender.Event(ev)
+	return nil
+}
+
+func (h *hostAPI) log(level int32, message string) {
+	switch level {
+	case logDebug:
+		log.Debugf("%s: %s", h.checkName, message)
+	case logInfo:
+		log.Infof("%s: %s", h.checkName, message)
+	case logWarn:
+		log.Warnf("%s: %s", h.checkName, message)
+	default:
+		log.Errorf("%s: %s", h.checkName, message)
+	}
+}
+
+func (h *hostAPI) tags(entity string, cardinality int32) ([]string, error) {
+	return tagsFunc(entity, types.TagCardinality(cardinality))
+}
+
+// hostAllowed returns whether the host, with or without its port, matches
+// one of the allowed host patterns.
+func (h *hostAPI) hostAllowed(u *url.URL) bool {
+	host := strings.ToLower(u.Host)
+	hostname := strings.ToLower(u.Hostname())
+	for _, pattern := range h.allowedHosts {
+		pattern = strings.ToLower(pattern)
+		if matched, _ := path.Match(pattern, host); matched {
+			return true
+		}
+		if _, _, err := net.SplitHostPort(pattern); err != nil {
+			if matched, _ := path.Match(pattern, hostname); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// checkURL returns an error when the check isn't allowed to fetch the URL.
+func (h *hostAPI) checkURL(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if !h.hostAllowed(u) {
+		return fmt.Errorf("host %s is not in the allowed_hosts of the check", u.Host)
+	}
+	return nil
+}
+
+// checkRedirect checks every redirect against the allowed hosts, and stops
+// after maxHTTPRedirects redirects like the default policy of http.Client.
+func (h *hostAPI) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxHTTPRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxHTTPRedirects)
+	}
+	return h.checkURL(req.URL)
+}
+
+// httpGet fetches an URL and stores the response body, it returns the status code.
+func (h *hostAPI) httpGet(ctx context.Context, rawURL string) (int, error) {
+	h.response = nil
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, err
+	}
+	if err := h.checkURL(u); err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return 0, err
+	}
+	// The redirects must not lead the check out of its allowed hosts
+	client := *h.client
+	client.CheckRedirect = h.checkRedirect
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxHTTPResponseSize+1))
+	if err != nil {
+		return 0, err
+	}
+	if len(body) > maxHTTPResponseSize {
+		return 0, fmt.Errorf("response exceeded %d bytes", maxHTTPResponseSize)
+	}
+	h.response = body
+	return resp.StatusCode, nil
+}
+
+// instantiate exports the host API to the runtime.
+func (h *hostAPI) instantiate(ctx context.Context, r wazero.Runtime) error {
+	_, err := r.NewHostModuleBuilder(hostModuleName).
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, metricType, namePtr, nameLen uint32, value float64, tagsPtr, tagsLen uint32) {
+			h.submitMetric(int32(metricType), readString(m, namePtr, nameLen), value, readTags(m, tagsPtr, tagsLen))
+		}).
+		Export("submit_metric").
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, namePtr, nameLen, status, tagsPtr, tagsLen, messagePtr, messageLen uint32) {
+			h.submitServiceCheck(readString(m, namePtr, nameLen), int32(status), readTags(m, tagsPtr, tagsLen), readString(m, messagePtr, messageLen))
+		}).
+		Export("submit_service_check").
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, ptr, length uint32) int32 {
+			return h.result(h.submitEvent([]byte(readString(m, ptr, length))))
+		}).
+		Export("submit_event").
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, level, ptr, length uint32) {
+			h.log(int32(level), readString(m, ptr, length))
+		}).
+		Export("log").
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, bufPtr, bufLen uint32) int32 {
+			return writeBuffer(m, bufPtr, bufLen, h.instance)
+		}).
+		Export("get_instance").
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, entityPtr, entityLen, cardinality, bufPtr, bufLen uint32) int32 {
+			tags, err := h.tags(readString(m, entityPtr, entityLen), int32(cardinality))
+			if err != nil {
+				return h.result(err)
+			}
+			return writeBuffer(m, bufPtr, bufLen, []byte(strings.Join(tags, "\n")))
+		}).
+		Export("get_tags").
+		NewFunctionBuilder().
+		WithFunc(func(ctx context.Context, m api.Module, urlPtr, urlLen uint32) int32 {
+			status, err := h.httpGet(ctx, readString(m, urlPtr, urlLen))
+			if err != nil {
+				return h.result(err)
+			}
+			return int32(status)
+		}).
+		Export("http_get").
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, bufPtr, bufLen uint32) int32 {
+			return writeBuffer(m, bufPtr, bufLen, h.response)
+		}).
+		Export("http_response").
+		NewFunctionBuilder().
+		WithFunc(func(_ context.Context, m api.Module, bufPtr, bufLen uint32) int32 {
+			return writeBuffer(m, bufPtr, bufLen, []byte(h.lastError))
+		}).
+		Export("last_error").
+		Instantiate(ctx)
+	return err
+}
+
+// result returns 0, or -1 and records the error for last_error.
+func (h *hostAPI) result(err error) int32 {
+	if err != nil {
+		h.lastError = err.Error()
+		return -1
+	}
+	return 0
+}
+
+func readString(m api.Module, ptr, length uint32) string {
+	if length == 0 {
+		return ""
+	}
+	data, ok := m.Memory().Read(ptr, length)
+	if !ok {
+		// The guest is misbehaving, abort its execution
+		panic(errors.New("out of bounds memory access"))
+	}
+	return string(data)
+}
+
+func readTags(m api.Module, ptr, length uint32) []string {
+	if length == 0 {
+		return nil
+	}
+	return strings.Split(readString(m, ptr, length), "\n")
+}
+
+func writeBuffer(m api.Module, ptr, length uint32, data []byte) int32 {
+	if uint32(len(data)) <= length && !m.Memory().Write(ptr, data) {
+		panic(errors.New("out of bounds memory access"))
+	}
+	return int32(len(data))
+}
@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package wasm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostAllowed(t *testing.T) {
+	h := &hostAPI{allowedHosts: []string{"api.example.com", "*.internal", "localhost:8080"}}
+	for rawURL, allowed := range map[string]bool{
+		"https://api.example.com/v1":      true,
+		"https://API.example.com:443/v1":  true,
+		"https://example.com":             false,
+		"http://db.internal/status":       true,
+		"http://db.internal:9000/status":  true,
+		"http://a.b.internal/":            true,
+		"http://localhost:8080/metrics":   true,
+		"http://localhost:8081/metrics":   false,
+		"http://localhost/metrics":        false,
+		"http://api.example.com.evil.com": false,
+	} {
+		u, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		assert.Equal(t, allowed, h.hostAllowed(u), rawURL)
+	}
+}
+
+func TestHTTPGet(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			w.Write([]byte(strings.Repeat("a", maxHTTPResponseSize+1)))
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	h := &hostAPI{client: server.Client()}
+	_, err = h.httpGet(context.Background(), server.URL)
+	assert.ErrorContains(t, err, "is not in the allowed_hosts of the check")
+
+	h.allowedHosts = []string{serverURL.Host}
+	status, err := h.httpGet(context.Background(), server.URL)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, status)
+	assert.Equal(t, "ok", string(h.response))
+
+	_, err = h.httpGet(context.Background(), server.URL+"/large")
+	assert.ErrorContains(t, err, "response exceeded")
+	assert.Nil(t, h.response)
+
+	_, err = h.httpGet(context.Background(), "file:///etc/passwd")
+	assert.ErrorContains(t, err, "unsupported scheme")
+}
+
+func TestHTTPGetRedirect(t *testing.T) {
+	forbidden := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("secret"))
+	}))
+	defer forbidden.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/forbidden":
+			http.Redirect(w, r, forbidden.URL, http.StatusFound)
+		case "/allowed":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	h := &hostAPI{client: &http.Client{}, allowedHosts: []string{serverURL.Host}}
+	status, err := h.httpGet(context.Background(), server.URL+"/allowed")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "ok", string(h.response))
+
+	_, err = h.httpGet(context.Background(), server.URL+"/forbidden")
+	assert.ErrorContains(t, err, "is not in the allowed_hosts of the check")
+	assert.Nil(t, h.response)
+
+	_, err = h.httpGet(context.Background(), server.URL+"/loop")
+	assert.ErrorContains(t, err, "stopped after 10 redirects")
+}
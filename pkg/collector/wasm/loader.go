@@ -0,0 +1,105 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package wasm implements a check loader running custom checks compiled to
+// WebAssembly in a sandbox, so a misbehaving check can't crash the Agent or
+// use more than the memory and the time it is given.
+package wasm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tetratelabs/wazero"
+	"gopkg.in/yaml.v2"
+
+	"github.com/DataDog/datadog-agent/comp/core/autodiscovery/integration"
+	integrations "github.com/DataDog/datadog-agent/comp/logs/integrations/def"
+	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
+	"github.com/DataDog/datadog-agent/pkg/collector/check"
+	"github.com/DataDog/datadog-agent/pkg/collector/loaders"
+	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+	"github.com/DataDog/datadog-agent/pkg/util/optional"
+)
+
+const moduleExtension = ".wasm"
+
+// WasmCheckLoader loads the checks compiled to WebAssembly
+//
+//nolint:revive // TODO(AML) Fix revive linter
+type WasmCheckLoader struct {
+	enabled    bool
+	checksPath string
+	maxMemory  int
+	maxRunTime time.Duration
+	// cache shares the compiled modules between the runtimes of the checks
+	cache wazero.CompilationCache
+}
+
+// NewWasmCheckLoader creates a loader for the WebAssembly checks
+func NewWasmCheckLoader() (*WasmCheckLoader, error) {
+	maxMemory := pkgconfigsetup.Datadog().GetInt("wasm_checks.max_memory_mb")
+	if maxMemory <= 0 {
+		return nil, fmt.Errorf("invalid wasm_checks.max_memory_mb %d", maxMemory)
+	}
+	return &WasmCheckLoader{
+		enabled:    pkgconfigsetup.Datadog().GetBool("wasm_checks.enabled"),
+		checksPath: pkgconfigsetup.Datadog().GetString("additional_checksd"),
+		maxMemory:  maxMemory * 1024 * 1024,
+		maxRunTime: pkgconfigsetup.Datadog().GetDuration("wasm_checks.max_run_time"),
+		cache:      wazero.NewCompilationCache(),
+	}, nil
+}
+
+// Name returns the WebAssembly loader name
+func (l *WasmCheckLoader) Name() string {
+	return "wasm"
+}
+
+// Load returns a check running the WebAssembly module named after the check
+// in the custom checks directory, or the one set with `module` in the init_config.
+func (l *WasmCheckLoader) Load(senderManager sender.SenderManager, config integration.Config, instance integration.Data) (check.Check, error) {
+	if !l.enabled {
+		return nil, errors.New("WebAssembly checks are disabled, set wasm_checks.enabled to enable them")
+	}
+
+	var initConf struct {
+		Module string `yaml:"module"`
+	}
+	if err := yaml.Unmarshal(config.InitConfig, &initConf); err != nil {
+		return nil, err
+	}
+	modulePath := initConf.Module
+	if modulePath == "" {
+		modulePath = filepath.Join(l.checksPath, config.Name+moduleExtension)
+	}
+	code, err := os.ReadFile(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read the WebAssembly module of %s: %w", config.Name, err)
+	}
+
+	c := newCheck(config.Name, code, l.cache, l.maxMemory, l.maxRunTime)
+	if err := c.Configure(senderManager, config.FastDigest(), instance, config.InitConfig, config.Source); err != nil {
+		log.Errorf("wasm.loader: could not configure check %s: %s", c, err)
+		return c, fmt.Errorf("Could not configure check %s: %s", c, err)
+	}
+	return c, nil
+}
+
+func (l *WasmCheckLoader) String() string {
+	return "WebAssembly Check Loader"
+}
+
+func init() {
+	factory := func(sender.SenderManager, optional.Option[integrations.Component]) (check.Loader, error) {
+		return NewWasmCheckLoader()
+	}
+
+	loaders.RegisterLoader(25, factory)
+}
@@ -0,0 +1,29 @@
+;; Test check using the host API, check.wasm is its binary encoding.
+(module
+  (import "datadog" "submit_metric" (func $submit_metric (param i32 i32 i32 f64 i32 i32)))
+  (import "datadog" "submit_service_check" (func $submit_service_check (param i32 i32 i32 i32 i32 i32 i32)))
+  (import "datadog" "get_instance" (func $get_instance (param i32 i32) (result i32)))
+  (import "datadog" "get_tags" (func $get_tags (param i32 i32 i32 i32 i32) (result i32)))
+  (import "datadog" "submit_event" (func $submit_event (param i32 i32) (result i32)))
+  (memory (export "memory") 1)
+  (data (i32.const 0) "wasm.gauge")
+  (data (i32.const 16) "env:test\nrole:db")
+  (data (i32.const 32) "wasm.can_connect")
+  (data (i32.const 48) "all good")
+  (data (i32.const 64) "wasm.instance_size")
+  (data (i32.const 96) "wasm.tags_size")
+  (data (i32.const 128) "container_id://abc")
+  (data (i32.const 256) "{\"title\":\"hello\",\"text\":\"world\",\"alert_type\":\"warning\",\"tags\":[\"env:test\"]}")
+  (func (export "check") (result i32)
+    ;; gauge wasm.gauge:42 tagged with env:test and role:db
+    (call $submit_metric (i32.const 0) (i32.const 0) (i32.const 10) (f64.const 42) (i32.const 16) (i32.const 16))
+    ;; warning service check
+    (call $submit_service_check (i32.const 32) (i32.const 16) (i32.const 1) (i32.const 16) (i32.const 16) (i32.const 48) (i32.const 8))
+    ;; size of the instance configuration
+    (call $submit_metric (i32.const 0) (i32.const 64) (i32.const 18)
+      (f64.convert_i32_s (call $get_instance (i32.const 1024) (i32.const 1024))) (i32.const 0) (i32.const 0))
+    ;; size of the high cardinality tags of the container
+    (call $submit_metric (i32.const 0) (i32.const 96) (i32.const 14)
+      (f64.convert_i32_s (call $get_tags (i32.const 128) (i32.const 18) (i32.const 2) (i32.const 2048) (i32.const 1024))) (i32.const 0) (i32.const 0))
+    (drop (call $submit_event (i32.const 256) (i32.const 75)))
+    (i32.const 0)))
@@ -0,0 +1,6 @@
+;; Test check which never returns, loop.wasm is its binary encoding.
+(module
+  (memory (export "memory") 1)
+  (func (export "check") (result i32)
+    (loop (br 0))
+    (i32.const 0)))
@@ -0,0 +1,5 @@
+;; Test check requiring 2MiB of memory, memory.wasm is its binary encoding.
+(module
+  (memory (export "memory") 32)
+  (func (export "check") (result i32)
+    (i32.const 0)))
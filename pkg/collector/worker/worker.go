@@ -13,6 +13,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/aggregator/sender"
 	"github.com/DataDog/datadog-agent/pkg/collector/check"
 	checkid "github.com/DataDog/datadog-agent/pkg/collector/check/id"
+	checkstats "github.com/DataDog/datadog-agent/pkg/collector/check/stats"
 	"github.com/DataDog/datadog-agent/pkg/collector/runner/expvars"
 	"github.com/DataDog/datadog-agent/pkg/collector/runner/tracker"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
@@ -27,6 +28,10 @@ const (
 
 	// Variables for the utilization expvars
 	pollingInterval = 15 * time.Second
+
+	// How long a check can take to stop after being interrupted, before it is
+	// considered stuck and its worker moves on to the next checks
+	interruptGracePeriod = 5 * time.Second
 )
 
 // The worker utilization is also reported via expvars, but it emits one metric
@@ -40,6 +45,13 @@ var workerUtilization = telemetry.NewGauge(
 	"Worker utilization. It's a value between 0 and 1 that represents the share of time that the check runner worker is running checks",
 )
 
+var tlmStuckChecks = telemetry.NewGauge(
+	"collector",
+	"stuck_checks",
+	[]string{"check_name"},
+	"Number of check instances still running after exceeding their run timeout",
+)
+
 // Worker is an object that encapsulates the logic to manage a loop of processing
 // checks over the provided `PendingCheckChan`
 type Worker struct {
@@ -47,7 +59,9 @@ type Worker struct {
 	Name string
 
 	checksTracker           *tracker.RunningChecksTracker
+	defaultRunTimeout       time.Duration
 	getDefaultSenderFunc    func() (sender.Sender, error)
+	interruptGracePeriod    time.Duration
 	pendingChecksChan       chan check.Check
 	runnerID                int
 	shouldAddCheckStatsFunc func(id checkid.ID) bool
@@ -110,6 +124,8 @@ func newWorkerWithOptions(
 		ID:                      ID,
 		Name:                    workerName,
 		checksTracker:           checksTracker,
+		defaultRunTimeout:       pkgconfigsetup.Datadog().GetDuration("check_run_timeout"),
+		interruptGracePeriod:    interruptGracePeriod,
 		pendingChecksChan:       pendingChecksChan,
 		runnerID:                runnerID,
 		shouldAddCheckStatsFunc: shouldAddCheckStatsFunc,
@@ -149,13 +165,20 @@ func (w *Worker) Run() {
 		utilizationTracker.CheckStarted()
 
 		// Run the check
-		checkErr := check.Run()
+		var runTimeout time.Duration
+		if !longRunning {
+			runTimeout = w.runTimeout(check)
+		}
+		stuckRun, checkErr := w.runCheck(check, runTimeout)
 
 		utilizationTracker.CheckFinished()
 
-		expvars.DeleteRunningStats(check.ID())
-
-		checkWarnings := check.GetWarnings()
+		// A stuck check is still running, it can't be safely queried
+		var checkWarnings []error
+		if stuckRun == nil {
+			expvars.DeleteRunningStats(check.ID())
+			checkWarnings = check.GetWarnings()
+		}
 
 		// Use the default sender for the service checks
 		sender, err := w.getDefaultSenderFunc()
@@ -188,28 +211,113 @@ func (w *Worker) Run() {
 			sender.Commit()
 		}
 
-		// Remove the check from the running list
-		w.checksTracker.DeleteCheck(check.ID())
+		// Remove the check from the running list, unless it is stuck: its
+		// next runs are skipped until it completes
+		if stuckRun == nil {
+			w.checksTracker.DeleteCheck(check.ID())
+			expvars.AddRunningCheckCount(-1)
+		}
 
 		// Publish statistics about this run
-		expvars.AddRunningCheckCount(-1)
 		expvars.AddRunsCount(1)
 
 		if !longRunning || len(checkWarnings) != 0 || checkErr != nil {
 			// If the scheduler isn't assigned (it should), just add stats
 			// otherwise only do so if the check is in the scheduler
 			if w.shouldAddCheckStatsFunc(check.ID()) {
-				sStats, _ := check.GetSenderStats()
+				sStats := checkstats.NewSenderStats()
+				if stuckRun == nil {
+					sStats, _ = check.GetSenderStats()
+				}
 				expvars.AddCheckStats(check, time.Since(checkStartTime), checkErr, checkWarnings, sStats)
 			}
 		}
 
+		if stuckRun != nil {
+			log.Warnc(fmt.Sprintf("Check is stuck: it is still running %s after being interrupted, its next runs are skipped until it completes", w.interruptGracePeriod), "check", check)
+			expvars.AddStuckChecksCount(1)
+			expvars.SetCheckStuck(check.ID(), true)
+			tlmStuckChecks.Inc(check.String())
+			go w.waitStuckCheck(check, stuckRun)
+		}
+
 		checkLogger.CheckFinished()
 	}
 
 	log.Debugf("Runner %d, worker %d: Finished processing checks.", w.runnerID, w.ID)
 }
 
+// runTimeout returns the run timeout of a check: its `run_timeout` or the
+// `check_run_timeout` default.
+func (w *Worker) runTimeout(c check.Check) time.Duration {
+	if tc, ok := c.(check.TimeoutCheck); ok {
+		if timeout := tc.RunTimeout(); timeout > 0 {
+			return timeout
+		}
+	}
+	return w.defaultRunTimeout
+}
+
+// runCheck runs a check, interrupting it when it exceeds its run timeout: the
+// context of the checks implementing check.ContextCheck is cancelled and the
+// checks implementing check.InterruptibleCheck are interrupted. If the check
+// is still running after the grace period, its worker gives up waiting for it
+// and runCheck returns a channel receiving the error of the run once it
+// completes.
+func (w *Worker) runCheck(c check.Check, timeout time.Duration) (<-chan error, error) {
+	if timeout <= 0 {
+		return nil, c.Run()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		if cc, ok := c.(check.ContextCheck); ok {
+			done <- cc.RunWithContext(ctx)
+		} else {
+			done <- c.Run()
+		}
+	}()
+
+	select {
+	case err := <-done:
+		return nil, err
+	case <-ctx.Done():
+	}
+
+	timeoutErr := fmt.Errorf("%w of %s", checkstats.ErrRunTimeout, timeout)
+	if ic, ok := c.(check.InterruptibleCheck); ok {
+		// Interrupting a check may block, for instance on the GIL for Python checks
+		go ic.Interrupt()
+	}
+
+	grace := time.NewTimer(w.interruptGracePeriod)
+	defer grace.Stop()
+	select {
+	case <-done:
+		return nil, timeoutErr
+	case <-grace.C:
+		return done, timeoutErr
+	}
+}
+
+// waitStuckCheck waits for a stuck check to complete and releases it so it
+// can run again.
+func (w *Worker) waitStuckCheck(c check.Check, stuckRun <-chan error) {
+	err := <-stuckRun
+	log.Infoc(fmt.Sprintf("Stuck check completed: %v", err), "check", c)
+
+	expvars.DeleteRunningStats(c.ID())
+	expvars.SetCheckStuck(c.ID(), false)
+	expvars.AddStuckChecksCount(-1)
+	tlmStuckChecks.Dec(c.String())
+
+	w.checksTracker.DeleteCheck(c.ID())
+	expvars.AddRunningCheckCount(-1)
+}
+
 func startUtilizationUpdater(name string, ut *UtilizationTracker) {
 	expvars.SetWorkerStats(name, &expvars.WorkerStats{
 		Utilization: 0.0,
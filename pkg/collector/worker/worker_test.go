@@ -6,6 +6,7 @@
 package worker
 
 import (
+	"context"
 	"expvar"
 	"fmt"
 	"sync"
@@ -628,6 +629,116 @@ func TestWorkerServiceCheckSendingLongRunningTasks(t *testing.T) {
 	mockSender.AssertNumberOfCalls(t, "ServiceCheck", 0)
 }
 
+// timeoutCheck is a check whose runs block until its context is cancelled, or
+// until it is released if it ignores its context.
+type timeoutCheck struct {
+	*testCheck
+	ignoreContext bool
+	release       chan struct{}
+	interrupts    *atomic.Uint64
+}
+
+func newTimeoutCheck(t *testing.T, id string, ignoreContext bool) *timeoutCheck {
+	return &timeoutCheck{
+		testCheck:     newCheck(t, id, false, nil),
+		ignoreContext: ignoreContext,
+		release:       make(chan struct{}),
+		interrupts:    atomic.NewUint64(0),
+	}
+}
+
+func (c *timeoutCheck) RunTimeout() time.Duration { return 50 * time.Millisecond }
+func (c *timeoutCheck) Interrupt()                { c.interrupts.Inc() }
+
+func (c *timeoutCheck) RunWithContext(ctx context.Context) error {
+	c.runCount.Inc()
+	if c.ignoreContext {
+		<-c.release
+		return nil
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWorkerRunTimeout(t *testing.T) {
+	expvars.Reset()
+	pkgconfigsetup.Datadog().SetWithoutSource("hostname", "myhost")
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockShouldAddStatsFunc := func(checkid.ID) bool { return true }
+
+	timeoutCheck := newTimeoutCheck(t, "timeout:123", false)
+	pendingChecksChan <- timeoutCheck
+	pendingChecksChan <- timeoutCheck
+	close(pendingChecksChan)
+
+	worker, err := NewWorker(aggregator.NewNoOpSenderManager(), 100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	require.Nil(t, err)
+
+	worker.Run()
+
+	// The runs were cancelled through their context
+	assert.Equal(t, 2, timeoutCheck.RunCount())
+	assert.Eventually(t, func() bool { return timeoutCheck.interrupts.Load() == 2 }, time.Second, 10*time.Millisecond)
+
+	stats, found := expvars.CheckStats(timeoutCheck.ID())
+	require.True(t, found)
+	assert.Equal(t, 2, int(stats.TotalErrors))
+	assert.Equal(t, 2, int(stats.TotalRunTimeouts))
+	assert.Equal(t, "the check run exceeded its run timeout of 50ms", stats.LastError)
+	assert.False(t, stats.Stuck)
+
+	assert.Equal(t, 0, int(expvars.GetStuckChecksCount()))
+	assert.Equal(t, 0, int(expvars.GetRunningCheckCount()))
+	assert.Equal(t, 0, len(checksTracker.RunningChecks()))
+}
+
+func TestWorkerStuckCheck(t *testing.T) {
+	expvars.Reset()
+	pkgconfigsetup.Datadog().SetWithoutSource("hostname", "myhost")
+
+	checksTracker := tracker.NewRunningChecksTracker()
+	pendingChecksChan := make(chan check.Check, 10)
+	mockShouldAddStatsFunc := func(checkid.ID) bool { return true }
+
+	stuckCheck := newTimeoutCheck(t, "stuck:123", true)
+	otherCheck := newCheck(t, "other:123", false, nil)
+	pendingChecksChan <- stuckCheck
+	pendingChecksChan <- stuckCheck
+	pendingChecksChan <- otherCheck
+	close(pendingChecksChan)
+
+	worker, err := NewWorker(aggregator.NewNoOpSenderManager(), 100, 200, pendingChecksChan, checksTracker, mockShouldAddStatsFunc)
+	require.Nil(t, err)
+	worker.interruptGracePeriod = 50 * time.Millisecond
+
+	// The worker gives up on the stuck check and runs the next checks
+	worker.Run()
+
+	assert.Equal(t, 1, stuckCheck.RunCount())
+	assert.Equal(t, 1, otherCheck.RunCount())
+	assert.Eventually(t, func() bool { return stuckCheck.interrupts.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	stats, found := expvars.CheckStats(stuckCheck.ID())
+	require.True(t, found)
+	assert.Equal(t, 1, int(stats.TotalRunTimeouts))
+	assert.True(t, stats.Stuck)
+	assert.Equal(t, 1, int(expvars.GetStuckChecksCount()))
+	assert.Equal(t, 1, int(expvars.GetRunningCheckCount()))
+	_, running := checksTracker.RunningChecks()[stuckCheck.ID()]
+	assert.True(t, running)
+
+	// The check is released once its run completes
+	close(stuckCheck.release)
+	assert.Eventually(t, func() bool { return expvars.GetStuckChecksCount() == 0 }, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return len(checksTracker.RunningChecks()) == 0 }, time.Second, 10*time.Millisecond)
+
+	stats, _ = expvars.CheckStats(stuckCheck.ID())
+	assert.False(t, stats.Stuck)
+	assert.Equal(t, 0, int(expvars.GetRunningCheckCount()))
+}
+
 // getWorkerUtilizationExpvar returns the utilization as presented by expvars
 // for a named worker.
 func getWorkerUtilizationExpvar(t *testing.T, name string) float64 {
@@ -356,6 +356,20 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	// flare configs
 	config.BindEnvAndSetDefault("flare_provider_timeout", 10*time.Second)
 
+	// Self flares captured when the agent runs into trouble
+	config.BindEnvAndSetDefault("self_flare.enabled", false)
+	// Defaults to `run_path`/self_flares when empty
+	config.BindEnvAndSetDefault("self_flare.path", "")
+	config.BindEnvAndSetDefault("self_flare.max_count", 5)
+	config.BindEnvAndSetDefault("self_flare.max_size_mb", 200)
+	config.BindEnvAndSetDefault("self_flare.triggers", []string{"panic", "memory", "stuck_checks", "forwarder_errors"})
+	config.BindEnvAndSetDefault("self_flare.check_interval", 30*time.Second)
+	config.BindEnvAndSetDefault("self_flare.min_interval", time.Hour)
+	// Ratio of the Go memory limit firing the memory trigger
+	config.BindEnvAndSetDefault("self_flare.memory_threshold", 0.95)
+	// Number of forwarder transactions errors during a check interval firing the forwarder errors trigger
+	config.BindEnvAndSetDefault("self_flare.forwarder_errors_threshold", 100)
+
 	// Docker
 	config.BindEnvAndSetDefault("docker_query_timeout", int64(5))
 	config.BindEnvAndSetDefault("docker_labels_as_tags", map[string]string{})
@@ -393,6 +407,7 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("prometheus_scrape.service_endpoints", false) // Enables Service Endpoints checks in the prometheus config provider
 	config.BindEnv("prometheus_scrape.checks")                                // Defines any extra prometheus/openmetrics check configurations to be handled by the prometheus config provider
 	config.BindEnvAndSetDefault("prometheus_scrape.version", 1)               // Version of the openmetrics check to be scheduled by the Prometheus auto-discovery
+	config.BindEnvAndSetDefault("prometheus_scrape.use_core_check", false)    // Schedules the native Go openmetrics check instead of the Python one
 
 	// Network Devices Monitoring
 	bindEnvAndSetLogsConfigKeys(config, "network_devices.metadata.")
@@ -445,6 +460,8 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("network_devices.snmp_traps.bind_host", "0.0.0.0")
 	config.BindEnvAndSetDefault("network_devices.snmp_traps.stop_timeout", 5) // in seconds
 	config.SetKnown("network_devices.snmp_traps.users")
+	config.SetKnown("network_devices.snmp_traps.managers")
+	config.SetKnown("network_devices.snmp_traps.rules")
 
 	// NetFlow
 	config.SetKnown("network_devices.netflow.listeners")
@@ -457,6 +474,10 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("network_devices.netflow.enabled", "false")
 	bindEnvAndSetLogsConfigKeys(config, "network_devices.netflow.forwarder.")
 	config.BindEnvAndSetDefault("network_devices.netflow.reverse_dns_enrichment_enabled", false)
+	config.BindEnvAndSetDefault("network_devices.netflow.geoip_enrichment.enabled", false)
+	config.SetKnown("network_devices.netflow.geoip_enrichment.location_database_path")
+	config.SetKnown("network_devices.netflow.geoip_enrichment.asn_database_path")
+	config.SetKnown("network_devices.netflow.geoip_enrichment.reload_interval")
 
 	// Network Path
 	config.BindEnvAndSetDefault("network_path.connections_monitoring.enabled", false)
@@ -469,6 +490,13 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("network_path.collector.pathtest_ttl", "15m")
 	config.BindEnvAndSetDefault("network_path.collector.pathtest_interval", "5m")
 	config.BindEnvAndSetDefault("network_path.collector.flush_interval", "10s")
+	config.BindEnvAndSetDefault("network_path.collector.target_selection.rank_by", "bytes")
+	config.BindEnvAndSetDefault("network_path.collector.target_selection.max_tests_per_interval", 0)
+	config.BindEnvAndSetDefault("network_path.collector.target_selection.include_cidrs", []string{})
+	config.BindEnvAndSetDefault("network_path.collector.target_selection.exclude_cidrs", []string{})
+	config.BindEnvAndSetDefault("network_path.collector.target_selection.include_domains", []string{})
+	config.BindEnvAndSetDefault("network_path.collector.target_selection.exclude_domains", []string{})
+	config.BindEnvAndSetDefault("network_path.collector.target_selection.rtt_regression_threshold", 0.0)
 	bindEnvAndSetLogsConfigKeys(config, "network_path.forwarder.")
 
 	// Kube ApiServer
@@ -748,6 +776,9 @@ func InitConfig(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("admission_controller.inject_tags.endpoint", "/injecttags")
 	config.BindEnvAndSetDefault("admission_controller.inject_tags.pod_owners_cache_validity", 10) // in minutes
 	config.BindEnv("admission_controller.pod_owners_cache_validity")                              // Alias for admission_controller.inject_tags.pod_owners_cache_validity. Was added without the "inject_tags" prefix by mistake but needs to be kept for backwards compatibility
+	config.BindEnvAndSetDefault("admission_controller.pod_config_validation.enabled", false)
+	config.BindEnvAndSetDefault("admission_controller.pod_config_validation.endpoint", "/validatepodconfig")
+	config.BindEnvAndSetDefault("admission_controller.pod_config_validation.mode", "warn") // possible values: warn / reject
 	config.BindEnvAndSetDefault("admission_controller.namespace_selector_fallback", false)
 	config.BindEnvAndSetDefault("admission_controller.failure_policy", "Ignore")
 	config.BindEnvAndSetDefault("admission_controller.reinvocation_policy", "IfNeeded")
@@ -1083,6 +1114,19 @@ func agent(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("metadata_provider_stop_timeout", 30*time.Second)
 	config.BindEnvAndSetDefault("check_runners", int64(4))
 	config.BindEnvAndSetDefault("check_cancel_timeout", 500*time.Millisecond)
+	// Default run timeout of the checks, 0 to disable it; overridden by their `run_timeout`
+	config.BindEnvAndSetDefault("check_run_timeout", time.Duration(0))
+
+	// Checks running external executables, like Nagios plugins
+	config.BindEnvAndSetDefault("exec_checks.enabled", false)
+	config.BindEnvAndSetDefault("exec_checks.output_max_size", 64*1024)
+	config.BindEnvAndSetDefault("exec_checks.allow_group_exec_perm", false)
+
+	// Custom checks compiled to WebAssembly
+	config.BindEnvAndSetDefault("wasm_checks.enabled", false)
+	config.BindEnvAndSetDefault("wasm_checks.max_memory_mb", 64)
+	config.BindEnvAndSetDefault("wasm_checks.max_run_time", 30*time.Second)
+
 	config.BindEnvAndSetDefault("auth_token_file_path", "")
 	config.BindEnv("bind_host")
 	config.BindEnvAndSetDefault("health_port", int64(0))
@@ -1308,6 +1352,7 @@ func serverless(config pkgconfigmodel.Setup) {
 func forwarder(config pkgconfigmodel.Setup) {
 	// Forwarder
 	config.BindEnvAndSetDefault("additional_endpoints", map[string][]string{})
+	config.BindEnvAndSetDefault("additional_endpoints_routing", map[string][]string{})
 	config.BindEnvAndSetDefault("forwarder_timeout", 20)
 	config.BindEnv("forwarder_retry_queue_max_size")                                                     // Deprecated in favor of `forwarder_retry_queue_payloads_max_size`
 	config.BindEnv("forwarder_retry_queue_payloads_max_size")                                            // Default value is defined inside `NewOptions` in pkg/forwarder/forwarder.go
@@ -1329,11 +1374,20 @@ func forwarder(config pkgconfigmodel.Setup) {
 	config.BindEnvAndSetDefault("forwarder_storage_max_size_in_bytes", 0)                // 0 means disabled. This is a BETA feature.
 	config.BindEnvAndSetDefault("forwarder_storage_max_disk_ratio", 0.80)                // Do not store transactions on disk when the disk usage exceeds 80% of the disk capacity. Use 80% as some applications do not behave well when the disk space is very small.
 	config.BindEnvAndSetDefault("forwarder_retry_queue_capacity_time_interval_sec", 900) // 15 mins
+	// Encryption of the retry files. The key can be sourced from the secrets backend with `ENC[]`.
+	config.BindEnvAndSetDefault("forwarder_storage_encryption_key", "")
+	config.BindEnvAndSetDefault("forwarder_storage_encryption_previous_keys", []string{})
 
 	// Forwarder channels buffer size
 	config.BindEnvAndSetDefault("forwarder_high_prio_buffer_size", 100)
 	config.BindEnvAndSetDefault("forwarder_low_prio_buffer_size", 100)
 	config.BindEnvAndSetDefault("forwarder_requeue_buffer_size", 100)
+
+	// Forwarder egress bandwidth budget shared by all the domains
+	config.BindEnvAndSetDefault("forwarder_bandwidth_budget.bytes_per_sec", 0) // 0 means disabled
+	config.BindEnvAndSetDefault("forwarder_bandwidth_budget.burst_bytes", 0)   // 0 means one second of budget
+	config.BindEnvAndSetDefault("forwarder_bandwidth_budget.max_wait_sec", 5)
+	config.BindEnvAndSetDefault("forwarder_bandwidth_budget.priority_order", []string{})
 }
 
 func dogstatsd(config pkgconfigmodel.Setup) {
@@ -1581,6 +1635,13 @@ func logsagent(config pkgconfigmodel.Setup) {
 	// SDS logs blocking mechanism
 	config.BindEnvAndSetDefault("logs_config.sds.wait_for_configuration", "")
 	config.BindEnvAndSetDefault("logs_config.sds.buffer_max_size", 0)
+
+	// Disk buffer storing the logs payloads while the destinations are unavailable
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.enabled", false)
+	// Defaults to `logs_config.run_path`/spool when empty
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.path", "")
+	// Max disk usage in MB of the buffer of each pipeline
+	config.BindEnvAndSetDefault("logs_config.disk_buffer.max_size_mb", 100)
 }
 
 func vector(config pkgconfigmodel.Setup) {
@@ -673,6 +673,10 @@ func TestNetworkPathDefaults(t *testing.T) {
 	assert.Equal(t, 15*time.Minute, config.GetDuration("network_path.collector.pathtest_ttl"))
 	assert.Equal(t, 5*time.Minute, config.GetDuration("network_path.collector.pathtest_interval"))
 	assert.Equal(t, 10*time.Second, config.GetDuration("network_path.collector.flush_interval"))
+	assert.Equal(t, "bytes", config.GetString("network_path.collector.target_selection.rank_by"))
+	assert.Equal(t, 0, config.GetInt("network_path.collector.target_selection.max_tests_per_interval"))
+	assert.Equal(t, []string{}, config.GetStringSlice("network_path.collector.target_selection.include_cidrs"))
+	assert.Equal(t, 0.0, config.GetFloat64("network_path.collector.target_selection.rtt_regression_threshold"))
 }
 
 func TestUsePodmanLogsAndDockerPathOverride(t *testing.T) {
@@ -215,6 +215,9 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 
 	cfg.BindEnvAndSetDefault(join(spNS, "source_excludes"), map[string][]string{})
 	cfg.BindEnvAndSetDefault(join(spNS, "dest_excludes"), map[string][]string{})
+	cfg.BindEnvAndSetDefault(join(spNS, "process_excludes", "cmdlines"), []string{})
+	cfg.BindEnvAndSetDefault(join(spNS, "process_excludes", "container_images"), []string{})
+	cfg.BindEnvAndSetDefault(join(spNS, "process_excludes", "kube_namespaces"), []string{})
 
 	cfg.BindEnvAndSetDefault(join(spNS, "language_detection.enabled"), false)
 
@@ -247,9 +250,13 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault(join(smNS, "enable_kafka_monitoring"), false)
 	cfg.BindEnv(join(smNS, "enable_postgres_monitoring"))
 	cfg.BindEnv(join(smNS, "enable_redis_monitoring"))
+	cfg.BindEnv(join(smNS, "enable_memcached_monitoring"))
+	cfg.BindEnv(join(smNS, "enable_cassandra_monitoring"))
 	cfg.BindEnvAndSetDefault(join(smNS, "tls", "istio", "enabled"), false)
 	cfg.BindEnvAndSetDefault(join(smNS, "tls", "istio", "envoy_path"), defaultEnvoyPath)
 	cfg.BindEnv(join(smNS, "tls", "nodejs", "enabled"))
+	cfg.BindEnv(join(smNS, "tls", "boringssl", "enabled"))
+	cfg.BindEnv(join(smNS, "tls", "rustls", "enabled"))
 	cfg.BindEnvAndSetDefault(join(smjtNS, "enabled"), false)
 	cfg.BindEnvAndSetDefault(join(smjtNS, "debug"), false)
 	cfg.BindEnvAndSetDefault(join(smjtNS, "args"), defaultServiceMonitoringJavaAgentArgs)
@@ -266,6 +273,8 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnv(join(smNS, "max_postgres_stats_buffered"))
 	cfg.BindEnvAndSetDefault(join(smNS, "max_postgres_telemetry_buffer"), 160)
 	cfg.BindEnv(join(smNS, "max_redis_stats_buffered"))
+	cfg.BindEnv(join(smNS, "max_memcached_stats_buffered"))
+	cfg.BindEnv(join(smNS, "max_cassandra_stats_buffered"))
 	cfg.BindEnv(join(smNS, "max_concurrent_requests"))
 	cfg.BindEnv(join(smNS, "enable_quantization"))
 	cfg.BindEnv(join(smNS, "enable_connection_rollup"))
@@ -303,6 +312,7 @@ func InitSystemProbeConfig(cfg pkgconfigmodel.Config) {
 	cfg.BindEnvAndSetDefault(join(netNS, "dns_recorded_query_types"), []string{})
 	// (temporary) enable submitting DNS stats by query type.
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_dns_by_querytype"), false)
+	cfg.BindEnvAndSetDefault(join(netNS, "dns_failing_domains_top_n"), 0)
 	// connection aggregation with port rollups
 	cfg.BindEnvAndSetDefault(join(netNS, "enable_connection_rollup"), false)
 
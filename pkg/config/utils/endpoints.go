@@ -110,6 +110,38 @@ func GetMultipleEndpoints(c pkgconfigmodel.Reader) (map[string][]string, error)
 	return mergeAdditionalEndpoints(keysPerDomain, additionalEndpoints)
 }
 
+// GetMetricsRoutingRules returns the metric name patterns restricting the metrics
+// sent to each additional endpoint. Additional endpoints without rules receive
+// all the metrics.
+//
+// Routes are keyed by domain, so additional endpoints sharing the domain of the
+// main endpoint (or of the MRF one) can't be routed: their API keys are merged
+// with the main ones and routing them would filter the main organization too.
+func GetMetricsRoutingRules(c pkgconfigmodel.Reader) map[string][]string {
+	additionalEndpoints := c.GetStringMapStringSlice("additional_endpoints")
+	unroutable := map[string]struct{}{GetInfraEndpoint(c): {}}
+	if c.GetBool("multi_region_failover.enabled") {
+		if mrfURL, err := GetMRFInfraEndpoint(c); err == nil {
+			unroutable[mrfURL] = struct{}{}
+		}
+	}
+	rules := map[string][]string{}
+	for domain, patterns := range c.GetStringMapStringSlice("additional_endpoints_routing") {
+		if _, ok := additionalEndpoints[domain]; !ok {
+			log.Warnf("Ignoring the routing rules of %q from 'additional_endpoints_routing': it is not an additional endpoint", domain)
+			continue
+		}
+		if _, ok := unroutable[domain]; ok {
+			log.Warnf("Ignoring the routing rules of %q from 'additional_endpoints_routing': it is the domain of the main endpoint", domain)
+			continue
+		}
+		if len(patterns) > 0 {
+			rules[domain] = patterns
+		}
+	}
+	return rules
+}
+
 // BuildURLWithPrefix will return an HTTP(s) URL for a site given a certain prefix
 func BuildURLWithPrefix(prefix, site string) string {
 	return prefix + strings.TrimSpace(site)
@@ -233,6 +233,58 @@ additional_endpoints:
 	assert.EqualValues(t, expectedMultipleEndpoints, multipleEndpoints)
 }
 
+func TestGetMetricsRoutingRules(t *testing.T) {
+	datadogYaml := `
+api_key: fakeapikey
+
+additional_endpoints:
+  "https://app.datadoghq.eu":
+  - someapikey
+  "https://foo.datadoghq.com":
+  - someotherapikey
+
+additional_endpoints_routing:
+  "https://app.datadoghq.eu":
+  - app.billing.*
+  - app.invoices
+  "https://foo.datadoghq.com": []
+  "https://unknown.datadoghq.com":
+  - app.*
+`
+
+	testConfig := mock.NewFromYAML(t, datadogYaml)
+
+	assert.Equal(t, map[string][]string{
+		"https://app.datadoghq.eu": {"app.billing.*", "app.invoices"},
+	}, GetMetricsRoutingRules(testConfig))
+}
+
+func TestGetMetricsRoutingRulesMainEndpoint(t *testing.T) {
+	datadogYaml := `
+api_key: fakeapikey
+dd_url: "https://app.datadoghq.com"
+
+additional_endpoints:
+  "https://app.datadoghq.com":
+  - someapikey
+  "https://app.datadoghq.eu":
+  - someotherapikey
+
+additional_endpoints_routing:
+  "https://app.datadoghq.com":
+  - app.billing.*
+  "https://app.datadoghq.eu":
+  - app.invoices
+`
+
+	testConfig := mock.NewFromYAML(t, datadogYaml)
+
+	// the endpoint sharing the main domain can't be routed without filtering the main organization
+	assert.Equal(t, map[string][]string{
+		"https://app.datadoghq.eu": {"app.invoices"},
+	}, GetMetricsRoutingRules(testConfig))
+}
+
 func TestSiteEnvVar(t *testing.T) {
 	t.Setenv("DD_API_KEY", "fakeapikey")
 	t.Setenv("DD_SITE", "datadoghq.eu")
@@ -0,0 +1,134 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package selfflare
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Detector returns true and a description of the trouble when its trigger fires.
+type Detector func() (bool, string)
+
+// MemoryDetector fires when the memory used by the Go runtime reaches
+// threshold times the Go memory limit. usage returns the memory used and the
+// memory limit, which is 0 when there is none.
+func MemoryDetector(threshold float64, usage func() (uint64, uint64)) Detector {
+	return func() (bool, string) {
+		used, limit := usage()
+		if limit == 0 || float64(used) < threshold*float64(limit) {
+			return false, ""
+		}
+		return true, fmt.Sprintf("the memory used by the Go runtime (%d bytes) reached %.0f%% of the Go memory limit (%d bytes)", used, threshold*100, limit)
+	}
+}
+
+// StuckChecksDetector fires when the number of stuck checks increases.
+func StuckChecksDetector(count func() int64) Detector {
+	var previous int64
+	return func() (bool, string) {
+		current := count()
+		increased := current > previous
+		previous = current
+		if !increased {
+			return false, ""
+		}
+		return true, fmt.Sprintf("%d checks are stuck after exceeding their run timeout", current)
+	}
+}
+
+// ErrorsDetector fires when the errors counted by count increase by at least
+// threshold between two evaluations.
+func ErrorsDetector(threshold int64, count func() int64) Detector {
+	previous := int64(-1)
+	return func() (bool, string) {
+		current := count()
+		delta := current - previous
+		first := previous < 0
+		previous = current
+		if first || delta < threshold {
+			return false, ""
+		}
+		return true, fmt.Sprintf("%d errors since the previous evaluation", delta)
+	}
+}
+
+type detector struct {
+	trigger      Trigger
+	detect       Detector
+	lastCaptured time.Time
+}
+
+// Monitor evaluates the detectors of the triggers at a regular interval and
+// captures a self flare when one fires, at most once per minInterval for each
+// trigger so that a lasting trouble doesn't fill the store.
+type Monitor struct {
+	interval    time.Duration
+	minInterval time.Duration
+	capture     func(trigger Trigger, details string)
+	detectors   []*detector
+	stop        chan struct{}
+	done        chan struct{}
+}
+
+// NewMonitor returns a monitor calling capture when a trigger fires.
+func NewMonitor(interval time.Duration, minInterval time.Duration, capture func(trigger Trigger, details string)) *Monitor {
+	return &Monitor{
+		interval:    interval,
+		minInterval: minInterval,
+		capture:     capture,
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Add adds the detector of a trigger. It must be called before Start.
+func (m *Monitor) Add(trigger Trigger, detect Detector) {
+	m.detectors = append(m.detectors, &detector{trigger: trigger, detect: detect})
+}
+
+// Start starts evaluating the detectors
+func (m *Monitor) Start() {
+	go func() {
+		defer close(m.done)
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				m.evaluate(now)
+			case <-m.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops evaluating the detectors, waiting for an ongoing capture
+func (m *Monitor) Stop() {
+	close(m.stop)
+	<-m.done
+}
+
+func (m *Monitor) evaluate(now time.Time) {
+	for _, d := range m.detectors {
+		// the detectors are evaluated even when their trigger can't fire
+		// to keep their state up to date
+		fired, details := d.detect()
+		if !fired {
+			continue
+		}
+		if !d.lastCaptured.IsZero() && now.Sub(d.lastCaptured) < m.minInterval {
+			log.Debugf("Self flare trigger %s fired but a flare was captured less than %s ago: %s", d.trigger, m.minInterval, details)
+			continue
+		}
+		d.lastCaptured = now
+		log.Warnf("Self flare trigger %s fired: %s", d.trigger, details)
+		m.capture(d.trigger, details)
+	}
+}
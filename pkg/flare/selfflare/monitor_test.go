@@ -0,0 +1,107 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package selfflare
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryDetector(t *testing.T) {
+	var used, limit uint64
+	detect := MemoryDetector(0.9, func() (uint64, uint64) { return used, limit })
+
+	used = 1000
+	fired, _ := detect()
+	assert.False(t, fired, "no memory limit")
+
+	limit = 2000
+	fired, _ = detect()
+	assert.False(t, fired)
+
+	used = 1800
+	fired, details := detect()
+	assert.True(t, fired)
+	assert.Contains(t, details, "90%")
+}
+
+func TestStuckChecksDetector(t *testing.T) {
+	var count int64
+	detect := StuckChecksDetector(func() int64 { return count })
+
+	fired, _ := detect()
+	assert.False(t, fired)
+
+	count = 1
+	fired, _ = detect()
+	assert.True(t, fired)
+
+	// the same check is still stuck
+	fired, _ = detect()
+	assert.False(t, fired)
+
+	count = 0
+	fired, _ = detect()
+	assert.False(t, fired)
+
+	count = 1
+	fired, _ = detect()
+	assert.True(t, fired)
+}
+
+func TestErrorsDetector(t *testing.T) {
+	count := int64(500)
+	detect := ErrorsDetector(100, func() int64 { return count })
+
+	// the errors counted before the first evaluation don't fire the trigger
+	fired, _ := detect()
+	assert.False(t, fired)
+
+	count += 99
+	fired, _ = detect()
+	assert.False(t, fired)
+
+	count += 100
+	fired, details := detect()
+	assert.True(t, fired)
+	assert.Equal(t, "100 errors since the previous evaluation", details)
+}
+
+func TestMonitorMinInterval(t *testing.T) {
+	var captured []Trigger
+	m := NewMonitor(time.Second, time.Hour, func(trigger Trigger, _ string) {
+		captured = append(captured, trigger)
+	})
+	m.Add(TriggerMemory, func() (bool, string) { return true, "memory" })
+	m.Add(TriggerStuckChecks, func() (bool, string) { return false, "" })
+
+	now := time.Now()
+	m.evaluate(now)
+	m.evaluate(now.Add(time.Minute))
+	assert.Equal(t, []Trigger{TriggerMemory}, captured)
+
+	m.evaluate(now.Add(time.Hour))
+	assert.Equal(t, []Trigger{TriggerMemory, TriggerMemory}, captured)
+}
+
+func TestMonitorStartStop(t *testing.T) {
+	captured := make(chan Trigger, 10)
+	m := NewMonitor(10*time.Millisecond, time.Hour, func(trigger Trigger, _ string) {
+		captured <- trigger
+	})
+	m.Add(TriggerForwarderErrors, func() (bool, string) { return true, "errors" })
+	m.Start()
+
+	select {
+	case trigger := <-captured:
+		assert.Equal(t, TriggerForwarderErrors, trigger)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "the trigger didn't fire")
+	}
+	m.Stop()
+}
@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package selfflare
+
+import (
+	"bytes"
+	"fmt"
+	"runtime/pprof"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// Profiles returns the goroutine and heap profiles of the process, keyed by
+// file name. The goroutines are also dumped as text with their full stacks.
+func Profiles() map[string][]byte {
+	profiles := make(map[string][]byte)
+	for _, profile := range []struct {
+		name  string
+		file  string
+		debug int
+	}{
+		{"goroutine", "goroutine.pprof", 0},
+		{"goroutine", "goroutines.txt", 2},
+		{"heap", "heap.pprof", 0},
+	} {
+		var b bytes.Buffer
+		if err := pprof.Lookup(profile.name).WriteTo(&b, profile.debug); err != nil {
+			log.Warnf("Unable to capture the %s profile: %v", profile.name, err)
+			continue
+		}
+		profiles[profile.file] = b.Bytes()
+	}
+	return profiles
+}
+
+// PanicHook returns a function capturing a self flare into store when a panic
+// is caught. The flare is written synchronously since the process is about to
+// crash, so it only holds the panic and the profiles of the process.
+func PanicHook(store *Store) func(msg string, stacktrace string) {
+	return func(msg string, stacktrace string) {
+		files := Profiles()
+		files["panic.log"] = []byte(fmt.Sprintf("Unexpected panic: %s\n%s", msg, stacktrace))
+		flare, err := store.Write(TriggerPanic, time.Now(), files)
+		if err != nil {
+			log.Errorf("Unable to capture a self flare on panic: %v", err)
+			return
+		}
+		log.Errorf("Captured the self flare %s on panic", flare.Path)
+		log.Flush()
+	}
+}
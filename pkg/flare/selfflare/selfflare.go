@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package selfflare captures flares when the agent runs into trouble, so that
+// its state at that time can be sent to Datadog later on.
+//
+// The flares are captured by triggers (panics, memory approaching the Go memory
+// limit, stuck checks, forwarder error storms) into a directory capped in
+// number of flares and in size, and are listed and sent with `agent flare list`
+// and `agent flare send`.
+package selfflare
+
+import (
+	"path/filepath"
+	"slices"
+	"time"
+
+	pkgconfigmodel "github.com/DataDog/datadog-agent/pkg/config/model"
+)
+
+// Trigger is the reason a self flare was captured
+type Trigger string
+
+const (
+	// TriggerPanic is fired when a panic is caught, it is only supported by the trace-agent
+	TriggerPanic Trigger = "panic"
+	// TriggerMemory is fired when the memory used approaches the Go memory limit
+	TriggerMemory Trigger = "memory"
+	// TriggerStuckChecks is fired when a check gets stuck after exceeding its run timeout
+	TriggerStuckChecks Trigger = "stuck_checks"
+	// TriggerForwarderErrors is fired when the forwarder transactions errors spike
+	TriggerForwarderErrors Trigger = "forwarder_errors"
+)
+
+// Config is the configuration of the self flares
+type Config struct {
+	Enabled bool
+	// Path is the directory the self flares are stored in
+	Path string
+	// MaxCount and MaxSize cap the number and the total size of the self flares
+	MaxCount int
+	MaxSize  int64
+	Triggers []Trigger
+	// CheckInterval is the interval at which the triggers are evaluated
+	CheckInterval time.Duration
+	// MinInterval is the minimum interval between two captures of a trigger
+	MinInterval time.Duration
+	// MemoryThreshold is the ratio of the Go memory limit firing the memory trigger
+	MemoryThreshold float64
+	// ForwarderErrorsThreshold is the number of errors during a check interval
+	// firing the forwarder errors trigger
+	ForwarderErrorsThreshold int64
+}
+
+// ReadConfig returns the self flares configuration
+func ReadConfig(cfg pkgconfigmodel.Reader) Config {
+	path := cfg.GetString("self_flare.path")
+	if path == "" {
+		path = filepath.Join(cfg.GetString("run_path"), "self_flares")
+	}
+	var triggers []Trigger
+	for _, trigger := range cfg.GetStringSlice("self_flare.triggers") {
+		triggers = append(triggers, Trigger(trigger))
+	}
+	return Config{
+		Enabled:                  cfg.GetBool("self_flare.enabled"),
+		Path:                     path,
+		MaxCount:                 cfg.GetInt("self_flare.max_count"),
+		MaxSize:                  int64(cfg.GetInt("self_flare.max_size_mb")) * 1024 * 1024,
+		Triggers:                 triggers,
+		CheckInterval:            cfg.GetDuration("self_flare.check_interval"),
+		MinInterval:              cfg.GetDuration("self_flare.min_interval"),
+		MemoryThreshold:          cfg.GetFloat64("self_flare.memory_threshold"),
+		ForwarderErrorsThreshold: cfg.GetInt64("self_flare.forwarder_errors_threshold"),
+	}
+}
+
+// TriggerEnabled returns true if self flares are enabled and captured by trigger
+func (c Config) TriggerEnabled(trigger Trigger) bool {
+	return c.Enabled && slices.Contains(c.Triggers, trigger)
+}
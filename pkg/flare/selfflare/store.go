@@ -0,0 +1,269 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package selfflare
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	namePrefix = "selfflare-"
+	nameSuffix = ".zip"
+	timeFormat = "20060102T150405.000Z"
+)
+
+// Flare is a self flare of the store
+type Flare struct {
+	Name      string
+	Path      string
+	Trigger   Trigger
+	CreatedAt time.Time
+	Size      int64
+}
+
+// Store keeps the self flares in a directory, removing the oldest ones when
+// the number or the total size of the flares exceed their caps.
+type Store struct {
+	path     string
+	maxCount int
+	maxSize  int64
+	// mu serializes the additions and the rotations of the flares
+	mu sync.Mutex
+}
+
+// NewStore returns a store keeping at most maxCount flares using at most
+// maxSize bytes in path. A cap of 0 or less disables it.
+func NewStore(path string, maxCount int, maxSize int64) *Store {
+	return &Store{
+		path:     path,
+		maxCount: maxCount,
+		maxSize:  maxSize,
+	}
+}
+
+// Path returns the directory of the store
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Add moves the flare archive at archivePath into the store.
+func (s *Store) Add(archivePath string, trigger Trigger, createdAt time.Time) (Flare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.path, 0700); err != nil {
+		return Flare{}, err
+	}
+	path := filepath.Join(s.path, flareName(trigger, createdAt))
+	if err := moveFile(archivePath, path); err != nil {
+		return Flare{}, err
+	}
+	return s.added(path)
+}
+
+// Write writes an archive holding files into the store. It is used when the
+// flare can't be built by the flare component, on panics for instance.
+func (s *Store) Write(trigger Trigger, createdAt time.Time, files map[string][]byte) (Flare, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.path, 0700); err != nil {
+		return Flare{}, err
+	}
+	path := filepath.Join(s.path, flareName(trigger, createdAt))
+	tmpPath := path + ".tmp"
+	if err := writeZip(tmpPath, files); err != nil {
+		os.Remove(tmpPath)
+		return Flare{}, err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return Flare{}, err
+	}
+	return s.added(path)
+}
+
+// added rotates the flares and returns the flare just added
+func (s *Store) added(path string) (Flare, error) {
+	s.rotate()
+	flare, err := newFlare(path)
+	if err != nil {
+		// the flare itself was removed by the rotation as it exceeds the size cap
+		return Flare{}, fmt.Errorf("the flare was removed as it exceeds the size cap of the self flares: %w", err)
+	}
+	return flare, nil
+}
+
+// List returns the flares of the store, most recent first
+func (s *Store) List() ([]Flare, error) {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var flares []Flare
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if _, _, ok := parseName(entry.Name()); !ok {
+			continue
+		}
+		flare, err := newFlare(filepath.Join(s.path, entry.Name()))
+		if err != nil {
+			continue
+		}
+		flares = append(flares, flare)
+	}
+	sort.Slice(flares, func(i, j int) bool {
+		return flares[i].CreatedAt.After(flares[j].CreatedAt)
+	})
+	return flares, nil
+}
+
+// Get returns the flare named name
+func (s *Store) Get(name string) (Flare, error) {
+	if _, _, ok := parseName(name); !ok || filepath.Base(name) != name {
+		return Flare{}, fmt.Errorf("%q is not a self flare name", name)
+	}
+	return newFlare(filepath.Join(s.path, name))
+}
+
+// rotate removes the oldest flares exceeding the caps of the store
+func (s *Store) rotate() {
+	flares, err := s.List()
+	if err != nil {
+		log.Warnf("Unable to list the self flares: %v", err)
+		return
+	}
+
+	var count int
+	var totalSize int64
+	for _, flare := range flares {
+		if (s.maxCount <= 0 || count < s.maxCount) && (s.maxSize <= 0 || totalSize+flare.Size <= s.maxSize) {
+			count++
+			totalSize += flare.Size
+			continue
+		}
+		log.Infof("Removing the self flare %s", flare.Name)
+		if err := os.Remove(flare.Path); err != nil {
+			log.Warnf("Unable to remove the self flare %s: %v", flare.Name, err)
+		}
+	}
+}
+
+func newFlare(path string) (Flare, error) {
+	trigger, createdAt, ok := parseName(filepath.Base(path))
+	if !ok {
+		return Flare{}, fmt.Errorf("%q is not a self flare name", filepath.Base(path))
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return Flare{}, err
+	}
+	return Flare{
+		Name:      filepath.Base(path),
+		Path:      path,
+		Trigger:   trigger,
+		CreatedAt: createdAt,
+		Size:      info.Size(),
+	}, nil
+}
+
+func flareName(trigger Trigger, createdAt time.Time) string {
+	return namePrefix + createdAt.UTC().Format(timeFormat) + "-" + string(trigger) + nameSuffix
+}
+
+func parseName(name string) (Trigger, time.Time, bool) {
+	rest, found := strings.CutPrefix(name, namePrefix)
+	if !found {
+		return "", time.Time{}, false
+	}
+	rest, found = strings.CutSuffix(rest, nameSuffix)
+	if !found {
+		return "", time.Time{}, false
+	}
+	timestamp, trigger, found := strings.Cut(rest, "-")
+	if !found || trigger == "" {
+		return "", time.Time{}, false
+	}
+	createdAt, err := time.Parse(timeFormat, timestamp)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return Trigger(trigger), createdAt, true
+}
+
+func writeZip(path string, files map[string][]byte) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// the files are sorted to get reproducible archives
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := zip.NewWriter(f)
+	for _, name := range names {
+		fw, err := w.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(files[name]); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// moveFile moves src to dst, copying it when they are on different devices
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Remove(src)
+}
@@ -0,0 +1,136 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package selfflare
+
+import (
+	"archive/zip"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreWrite(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "self_flares"), 5, 0)
+	createdAt := time.Date(2024, 3, 1, 12, 30, 15, 250*int(time.Millisecond), time.UTC)
+
+	flare, err := store.Write(TriggerPanic, createdAt, map[string][]byte{"panic.log": []byte("boom")})
+	require.NoError(t, err)
+	assert.Equal(t, "selfflare-20240301T123015.250Z-panic.zip", flare.Name)
+	assert.Equal(t, TriggerPanic, flare.Trigger)
+	assert.True(t, createdAt.Equal(flare.CreatedAt))
+	assert.Positive(t, flare.Size)
+
+	r, err := zip.OpenReader(flare.Path)
+	require.NoError(t, err)
+	defer r.Close()
+	require.Len(t, r.File, 1)
+	assert.Equal(t, "panic.log", r.File[0].Name)
+	f, err := r.File[0].Open()
+	require.NoError(t, err)
+	content, err := io.ReadAll(f)
+	require.NoError(t, err)
+	assert.Equal(t, "boom", string(content))
+}
+
+func TestStoreAdd(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "datadog-agent.zip")
+	require.NoError(t, os.WriteFile(archive, []byte("archive"), 0600))
+
+	store := NewStore(filepath.Join(dir, "self_flares"), 5, 0)
+	flare, err := store.Add(archive, TriggerStuckChecks, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, TriggerStuckChecks, flare.Trigger)
+	assert.NoFileExists(t, archive)
+
+	got, err := store.Get(flare.Name)
+	require.NoError(t, err)
+	assert.Equal(t, flare, got)
+}
+
+func TestStoreRotation(t *testing.T) {
+	store := NewStore(t.TempDir(), 3, 0)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := store.Write(TriggerMemory, start.Add(time.Duration(i)*time.Minute), map[string][]byte{"file": []byte("content")})
+		require.NoError(t, err)
+	}
+
+	flares, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, flares, 3)
+	// the most recent flares are kept, most recent first
+	for i, flare := range flares {
+		assert.True(t, start.Add(time.Duration(4-i)*time.Minute).UTC().Truncate(time.Millisecond).Equal(flare.CreatedAt))
+	}
+}
+
+func TestStoreSizeCap(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 0, 2048)
+	start := time.Now()
+
+	small := map[string][]byte{"file": make([]byte, 100)}
+	_, err := store.Write(TriggerMemory, start, small)
+	require.NoError(t, err)
+
+	// incompressible content bigger than the cap is removed right away
+	big := make([]byte, 4096)
+	_, err = rand.Read(big)
+	require.NoError(t, err)
+	_, err = store.Write(TriggerMemory, start.Add(time.Minute), map[string][]byte{"file": big})
+	assert.Error(t, err)
+
+	// the older flares fitting in the cap are kept
+	flares, err := store.List()
+	require.NoError(t, err)
+	require.Len(t, flares, 1)
+	assert.True(t, start.UTC().Truncate(time.Millisecond).Equal(flares[0].CreatedAt))
+}
+
+func TestStoreList(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir, 0, 0)
+
+	flares, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, flares)
+
+	// missing directory
+	flares, err = NewStore(filepath.Join(dir, "missing"), 0, 0).List()
+	require.NoError(t, err)
+	assert.Empty(t, flares)
+
+	// other files are ignored
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "other.zip"), nil, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "selfflare-invalid-panic.zip"), nil, 0600))
+	_, err = store.Write(TriggerForwarderErrors, time.Now(), nil)
+	require.NoError(t, err)
+
+	flares, err = store.List()
+	require.NoError(t, err)
+	require.Len(t, flares, 1)
+	assert.Equal(t, TriggerForwarderErrors, flares[0].Trigger)
+}
+
+func TestStoreGetInvalidName(t *testing.T) {
+	store := NewStore(t.TempDir(), 0, 0)
+
+	for _, name := range []string{
+		"other.zip",
+		"../selfflare-20240301T123015.250Z-panic.zip",
+		"selfflare-20240301T123015.250Z-panic.zip",
+	} {
+		_, err := store.Get(name)
+		assert.Error(t, err, name)
+	}
+}
@@ -70,6 +70,8 @@ var (
 		nil, "Histogram of http sender latency in ms", []float64{10, 25, 50, 75, 100, 250, 500, 1000, 10000})
 	// DestinationExpVars a map of sender utilization metrics for each http destination
 	DestinationExpVars = expvar.Map{}
+	// SpoolExpVars a map of the number of payloads and bytes stored in the disk spool of each pipeline
+	SpoolExpVars = expvar.Map{}
 	// TODO: Add LogsCollected for the total number of collected logs.
 	//nolint:revive // TODO(AML) Fix revive linter
 	DestinationHttpRespByStatusAndUrl = expvar.Map{}
@@ -97,4 +99,5 @@ func init() {
 	LogsExpvars.Set("BytesMissed", &BytesMissed)
 	LogsExpvars.Set("SenderLatency", &SenderLatency)
 	LogsExpvars.Set("HttpDestinationStats", &DestinationExpVars)
+	LogsExpvars.Set("Spool", &SpoolExpVars)
 }
@@ -9,6 +9,7 @@ package pipeline
 import (
 	"context"
 	"fmt"
+	"path/filepath"
 	"sync"
 
 	"github.com/DataDog/datadog-agent/comp/core/hostname/hostnameinterface"
@@ -22,6 +23,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/logs/processor"
 	"github.com/DataDog/datadog-agent/pkg/logs/sender"
 	"github.com/DataDog/datadog-agent/pkg/logs/status/statusinterface"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
 // Pipeline processes and sends messages to the backend
@@ -30,6 +32,7 @@ type Pipeline struct {
 	flushChan  chan struct{}
 	processor  *processor.Processor
 	strategy   sender.Strategy
+	spool      *sender.Spool
 	sender     *sender.Sender
 	serverless bool
 	flushWg    *sync.WaitGroup
@@ -73,7 +76,17 @@ func NewPipeline(outputChan chan *message.Payload,
 		encoder = processor.RawEncoder
 	}
 
-	strategy := getStrategy(strategyInput, senderInput, flushChan, endpoints, serverless, flushWg, pipelineID)
+	// The spool, if enabled, sits between the strategy and the sender
+	strategyOutput := senderInput
+	var spool *sender.Spool
+	if !serverless && cfg != nil && cfg.GetBool("logs_config.disk_buffer.enabled") {
+		spoolInput := make(chan *message.Payload, 1)
+		if spool = getSpool(spoolInput, senderInput, pipelineID, cfg); spool != nil {
+			strategyOutput = spoolInput
+		}
+	}
+
+	strategy := getStrategy(strategyInput, strategyOutput, flushChan, endpoints, serverless, flushWg, pipelineID)
 	logsSender = sender.NewSender(cfg, senderInput, outputChan, mainDestinations, config.DestinationPayloadChanSize, senderDoneChan, flushWg)
 
 	inputChan := make(chan *message.Message, config.ChanSize)
@@ -86,6 +99,7 @@ func NewPipeline(outputChan chan *message.Payload,
 		flushChan:  flushChan,
 		processor:  processor,
 		strategy:   strategy,
+		spool:      spool,
 		sender:     logsSender,
 		serverless: serverless,
 		flushWg:    flushWg,
@@ -95,6 +109,9 @@ func NewPipeline(outputChan chan *message.Payload,
 // Start launches the pipeline
 func (p *Pipeline) Start() {
 	p.sender.Start()
+	if p.spool != nil {
+		p.spool.Start()
+	}
 	p.strategy.Start()
 	p.processor.Start()
 }
@@ -103,6 +120,9 @@ func (p *Pipeline) Start() {
 func (p *Pipeline) Stop() {
 	p.processor.Stop()
 	p.strategy.Stop()
+	if p.spool != nil {
+		p.spool.Stop()
+	}
 	p.sender.Stop()
 }
 
@@ -122,29 +142,42 @@ func getDestinations(endpoints *config.Endpoints, destinationsContext *client.De
 	additionals := []client.Destination{}
 
 	if endpoints.UseHTTP {
+		contentEncoding := getContentEncoding(endpoints)
 		for i, endpoint := range endpoints.GetReliableEndpoints() {
+			if serverless && len(endpoint.RoutingRules) > 0 {
+				log.Warnf("Routing rules are not supported in serverless, ignoring the logs endpoint %s", endpoint.Host)
+				continue
+			}
 			telemetryName := fmt.Sprintf("logs_%d_reliable_%d", pipelineID, i)
 			if serverless {
 				reliable = append(reliable, http.NewSyncDestination(endpoint, http.JSONContentType, destinationsContext, senderDoneChan, telemetryName, cfg))
 			} else {
-				reliable = append(reliable, http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend, true, telemetryName, cfg))
+				destination := http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend, true, telemetryName, cfg)
+				reliable = append(reliable, withRoutingRules(destination, endpoint, sender.ArraySerializer, contentEncoding))
 			}
 		}
 		for i, endpoint := range endpoints.GetUnReliableEndpoints() {
+			if serverless && len(endpoint.RoutingRules) > 0 {
+				log.Warnf("Routing rules are not supported in serverless, ignoring the logs endpoint %s", endpoint.Host)
+				continue
+			}
 			telemetryName := fmt.Sprintf("logs_%d_unreliable_%d", pipelineID, i)
 			if serverless {
 				additionals = append(additionals, http.NewSyncDestination(endpoint, http.JSONContentType, destinationsContext, senderDoneChan, telemetryName, cfg))
 			} else {
-				additionals = append(additionals, http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend, false, telemetryName, cfg))
+				destination := http.NewDestination(endpoint, http.JSONContentType, destinationsContext, endpoints.BatchMaxConcurrentSend, false, telemetryName, cfg)
+				additionals = append(additionals, withRoutingRules(destination, endpoint, sender.ArraySerializer, contentEncoding))
 			}
 		}
 		return client.NewDestinations(reliable, additionals)
 	}
 	for _, endpoint := range endpoints.GetReliableEndpoints() {
-		reliable = append(reliable, tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext, !serverless, status))
+		destination := tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext, !serverless, status)
+		reliable = append(reliable, withRoutingRules(destination, endpoint, sender.LineSerializer, sender.IdentityContentType))
 	}
 	for _, endpoint := range endpoints.GetUnReliableEndpoints() {
-		additionals = append(additionals, tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext, false, status))
+		destination := tcp.NewDestination(endpoint, endpoints.UseProto, destinationsContext, false, status)
+		additionals = append(additionals, withRoutingRules(destination, endpoint, sender.LineSerializer, sender.IdentityContentType))
 	}
 
 	return client.NewDestinations(reliable, additionals)
@@ -153,11 +186,39 @@ func getDestinations(endpoints *config.Endpoints, destinationsContext *client.De
 //nolint:revive // TODO(AML) Fix revive linter
 func getStrategy(inputChan chan *message.Message, outputChan chan *message.Payload, flushChan chan struct{}, endpoints *config.Endpoints, serverless bool, flushWg *sync.WaitGroup, _ int) sender.Strategy {
 	if endpoints.UseHTTP || serverless {
-		encoder := sender.IdentityContentType
-		if endpoints.Main.UseCompression {
-			encoder = sender.NewGzipContentEncoding(endpoints.Main.CompressionLevel)
-		}
-		return sender.NewBatchStrategy(inputChan, outputChan, flushChan, serverless, flushWg, sender.ArraySerializer, endpoints.BatchWait, endpoints.BatchMaxSize, endpoints.BatchMaxContentSize, "logs", encoder)
+		return sender.NewBatchStrategy(inputChan, outputChan, flushChan, serverless, flushWg, sender.ArraySerializer, endpoints.BatchWait, endpoints.BatchMaxSize, endpoints.BatchMaxContentSize, "logs", getContentEncoding(endpoints))
 	}
 	return sender.NewStreamStrategy(inputChan, outputChan, sender.IdentityContentType)
 }
+
+func getContentEncoding(endpoints *config.Endpoints) sender.ContentEncoding {
+	if endpoints.Main.UseCompression {
+		return sender.NewGzipContentEncoding(endpoints.Main.CompressionLevel)
+	}
+	return sender.IdentityContentType
+}
+
+// getSpool returns the disk spool of the pipeline, or nil if it can't be created.
+func getSpool(inputChan chan *message.Payload, outputChan chan *message.Payload, pipelineID int, cfg pkgconfigmodel.Reader) *sender.Spool {
+	path := cfg.GetString("logs_config.disk_buffer.path")
+	if path == "" {
+		path = filepath.Join(cfg.GetString("logs_config.run_path"), "spool")
+	}
+	path = filepath.Join(path, fmt.Sprintf("pipeline_%d", pipelineID))
+	maxSize := int64(cfg.GetInt("logs_config.disk_buffer.max_size_mb")) * 1024 * 1024
+
+	spool, err := sender.NewSpool(inputChan, outputChan, path, maxSize, pipelineID)
+	if err != nil {
+		log.Errorf("The logs disk buffer is disabled: %v", err)
+		return nil
+	}
+	return spool
+}
+
+// withRoutingRules restricts the logs sent to the destination when its endpoint has routing rules.
+func withRoutingRules(destination client.Destination, endpoint config.Endpoint, serializer sender.Serializer, contentEncoding sender.ContentEncoding) client.Destination {
+	if len(endpoint.RoutingRules) == 0 {
+		return destination
+	}
+	return sender.NewRoutedDestination(destination, endpoint.RoutingRules, serializer, contentEncoding)
+}
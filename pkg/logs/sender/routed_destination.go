@@ -0,0 +1,147 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sender
+
+import (
+	"path"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/logs/client"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// routingRule matches the logs having a given attribute or tag, for instance
+// `service:payments` or `env:prod`. The value can be a glob pattern.
+type routingRule struct {
+	key   string
+	value string
+}
+
+func parseRoutingRule(rule string) (routingRule, bool) {
+	key, value, found := strings.Cut(rule, ":")
+	if !found || key == "" || value == "" {
+		return routingRule{}, false
+	}
+	if _, err := path.Match(value, ""); err != nil {
+		return routingRule{}, false
+	}
+	return routingRule{key: key, value: value}, true
+}
+
+func (r routingRule) matchValue(value string) bool {
+	matched, _ := path.Match(r.value, value)
+	return matched
+}
+
+func (r routingRule) match(msg *message.Message) bool {
+	switch r.key {
+	case "host":
+		return r.matchValue(msg.Hostname)
+	case "status":
+		return r.matchValue(msg.GetStatus())
+	}
+	if msg.Origin == nil {
+		return false
+	}
+	switch r.key {
+	case "service":
+		return r.matchValue(msg.Origin.Service())
+	case "source":
+		return r.matchValue(msg.Origin.Source())
+	}
+	for _, tag := range msg.Tags() {
+		if key, value, found := strings.Cut(tag, ":"); found && key == r.key && r.matchValue(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// routedDestination wraps a destination to only send the messages matching
+// its routing rules. The payloads are serialized again when only some of
+// their messages match.
+type routedDestination struct {
+	client.Destination
+	rules           []routingRule
+	serializer      Serializer
+	contentEncoding ContentEncoding
+}
+
+// NewRoutedDestination returns a destination sending to `destination` only the messages matching at least
+// one of the routing rules. Rules are formatted as `<key>:<value>` where the key is `service`, `source`,
+// `host`, `status` or a tag name.
+func NewRoutedDestination(destination client.Destination, routingRules []string, serializer Serializer, contentEncoding ContentEncoding) client.Destination {
+	d := &routedDestination{
+		Destination:     destination,
+		serializer:      serializer,
+		contentEncoding: contentEncoding,
+	}
+	for _, rule := range routingRules {
+		r, ok := parseRoutingRule(rule)
+		if !ok {
+			log.Warnf("Ignoring the invalid routing rule %q of %s: rules must be formatted as <key>:<value>", rule, destination.Target())
+			continue
+		}
+		d.rules = append(d.rules, r)
+	}
+	return d
+}
+
+// Start starts the wrapped destination and filters the payloads sent to it.
+func (d *routedDestination) Start(input chan *message.Payload, output chan *message.Payload, isRetrying chan bool) (stopChan <-chan struct{}) {
+	routedInput := make(chan *message.Payload, cap(input))
+	stopChan = d.Destination.Start(routedInput, output, isRetrying)
+	go func() {
+		for payload := range input {
+			if routed := d.route(payload); routed != nil {
+				routedInput <- routed
+			}
+		}
+		close(routedInput)
+	}()
+	return stopChan
+}
+
+// route returns the payload restricted to the matching messages, or nil if
+// no message matches.
+func (d *routedDestination) route(payload *message.Payload) *message.Payload {
+	messages := make([]*message.Message, 0, len(payload.Messages))
+	for _, msg := range payload.Messages {
+		if d.match(msg) {
+			messages = append(messages, msg)
+		}
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+	if len(messages) == len(payload.Messages) {
+		return payload
+	}
+
+	serialized := d.serializer.Serialize(messages)
+	encoded, err := d.contentEncoding.encode(serialized)
+	if err != nil {
+		log.Warn("Encoding failed - dropping payload", err)
+		return nil
+	}
+	return &message.Payload{
+		Messages:      messages,
+		Encoded:       encoded,
+		Encoding:      d.contentEncoding.name(),
+		UnencodedSize: len(serialized),
+	}
+}
+
+func (d *routedDestination) match(msg *message.Message) bool {
+	for _, rule := range d.rules {
+		if rule.match(msg) {
+			return true
+		}
+	}
+	return false
+}
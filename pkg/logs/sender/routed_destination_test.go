@@ -0,0 +1,83 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sender
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func newRoutingTestMessage(content string, service string, tags ...string) *message.Message {
+	source := sources.NewLogSource("", &config.LogsConfig{Service: service, Source: "python", Tags: tags})
+	msg := message.NewMessageWithSource([]byte(content), message.StatusInfo, source, 0)
+	msg.Hostname = "web-1"
+	return msg
+}
+
+func TestRoutingRuleMatch(t *testing.T) {
+	msg := newRoutingTestMessage(`{"message":"a"}`, "payments", "env:prod", "team:billing")
+
+	tests := []struct {
+		rule    string
+		matched bool
+	}{
+		{"service:payments", true},
+		{"service:pay*", true},
+		{"service:checkout", false},
+		{"source:python", true},
+		{"host:web-*", true},
+		{"status:info", true},
+		{"env:prod", true},
+		{"env:staging", false},
+		{"team:*", true},
+		{"region:*", false},
+	}
+	for _, tt := range tests {
+		rule, ok := parseRoutingRule(tt.rule)
+		require.True(t, ok, tt.rule)
+		assert.Equal(t, tt.matched, rule.match(msg), tt.rule)
+	}
+
+	for _, invalid := range []string{"payments", "service:", ":payments", "service:[payments"} {
+		_, ok := parseRoutingRule(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestRoutedDestination(t *testing.T) {
+	dest := &mockDestination{}
+	routed := NewRoutedDestination(dest, []string{"service:payments", "invalid"}, ArraySerializer, IdentityContentType)
+
+	input := make(chan *message.Payload, 1)
+	output := make(chan *message.Payload, 1)
+	routed.Start(input, output, nil)
+
+	payments := newRoutingTestMessage(`{"message":"a"}`, "payments")
+	checkout := newRoutingTestMessage(`{"message":"b"}`, "checkout")
+
+	// Payloads without matching messages are dropped, the matching messages are serialized again
+	input <- &message.Payload{Messages: []*message.Message{checkout}, Encoded: []byte(`[{"message":"b"}]`)}
+	input <- &message.Payload{Messages: []*message.Message{checkout, payments}, Encoded: []byte(`[{"message":"b"},{"message":"a"}]`)}
+	payload := <-dest.input
+	assert.Equal(t, []*message.Message{payments}, payload.Messages)
+	assert.Equal(t, `[{"message":"a"}]`, string(payload.Encoded))
+	assert.Equal(t, "identity", payload.Encoding)
+
+	// Payloads whose messages all match are sent as is
+	original := &message.Payload{Messages: []*message.Message{payments}, Encoded: []byte(`[{"message":"a"}]`)}
+	input <- original
+	assert.Same(t, original, <-dest.input)
+
+	close(input)
+	_, open := <-dest.input
+	assert.False(t, open)
+}
@@ -0,0 +1,399 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sender
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/metrics"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+	"github.com/DataDog/datadog-agent/pkg/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const spoolFileExtension = ".spool"
+
+// spoolSpillTimeout is how long a payload waits for the sender before it is
+// stored: the sender input only holds one payload, it is briefly full while
+// the destinations are available.
+const spoolSpillTimeout = 5 * time.Second
+
+var (
+	tlmSpoolPayloads = telemetry.NewGauge("logs_sender", "spool_payloads", []string{"pipeline"}, "Number of payloads stored in the disk spool")
+	tlmSpoolBytes    = telemetry.NewGauge("logs_sender", "spool_bytes", []string{"pipeline"}, "Size in bytes of the payloads stored in the disk spool")
+	tlmSpoolDropped  = telemetry.NewCounter("logs_sender", "spool_payloads_dropped", []string{"pipeline"}, "Payloads dropped because they could not be stored in the disk spool")
+)
+
+// spoolRecord is a payload stored on the disk.
+type spoolRecord struct {
+	path string
+	size int64
+}
+
+// spoolMessage holds a spooled message: its content, needed when a routed
+// destination serializes again a part of the payload, and the metadata needed
+// by the destinations and by the auditor.
+type spoolMessage struct {
+	Content            []byte   `json:"content,omitempty"`
+	Identifier         string   `json:"identifier,omitempty"`
+	Offset             string   `json:"offset,omitempty"`
+	TailingMode        string   `json:"tailing_mode,omitempty"`
+	IngestionTimestamp int64    `json:"ingestion_timestamp,omitempty"`
+	Hostname           string   `json:"hostname,omitempty"`
+	Status             string   `json:"status,omitempty"`
+	Service            string   `json:"service,omitempty"`
+	Source             string   `json:"source,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
+}
+
+type spoolMetadata struct {
+	Encoding      string         `json:"encoding,omitempty"`
+	UnencodedSize int            `json:"unencoded_size"`
+	Messages      []spoolMessage `json:"messages"`
+}
+
+// Spool sits between a strategy and the sender. Payloads go straight to the
+// sender while it keeps up; when it is blocked for longer than spillTimeout,
+// because the reliable destinations are unavailable, the payloads are stored
+// on the disk and replayed in order once it recovers.
+//
+// The offsets of the spooled messages are committed by the sender once they
+// are replayed, like the others. After a restart with a non-empty spool, the
+// tailers resume from the committed offsets: the logs spooled before the
+// restart may be sent twice, but they are never lost.
+//
+// When the spool is full, it applies backpressure like the sender does.
+type Spool struct {
+	inputChan    chan *message.Payload
+	outputChan   chan *message.Payload
+	path         string
+	maxSize      int64
+	pipelineID   string
+	spillTimeout time.Duration
+
+	mutex   sync.Mutex
+	cond    *sync.Cond
+	records []spoolRecord
+	size    int64
+	nextSeq uint64
+	stopped bool
+
+	stop     chan struct{}
+	done     sync.WaitGroup
+	expVars  *expvar.Map
+	payloads expvar.Int
+	bytes    expvar.Int
+}
+
+// NewSpool returns a new spool storing up to maxSize bytes of payloads in
+// path. The payloads left by a previous run are replayed first.
+func NewSpool(inputChan chan *message.Payload, outputChan chan *message.Payload, path string, maxSize int64, pipelineID int) (*Spool, error) {
+	if err := os.MkdirAll(path, 0700); err != nil {
+		return nil, fmt.Errorf("can't create the logs spool directory %s: %w", path, err)
+	}
+
+	s := &Spool{
+		inputChan:    inputChan,
+		outputChan:   outputChan,
+		path:         path,
+		maxSize:      maxSize,
+		pipelineID:   strconv.Itoa(pipelineID),
+		spillTimeout: spoolSpillTimeout,
+		stop:         make(chan struct{}),
+		expVars:      &expvar.Map{},
+	}
+	s.cond = sync.NewCond(&s.mutex)
+	s.expVars.Set("Payloads", &s.payloads)
+	s.expVars.Set("Bytes", &s.bytes)
+
+	if err := s.recover(); err != nil {
+		return nil, err
+	}
+	if len(s.records) > 0 {
+		log.Infof("Replaying %d logs payloads (%d bytes) from the spool %s", len(s.records), s.size, path)
+	}
+	return s, nil
+}
+
+// Start starts the spool.
+func (s *Spool) Start() {
+	metrics.SpoolExpVars.Set(s.pipelineID, s.expVars)
+	s.done.Add(2)
+	go s.run()
+	go s.replay()
+}
+
+// Stop stops the spool, the payloads which were not replayed yet are kept
+// on the disk for the next run. This call blocks until inputChan is flushed.
+func (s *Spool) Stop() {
+	close(s.inputChan)
+	s.mutex.Lock()
+	s.stopped = true
+	s.cond.Broadcast()
+	s.mutex.Unlock()
+	close(s.stop)
+	s.done.Wait()
+	metrics.SpoolExpVars.Delete(s.pipelineID)
+}
+
+// run forwards the payloads to the sender, or stores them when the sender is
+// blocked or older payloads are still stored.
+func (s *Spool) run() {
+	defer s.done.Done()
+	timer := time.NewTimer(s.spillTimeout)
+	defer timer.Stop()
+	for payload := range s.inputChan {
+		if !s.send(payload, timer) {
+			s.store(payload)
+		}
+	}
+}
+
+// send forwards the payload to the sender when no older payload is stored,
+// it returns false if the sender is blocked for longer than spillTimeout.
+func (s *Spool) send(payload *message.Payload, timer *time.Timer) bool {
+	// only run stores payloads, none can be stored while it waits for the sender
+	s.mutex.Lock()
+	spooling := len(s.records) > 0 || s.stopped
+	s.mutex.Unlock()
+	if spooling {
+		return false
+	}
+
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	timer.Reset(s.spillTimeout)
+	select {
+	case s.outputChan <- payload:
+		return true
+	case <-timer.C:
+		return false
+	case <-s.stop:
+		return false
+	}
+}
+
+// store stores the payload on the disk.
+func (s *Spool) store(payload *message.Payload) {
+	data, err := encodeSpoolRecord(payload)
+	if err != nil {
+		log.Warnf("Can't spool logs payload, dropping it: %v", err)
+		tlmSpoolDropped.Inc(s.pipelineID)
+		return
+	}
+	size := int64(len(data))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for s.size > 0 && s.size+size > s.maxSize {
+		if s.stopped {
+			log.Warnf("The logs spool %s is full, dropping a payload while stopping", s.path)
+			tlmSpoolDropped.Inc(s.pipelineID)
+			return
+		}
+		// Wait for the replay to make some room
+		s.cond.Wait()
+	}
+
+	path := filepath.Join(s.path, fmt.Sprintf("%020d%s", s.nextSeq, spoolFileExtension))
+	if err := writeSpoolFile(path, data); err != nil {
+		log.Warnf("Can't spool logs payload, dropping it: %v", err)
+		tlmSpoolDropped.Inc(s.pipelineID)
+		return
+	}
+	s.nextSeq++
+	s.records = append(s.records, spoolRecord{path: path, size: size})
+	s.size += size
+	s.updateMetricsLocked()
+	s.cond.Broadcast()
+}
+
+// replay sends the stored payloads to the sender, oldest first.
+func (s *Spool) replay() {
+	defer s.done.Done()
+	for {
+		s.mutex.Lock()
+		for len(s.records) == 0 && !s.stopped {
+			s.cond.Wait()
+		}
+		if s.stopped {
+			s.mutex.Unlock()
+			return
+		}
+		record := s.records[0]
+		s.mutex.Unlock()
+
+		payload, err := readSpoolFile(record.path)
+		if err != nil {
+			log.Warnf("Can't read the spooled logs payload %s, dropping it: %v", record.path, err)
+			tlmSpoolDropped.Inc(s.pipelineID)
+		} else {
+			select {
+			case s.outputChan <- payload:
+			case <-s.stop:
+				return
+			}
+		}
+
+		s.mutex.Lock()
+		if err := os.Remove(record.path); err != nil && !os.IsNotExist(err) {
+			log.Warnf("Can't remove the spooled logs payload %s: %v", record.path, err)
+		}
+		s.records = s.records[1:]
+		s.size -= record.size
+		s.updateMetricsLocked()
+		s.cond.Broadcast()
+		s.mutex.Unlock()
+	}
+}
+
+// recover loads the payloads stored by a previous run.
+func (s *Spool) recover() error {
+	entries, err := os.ReadDir(s.path)
+	if err != nil {
+		return fmt.Errorf("can't read the logs spool directory %s: %w", s.path, err)
+	}
+
+	var seqs []uint64
+	sizes := make(map[uint64]int64)
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, spoolFileExtension) {
+			continue
+		}
+		seq, err := strconv.ParseUint(strings.TrimSuffix(name, spoolFileExtension), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		seqs = append(seqs, seq)
+		sizes[seq] = info.Size()
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+
+	for _, seq := range seqs {
+		path := filepath.Join(s.path, fmt.Sprintf("%020d%s", seq, spoolFileExtension))
+		s.records = append(s.records, spoolRecord{path: path, size: sizes[seq]})
+		s.size += sizes[seq]
+		s.nextSeq = seq + 1
+	}
+	s.updateMetricsLocked()
+	return nil
+}
+
+func (s *Spool) updateMetricsLocked() {
+	s.payloads.Set(int64(len(s.records)))
+	s.bytes.Set(s.size)
+	tlmSpoolPayloads.Set(float64(len(s.records)), s.pipelineID)
+	tlmSpoolBytes.Set(float64(s.size), s.pipelineID)
+}
+
+func writeSpoolFile(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// encodeSpoolRecord encodes the length of the metadata, the metadata, holding
+// the content of the messages, and the encoded payload.
+func encodeSpoolRecord(payload *message.Payload) ([]byte, error) {
+	metadata := spoolMetadata{
+		Encoding:      payload.Encoding,
+		UnencodedSize: payload.UnencodedSize,
+		Messages:      make([]spoolMessage, 0, len(payload.Messages)),
+	}
+	for _, msg := range payload.Messages {
+		m := spoolMessage{
+			Content:            msg.GetContent(),
+			IngestionTimestamp: msg.IngestionTimestamp,
+			Hostname:           msg.Hostname,
+			Status:             msg.Status,
+		}
+		if msg.Origin != nil {
+			m.Identifier = msg.Origin.Identifier
+			m.Offset = msg.Origin.Offset
+			if msg.Origin.LogSource != nil {
+				m.TailingMode = msg.Origin.LogSource.Config.TailingMode
+				m.Service = msg.Origin.Service()
+				m.Source = msg.Origin.Source()
+				m.Tags = msg.Tags()
+			}
+		}
+		metadata.Messages = append(metadata.Messages, m)
+	}
+
+	meta, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 4, 4+len(meta)+len(payload.Encoded))
+	binary.BigEndian.PutUint32(data, uint32(len(meta)))
+	data = append(data, meta...)
+	data = append(data, payload.Encoded...)
+	return data, nil
+}
+
+func readSpoolFile(path string) (*message.Payload, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 4 {
+		return nil, errors.New("truncated spool file")
+	}
+	metaLen := int(binary.BigEndian.Uint32(data))
+	if len(data) < 4+metaLen {
+		return nil, errors.New("truncated spool file")
+	}
+
+	var metadata spoolMetadata
+	if err := json.Unmarshal(data[4:4+metaLen], &metadata); err != nil {
+		return nil, err
+	}
+
+	payload := &message.Payload{
+		Messages:      make([]*message.Message, 0, len(metadata.Messages)),
+		Encoded:       data[4+metaLen:],
+		Encoding:      metadata.Encoding,
+		UnencodedSize: metadata.UnencodedSize,
+	}
+	for _, m := range metadata.Messages {
+		origin := message.NewOrigin(sources.NewLogSource("", &config.LogsConfig{TailingMode: m.TailingMode}))
+		origin.Identifier = m.Identifier
+		origin.Offset = m.Offset
+		origin.SetService(m.Service)
+		origin.SetSource(m.Source)
+		origin.SetTags(m.Tags)
+		msg := message.NewMessage(nil, origin, m.Status, m.IngestionTimestamp)
+		msg.Hostname = m.Hostname
+		// the content was stored once encoded by the processor
+		msg.SetEncoded(m.Content)
+		payload.Messages = append(payload.Messages, msg)
+	}
+	return payload, nil
+}
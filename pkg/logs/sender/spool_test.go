@@ -0,0 +1,200 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package sender
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/comp/logs/agent/config"
+	"github.com/DataDog/datadog-agent/pkg/logs/message"
+	"github.com/DataDog/datadog-agent/pkg/logs/sources"
+)
+
+func newSpoolTestPayload(i int) *message.Payload {
+	source := sources.NewLogSource("", &config.LogsConfig{Service: "payments", Source: "python", Tags: []string{"env:prod"}, TailingMode: "end"})
+	msg := message.NewMessageWithSource([]byte("log"), message.StatusWarning, source, int64(i))
+	msg.Hostname = "web-1"
+	msg.Origin.Identifier = "file:/var/log/app.log"
+	msg.Origin.Offset = fmt.Sprintf("%d", i)
+	return &message.Payload{
+		Messages:      []*message.Message{msg},
+		Encoded:       []byte(fmt.Sprintf("payload-%d", i)),
+		Encoding:      "gzip",
+		UnencodedSize: 42,
+	}
+}
+
+func newTestSpool(t *testing.T, input chan *message.Payload, output chan *message.Payload, path string, maxSize int64) *Spool {
+	spool, err := NewSpool(input, output, path, maxSize, 0)
+	require.NoError(t, err)
+	// Nobody reads the output in most tests, the payloads are stored right away
+	spool.spillTimeout = time.Millisecond
+	return spool
+}
+
+func spoolDepth(s *Spool) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.records)
+}
+
+func TestSpoolPassThrough(t *testing.T) {
+	input := make(chan *message.Payload, 1)
+	output := make(chan *message.Payload, 1)
+	spool := newTestSpool(t, input, output, t.TempDir(), 1024*1024)
+	spool.Start()
+	defer spool.Stop()
+
+	payload := newSpoolTestPayload(0)
+	input <- payload
+	assert.Same(t, payload, <-output)
+	assert.Equal(t, 0, spoolDepth(spool))
+}
+
+func TestSpoolReplayInOrder(t *testing.T) {
+	input := make(chan *message.Payload, 1)
+	output := make(chan *message.Payload)
+	spool := newTestSpool(t, input, output, t.TempDir(), 1024*1024)
+	spool.Start()
+	defer spool.Stop()
+
+	// Nobody reads the output, the payloads are stored
+	for i := 0; i < 5; i++ {
+		input <- newSpoolTestPayload(i)
+	}
+	require.Eventually(t, func() bool { return spoolDepth(spool) == 5 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(5), spool.payloads.Value())
+
+	for i := 0; i < 5; i++ {
+		payload := <-output
+		assert.Equal(t, fmt.Sprintf("payload-%d", i), string(payload.Encoded))
+	}
+	require.Eventually(t, func() bool { return spoolDepth(spool) == 0 }, time.Second, time.Millisecond)
+	assert.Equal(t, int64(0), spool.bytes.Value())
+}
+
+func TestSpoolRecover(t *testing.T) {
+	path := t.TempDir()
+	input := make(chan *message.Payload, 1)
+	spool := newTestSpool(t, input, make(chan *message.Payload), path, 1024*1024)
+	spool.Start()
+	for i := 0; i < 3; i++ {
+		input <- newSpoolTestPayload(i)
+	}
+	require.Eventually(t, func() bool { return spoolDepth(spool) == 3 }, time.Second, time.Millisecond)
+	spool.Stop()
+
+	output := make(chan *message.Payload)
+	spool = newTestSpool(t, make(chan *message.Payload), output, path, 1024*1024)
+	assert.Equal(t, 3, spoolDepth(spool))
+	spool.Start()
+	defer spool.Stop()
+
+	for i := 0; i < 3; i++ {
+		payload := <-output
+		assert.Equal(t, fmt.Sprintf("payload-%d", i), string(payload.Encoded))
+		assert.Equal(t, "gzip", payload.Encoding)
+		assert.Equal(t, 42, payload.UnencodedSize)
+		require.Len(t, payload.Messages, 1)
+
+		// The destinations get the content and the metadata of the messages
+		msg := payload.Messages[0]
+		assert.Equal(t, "log", string(msg.GetContent()))
+		assert.Equal(t, "web-1", msg.Hostname)
+		assert.Equal(t, message.StatusWarning, msg.GetStatus())
+		assert.Equal(t, int64(i), msg.IngestionTimestamp)
+		assert.Equal(t, "file:/var/log/app.log", msg.Origin.Identifier)
+		assert.Equal(t, fmt.Sprintf("%d", i), msg.Origin.Offset)
+		assert.Equal(t, "end", msg.Origin.LogSource.Config.TailingMode)
+		assert.Equal(t, "payments", msg.Origin.Service())
+		assert.Equal(t, "python", msg.Origin.Source())
+		assert.Contains(t, msg.Tags(), "env:prod")
+	}
+	require.Eventually(t, func() bool { return spoolDepth(spool) == 0 }, time.Second, time.Millisecond)
+
+	entries, err := os.ReadDir(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSpoolWaitsForSender(t *testing.T) {
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload)
+	spool, err := NewSpool(input, output, t.TempDir(), 1024*1024, 0)
+	require.NoError(t, err)
+	spool.spillTimeout = time.Minute
+	spool.Start()
+	defer spool.Stop()
+
+	// The sender is busy for a moment, the payload is not stored
+	payload := newSpoolTestPayload(0)
+	input <- payload
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 0, spoolDepth(spool))
+	assert.Same(t, payload, <-output)
+	assert.Equal(t, 0, spoolDepth(spool))
+}
+
+func TestSpoolFull(t *testing.T) {
+	input := make(chan *message.Payload)
+	output := make(chan *message.Payload)
+	// Room for two payloads
+	var maxSize int64
+	for i := 0; i < 2; i++ {
+		data, err := encodeSpoolRecord(newSpoolTestPayload(i))
+		require.NoError(t, err)
+		maxSize += int64(len(data))
+	}
+	spool := newTestSpool(t, input, output, t.TempDir(), maxSize)
+	spool.Start()
+	defer spool.Stop()
+
+	input <- newSpoolTestPayload(0)
+	input <- newSpoolTestPayload(1)
+	require.Eventually(t, func() bool { return spoolDepth(spool) == 2 }, time.Second, time.Millisecond)
+
+	// The spool is full, the input is blocked until a payload is replayed
+	sent := make(chan struct{})
+	go func() {
+		input <- newSpoolTestPayload(2)
+		input <- newSpoolTestPayload(3)
+		close(sent)
+	}()
+	select {
+	case <-sent:
+		t.Fatal("the spool should apply backpressure when it is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, fmt.Sprintf("payload-%d", i), string((<-output).Encoded))
+	}
+	<-sent
+}
+
+func TestSpoolReplayRouted(t *testing.T) {
+	payments := newRoutingTestMessage(`{"message":"a"}`, "payments")
+	checkout := newRoutingTestMessage(`{"message":"b"}`, "checkout")
+	data, err := encodeSpoolRecord(&message.Payload{Messages: []*message.Message{checkout, payments}, Encoded: []byte(`[{"message":"b"},{"message":"a"}]`)})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "0"+spoolFileExtension)
+	require.NoError(t, writeSpoolFile(path, data))
+	replayed, err := readSpoolFile(path)
+	require.NoError(t, err)
+
+	// The routed destinations serialize again the content of the replayed messages
+	routed := NewRoutedDestination(&mockDestination{}, []string{"service:payments"}, ArraySerializer, IdentityContentType).(*routedDestination)
+	payload := routed.route(replayed)
+	require.NotNil(t, payload)
+	assert.Equal(t, `[{"message":"a"}]`, string(payload.Encoded))
+}
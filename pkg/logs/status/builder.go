@@ -209,6 +209,21 @@ func (b *Builder) getMetricsStatus() map[string]string {
 	metrics["RetryCount"] = fmt.Sprintf("%v", b.logsExpVars.Get("RetryCount").(*expvar.Int).Value())
 	metrics["RetryTimeSpent"] = time.Duration(b.logsExpVars.Get("RetryTimeSpent").(*expvar.Int).Value()).String()
 	metrics["EncodedBytesSent"] = fmt.Sprintf("%v", b.logsExpVars.Get("EncodedBytesSent").(*expvar.Int).Value())
+	if spool, ok := b.logsExpVars.Get("Spool").(*expvar.Map); ok {
+		// The disk spool is only reported when it is enabled
+		enabled := false
+		var payloads, bytes int64
+		spool.Do(func(kv expvar.KeyValue) {
+			enabled = true
+			pipeline := kv.Value.(*expvar.Map)
+			payloads += pipeline.Get("Payloads").(*expvar.Int).Value()
+			bytes += pipeline.Get("Bytes").(*expvar.Int).Value()
+		})
+		if enabled {
+			metrics["SpoolPayloads"] = fmt.Sprintf("%v", payloads)
+			metrics["SpoolBytes"] = fmt.Sprintf("%v", bytes)
+		}
+	}
 	return metrics
 }
 
@@ -6,8 +6,10 @@
 package status
 
 import (
+	"expvar"
 	"fmt"
 	"math"
+	"strconv"
 	"testing"
 	"time"
 
@@ -96,13 +98,13 @@ func TestStatusDeduplicateErrorsAndWarnings(t *testing.T) {
 func TestMetrics(t *testing.T) {
 	defer Clear()
 	Clear()
-	var expected = `{"BytesMissed": 0, "BytesSent": 0, "DestinationErrors": 0, "DestinationLogsDropped": {}, "EncodedBytesSent": 0, "Errors": "", "HttpDestinationStats": {}, "IsRunning": false, "LogsDecoded": 0, "LogsProcessed": 0, "LogsSent": 0, "RetryCount": 0, "RetryTimeSpent": 0, "SenderLatency": 0, "Warnings": ""}`
+	var expected = `{"BytesMissed": 0, "BytesSent": 0, "DestinationErrors": 0, "DestinationLogsDropped": {}, "EncodedBytesSent": 0, "Errors": "", "HttpDestinationStats": {}, "IsRunning": false, "LogsDecoded": 0, "LogsProcessed": 0, "LogsSent": 0, "RetryCount": 0, "RetryTimeSpent": 0, "SenderLatency": 0, "Spool": {}, "Warnings": ""}`
 	assert.Equal(t, expected, metrics.LogsExpvars.String())
 
 	initStatus()
 	AddGlobalWarning("bar", "Unique Warning")
 	AddGlobalError("bar", "I am an error")
-	expected = `{"BytesMissed": 0, "BytesSent": 0, "DestinationErrors": 0, "DestinationLogsDropped": {}, "EncodedBytesSent": 0, "Errors": "I am an error", "HttpDestinationStats": {}, "IsRunning": true, "LogsDecoded": 0, "LogsProcessed": 0, "LogsSent": 0, "RetryCount": 0, "RetryTimeSpent": 0, "SenderLatency": 0, "Warnings": "Unique Warning"}`
+	expected = `{"BytesMissed": 0, "BytesSent": 0, "DestinationErrors": 0, "DestinationLogsDropped": {}, "EncodedBytesSent": 0, "Errors": "I am an error", "HttpDestinationStats": {}, "IsRunning": true, "LogsDecoded": 0, "LogsProcessed": 0, "LogsSent": 0, "RetryCount": 0, "RetryTimeSpent": 0, "SenderLatency": 0, "Spool": {}, "Warnings": "Unique Warning"}`
 	assert.Equal(t, expected, metrics.LogsExpvars.String())
 }
 
@@ -139,6 +141,30 @@ func TestStatusMetrics(t *testing.T) {
 	assert.Equal(t, fmt.Sprintf("%v", math.MinInt64), status.StatusMetrics["LogsProcessed"])
 }
 
+func TestStatusMetricsSpool(t *testing.T) {
+	defer Clear()
+	initStatus()
+
+	status := Get(false)
+	assert.NotContains(t, status.StatusMetrics, "SpoolPayloads")
+	assert.NotContains(t, status.StatusMetrics, "SpoolBytes")
+
+	for i, depth := range []int64{2, 3} {
+		pipeline := &expvar.Map{}
+		payloads, bytes := &expvar.Int{}, &expvar.Int{}
+		payloads.Set(depth)
+		bytes.Set(depth * 100)
+		pipeline.Set("Payloads", payloads)
+		pipeline.Set("Bytes", bytes)
+		metrics.SpoolExpVars.Set(strconv.Itoa(i), pipeline)
+		defer metrics.SpoolExpVars.Delete(strconv.Itoa(i))
+	}
+
+	status = Get(false)
+	assert.Equal(t, "5", status.StatusMetrics["SpoolPayloads"])
+	assert.Equal(t, "500", status.StatusMetrics["SpoolBytes"])
+}
+
 func TestStatusEndpoints(t *testing.T) {
 	defer Clear()
 	initStatus()
@@ -73,6 +73,10 @@ type Config struct {
 	// These stats objects get flushed on every client request (default 30s check interval)
 	MaxDNSStats int
 
+	// DNSFailingDomainsTopN is the number of domains with the most NXDOMAIN/SERVFAIL responses
+	// reported along with the per-resolver DNS stats. Zero disables the report.
+	DNSFailingDomainsTopN int
+
 	// EnableHTTPMonitoring specifies whether the tracer should monitor HTTP traffic
 	EnableHTTPMonitoring bool
 
@@ -88,6 +92,12 @@ type Config struct {
 	// EnableRedisMonitoring specifies whether the tracer should monitor Redis traffic.
 	EnableRedisMonitoring bool
 
+	// EnableMemcachedMonitoring specifies whether the tracer should monitor Memcached traffic.
+	EnableMemcachedMonitoring bool
+
+	// EnableCassandraMonitoring specifies whether the tracer should monitor Cassandra (CQL) traffic.
+	EnableCassandraMonitoring bool
+
 	// EnableNativeTLSMonitoring specifies whether the USM should monitor HTTPS traffic via native libraries.
 	// Supported libraries: OpenSSL, GnuTLS, LibCrypto.
 	EnableNativeTLSMonitoring bool
@@ -101,6 +111,14 @@ type Config struct {
 	// EnableNodeJSMonitoring specifies whether USM should monitor NodeJS TLS traffic
 	EnableNodeJSMonitoring bool
 
+	// EnableBoringSSLMonitoring specifies whether USM should monitor TLS traffic of
+	// executables statically linked against BoringSSL (e.g. Envoy based gateways)
+	EnableBoringSSLMonitoring bool
+
+	// EnableRustlsMonitoring specifies whether USM should monitor TLS traffic of
+	// Rust executables using rustls
+	EnableRustlsMonitoring bool
+
 	// EnableGoTLSSupport specifies whether the tracer should monitor HTTPS
 	// traffic done through Go's standard library's TLS implementation
 	EnableGoTLSSupport bool
@@ -179,6 +197,14 @@ type Config struct {
 	// get flushed on every client request (default 30s check interval)
 	MaxRedisStatsBuffered int
 
+	// MaxMemcachedStatsBuffered represents the maximum number of Memcached stats we'll buffer in memory. These stats
+	// get flushed on every client request (default 30s check interval)
+	MaxMemcachedStatsBuffered int
+
+	// MaxCassandraStatsBuffered represents the maximum number of Cassandra stats we'll buffer in memory. These stats
+	// get flushed on every client request (default 30s check interval)
+	MaxCassandraStatsBuffered int
+
 	// MaxConnectionsStateBuffered represents the maximum number of state objects that we'll store in memory. These state objects store
 	// the stats for a connection so we can accurately determine traffic change between client requests.
 	MaxConnectionsStateBuffered int
@@ -222,6 +248,15 @@ type Config struct {
 	// ExcludedDestinationConnections is a map of destination connections to blacklist
 	ExcludedDestinationConnections map[string][]string
 
+	// ExcludedProcessCmdlines is a list of regexes matching the command lines of the processes whose connections are excluded
+	ExcludedProcessCmdlines []string
+
+	// ExcludedContainerImages is a list of container images whose connections are excluded
+	ExcludedContainerImages []string
+
+	// ExcludedKubeNamespaces is a list of Kubernetes namespaces whose connections are excluded
+	ExcludedKubeNamespaces []string
+
 	// OffsetGuessThreshold is the size of the byte threshold we will iterate over when guessing offsets
 	OffsetGuessThreshold uint64
 
@@ -319,6 +354,9 @@ func New() *Config {
 		OffsetGuessThreshold:           uint64(cfg.GetInt64(join(spNS, "offset_guess_threshold"))),
 		ExcludedSourceConnections:      cfg.GetStringMapStringSlice(join(spNS, "source_excludes")),
 		ExcludedDestinationConnections: cfg.GetStringMapStringSlice(join(spNS, "dest_excludes")),
+		ExcludedProcessCmdlines:        cfg.GetStringSlice(join(spNS, "process_excludes", "cmdlines")),
+		ExcludedContainerImages:        cfg.GetStringSlice(join(spNS, "process_excludes", "container_images")),
+		ExcludedKubeNamespaces:         cfg.GetStringSlice(join(spNS, "process_excludes", "kube_namespaces")),
 
 		TCPFailedConnectionsEnabled:    cfg.GetBool(join(netNS, "enable_tcp_failed_connections")),
 		MaxTrackedConnections:          uint32(cfg.GetInt64(join(spNS, "max_tracked_connections"))),
@@ -329,13 +367,14 @@ func New() *Config {
 		MaxConnectionsStateBuffered:    cfg.GetInt(join(spNS, "max_connection_state_buffered")),
 		ClientStateExpiry:              2 * time.Minute,
 
-		DNSInspection:       !cfg.GetBool(join(spNS, "disable_dns_inspection")),
-		CollectDNSStats:     cfg.GetBool(join(spNS, "collect_dns_stats")),
-		CollectLocalDNS:     cfg.GetBool(join(spNS, "collect_local_dns")),
-		CollectDNSDomains:   cfg.GetBool(join(spNS, "collect_dns_domains")),
-		MaxDNSStats:         cfg.GetInt(join(spNS, "max_dns_stats")),
-		MaxDNSStatsBuffered: 75000,
-		DNSTimeout:          time.Duration(cfg.GetInt(join(spNS, "dns_timeout_in_s"))) * time.Second,
+		DNSInspection:         !cfg.GetBool(join(spNS, "disable_dns_inspection")),
+		CollectDNSStats:       cfg.GetBool(join(spNS, "collect_dns_stats")),
+		CollectLocalDNS:       cfg.GetBool(join(spNS, "collect_local_dns")),
+		CollectDNSDomains:     cfg.GetBool(join(spNS, "collect_dns_domains")),
+		MaxDNSStats:           cfg.GetInt(join(spNS, "max_dns_stats")),
+		MaxDNSStatsBuffered:   75000,
+		DNSTimeout:            time.Duration(cfg.GetInt(join(spNS, "dns_timeout_in_s"))) * time.Second,
+		DNSFailingDomainsTopN: cfg.GetInt(join(netNS, "dns_failing_domains_top_n")),
 
 		ProtocolClassificationEnabled: cfg.GetBool(join(netNS, "enable_protocol_classification")),
 
@@ -346,16 +385,22 @@ func New() *Config {
 		EnableKafkaMonitoring:      cfg.GetBool(join(smNS, "enable_kafka_monitoring")),
 		EnablePostgresMonitoring:   cfg.GetBool(join(smNS, "enable_postgres_monitoring")),
 		EnableRedisMonitoring:      cfg.GetBool(join(smNS, "enable_redis_monitoring")),
+		EnableMemcachedMonitoring:  cfg.GetBool(join(smNS, "enable_memcached_monitoring")),
+		EnableCassandraMonitoring:  cfg.GetBool(join(smNS, "enable_cassandra_monitoring")),
 		EnableNativeTLSMonitoring:  cfg.GetBool(join(smNS, "tls", "native", "enabled")),
 		EnableIstioMonitoring:      cfg.GetBool(join(smNS, "tls", "istio", "enabled")),
 		EnvoyPath:                  cfg.GetString(join(smNS, "tls", "istio", "envoy_path")),
 		EnableNodeJSMonitoring:     cfg.GetBool(join(smNS, "tls", "nodejs", "enabled")),
+		EnableBoringSSLMonitoring:  cfg.GetBool(join(smNS, "tls", "boringssl", "enabled")),
+		EnableRustlsMonitoring:     cfg.GetBool(join(smNS, "tls", "rustls", "enabled")),
 		MaxUSMConcurrentRequests:   uint32(cfg.GetInt(join(smNS, "max_concurrent_requests"))),
 		MaxHTTPStatsBuffered:       cfg.GetInt(join(smNS, "max_http_stats_buffered")),
 		MaxKafkaStatsBuffered:      cfg.GetInt(join(smNS, "max_kafka_stats_buffered")),
 		MaxPostgresStatsBuffered:   cfg.GetInt(join(smNS, "max_postgres_stats_buffered")),
 		MaxPostgresTelemetryBuffer: cfg.GetInt(join(smNS, "max_postgres_telemetry_buffer")),
 		MaxRedisStatsBuffered:      cfg.GetInt(join(smNS, "max_redis_stats_buffered")),
+		MaxMemcachedStatsBuffered:  cfg.GetInt(join(smNS, "max_memcached_stats_buffered")),
+		MaxCassandraStatsBuffered:  cfg.GetInt(join(smNS, "max_cassandra_stats_buffered")),
 
 		MaxTrackedHTTPConnections: cfg.GetInt64(join(smNS, "max_tracked_http_connections")),
 		HTTPNotificationThreshold: cfg.GetInt64(join(smNS, "http_notification_threshold")),
@@ -1359,6 +1359,81 @@ func TestNodeJSMonitoring(t *testing.T) {
 	})
 }
 
+func TestBoringSSLMonitoring(t *testing.T) {
+	t.Run("default value", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.False(t, cfg.EnableBoringSSLMonitoring)
+	})
+
+	t.Run("via yaml", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.tls.boringssl.enabled", true)
+		cfg := New()
+
+		assert.True(t, cfg.EnableBoringSSLMonitoring)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_TLS_BORINGSSL_ENABLED", "true")
+		cfg := New()
+
+		assert.True(t, cfg.EnableBoringSSLMonitoring)
+	})
+}
+
+func TestRustlsMonitoring(t *testing.T) {
+	t.Run("default value", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.False(t, cfg.EnableRustlsMonitoring)
+	})
+
+	t.Run("via yaml", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("service_monitoring_config.tls.rustls.enabled", true)
+		cfg := New()
+
+		assert.True(t, cfg.EnableRustlsMonitoring)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_SERVICE_MONITORING_CONFIG_TLS_RUSTLS_ENABLED", "true")
+		cfg := New()
+
+		assert.True(t, cfg.EnableRustlsMonitoring)
+	})
+}
+
+func TestDNSFailingDomainsTopN(t *testing.T) {
+	t.Run("default value", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		cfg := New()
+
+		assert.Equal(t, 0, cfg.DNSFailingDomainsTopN)
+	})
+
+	t.Run("via yaml", func(t *testing.T) {
+		mockSystemProbe := mock.NewSystemProbe(t)
+		mockSystemProbe.SetWithoutSource("network_config.dns_failing_domains_top_n", 20)
+		cfg := New()
+
+		assert.Equal(t, 20, cfg.DNSFailingDomainsTopN)
+	})
+
+	t.Run("via ENV variable", func(t *testing.T) {
+		mock.NewSystemProbe(t)
+		t.Setenv("DD_NETWORK_CONFIG_DNS_FAILING_DOMAINS_TOP_N", "20")
+		cfg := New()
+
+		assert.Equal(t, 20, cfg.DNSFailingDomainsTopN)
+	})
+}
+
 func TestUSMEventStream(t *testing.T) {
 	t.Run("default value", func(t *testing.T) {
 		mock.NewSystemProbe(t)
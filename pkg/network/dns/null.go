@@ -30,6 +30,10 @@ func (nullReverseDNS) GetDNSStats() StatsByKeyByNameByType {
 	return nil
 }
 
+func (nullReverseDNS) GetDNSResolverStats() ResolverReport {
+	return ResolverReport{}
+}
+
 func (nullReverseDNS) Start() error {
 	return nil
 }
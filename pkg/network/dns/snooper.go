@@ -66,7 +66,7 @@ func newSocketFilterSnooper(cfg *config.Config, source filter.PacketSource) (*so
 	cache := newReverseDNSCache(dnsCacheSize, dnsCacheExpirationPeriod)
 	var statKeeper *dnsStatKeeper
 	if cfg.CollectDNSStats {
-		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, int64(cfg.MaxDNSStats))
+		statKeeper = newDNSStatkeeper(cfg.DNSTimeout, int64(cfg.MaxDNSStats), cfg.DNSFailingDomainsTopN)
 		log.Infof("DNS Stats Collection has been enabled. Maximum number of stats objects: %d", cfg.MaxDNSStats)
 		if cfg.CollectDNSDomains {
 			log.Infof("DNS domain collection has been enabled")
@@ -113,6 +113,14 @@ func (s *socketFilterSnooper) GetDNSStats() StatsByKeyByNameByType {
 	return s.statKeeper.GetAndResetAllStats()
 }
 
+// GetDNSResolverStats gets the latest per-resolver stats and the top failing domains
+func (s *socketFilterSnooper) GetDNSResolverStats() ResolverReport {
+	if s.statKeeper == nil {
+		return ResolverReport{}
+	}
+	return s.statKeeper.GetAndResetResolverStats()
+}
+
 // Start starts the snooper (no-op currently)
 func (s *socketFilterSnooper) Start() error {
 	return nil // no-op as this is done in newSocketFilterSnooper above
@@ -9,9 +9,13 @@ package dns
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/DataDog/sketches-go/ddsketch"
+
+	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
@@ -34,6 +38,16 @@ const (
 
 	// See WaitForDomain
 	waitForDomainTimeout = 5 * time.Second
+
+	// maxResolverStats limits the number of resolvers tracked between two reports
+	maxResolverStats = 1024
+	// maxFailingDomains limits the number of failing domains tracked between two reports
+	maxFailingDomains = 10000
+	// latencyRelativeAccuracy defines the acceptable error in quantile values calculated by DDSketch
+	latencyRelativeAccuracy = 0.01
+
+	rcodeServFail = 2
+	rcodeNXDomain = 3
 )
 
 var statsTelemetry = struct {
@@ -76,16 +90,26 @@ type dnsStatKeeper struct {
 	processedStats   int64
 	droppedStats     int64
 	maxStats         int64
+
+	// resolvers aggregates the stats by resolver, regardless of the domain
+	resolvers StatsByResolver
+	// failingDomains counts NXDOMAIN and SERVFAIL responses by domain. It is
+	// only populated when failingDomainsTopN is positive.
+	failingDomains     map[Hostname]*FailingDomain
+	failingDomainsTopN int
 }
 
-func newDNSStatkeeper(timeout time.Duration, maxStats int64) *dnsStatKeeper {
+func newDNSStatkeeper(timeout time.Duration, maxStats int64, failingDomainsTopN int) *dnsStatKeeper {
 	statsKeeper := &dnsStatKeeper{
-		stats:            make(StatsByKeyByNameByType),
-		state:            make(map[stateKey]stateValue),
-		expirationPeriod: timeout,
-		exit:             make(chan struct{}),
-		maxSize:          maxStateMapSize,
-		maxStats:         maxStats,
+		stats:              make(StatsByKeyByNameByType),
+		state:              make(map[stateKey]stateValue),
+		expirationPeriod:   timeout,
+		exit:               make(chan struct{}),
+		maxSize:            maxStateMapSize,
+		maxStats:           maxStats,
+		resolvers:          make(StatsByResolver),
+		failingDomains:     make(map[Hostname]*FailingDomain),
+		failingDomainsTopN: failingDomainsTopN,
 	}
 
 	ticker := time.NewTicker(statsKeeper.expirationPeriod)
@@ -134,6 +158,14 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 	d.deleteCount++
 
 	latency := microSecs(ts) - start.ts
+	timedOut := latency > uint64(d.expirationPeriod.Microseconds())
+
+	if timedOut {
+		d.recordResolverTimeout(info.key.ServerIP)
+	} else {
+		d.recordResolverResponse(info.key.ServerIP, info.pktType, info.rCode, latency)
+		d.recordFailingDomain(start.question, info.rCode)
+	}
 
 	allStats, ok := d.stats[info.key]
 	if !ok {
@@ -160,7 +192,7 @@ func (d *dnsStatKeeper) ProcessPacketInfo(info dnsPacketInfo, ts time.Time) {
 		statsTelemetry.processedStats.Inc()
 	}
 
-	if latency > uint64(d.expirationPeriod.Microseconds()) {
+	if timedOut {
 		byqtype.Timeouts++
 	} else {
 		byqtype.CountByRcode[uint32(info.rCode)]++
@@ -186,6 +218,111 @@ func (d *dnsStatKeeper) GetAndResetAllStats() StatsByKeyByNameByType {
 	return ret
 }
 
+// GetAndResetResolverStats returns the stats aggregated by resolver and the top
+// failing domains since the last call.
+func (d *dnsStatKeeper) GetAndResetResolverStats() ResolverReport {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	report := ResolverReport{
+		Resolvers:      d.resolvers,
+		FailingDomains: topFailingDomains(d.failingDomains, d.failingDomainsTopN),
+	}
+	d.resolvers = make(StatsByResolver)
+	d.failingDomains = make(map[Hostname]*FailingDomain)
+	return report
+}
+
+// getResolverStats returns the stats of the given resolver, or nil if the
+// maximum number of tracked resolvers is reached.
+// Must be called with d.mux held.
+func (d *dnsStatKeeper) getResolverStats(server util.Address) *ResolverStats {
+	stats, ok := d.resolvers[server]
+	if ok {
+		return stats
+	}
+	if len(d.resolvers) >= maxResolverStats {
+		return nil
+	}
+	stats = &ResolverStats{CountByRcode: make(map[uint32]uint32)}
+	d.resolvers[server] = stats
+	return stats
+}
+
+// Must be called with d.mux held.
+func (d *dnsStatKeeper) recordResolverResponse(server util.Address, pktType packetType, rCode uint8, latency uint64) {
+	stats := d.getResolverStats(server)
+	if stats == nil {
+		return
+	}
+
+	stats.CountByRcode[uint32(rCode)]++
+	sketch := &stats.SuccessLatencies
+	if pktType == failedResponse {
+		sketch = &stats.FailureLatencies
+	}
+	if *sketch == nil {
+		var err error
+		if *sketch, err = ddsketch.NewDefaultDDSketch(latencyRelativeAccuracy); err != nil {
+			log.Debugf("could not create DNS latency sketch: %s", err)
+			return
+		}
+	}
+	if err := (*sketch).Add(float64(latency)); err != nil {
+		log.Debugf("could not add DNS latency to sketch: %s", err)
+	}
+}
+
+// Must be called with d.mux held.
+func (d *dnsStatKeeper) recordResolverTimeout(server util.Address) {
+	if stats := d.getResolverStats(server); stats != nil {
+		stats.Timeouts++
+	}
+}
+
+// Must be called with d.mux held.
+func (d *dnsStatKeeper) recordFailingDomain(domain Hostname, rCode uint8) {
+	if d.failingDomainsTopN <= 0 || (rCode != rcodeNXDomain && rCode != rcodeServFail) {
+		return
+	}
+
+	failing, ok := d.failingDomains[domain]
+	if !ok {
+		if len(d.failingDomains) >= maxFailingDomains {
+			return
+		}
+		failing = &FailingDomain{Domain: domain}
+		d.failingDomains[domain] = failing
+	}
+	if rCode == rcodeNXDomain {
+		failing.NXDomain++
+	} else {
+		failing.ServFail++
+	}
+}
+
+// topFailingDomains returns the n domains with the most failed responses
+func topFailingDomains(failingDomains map[Hostname]*FailingDomain, n int) []FailingDomain {
+	if n <= 0 || len(failingDomains) == 0 {
+		return nil
+	}
+
+	all := make([]FailingDomain, 0, len(failingDomains))
+	for _, failing := range failingDomains {
+		all = append(all, *failing)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		ti, tj := all[i].NXDomain+all[i].ServFail, all[j].NXDomain+all[j].ServFail
+		if ti != tj {
+			return ti > tj
+		}
+		return ToString(all[i].Domain) < ToString(all[j].Domain)
+	})
+	if len(all) > n {
+		all = all[:n]
+	}
+	return all
+}
+
 func (d *dnsStatKeeper) WaitForDomain(domain string) error {
 
 	tick := time.NewTicker(10 * time.Millisecond)
@@ -252,6 +389,7 @@ func (d *dnsStatKeeper) removeExpiredStates(earliestTs time.Time) {
 		if v.ts < threshold {
 			delete(d.state, k)
 			d.deleteCount++
+			d.recordResolverTimeout(k.key.ServerIP)
 			// When we expire a state, we need to increment timeout count for that key:domain
 			allStats, ok := d.stats[k.key]
 			if !ok {
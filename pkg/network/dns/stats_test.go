@@ -42,7 +42,7 @@ func testLatency(
 	expectedTimeouts uint32,
 ) {
 	var d = ToHostname("abc.com")
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, 0)
 	key := getSampleDNSKey()
 	qPkt := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
 	then := time.Now()
@@ -79,7 +79,7 @@ func TestTimeout(t *testing.T) {
 }
 
 func TestExpiredStateRemoval(t *testing.T) {
-	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000)
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, 0)
 	key := getSampleDNSKey()
 	var d = ToHostname("abc.com")
 	qPkt1 := dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}
@@ -106,6 +106,77 @@ func TestExpiredStateRemoval(t *testing.T) {
 	assert.Equal(t, uint32(1), stats[key][d][TypeA].Timeouts)
 }
 
+func TestResolverStats(t *testing.T) {
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, 0)
+	key := getSampleDNSKey()
+	var d = ToHostname("abc.com")
+	then := time.Now()
+
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 1, pktType: successfulResponse, key: key, queryType: TypeA}, then.Add(100*time.Microsecond))
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 2, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 2, pktType: failedResponse, rCode: rcodeServFail, key: key, queryType: TypeA}, then.Add(time.Millisecond))
+	sk.ProcessPacketInfo(dnsPacketInfo{transactionID: 3, pktType: query, key: key, question: d, queryType: TypeA}, then)
+	sk.removeExpiredStates(then.Add(DNSTimeoutSecs * time.Second))
+
+	report := sk.GetAndResetResolverStats()
+	require.Contains(t, report.Resolvers, key.ServerIP)
+	assert.Nil(t, report.FailingDomains)
+
+	stats := report.Resolvers[key.ServerIP]
+	assert.Equal(t, uint32(1), stats.Timeouts)
+	assert.Equal(t, map[uint32]uint32{0: 1, rcodeServFail: 1}, stats.CountByRcode)
+	require.NotNil(t, stats.SuccessLatencies)
+	require.NotNil(t, stats.FailureLatencies)
+	assert.Equal(t, float64(1), stats.SuccessLatencies.GetCount())
+	successLatency, err := stats.SuccessLatencies.GetMaxValue()
+	require.NoError(t, err)
+	assert.InEpsilon(t, 100, successLatency, latencyRelativeAccuracy)
+	failureLatency, err := stats.FailureLatencies.GetMaxValue()
+	require.NoError(t, err)
+	assert.InEpsilon(t, 1000, failureLatency, latencyRelativeAccuracy)
+
+	assert.Empty(t, sk.GetAndResetResolverStats().Resolvers)
+}
+
+func TestTopFailingDomains(t *testing.T) {
+	sk := newDNSStatkeeper(DNSTimeoutSecs*time.Second, 10000, 2)
+	key := getSampleDNSKey()
+	now := time.Now()
+
+	failures := []struct {
+		domain string
+		rCode  uint8
+	}{
+		{"a.com", rcodeNXDomain},
+		{"b.com", rcodeNXDomain},
+		{"b.com", rcodeServFail},
+		{"c.com", rcodeServFail},
+		{"c.com", rcodeServFail},
+		{"c.com", rcodeServFail},
+		{"d.com", 0},
+		{"d.com", 0},
+		{"d.com", 0},
+		{"d.com", 0},
+	}
+	for i, f := range failures {
+		pktType := failedResponse
+		if f.rCode == 0 {
+			pktType = successfulResponse
+		}
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: uint16(i), pktType: query, key: key, question: ToHostname(f.domain), queryType: TypeA}, now)
+		sk.ProcessPacketInfo(dnsPacketInfo{transactionID: uint16(i), pktType: pktType, rCode: f.rCode, key: key, queryType: TypeA}, now)
+	}
+
+	report := sk.GetAndResetResolverStats()
+	assert.Equal(t, []FailingDomain{
+		{Domain: ToHostname("c.com"), ServFail: 3},
+		{Domain: ToHostname("b.com"), NXDomain: 1, ServFail: 1},
+	}, report.FailingDomains)
+
+	assert.Nil(t, sk.GetAndResetResolverStats().FailingDomains)
+}
+
 func BenchmarkStats(b *testing.B) {
 	key := getSampleDNSKey()
 
@@ -123,7 +194,7 @@ func BenchmarkStats(b *testing.B) {
 			b.ResetTimer()
 			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				sk := newDNSStatkeeper(1000*time.Second, 10000)
+				sk := newDNSStatkeeper(1000*time.Second, 10000, 0)
 				for j := 0; j < numPackets; j++ {
 					sk.ProcessPacketInfo(packets[j], ts)
 				}
@@ -7,6 +7,8 @@
 package dns
 
 import (
+	"github.com/DataDog/sketches-go/ddsketch"
+
 	"github.com/DataDog/datadog-agent/pkg/process/util"
 	"github.com/DataDog/datadog-agent/pkg/util/intern"
 )
@@ -66,6 +68,7 @@ type StatsByKeyByNameByType map[Key]map[Hostname]map[QueryType]Stats
 type ReverseDNS interface {
 	Resolve(map[util.Address]struct{}) map[util.Address][]Hostname
 	GetDNSStats() StatsByKeyByNameByType
+	GetDNSResolverStats() ResolverReport
 
 	// WaitForDomain is used in tests to ensure a domain has been
 	// seen by the ReverseDNS.
@@ -91,3 +94,30 @@ type Stats struct {
 	FailureLatencySum uint64
 	CountByRcode      map[uint32]uint32
 }
+
+// ResolverStats holds statistics corresponding to a particular DNS resolver.
+// Latencies are expressed in microseconds.
+type ResolverStats struct {
+	Timeouts         uint32
+	CountByRcode     map[uint32]uint32
+	SuccessLatencies *ddsketch.DDSketch
+	FailureLatencies *ddsketch.DDSketch
+}
+
+// StatsByResolver provides a type name for the map of DNS stats
+// based on the resolver (server) address
+type StatsByResolver map[util.Address]*ResolverStats
+
+// FailingDomain holds the number of failed responses received for a domain
+type FailingDomain struct {
+	Domain   Hostname
+	NXDomain uint32
+	ServFail uint32
+}
+
+// ResolverReport holds the per-resolver DNS statistics along with the domains
+// that failed the most since the last report
+type ResolverReport struct {
+	Resolvers      StatsByResolver
+	FailingDomains []FailingDomain
+}
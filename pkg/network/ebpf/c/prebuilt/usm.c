@@ -7,16 +7,19 @@
 #include "offsets.h"
 
 #include "protocols/classification/dispatcher-helpers.h"
+#include "protocols/cassandra/decoding.h"
 #include "protocols/http/buffer.h"
 #include "protocols/http/http.h"
 #include "protocols/http2/decoding.h"
 #include "protocols/http2/decoding-tls.h"
 #include "protocols/kafka/kafka-parsing.h"
+#include "protocols/memcached/decoding.h"
 #include "protocols/postgres/decoding.h"
 #include "protocols/redis/decoding.h"
 #include "protocols/sockfd-probes.h"
 #include "protocols/tls/https.h"
 #include "protocols/tls/native-tls.h"
+#include "protocols/tls/rustls.h"
 #include "protocols/tls/tags-types.h"
 
 SEC("socket/protocol_dispatcher")
@@ -60,6 +63,8 @@ int tracepoint__net__netif_receive_skb(void *ctx) {
     kafka_batch_flush(ctx);
     postgres_batch_flush(ctx);
     redis_batch_flush(ctx);
+    memcached_batch_flush(ctx);
+    cassandra_batch_flush(ctx);
     return 0;
 }
 
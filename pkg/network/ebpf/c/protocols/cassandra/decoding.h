@@ -0,0 +1,170 @@
+#ifndef __CASSANDRA_DECODING_H
+#define __CASSANDRA_DECODING_H
+
+#include "bpf_builtins.h"
+#include "bpf_telemetry.h"
+
+#include "protocols/sockfd.h"
+
+#include "protocols/cassandra/decoding-maps.h"
+#include "protocols/cassandra/defs.h"
+#include "protocols/cassandra/types.h"
+#include "protocols/cassandra/usm-events.h"
+#include "protocols/helpers/pktbuf.h"
+#include "protocols/read_into_buffer.h"
+
+PKTBUF_READ_INTO_BUFFER(cassandra_query, CASSANDRA_BUFFER_SIZE, BLK_SIZE)
+
+// Reads a frame header from the given context. Returns true if the header was read successfully, false otherwise.
+static __always_inline bool cassandra_read_header(pktbuf_t pkt, struct cassandra_header *header) {
+    u32 data_off = pktbuf_data_offset(pkt);
+    u32 data_end = pktbuf_data_end(pkt);
+    // Ensuring that the header is in the buffer.
+    if (data_off + sizeof(struct cassandra_header) > data_end) {
+        return false;
+    }
+    pktbuf_load_bytes(pkt, data_off, header, sizeof(struct cassandra_header));
+    // Converting the header to host byte order.
+    header->stream = bpf_ntohs(header->stream);
+    header->length = bpf_ntohl(header->length);
+    return true;
+}
+
+// Reads the big-endian 32 bits integer at the beginning of the frame body, which is the length of the query for
+// QUERY and PREPARE requests, the error code for ERROR responses and the result kind for RESULT responses.
+static __always_inline bool cassandra_read_body_int(pktbuf_t pkt, __u32 *value) {
+    u32 body_off = pktbuf_data_offset(pkt) + CASSANDRA_HEADER_LENGTH;
+    if (body_off + sizeof(__u32) > pktbuf_data_end(pkt)) {
+        return false;
+    }
+    pktbuf_load_bytes(pkt, body_off, value, sizeof(__u32));
+    *value = bpf_ntohl(*value);
+    return true;
+}
+
+static __always_inline void cassandra_build_key(cassandra_key_t *key, conn_tuple_t *tup, __u16 stream_id) {
+    bpf_memset(key, 0, sizeof(cassandra_key_t));
+    bpf_memcpy(&key->tup, tup, sizeof(conn_tuple_t));
+    key->stream_id = stream_id;
+}
+
+// Handles a new request by creating a new transaction and storing it in the map. The query is only read from
+// uncompressed QUERY and PREPARE requests, as it is the first field of their body.
+static __always_inline void cassandra_handle_request(pktbuf_t pkt, conn_tuple_t *tup, struct cassandra_header *header, __u8 tags) {
+    const __u32 zero = 0;
+    // We use the scratch buffer to build the transaction, as it doesn't fit in the stack.
+    cassandra_event_t *event = bpf_map_lookup_elem(&cassandra_scratch_buffer, &zero);
+    if (!event) {
+        return;
+    }
+    cassandra_transaction_t *transaction = &event->tx;
+    bpf_memset(transaction, 0, sizeof(cassandra_transaction_t));
+    transaction->request_started = bpf_ktime_get_ns();
+    transaction->request_opcode = header->opcode;
+    transaction->tags = tags;
+
+    bool has_query = header->opcode == CASSANDRA_OPCODE_QUERY || header->opcode == CASSANDRA_OPCODE_PREPARE;
+    __u32 query_len = 0;
+    if (has_query && !(header->flags & CASSANDRA_FLAG_COMPRESSION) && cassandra_read_body_int(pkt, &query_len)) {
+        transaction->original_query_size = query_len;
+        pktbuf_read_into_buffer_cassandra_query((char *)transaction->request_fragment, pkt, pktbuf_data_offset(pkt) + CASSANDRA_HEADER_LENGTH + sizeof(__u32));
+    }
+
+    cassandra_key_t key;
+    cassandra_build_key(&key, tup, header->stream);
+    bpf_map_update_elem(&cassandra_in_flight, &key, transaction, BPF_ANY);
+}
+
+// Handles a response by completing the in-flight transaction of its stream, enqueuing it and deleting it from the
+// in-flight map.
+static __always_inline void cassandra_handle_response(pktbuf_t pkt, conn_tuple_t *tup, struct cassandra_header *header, __u8 tags) {
+    cassandra_key_t key;
+    cassandra_build_key(&key, tup, header->stream);
+    cassandra_transaction_t *transaction = bpf_map_lookup_elem(&cassandra_in_flight, &key);
+    if (!transaction) {
+        return;
+    }
+
+    const __u32 zero = 0;
+    cassandra_event_t *event = bpf_map_lookup_elem(&cassandra_scratch_buffer, &zero);
+    if (!event) {
+        return;
+    }
+    bpf_memcpy(&event->tuple, tup, sizeof(conn_tuple_t));
+    bpf_memcpy(&event->tx, transaction, sizeof(cassandra_transaction_t));
+    bpf_map_delete_elem(&cassandra_in_flight, &key);
+
+    event->tx.response_last_seen = bpf_ktime_get_ns();
+    event->tx.response_opcode = header->opcode;
+    event->tx.tags |= tags;
+    bool has_code = header->opcode == CASSANDRA_OPCODE_ERROR || header->opcode == CASSANDRA_OPCODE_RESULT;
+    __u32 response_code = 0;
+    if (has_code && !(header->flags & CASSANDRA_FLAG_COMPRESSION) && cassandra_read_body_int(pkt, &response_code)) {
+        event->tx.response_code = response_code;
+    }
+    cassandra_batch_enqueue(event);
+}
+
+// Main processing logic for the Cassandra protocol. Only the first frame of the packet is processed, frames spanning
+// multiple packets or following another frame in the same packet are ignored.
+static __always_inline void cassandra_process(pktbuf_t pkt, conn_tuple_t *tup, __u8 tags) {
+    struct cassandra_header header;
+    if (!cassandra_read_header(pkt, &header)) {
+        return;
+    }
+
+    __u8 version = header.version & CASSANDRA_VERSION_MASK;
+    if (version < CASSANDRA_MIN_VERSION || version > CASSANDRA_MAX_VERSION) {
+        return;
+    }
+
+    if (header.version & CASSANDRA_DIRECTION_MASK) {
+        cassandra_handle_response(pkt, tup, &header, tags);
+    } else {
+        cassandra_handle_request(pkt, tup, &header, tags);
+    }
+}
+
+// Entrypoint to process plaintext Cassandra traffic. Pulls the connection tuple and the packet buffer from the map and
+// calls the main processing function. In-flight transactions of terminated connections are removed by the map
+// cleaner, as they can't be looked up by connection.
+SEC("socket/cassandra_process")
+int socket__cassandra_process(struct __sk_buff *skb) {
+    skb_info_t skb_info = {};
+    conn_tuple_t conn_tuple = {};
+
+    if (!fetch_dispatching_arguments(&conn_tuple, &skb_info)) {
+        return 0;
+    }
+
+    if (is_tcp_termination(&skb_info)) {
+        return 0;
+    }
+
+    normalize_tuple(&conn_tuple);
+
+    pktbuf_t pkt = pktbuf_from_skb(skb, &skb_info);
+    cassandra_process(pkt, &conn_tuple, NO_TAGS);
+    return 0;
+}
+
+// Entrypoint to process TLS Cassandra traffic. Pulls the connection tuple and the packet buffer from the map and calls
+// the main processing function.
+SEC("uprobe/cassandra_tls_process")
+int uprobe__cassandra_tls_process(struct pt_regs *ctx) {
+    const __u32 zero = 0;
+
+    tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+    if (args == NULL) {
+        return 0;
+    }
+
+    // Copying the tuple to the stack to handle verifier issues on kernel 4.14.
+    conn_tuple_t tup = args->tup;
+
+    pktbuf_t pkt = pktbuf_from_tls(ctx, args);
+    cassandra_process(pkt, &tup, (__u8)args->tags);
+    return 0;
+}
+
+#endif /* __CASSANDRA_DECODING_H */
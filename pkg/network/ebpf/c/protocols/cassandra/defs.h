@@ -0,0 +1,48 @@
+#ifndef __CASSANDRA_DEFS_H
+#define __CASSANDRA_DEFS_H
+
+// CQL native protocol, as described in https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec
+// Every frame starts with a 9 bytes header (protocol versions 3 and above).
+#define CASSANDRA_HEADER_LENGTH 9
+
+// Supported protocol versions. Responses have the direction bit set in the version byte. Version 5 wraps the frames
+// in segments once the connection is ready, which we don't parse.
+#define CASSANDRA_MIN_VERSION 3
+#define CASSANDRA_MAX_VERSION 4
+#define CASSANDRA_DIRECTION_MASK 0x80
+#define CASSANDRA_VERSION_MASK 0x7f
+
+// Header flags, any other bit is invalid.
+#define CASSANDRA_FLAG_COMPRESSION 0x01
+#define CASSANDRA_VALID_FLAGS 0x1f
+
+// The maximum length of a frame body is 256MB.
+#define CASSANDRA_MAX_BODY_LENGTH (256 * 1024 * 1024)
+
+// Opcodes
+#define CASSANDRA_OPCODE_ERROR 0x00
+#define CASSANDRA_OPCODE_STARTUP 0x01
+#define CASSANDRA_OPCODE_READY 0x02
+#define CASSANDRA_OPCODE_AUTHENTICATE 0x03
+#define CASSANDRA_OPCODE_OPTIONS 0x05
+#define CASSANDRA_OPCODE_SUPPORTED 0x06
+#define CASSANDRA_OPCODE_QUERY 0x07
+#define CASSANDRA_OPCODE_RESULT 0x08
+#define CASSANDRA_OPCODE_PREPARE 0x09
+#define CASSANDRA_OPCODE_EXECUTE 0x0a
+#define CASSANDRA_OPCODE_REGISTER 0x0b
+#define CASSANDRA_OPCODE_EVENT 0x0c
+#define CASSANDRA_OPCODE_BATCH 0x0d
+#define CASSANDRA_OPCODE_AUTH_CHALLENGE 0x0e
+#define CASSANDRA_OPCODE_AUTH_RESPONSE 0x0f
+#define CASSANDRA_OPCODE_AUTH_SUCCESS 0x10
+
+struct cassandra_header {
+    __u8 version;
+    __u8 flags;
+    __u16 stream; // Big-endian: use bpf_ntohs to read this field
+    __u8 opcode;
+    __u32 length; // Big-endian: use bpf_ntohl to read this field
+} __attribute__((packed));
+
+#endif
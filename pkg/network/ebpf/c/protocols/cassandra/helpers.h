@@ -0,0 +1,64 @@
+#ifndef __CASSANDRA_HELPERS_H
+#define __CASSANDRA_HELPERS_H
+
+#include "protocols/classification/common.h"
+#include "protocols/cassandra/defs.h"
+
+// Checks the opcode is valid for a request.
+static __always_inline bool is_cassandra_request_opcode(__u8 opcode) {
+    switch (opcode) {
+    case CASSANDRA_OPCODE_STARTUP:
+    case CASSANDRA_OPCODE_OPTIONS:
+    case CASSANDRA_OPCODE_QUERY:
+    case CASSANDRA_OPCODE_PREPARE:
+    case CASSANDRA_OPCODE_EXECUTE:
+    case CASSANDRA_OPCODE_REGISTER:
+    case CASSANDRA_OPCODE_BATCH:
+    case CASSANDRA_OPCODE_AUTH_RESPONSE:
+        return true;
+    default:
+        return false;
+    }
+}
+
+// Checks the opcode is valid for a response.
+static __always_inline bool is_cassandra_response_opcode(__u8 opcode) {
+    switch (opcode) {
+    case CASSANDRA_OPCODE_ERROR:
+    case CASSANDRA_OPCODE_READY:
+    case CASSANDRA_OPCODE_AUTHENTICATE:
+    case CASSANDRA_OPCODE_SUPPORTED:
+    case CASSANDRA_OPCODE_RESULT:
+    case CASSANDRA_OPCODE_EVENT:
+    case CASSANDRA_OPCODE_AUTH_CHALLENGE:
+    case CASSANDRA_OPCODE_AUTH_SUCCESS:
+        return true;
+    default:
+        return false;
+    }
+}
+
+// Checks the buffer starts with a valid CQL frame header: a supported protocol version, valid flags, an opcode
+// matching the direction of the frame, and a body length within the protocol limits.
+static __always_inline bool is_cassandra(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, CASSANDRA_HEADER_LENGTH);
+
+    const struct cassandra_header *header = (const struct cassandra_header *)buf;
+    __u8 version = header->version & CASSANDRA_VERSION_MASK;
+    if (version < CASSANDRA_MIN_VERSION || version > CASSANDRA_MAX_VERSION) {
+        return false;
+    }
+    if (header->flags & ~CASSANDRA_VALID_FLAGS) {
+        return false;
+    }
+    if (bpf_ntohl(header->length) > CASSANDRA_MAX_BODY_LENGTH) {
+        return false;
+    }
+
+    if (header->version & CASSANDRA_DIRECTION_MASK) {
+        return is_cassandra_response_opcode(header->opcode);
+    }
+    return is_cassandra_request_opcode(header->opcode);
+}
+
+#endif
@@ -0,0 +1,36 @@
+#ifndef __CASSANDRA_TYPES_H
+#define __CASSANDRA_TYPES_H
+
+#include "conn_tuple.h"
+
+// Maximum length of CQL query to send to userspace.
+#define CASSANDRA_BUFFER_SIZE 128
+
+// CQL multiplexes requests over a connection, so in-flight transactions are identified by their stream id as well.
+typedef struct {
+    conn_tuple_t tup;
+    __u16 stream_id;
+} cassandra_key_t;
+
+// Cassandra transaction information we store in the kernel.
+typedef struct {
+    // The query of QUERY and PREPARE requests. Stored up to CASSANDRA_BUFFER_SIZE bytes.
+    char request_fragment[CASSANDRA_BUFFER_SIZE];
+    __u64 request_started;
+    __u64 response_last_seen;
+    // The actual size of the query stored in request_fragment.
+    __u32 original_query_size;
+    // The error code of ERROR responses, or the result kind of RESULT responses.
+    __u32 response_code;
+    __u8 request_opcode;
+    __u8 response_opcode;
+    __u8 tags;
+} cassandra_transaction_t;
+
+// The struct we send to userspace, containing the connection tuple and the transaction information.
+typedef struct {
+    conn_tuple_t tuple;
+    cassandra_transaction_t tx;
+} cassandra_event_t;
+
+#endif /* __CASSANDRA_TYPES_H */
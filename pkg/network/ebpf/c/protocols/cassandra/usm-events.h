@@ -0,0 +1,12 @@
+#ifndef __CASSANDRA_USM_EVENTS_H
+#define __CASSANDRA_USM_EVENTS_H
+
+#include "protocols/events.h"
+#include "protocols/cassandra/types.h"
+
+// Controls the number of Cassandra transactions read from userspace at a time.
+#define CASSANDRA_BATCH_SIZE (MAX_BATCH_SIZE(cassandra_event_t))
+
+USM_EVENTS_INIT(cassandra, cassandra_event_t, CASSANDRA_BATCH_SIZE);
+
+#endif /* __CASSANDRA_USM_EVENTS_H */
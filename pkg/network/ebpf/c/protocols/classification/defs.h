@@ -6,8 +6,10 @@
 #include "bpf_helpers_custom.h"
 
 #include "protocols/amqp/defs.h"
+#include "protocols/cassandra/defs.h"
 #include "protocols/http/classification-defs.h"
 #include "protocols/http2/defs.h"
+#include "protocols/memcached/defs.h"
 #include "protocols/mongo/defs.h"
 #include "protocols/mysql/defs.h"
 #include "protocols/redis/defs.h"
@@ -63,6 +65,8 @@ typedef enum {
     PROTOCOL_AMQP,
     PROTOCOL_REDIS,
     PROTOCOL_MYSQL,
+    PROTOCOL_MEMCACHED,
+    PROTOCOL_CASSANDRA,
     __LAYER_APPLICATION_MAX = LAYER_APPLICATION_MAX,
 
     __LAYER_ENCRYPTION_MIN = LAYER_ENCRYPTION_BIT,
@@ -156,6 +160,9 @@ typedef enum {
     PROG_POSTGRES_TERMINATION,
     PROG_REDIS,
     PROG_REDIS_TERMINATION,
+    PROG_MEMCACHED,
+    PROG_MEMCACHED_TERMINATION,
+    PROG_CASSANDRA,
     // Add before this value.
     PROG_MAX,
 } protocol_prog_t;
@@ -9,12 +9,16 @@
 #include "protocols/classification/maps.h"
 #include "protocols/classification/structs.h"
 #include "protocols/classification/dispatcher-maps.h"
+#include "protocols/cassandra/helpers.h"
+#include "protocols/cassandra/usm-events.h"
 #include "protocols/http/classification-helpers.h"
 #include "protocols/http/usm-events.h"
 #include "protocols/http2/helpers.h"
 #include "protocols/http2/usm-events.h"
 #include "protocols/kafka/kafka-classification.h"
 #include "protocols/kafka/usm-events.h"
+#include "protocols/memcached/helpers.h"
+#include "protocols/memcached/usm-events.h"
 #include "protocols/postgres/helpers.h"
 #include "protocols/postgres/usm-events.h"
 #include "protocols/redis/helpers.h"
@@ -32,6 +36,10 @@ __maybe_unused static __always_inline protocol_prog_t protocol_to_program(protoc
         return PROG_POSTGRES;
     case PROTOCOL_REDIS:
         return PROG_REDIS;
+    case PROTOCOL_MEMCACHED:
+        return PROG_MEMCACHED;
+    case PROTOCOL_CASSANDRA:
+        return PROG_CASSANDRA;
     default:
         if (proto != PROTOCOL_UNKNOWN) {
             log_debug("protocol doesn't have a matching program: %d", proto);
@@ -83,6 +91,10 @@ static __always_inline void classify_protocol_for_dispatcher(protocol_t *protoco
         *protocol = PROTOCOL_POSTGRES;
     } else if (is_redis_monitoring_enabled() && is_redis(buf, size)) {
         *protocol = PROTOCOL_REDIS;
+    } else if (is_cassandra_monitoring_enabled() && is_cassandra(buf, size)) {
+        *protocol = PROTOCOL_CASSANDRA;
+    } else if (is_memcached_monitoring_enabled() && is_memcached(buf, size)) {
+        *protocol = PROTOCOL_MEMCACHED;
     } else {
         *protocol = PROTOCOL_UNKNOWN;
     }
@@ -7,6 +7,7 @@
 #include "port_range.h"
 
 #include "protocols/amqp/helpers.h"
+#include "protocols/cassandra/helpers.h"
 #include "protocols/classification/common.h"
 #include "protocols/classification/defs.h"
 #include "protocols/classification/maps.h"
@@ -19,6 +20,7 @@
 #include "protocols/http/classification-helpers.h"
 #include "protocols/http2/helpers.h"
 #include "protocols/kafka/kafka-classification.h"
+#include "protocols/memcached/helpers.h"
 #include "protocols/mongo/helpers.h"
 #include "protocols/mysql/helpers.h"
 #include "protocols/redis/helpers.h"
@@ -100,7 +102,7 @@ static __always_inline protocol_t classify_applayer_protocols(const char *buf, _
     return PROTOCOL_UNKNOWN;
 }
 
-// Checks if a given buffer is redis, mongo, postgres, or mysql.
+// Checks if a given buffer is redis, mongo, postgres, mysql, cassandra or memcached.
 static __always_inline protocol_t classify_db_protocols(conn_tuple_t *tup, const char *buf, __u32 size) {
     if (is_redis(buf, size)) {
         return PROTOCOL_REDIS;
@@ -118,6 +120,14 @@ static __always_inline protocol_t classify_db_protocols(conn_tuple_t *tup, const
         return PROTOCOL_MYSQL;
     }
 
+    if (is_cassandra(buf, size)) {
+        return PROTOCOL_CASSANDRA;
+    }
+
+    if (is_memcached(buf, size)) {
+        return PROTOCOL_MEMCACHED;
+    }
+
     return PROTOCOL_UNKNOWN;
 }
 
@@ -0,0 +1,12 @@
+#ifndef __MEMCACHED_MAPS_H
+#define __MEMCACHED_MAPS_H
+
+#include "bpf_helpers.h"
+#include "map-defs.h"
+
+#include "protocols/memcached/types.h"
+
+// Keeps track of in-flight Memcached transactions
+BPF_HASH_MAP(memcached_in_flight, conn_tuple_t, memcached_transaction_t, 0)
+
+#endif /* __MEMCACHED_MAPS_H */
@@ -0,0 +1,131 @@
+#ifndef __MEMCACHED_DECODING_H
+#define __MEMCACHED_DECODING_H
+
+#include "bpf_builtins.h"
+#include "bpf_telemetry.h"
+
+#include "protocols/sockfd.h"
+
+#include "protocols/helpers/pktbuf.h"
+#include "protocols/memcached/decoding-maps.h"
+#include "protocols/memcached/helpers.h"
+#include "protocols/memcached/types.h"
+#include "protocols/memcached/usm-events.h"
+#include "protocols/read_into_buffer.h"
+
+PKTBUF_READ_INTO_BUFFER(memcached_fragment, MEMCACHED_BUFFER_SIZE, BLK_SIZE)
+
+// Enqueues a completed transaction to be sent to userspace.
+static __always_inline void memcached_batch_enqueue_wrapper(conn_tuple_t *tuple, memcached_transaction_t *tx) {
+    memcached_event_t event = {};
+    bpf_memcpy(&event.tuple, tuple, sizeof(conn_tuple_t));
+    bpf_memcpy(&event.tx, tx, sizeof(memcached_transaction_t));
+    memcached_batch_enqueue(&event);
+}
+
+static void __always_inline memcached_tcp_termination(conn_tuple_t *tup) {
+    bpf_map_delete_elem(&memcached_in_flight, tup);
+    flip_tuple(tup);
+    bpf_map_delete_elem(&memcached_in_flight, tup);
+}
+
+// Main processing logic for the Memcached protocol. Only the beginning of the packet is read: the command and the
+// status are decoded in userspace.
+// A request starts a new transaction, overriding the in-flight one of the connection if any, as we only track a
+// single request at a time per connection. The first response seen for the in-flight transaction completes it,
+// so the latency is the time to the first byte of the response.
+static __always_inline void memcached_process(pktbuf_t pkt, conn_tuple_t *tup, __u8 tags) {
+    char fragment[MEMCACHED_BUFFER_SIZE];
+    bpf_memset(fragment, 0, sizeof(fragment));
+
+    u32 data_off = pktbuf_data_offset(pkt);
+    u32 data_end = pktbuf_data_end(pkt);
+    pktbuf_read_into_buffer_memcached_fragment(fragment, pkt, data_off);
+    const __u32 payload_length = data_end - data_off;
+
+    if (is_memcached_request(fragment, payload_length)) {
+        memcached_transaction_t new_transaction = {};
+        bpf_memcpy(new_transaction.request_fragment, fragment, sizeof(fragment));
+        new_transaction.request_started = bpf_ktime_get_ns();
+        new_transaction.tags = tags;
+        bpf_map_update_elem(&memcached_in_flight, tup, &new_transaction, BPF_ANY);
+        return;
+    }
+
+    memcached_transaction_t *transaction = bpf_map_lookup_elem(&memcached_in_flight, tup);
+    if (!transaction) {
+        return;
+    }
+
+    if (!is_memcached_response(fragment, payload_length)) {
+        // Either the continuation of the request (e.g. the data block of a storage command), or the continuation of
+        // a response we already handled.
+        return;
+    }
+
+    bpf_memcpy(transaction->response_fragment, fragment, sizeof(fragment));
+    transaction->response_last_seen = bpf_ktime_get_ns();
+    transaction->tags |= tags;
+    memcached_batch_enqueue_wrapper(tup, transaction);
+    bpf_map_delete_elem(&memcached_in_flight, tup);
+}
+
+// Entrypoint to process plaintext Memcached traffic. Pulls the connection tuple and the packet buffer from the map and
+// calls the main processing function. If the packet is a TCP termination, it calls the termination function.
+SEC("socket/memcached_process")
+int socket__memcached_process(struct __sk_buff *skb) {
+    skb_info_t skb_info = {};
+    conn_tuple_t conn_tuple = {};
+
+    if (!fetch_dispatching_arguments(&conn_tuple, &skb_info)) {
+        return 0;
+    }
+
+    if (is_tcp_termination(&skb_info)) {
+        memcached_tcp_termination(&conn_tuple);
+        return 0;
+    }
+
+    normalize_tuple(&conn_tuple);
+
+    pktbuf_t pkt = pktbuf_from_skb(skb, &skb_info);
+    memcached_process(pkt, &conn_tuple, NO_TAGS);
+    return 0;
+}
+
+// Entrypoint to process TLS Memcached traffic. Pulls the connection tuple and the packet buffer from the map and calls
+// the main processing function.
+SEC("uprobe/memcached_tls_process")
+int uprobe__memcached_tls_process(struct pt_regs *ctx) {
+    const __u32 zero = 0;
+
+    tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+    if (args == NULL) {
+        return 0;
+    }
+
+    // Copying the tuple to the stack to handle verifier issues on kernel 4.14.
+    conn_tuple_t tup = args->tup;
+
+    pktbuf_t pkt = pktbuf_from_tls(ctx, args);
+    memcached_process(pkt, &tup, (__u8)args->tags);
+    return 0;
+}
+
+// Handles connection termination for a TLS Memcached connection.
+SEC("uprobe/memcached_tls_termination")
+int uprobe__memcached_tls_termination(struct pt_regs *ctx) {
+    const __u32 zero = 0;
+
+    tls_dispatcher_arguments_t *args = bpf_map_lookup_elem(&tls_dispatcher_arguments, &zero);
+    if (args == NULL) {
+        return 0;
+    }
+
+    // Copying the tuple to the stack to handle verifier issues on kernel 4.14.
+    conn_tuple_t tup = args->tup;
+    memcached_tcp_termination(&tup);
+    return 0;
+}
+
+#endif /* __MEMCACHED_DECODING_H */
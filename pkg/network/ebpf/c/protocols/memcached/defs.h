@@ -0,0 +1,31 @@
+#ifndef __MEMCACHED_DEFS_H
+#define __MEMCACHED_DEFS_H
+
+// The shortest request we can classify is a text command followed by a single character key and the CRLF
+// terminator, e.g. "get k\r\n".
+#define MEMCACHED_MIN_FRAME_LENGTH 7
+
+// Binary protocol, as described in https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped:
+// every request and response starts with a 24 bytes header, the first byte being the magic byte.
+#define MEMCACHED_BINARY_HEADER_LENGTH 24
+#define MEMCACHED_BINARY_REQUEST_MAGIC 0x80
+#define MEMCACHED_BINARY_RESPONSE_MAGIC 0x81
+// The highest opcode defined by the binary protocol (GATKQ).
+#define MEMCACHED_BINARY_MAX_OPCODE 0x24
+// The only data type defined by the binary protocol (raw bytes).
+#define MEMCACHED_BINARY_RAW_DATA_TYPE 0x00
+
+// Binary protocol header, shared by requests and responses. For requests, the status field holds the vbucket id.
+struct memcached_binary_header {
+    __u8 magic;
+    __u8 opcode;
+    __u16 key_length; // Big-endian
+    __u8 extras_length;
+    __u8 data_type;
+    __u16 status; // Big-endian
+    __u32 total_body_length; // Big-endian
+    __u32 opaque;
+    __u64 cas;
+} __attribute__((packed));
+
+#endif
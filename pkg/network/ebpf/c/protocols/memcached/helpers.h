@@ -0,0 +1,93 @@
+#ifndef __MEMCACHED_HELPERS_H
+#define __MEMCACHED_HELPERS_H
+
+#include "protocols/classification/common.h"
+#include "protocols/memcached/defs.h"
+
+// Matches the prefix only when the buffer is long enough to hold it, so that we never read past buf_size.
+#define MEMCACHED_MATCH_PREFIX(buf, buf_size, prefix) ((buf_size) >= sizeof(prefix) - 1 && !bpf_memcmp(buf, prefix, sizeof(prefix) - 1))
+
+// Checks the buffer holds a binary protocol header with the given magic byte. The header is checked as a whole, the
+// key and extras must fit in the body of the message.
+static __always_inline bool is_memcached_binary(const char *buf, __u32 buf_size, __u8 magic) {
+    if (buf_size < MEMCACHED_BINARY_HEADER_LENGTH) {
+        return false;
+    }
+
+    const struct memcached_binary_header *header = (const struct memcached_binary_header *)buf;
+    if (header->magic != magic || header->opcode > MEMCACHED_BINARY_MAX_OPCODE || header->data_type != MEMCACHED_BINARY_RAW_DATA_TYPE) {
+        return false;
+    }
+
+    __u32 key_and_extras_length = bpf_ntohs(header->key_length) + header->extras_length;
+    return key_and_extras_length <= bpf_ntohl(header->total_body_length);
+}
+
+// Checks the buffer starts with one of the storage or retrieval commands of the text protocol, as described in
+// https://github.com/memcached/memcached/blob/master/doc/protocol.txt
+static __always_inline bool is_memcached_text_request(const char *buf, __u32 buf_size) {
+    return MEMCACHED_MATCH_PREFIX(buf, buf_size, "get ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "gets ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "gat ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "gats ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "set ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "add ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "replace ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "append ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "prepend ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "cas ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "delete ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "incr ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "decr ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "touch ");
+}
+
+// Checks the buffer starts with one of the replies of the text protocol. Replies to incr and decr commands are the
+// new value of the item, so any digit is accepted.
+static __always_inline bool is_memcached_text_response(const char *buf, __u32 buf_size) {
+    if ('0' <= buf[0] && buf[0] <= '9') {
+        return true;
+    }
+
+    return MEMCACHED_MATCH_PREFIX(buf, buf_size, "VALUE ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "END\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "STORED\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "NOT_STORED\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "EXISTS\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "NOT_FOUND\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "DELETED\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "TOUCHED\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "ERROR\r\n")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "CLIENT_ERROR ")
+        || MEMCACHED_MATCH_PREFIX(buf, buf_size, "SERVER_ERROR ");
+}
+
+// Checks the buffer represents a memcached request, either of the text or the binary protocol.
+static __always_inline bool is_memcached_request(const char *buf, __u32 buf_size) {
+    CHECK_PRELIMINARY_BUFFER_CONDITIONS(buf, buf_size, MEMCACHED_MIN_FRAME_LENGTH);
+
+    if ((__u8)buf[0] == MEMCACHED_BINARY_REQUEST_MAGIC) {
+        return is_memcached_binary(buf, buf_size, MEMCACHED_BINARY_REQUEST_MAGIC);
+    }
+    return is_memcached_text_request(buf, buf_size);
+}
+
+// Checks the buffer represents a memcached response, either of the text or the binary protocol.
+static __always_inline bool is_memcached_response(const char *buf, __u32 buf_size) {
+    if (buf == NULL || buf_size == 0) {
+        return false;
+    }
+
+    if ((__u8)buf[0] == MEMCACHED_BINARY_RESPONSE_MAGIC) {
+        return is_memcached_binary(buf, buf_size, MEMCACHED_BINARY_RESPONSE_MAGIC);
+    }
+    return is_memcached_text_response(buf, buf_size);
+}
+
+// Checks the buffer represents a memcached request. We only classify requests, as the text protocol replies are too
+// generic (e.g. a number) to be reliably told apart from other protocols.
+static __always_inline bool is_memcached(const char *buf, __u32 buf_size) {
+    return is_memcached_request(buf, buf_size);
+}
+
+#endif
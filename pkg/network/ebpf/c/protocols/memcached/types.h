@@ -0,0 +1,27 @@
+#ifndef __MEMCACHED_TYPES_H
+#define __MEMCACHED_TYPES_H
+
+#include "conn_tuple.h"
+
+// Number of bytes of the request and the response sent to userspace. It is enough to hold the longest text command
+// ("prepend ") or reply ("NOT_STORED\r\n"), and the opcode and status of the binary protocol header.
+#define MEMCACHED_BUFFER_SIZE 16
+
+// Memcached in-flight transaction info
+typedef struct {
+    // The beginning of the request, stored up to MEMCACHED_BUFFER_SIZE bytes.
+    char request_fragment[MEMCACHED_BUFFER_SIZE];
+    // The beginning of the response, stored up to MEMCACHED_BUFFER_SIZE bytes.
+    char response_fragment[MEMCACHED_BUFFER_SIZE];
+    __u64 request_started;
+    __u64 response_last_seen;
+    __u8 tags;
+} memcached_transaction_t;
+
+// The struct we send to userspace, containing the connection tuple and the transaction information.
+typedef struct {
+    conn_tuple_t tuple;
+    memcached_transaction_t tx;
+} memcached_event_t;
+
+#endif /* __MEMCACHED_TYPES_H */
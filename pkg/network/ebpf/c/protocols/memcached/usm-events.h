@@ -0,0 +1,12 @@
+#ifndef __MEMCACHED_USM_EVENTS_H
+#define __MEMCACHED_USM_EVENTS_H
+
+#include "protocols/events.h"
+#include "protocols/memcached/types.h"
+
+// Controls the number of Memcached transactions read from userspace at a time.
+#define MEMCACHED_BATCH_SIZE (MAX_BATCH_SIZE(memcached_event_t))
+
+USM_EVENTS_INIT(memcached, memcached_event_t, MEMCACHED_BATCH_SIZE);
+
+#endif /* __MEMCACHED_USM_EVENTS_H */
@@ -123,6 +123,14 @@ static __always_inline void tls_process(struct pt_regs *ctx, conn_tuple_t *t, vo
         prog = PROG_POSTGRES;
         final_tuple = normalized_tuple;
         break;
+    case PROTOCOL_MEMCACHED:
+        prog = PROG_MEMCACHED;
+        final_tuple = normalized_tuple;
+        break;
+    case PROTOCOL_CASSANDRA:
+        prog = PROG_CASSANDRA;
+        final_tuple = normalized_tuple;
+        break;
     default:
         return;
     }
@@ -211,6 +219,10 @@ static __always_inline void tls_finish(struct pt_regs *ctx, conn_tuple_t *t, boo
         prog = PROG_POSTGRES_TERMINATION;
         final_tuple = normalized_tuple;
         break;
+    case PROTOCOL_MEMCACHED:
+        prog = PROG_MEMCACHED_TERMINATION;
+        final_tuple = normalized_tuple;
+        break;
     default:
         return;
     }
@@ -288,38 +300,49 @@ static __always_inline void map_ssl_ctx_to_sock(struct sock *skp) {
 }
 
 /**
- * get_offsets_data retrieves the result of binary analysis for the
- * current task binary's inode number.
+ * get_task_binary_key fills the key identifying the current task binary in the
+ * maps storing the results of binary analysis. Returns false on failure.
  */
-static __always_inline tls_offsets_data_t* get_offsets_data() {
+static __always_inline bool get_task_binary_key(go_tls_offsets_data_key_t *key) {
     struct task_struct *t = (struct task_struct *) bpf_get_current_task();
     struct inode *inode;
-    go_tls_offsets_data_key_t key;
     dev_t dev_id;
 
     inode = BPF_CORE_READ(t, mm, exe_file, f_inode);
     if (!inode) {
-        log_debug("get_offsets_data: could not read f_inode field");
-        return NULL;
+        log_debug("get_task_binary_key: could not read f_inode field");
+        return false;
     }
 
     int err;
-    err = BPF_CORE_READ_INTO(&key.ino, inode, i_ino);
+    err = BPF_CORE_READ_INTO(&key->ino, inode, i_ino);
     if (err) {
-        log_debug("get_offsets_data: could not read i_ino field");
-        return NULL;
+        log_debug("get_task_binary_key: could not read i_ino field");
+        return false;
     }
 
     err = BPF_CORE_READ_INTO(&dev_id, inode, i_sb, s_dev);
     if (err) {
-        log_debug("get_offsets_data: could not read s_dev field");
-        return NULL;
+        log_debug("get_task_binary_key: could not read s_dev field");
+        return false;
     }
 
-    key.device_id_major = MAJOR(dev_id);
-    key.device_id_minor = MINOR(dev_id);
+    key->device_id_major = MAJOR(dev_id);
+    key->device_id_minor = MINOR(dev_id);
+
+    log_debug("get_task_binary_key: task binary inode number: %llu; device ID %x:%x", key->ino, key->device_id_major, key->device_id_minor);
+    return true;
+}
 
-    log_debug("get_offsets_data: task binary inode number: %llu; device ID %x:%x", key.ino, key.device_id_major, key.device_id_minor);
+/**
+ * get_offsets_data retrieves the result of binary analysis for the
+ * current task binary's inode number.
+ */
+static __always_inline tls_offsets_data_t* get_offsets_data() {
+    go_tls_offsets_data_key_t key = {0};
+    if (!get_task_binary_key(&key)) {
+        return NULL;
+    }
 
     return bpf_map_lookup_elem(&offsets_data, &key);
 }
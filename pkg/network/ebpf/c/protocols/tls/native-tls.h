@@ -100,9 +100,11 @@ int BPF_BYPASSABLE_UPROBE(uprobe__SSL_read) {
     return 0;
 }
 
-static __always_inline int SSL_read_ret(struct pt_regs *ctx, __u64 tags) {
+// SSL_read_ret_len handles the return of a read call for libraries that do not
+// follow the SSL_read calling convention, where the number of bytes read has
+// to be extracted by the caller.
+static __always_inline int SSL_read_ret_len(struct pt_regs *ctx, int len, __u64 tags) {
     u64 pid_tgid = bpf_get_current_pid_tgid();
-    int len = (int)PT_REGS_RC(ctx);
     if (len <= 0) {
         log_debug("uretprobe/SSL_read: pid_tgid=%llx ret=%d", pid_tgid, len);
         goto cleanup;
@@ -137,6 +139,10 @@ cleanup:
     return 0;
 }
 
+static __always_inline int SSL_read_ret(struct pt_regs *ctx, __u64 tags) {
+    return SSL_read_ret_len(ctx, (int)PT_REGS_RC(ctx), tags);
+}
+
 SEC("uretprobe/SSL_read")
 int BPF_BYPASSABLE_URETPROBE(uretprobe__SSL_read) {
     return SSL_read_ret(ctx, LIBSSL);
@@ -152,6 +158,11 @@ int BPF_BYPASSABLE_URETPROBE(nodejs_uretprobe__SSL_read) {
     return SSL_read_ret(ctx, NODEJS);
 }
 
+SEC("uretprobe/SSL_read")
+int BPF_BYPASSABLE_URETPROBE(boringssl_uretprobe__SSL_read) {
+    return SSL_read_ret(ctx, BORINGSSL);
+}
+
 SEC("uprobe/SSL_write")
 int BPF_BYPASSABLE_UPROBE(uprobe__SSL_write) {
     ssl_write_args_t args = {0};
@@ -163,9 +174,9 @@ int BPF_BYPASSABLE_UPROBE(uprobe__SSL_write) {
     return 0;
 }
 
-static __always_inline int SSL_write_ret(struct pt_regs* ctx, __u64 flags) {
+// SSL_write_ret_len is the SSL_write counterpart of SSL_read_ret_len.
+static __always_inline int SSL_write_ret_len(struct pt_regs* ctx, int write_len, __u64 flags) {
     u64 pid_tgid = bpf_get_current_pid_tgid();
-    int write_len = (int)PT_REGS_RC(ctx);
     log_debug("uretprobe/SSL_write: pid_tgid=%llx len=%d", pid_tgid, write_len);
     if (write_len <= 0) {
         goto cleanup;
@@ -197,6 +208,10 @@ cleanup:
     return 0;
 }
 
+static __always_inline int SSL_write_ret(struct pt_regs* ctx, __u64 flags) {
+    return SSL_write_ret_len(ctx, (int)PT_REGS_RC(ctx), flags);
+}
+
 SEC("uretprobe/SSL_write")
 int BPF_BYPASSABLE_URETPROBE(uretprobe__SSL_write) {
     return SSL_write_ret(ctx, LIBSSL);
@@ -212,6 +227,11 @@ int BPF_BYPASSABLE_URETPROBE(nodejs_uretprobe__SSL_write) {
     return SSL_write_ret(ctx, NODEJS);
 }
 
+SEC("uretprobe/SSL_write")
+int BPF_BYPASSABLE_URETPROBE(boringssl_uretprobe__SSL_write) {
+    return SSL_write_ret(ctx, BORINGSSL);
+}
+
 SEC("uprobe/SSL_read_ex")
 int BPF_BYPASSABLE_UPROBE(uprobe__SSL_read_ex) {
     ssl_read_ex_args_t args = {0};
@@ -0,0 +1,13 @@
+#ifndef __RUSTLS_MAPS_H
+#define __RUSTLS_MAPS_H
+
+#include "map-defs.h"
+
+#include "protocols/tls/go-tls-types.h"
+#include "protocols/tls/rustls-types.h"
+
+// rustls_offsets_data contains the layout of the rustls types in the inspected
+// binaries, mapped by the binary's device and inode numbers.
+BPF_HASH_MAP(rustls_offsets_data, go_tls_offsets_data_key_t, rustls_offsets_data_t, 1024)
+
+#endif
@@ -0,0 +1,20 @@
+#ifndef __RUSTLS_TYPES_H
+#define __RUSTLS_TYPES_H
+
+#include "ktypes.h"
+
+// rustls has no stable ABI: the layout of its types depends on the rustls
+// version, the compiler version and the architecture. The agent only attaches
+// to builds with a known layout, and stores it in this structure.
+typedef struct {
+    // offset of the pointer to the received plaintext buffer in rustls::Reader
+    __u64 reader_buffer_offset;
+    // offset of the pointer to the connection in rustls::Writer
+    __u64 writer_conn_offset;
+    // offset of the received plaintext buffer in the connection
+    __u64 conn_buffer_offset;
+    // offset of the buffer of the TLS records to send in the connection
+    __u64 sendable_tls_offset;
+} rustls_offsets_data_t;
+
+#endif
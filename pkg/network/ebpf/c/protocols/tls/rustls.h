@@ -0,0 +1,156 @@
+#ifndef __RUSTLS_H
+#define __RUSTLS_H
+
+#include "ktypes.h"
+#include "bpf_builtins.h"
+#include "bpf_bypass.h"
+
+#include "protocols/tls/https.h"
+#include "protocols/tls/native-tls.h"
+#include "protocols/tls/rustls-maps.h"
+
+// rustls is a pure Rust TLS library that is always statically linked and has no
+// stable ABI. We hook the std::io::Read and std::io::Write implementations of
+// the rustls::Reader and rustls::Writer types, which are the only entry points
+// to the plaintext of a connection:
+//
+//   fn read(&mut self, buf: &mut [u8]) -> io::Result<usize>
+//   fn write(&mut self, buf: &[u8]) -> io::Result<usize>
+//
+// `self` (a pointer to the Reader/Writer) is passed in the first argument
+// register, followed by the slice as a (pointer, length) pair. The
+// io::Result<usize> is returned in a register pair: the first register holds
+// the discriminant (0 means Ok) and the second one holds the value.
+//
+// None of this is guaranteed by the Rust ABI, which is why user space only
+// attaches to the rustls builds for which these assumptions and the layout
+// stored in rustls_offsets_data have been verified.
+//
+// Reader and Writer are short-lived wrappers, so instead of keying the
+// connection on their address we key it on the address of the received
+// plaintext buffer of the connection, which both of them can reach.
+
+#if defined(__x86_64__)
+#define RUSTLS_RESULT_VALUE(x) (PT_REGS_PARM3(x)) // rdx
+#elif defined(__aarch64__)
+#define RUSTLS_RESULT_VALUE(x) (PT_REGS_PARM2(x)) // x1
+#endif
+
+static __always_inline rustls_offsets_data_t *get_rustls_offsets_data() {
+    go_tls_offsets_data_key_t key = {0};
+    if (!get_task_binary_key(&key)) {
+        return NULL;
+    }
+    return bpf_map_lookup_elem(&rustls_offsets_data, &key);
+}
+
+// rustls_reader_ctx returns the connection key of a rustls::Reader, which holds
+// a pointer to the received plaintext buffer.
+static __always_inline void *rustls_reader_ctx(rustls_offsets_data_t *od, void *self) {
+    void *buffer = NULL;
+    bpf_probe_read_user(&buffer, sizeof(buffer), self + od->reader_buffer_offset);
+    return buffer;
+}
+
+// rustls_writer_ctx returns the connection key of a rustls::Writer, which holds
+// a pointer to the connection.
+static __always_inline void *rustls_writer_ctx(rustls_offsets_data_t *od, void *self) {
+    void *conn = NULL;
+    bpf_probe_read_user(&conn, sizeof(conn), self + od->writer_conn_offset);
+    if (conn == NULL) {
+        return NULL;
+    }
+    return conn + od->conn_buffer_offset;
+}
+
+static __always_inline int rustls_result_len(struct pt_regs *ctx) {
+    if (PT_REGS_RC(ctx) != 0) {
+        // Err variant
+        return -1;
+    }
+    return (int)RUSTLS_RESULT_VALUE(ctx);
+}
+
+SEC("uprobe/rustls_reader_read")
+int BPF_BYPASSABLE_UPROBE(uprobe__rustls_reader_read) {
+    rustls_offsets_data_t *od = get_rustls_offsets_data();
+    if (od == NULL) {
+        return 0;
+    }
+
+    ssl_read_args_t args = { 0 };
+    args.ctx = rustls_reader_ctx(od, (void *)PT_REGS_PARM1(ctx));
+    args.buf = (void *)PT_REGS_PARM2(ctx);
+    if (args.ctx == NULL) {
+        return 0;
+    }
+    u64 pid_tgid = bpf_get_current_pid_tgid();
+    log_debug("uprobe/rustls_reader_read: pid_tgid=%llx ctx=%p", pid_tgid, args.ctx);
+    bpf_map_update_with_telemetry(ssl_read_args, &pid_tgid, &args, BPF_ANY);
+    return 0;
+}
+
+SEC("uretprobe/rustls_reader_read")
+int BPF_BYPASSABLE_URETPROBE(uretprobe__rustls_reader_read) {
+    return SSL_read_ret_len(ctx, rustls_result_len(ctx), RUSTLS);
+}
+
+SEC("uprobe/rustls_writer_write")
+int BPF_BYPASSABLE_UPROBE(uprobe__rustls_writer_write) {
+    rustls_offsets_data_t *od = get_rustls_offsets_data();
+    if (od == NULL) {
+        return 0;
+    }
+
+    ssl_write_args_t args = { 0 };
+    args.ctx = rustls_writer_ctx(od, (void *)PT_REGS_PARM1(ctx));
+    args.buf = (void *)PT_REGS_PARM2(ctx);
+    if (args.ctx == NULL) {
+        return 0;
+    }
+    u64 pid_tgid = bpf_get_current_pid_tgid();
+    log_debug("uprobe/rustls_writer_write: pid_tgid=%llx ctx=%p", pid_tgid, args.ctx);
+    bpf_map_update_with_telemetry(ssl_write_args, &pid_tgid, &args, BPF_ANY);
+    return 0;
+}
+
+SEC("uretprobe/rustls_writer_write")
+int BPF_BYPASSABLE_URETPROBE(uretprobe__rustls_writer_write) {
+    return SSL_write_ret_len(ctx, rustls_result_len(ctx), RUSTLS);
+}
+
+// rustls does not perform any I/O when the plaintext is written: the records
+// are buffered and written to the socket later, possibly by another thread,
+// through ChunkVecBuffer::write_to. Hooking it maps the connection to the socket
+// written by the current thread, which is then resolved by tcp_sendmsg.
+//
+//   fn write_to(&mut self, wr: &mut dyn io::Write) -> io::Result<usize>
+//
+// `self` points to the buffer of the records to send of the connection.
+SEC("uprobe/rustls_write_tls")
+int BPF_BYPASSABLE_UPROBE(uprobe__rustls_write_tls) {
+    rustls_offsets_data_t *od = get_rustls_offsets_data();
+    if (od == NULL) {
+        return 0;
+    }
+
+    void *sendable_tls = (void *)PT_REGS_PARM1(ctx);
+    if (sendable_tls == NULL) {
+        return 0;
+    }
+    void *ssl_ctx = sendable_tls - od->sendable_tls_offset + od->conn_buffer_offset;
+    u64 pid_tgid = bpf_get_current_pid_tgid();
+    log_debug("uprobe/rustls_write_tls: pid_tgid=%llx ctx=%p", pid_tgid, ssl_ctx);
+    bpf_map_update_with_telemetry(ssl_ctx_by_pid_tgid, &pid_tgid, &ssl_ctx, BPF_ANY);
+    return 0;
+}
+
+SEC("uretprobe/rustls_write_tls")
+int BPF_BYPASSABLE_URETPROBE(uretprobe__rustls_write_tls) {
+    u64 pid_tgid = bpf_get_current_pid_tgid();
+    log_debug("uretprobe/rustls_write_tls: pid_tgid=%llx", pid_tgid);
+    bpf_map_delete_elem(&ssl_ctx_by_pid_tgid, &pid_tgid);
+    return 0;
+}
+
+#endif
@@ -10,6 +10,8 @@ enum static_tags {
     CONN_TLS = (1<<3),
     ISTIO = (1<<4),
     NODEJS = (1<<5),
+    BORINGSSL = (1<<6),
+    RUSTLS = (1<<7),
 };
 
 #endif
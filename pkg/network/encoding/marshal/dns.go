@@ -6,8 +6,13 @@
 package marshal
 
 import (
+	"sort"
+
 	model "github.com/DataDog/agent-payload/v5/process"
+	"github.com/DataDog/sketches-go/ddsketch"
+	"github.com/gogo/protobuf/proto"
 
+	netmodel "github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/network/model"
 	pkgconfigsetup "github.com/DataDog/datadog-agent/pkg/config/setup"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
@@ -188,3 +193,42 @@ func formatDNSStatsByDomain(builder *model.ConnectionBuilder, stats map[dns.Host
 		})
 	}
 }
+
+// FormatDNSResolverStats converts the per-resolver DNS statistics and the top
+// failing domains into the payload consumed by the network check
+func FormatDNSResolverStats(report dns.ResolverReport) *netmodel.DNSResolverStats {
+	payload := &netmodel.DNSResolverStats{
+		Resolvers: make([]netmodel.ResolverStats, 0, len(report.Resolvers)),
+	}
+
+	for addr, stats := range report.Resolvers {
+		payload.Resolvers = append(payload.Resolvers, netmodel.ResolverStats{
+			Address:          addr.String(),
+			Timeouts:         stats.Timeouts,
+			CountByRcode:     stats.CountByRcode,
+			SuccessLatencies: encodeLatencies(stats.SuccessLatencies),
+			FailureLatencies: encodeLatencies(stats.FailureLatencies),
+		})
+	}
+	sort.Slice(payload.Resolvers, func(i, j int) bool {
+		return payload.Resolvers[i].Address < payload.Resolvers[j].Address
+	})
+
+	for _, domain := range report.FailingDomains {
+		payload.FailingDomains = append(payload.FailingDomains, netmodel.FailingDomain{
+			Domain:   dns.ToString(domain.Domain),
+			NXDomain: domain.NXDomain,
+			ServFail: domain.ServFail,
+		})
+	}
+
+	return payload
+}
+
+func encodeLatencies(latencies *ddsketch.DDSketch) []byte {
+	if latencies == nil || latencies.IsEmpty() {
+		return nil
+	}
+	blob, _ := proto.Marshal(latencies.ToProto())
+	return blob
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 	"testing"
 
+	"github.com/DataDog/sketches-go/ddsketch"
 	"github.com/gogo/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -138,3 +139,45 @@ func (p *ProtoTestStreamer[T]) Unwrap(t *testing.T, v T) T {
 func (p *ProtoTestStreamer[T]) Reset() {
 	p.buf.Reset()
 }
+
+func TestFormatDNSResolverStats(t *testing.T) {
+	latencies, err := ddsketch.NewDefaultDDSketch(0.01)
+	require.NoError(t, err)
+	require.NoError(t, latencies.Add(1500))
+
+	report := dns.ResolverReport{
+		Resolvers: dns.StatsByResolver{
+			util.AddressFromString("8.8.8.8"): {
+				Timeouts:         2,
+				CountByRcode:     map[uint32]uint32{0: 1},
+				SuccessLatencies: latencies,
+			},
+			util.AddressFromString("1.1.1.1"): {
+				CountByRcode: map[uint32]uint32{3: 4},
+			},
+		},
+		FailingDomains: []dns.FailingDomain{
+			{Domain: dns.ToHostname("missing.example"), NXDomain: 4},
+		},
+	}
+
+	payload := FormatDNSResolverStats(report)
+	require.Len(t, payload.Resolvers, 2)
+
+	assert.Equal(t, "1.1.1.1", payload.Resolvers[0].Address)
+	assert.Equal(t, map[uint32]uint32{3: 4}, payload.Resolvers[0].CountByRcode)
+	assert.Nil(t, payload.Resolvers[0].SuccessLatencies)
+	assert.Nil(t, payload.Resolvers[0].FailureLatencies)
+
+	resolver := payload.Resolvers[1]
+	assert.Equal(t, "8.8.8.8", resolver.Address)
+	assert.Equal(t, uint32(2), resolver.Timeouts)
+	assert.Nil(t, resolver.FailureLatencies)
+
+	decoded := unmarshalSketch(t, resolver.SuccessLatencies)
+	assert.Equal(t, float64(1), decoded.GetCount())
+
+	require.Len(t, payload.FailingDomains, 1)
+	assert.Equal(t, "missing.example", payload.FailingDomains[0].Domain)
+	assert.Equal(t, uint32(4), payload.FailingDomains[0].NXDomain)
+}
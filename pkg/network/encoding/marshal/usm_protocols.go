@@ -86,6 +86,10 @@ func formatProtocol(proto protocols.ProtocolType) model.ProtocolType {
 		return model.ProtocolType_protocolRedis
 	case protocols.MySQL:
 		return model.ProtocolType_protocolMySQL
+	case protocols.Memcached, protocols.Cassandra:
+		// the payload doesn't have a representation for these protocols yet, their stats are only
+		// available from the debug endpoints of system-probe
+		return model.ProtocolType_protocolUnknown
 	default:
 		log.Warnf("missing protobuf representation for protocol %d", proto)
 		return model.ProtocolType_protocolUnknown
@@ -43,6 +43,10 @@ var (
 	}
 )
 
+// collectProcessDetails is set when the command line and container tags
+// of the processes are needed, see CollectProcessDetails
+var collectProcessDetails = atomic.NewBool(false)
+
 // Process is a process
 type Process struct {
 	Pid         uint32
@@ -50,6 +54,10 @@ type Process struct {
 	ContainerID *intern.Value
 	StartTime   int64
 	Expiry      int64
+
+	// Cmdline and ContainerTags are only collected when CollectProcessDetails was called
+	Cmdline       *intern.Value
+	ContainerTags []*intern.Value
 }
 
 // Init initializes the events package
@@ -65,6 +73,12 @@ func Init() error {
 	return initErr
 }
 
+// CollectProcessDetails enables the collection of the command line and container tags of the processes,
+// e.g. to filter connections by process
+func CollectProcessDetails() {
+	collectProcessDetails.Store(true)
+}
+
 // Initialized returns true if Init() has been called successfully
 func Initialized() bool {
 	return theMonitor.Load() != nil
@@ -159,6 +173,18 @@ func (h *eventConsumerWrapper) Copy(ev *model.Event) any {
 		p.ContainerID = intern.GetByString(cid)
 	}
 
+	if collectProcessDetails.Load() {
+		if cmdline := getProcessCmdline(ev); cmdline != "" {
+			p.Cmdline = intern.GetByString(cmdline)
+		}
+		if containerTags := ev.GetContainerTags(); len(containerTags) > 0 {
+			p.ContainerTags = make([]*intern.Value, 0, len(containerTags))
+			for _, tag := range containerTags {
+				p.ContainerTags = append(p.ContainerTags, intern.GetByString(tag))
+			}
+		}
+	}
+
 	return p
 }
 
@@ -7,6 +7,7 @@
 package events
 
 import (
+	"strings"
 	"time"
 
 	"go4.org/intern"
@@ -24,6 +25,14 @@ func getProcessStartTime(ev *model.Event) time.Time {
 	return time.Time{}
 }
 
+func getProcessCmdline(ev *model.Event) string {
+	argv0 := ev.GetProcessArgv0()
+	if argv0 == "" {
+		return ""
+	}
+	return strings.Join(append([]string{argv0}, ev.GetProcessArgv()...), " ")
+}
+
 func getAPMTags(_ map[string]struct{}, _ string) []*intern.Value {
 	return nil
 }
@@ -27,6 +27,10 @@ func getProcessStartTime(ev *model.Event) time.Time {
 	return time.Time{}
 }
 
+func getProcessCmdline(ev *model.Event) string {
+	return ev.GetProcessCmdline()
+}
+
 func makeTagsSlice(already map[string]struct{}, apmtags iisconfig.APMTags) []*intern.Value {
 	tags := make([]*intern.Value, 0, 3)
 	if _, found := already["DD_SERVICE"]; !found {
@@ -9,6 +9,7 @@ package bininspect
 
 import (
 	"debug/elf"
+	"regexp"
 	"strings"
 
 	"github.com/DataDog/datadog-agent/pkg/util/common"
@@ -100,3 +101,39 @@ func (f prefixSymbolFilter) want(symbol string) bool {
 func (f prefixSymbolFilter) findMissing(_ map[string]elf.Symbol) []string {
 	return []string{f.prefix}
 }
+
+// regexpSymbolFilter is a symbol filter which gets any one symbol matching the
+// specified regular expression.
+type regexpSymbolFilter struct {
+	re        *regexp.Regexp
+	minLength int
+	maxLength int
+}
+
+func newRegexpSymbolFilter(re *regexp.Regexp, maxLength int) regexpSymbolFilter {
+	// The literal prefix of the expression is the shortest name it can match.
+	prefix, _ := re.LiteralPrefix()
+	return regexpSymbolFilter{
+		re:        re,
+		minLength: len(prefix),
+		maxLength: maxLength,
+	}
+}
+
+func (f regexpSymbolFilter) getMinMaxLength() (int, int) {
+	return f.minLength, f.maxLength
+}
+
+func (f regexpSymbolFilter) getNumWanted() int {
+	return 1
+}
+
+func (f regexpSymbolFilter) want(symbol string) bool {
+	return f.re.MatchString(symbol)
+}
+
+// findMissing gets the list of symbols which were missing. Only used for error
+// prints. Since we only know we were looking for an expression, return that.
+func (f regexpSymbolFilter) findMissing(_ map[string]elf.Symbol) []string {
+	return []string{f.re.String()}
+}
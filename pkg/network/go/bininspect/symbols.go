@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"regexp"
 
 	"github.com/cihub/seelog"
 
@@ -282,6 +283,23 @@ func GetAnySymbolWithPrefix(elfFile *elf.File, prefix string, maxLength int) (*e
 	return nil, errors.New("empty symbols map")
 }
 
+// GetAnySymbolMatching returns any one symbol matching the given regular
+// expression and the specified maximum length from the ELF file.
+func GetAnySymbolMatching(elfFile *elf.File, re *regexp.Regexp, maxLength int) (*elf.Symbol, error) {
+	symbols, err := GetAllSymbolsByName(elfFile, newRegexpSymbolFilter(re, maxLength))
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range symbols {
+		symbol := symbols[key]
+		return &symbol, nil
+	}
+
+	// Shouldn't happen
+	return nil, errors.New("empty symbols map")
+}
+
 // GetAnySymbolWithPrefixPCLNTAB returns any one symbol with the given prefix and the
 // specified maximum length from the pclntab section in ELF file.
 func GetAnySymbolWithPrefixPCLNTAB(elfFile *elf.File, prefix string, maxLength int) (*elf.Symbol, error) {
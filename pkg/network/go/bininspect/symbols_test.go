@@ -10,6 +10,7 @@ package bininspect
 import (
 	"debug/elf"
 	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -115,3 +116,22 @@ func TestPrefix(t *testing.T) {
 	require.NotNil(t, symbol)
 	require.Equal(t, "SSL_connect", symbol.Name)
 }
+
+func TestMatching(t *testing.T) {
+	elfFile := openTestElf(t)
+
+	symbol, err := GetAnySymbolMatching(elfFile, regexp.MustCompile(`^SSL_read_e.$`), len("SSL_read_ex"))
+	require.NoError(t, err)
+	require.NotNil(t, symbol)
+	require.Equal(t, "SSL_read_ex", symbol.Name)
+
+	symbol, err = GetAnySymbolMatching(elfFile, regexp.MustCompile(`^SSL_read_e.$`), len("SSL_read_ex")-1)
+	require.Error(t, err)
+	require.Nil(t, symbol)
+	assert.Contains(t, err.Error(), "^SSL_read_e.$")
+
+	symbol, err = GetAnySymbolMatching(elfFile, regexp.MustCompile(`^foo[0-9]+$`), 10)
+	require.Error(t, err)
+	require.Nil(t, symbol)
+	assert.Contains(t, err.Error(), "^foo[0-9]+$")
+}
@@ -0,0 +1,104 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package network
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// ProcessFilter holds user-defined exclusions of connections by process command line,
+// container image or Kubernetes namespace
+type ProcessFilter struct {
+	cmdlines        []*regexp.Regexp
+	containerImages map[string]struct{}
+	kubeNamespaces  map[string]struct{}
+}
+
+// ParseProcessFilter takes the user defined exclusions and returns a ProcessFilter, or nil if there are none.
+// Container images can be given by name (`nginx`, `docker.io/library/nginx`) or by name and tag (`nginx:1.25`).
+func ParseProcessFilter(cmdlines []string, containerImages []string, kubeNamespaces []string) *ProcessFilter {
+	filter := &ProcessFilter{
+		containerImages: make(map[string]struct{}, len(containerImages)),
+		kubeNamespaces:  make(map[string]struct{}, len(kubeNamespaces)),
+	}
+	for _, cmdline := range cmdlines {
+		pattern, err := regexp.Compile(cmdline)
+		if err != nil {
+			log.Errorf("Given process command line filter will not be respected. Could not parse regex %q: %s", cmdline, err)
+			continue
+		}
+		filter.cmdlines = append(filter.cmdlines, pattern)
+	}
+	for _, image := range containerImages {
+		filter.containerImages[image] = struct{}{}
+	}
+	for _, namespace := range kubeNamespaces {
+		filter.kubeNamespaces[namespace] = struct{}{}
+	}
+	if filter.IsEmpty() {
+		return nil
+	}
+	return filter
+}
+
+// IsEmpty returns whether the filter doesn't exclude anything
+func (f *ProcessFilter) IsEmpty() bool {
+	return f == nil || (len(f.cmdlines) == 0 && len(f.containerImages) == 0 && len(f.kubeNamespaces) == 0)
+}
+
+// IsExcluded returns whether the connections of a process, given its command line and container tags, are excluded
+func (f *ProcessFilter) IsExcluded(cmdline string, containerTags []string) bool {
+	if f.IsEmpty() {
+		return false
+	}
+	if cmdline != "" {
+		for _, pattern := range f.cmdlines {
+			if pattern.MatchString(cmdline) {
+				return true
+			}
+		}
+	}
+	if len(containerTags) == 0 {
+		return false
+	}
+
+	var imageName, shortImage, imageTag string
+	for _, tag := range containerTags {
+		name, value, ok := strings.Cut(tag, ":")
+		if !ok {
+			continue
+		}
+		switch name {
+		case "image_name":
+			imageName = value
+		case "short_image":
+			shortImage = value
+		case "image_tag":
+			imageTag = value
+		case "kube_namespace":
+			if _, excluded := f.kubeNamespaces[value]; excluded {
+				return true
+			}
+		}
+	}
+	for _, image := range []string{imageName, shortImage} {
+		if image == "" {
+			continue
+		}
+		if _, excluded := f.containerImages[image]; excluded {
+			return true
+		}
+		if imageTag != "" {
+			if _, excluded := f.containerImages[image+":"+imageTag]; excluded {
+				return true
+			}
+		}
+	}
+	return false
+}
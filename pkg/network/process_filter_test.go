@@ -0,0 +1,55 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseProcessFilter(t *testing.T) {
+	assert.Nil(t, ParseProcessFilter(nil, nil, nil))
+	// invalid regexes are ignored
+	assert.Nil(t, ParseProcessFilter([]string{"("}, nil, nil))
+
+	var filter *ProcessFilter
+	assert.True(t, filter.IsEmpty())
+	assert.False(t, filter.IsExcluded("/usr/bin/curl", []string{"kube_namespace:default"}))
+}
+
+func TestProcessFilterIsExcluded(t *testing.T) {
+	filter := ParseProcessFilter(
+		[]string{"^/usr/bin/curl ", "("},
+		[]string{"docker.io/library/redis", "nginx:1.25"},
+		[]string{"kube-system"},
+	)
+	containerTags := func(tags ...string) []string {
+		return append([]string{"container_name:app", "invalid"}, tags...)
+	}
+
+	tests := []struct {
+		name          string
+		cmdline       string
+		containerTags []string
+		expected      bool
+	}{
+		{name: "no process details", expected: false},
+		{name: "cmdline match", cmdline: "/usr/bin/curl https://example.com", expected: true},
+		{name: "cmdline mismatch", cmdline: "/usr/bin/wget https://example.com", expected: false},
+		{name: "image name", containerTags: containerTags("image_name:docker.io/library/redis", "short_image:redis", "image_tag:7"), expected: true},
+		{name: "image name and tag", containerTags: containerTags("image_name:nginx", "image_tag:1.25"), expected: true},
+		{name: "short image and tag", containerTags: containerTags("image_name:docker.io/library/nginx", "short_image:nginx", "image_tag:1.25"), expected: true},
+		{name: "other image tag", containerTags: containerTags("image_name:nginx", "image_tag:1.26"), expected: false},
+		{name: "kube namespace", containerTags: containerTags("image_name:app", "kube_namespace:kube-system"), expected: true},
+		{name: "other kube namespace", containerTags: containerTags("image_name:app", "kube_namespace:default"), expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, filter.IsExcluded(tt.cmdline, tt.containerTags))
+		})
+	}
+}
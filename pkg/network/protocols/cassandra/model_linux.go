@@ -0,0 +1,58 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"bytes"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+)
+
+// ConnTuple returns the connection tuple for the transaction
+func (e *EbpfEvent) ConnTuple() types.ConnectionKey {
+	return types.ConnectionKey{
+		SrcIPHigh: e.Tuple.Saddr_h,
+		SrcIPLow:  e.Tuple.Saddr_l,
+		DstIPHigh: e.Tuple.Daddr_h,
+		DstIPLow:  e.Tuple.Daddr_l,
+		SrcPort:   e.Tuple.Sport,
+		DstPort:   e.Tuple.Dport,
+	}
+}
+
+// Opcode returns the opcode of the request
+func (e *EbpfEvent) Opcode() Opcode {
+	return Opcode(e.Tx.Request_opcode)
+}
+
+// Status returns the status of the transaction
+func (e *EbpfEvent) Status() Status {
+	return ParseStatus(e.Tx.Response_opcode, e.Tx.Response_code)
+}
+
+// Keyspace returns the keyspace the query of QUERY and PREPARE requests refers to
+func (e *EbpfEvent) Keyspace() string {
+	size := min(int(e.Tx.Original_query_size), BufferSize)
+	query := e.Tx.Request_fragment[:size]
+	if int(e.Tx.Original_query_size) > BufferSize {
+		// The last word of a truncated query may be incomplete, so we drop it
+		if idx := bytes.LastIndexByte(query, ' '); idx >= 0 {
+			query = query[:idx]
+		}
+	}
+	return ParseKeyspace(string(query))
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (e *EbpfEvent) RequestLatency() float64 {
+	if e.Tx.Request_started == 0 || e.Tx.Response_last_seen == 0 {
+		return 0
+	}
+	return protocols.NSTimestampToFloat(e.Tx.Response_last_seen - e.Tx.Request_started)
+}
@@ -0,0 +1,209 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cassandra
+
+import (
+	"strings"
+)
+
+// This file decodes the opcode, the status and the keyspace of CQL transactions, as described in
+// https://github.com/apache/cassandra/blob/trunk/doc/native_protocol_v4.spec
+
+// Opcode represents the opcode of a CQL request.
+type Opcode uint8
+
+// Request opcodes, as defined in the section 2.4 of the specification.
+const (
+	// UnknownOpcode represents an unknown opcode.
+	UnknownOpcode Opcode = 0x00
+	// StartupOpcode represents a STARTUP request.
+	StartupOpcode Opcode = 0x01
+	// OptionsOpcode represents an OPTIONS request.
+	OptionsOpcode Opcode = 0x05
+	// QueryOpcode represents a QUERY request.
+	QueryOpcode Opcode = 0x07
+	// PrepareOpcode represents a PREPARE request.
+	PrepareOpcode Opcode = 0x09
+	// ExecuteOpcode represents an EXECUTE request.
+	ExecuteOpcode Opcode = 0x0a
+	// RegisterOpcode represents a REGISTER request.
+	RegisterOpcode Opcode = 0x0b
+	// BatchOpcode represents a BATCH request.
+	BatchOpcode Opcode = 0x0d
+	// AuthResponseOpcode represents an AUTH_RESPONSE request.
+	AuthResponseOpcode Opcode = 0x0f
+)
+
+// Response opcodes, as defined in the section 2.4 of the specification.
+const (
+	errorResponseOpcode  = 0x00
+	resultResponseOpcode = 0x08
+)
+
+// String returns the string representation of the opcode.
+func (o Opcode) String() string {
+	switch o {
+	case StartupOpcode:
+		return "STARTUP"
+	case OptionsOpcode:
+		return "OPTIONS"
+	case QueryOpcode:
+		return "QUERY"
+	case PrepareOpcode:
+		return "PREPARE"
+	case ExecuteOpcode:
+		return "EXECUTE"
+	case RegisterOpcode:
+		return "REGISTER"
+	case BatchOpcode:
+		return "BATCH"
+	case AuthResponseOpcode:
+		return "AUTH_RESPONSE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Status represents the outcome of a CQL transaction.
+type Status uint8
+
+const (
+	// UnknownStatus represents an unknown outcome, e.g. a response which isn't a RESULT nor an ERROR.
+	UnknownStatus Status = iota
+	// OkStatus represents a successful transaction.
+	OkStatus
+	// ServerErrorStatus represents an unexpected error of the server.
+	ServerErrorStatus
+	// ProtocolErrorStatus represents a protocol violation of the client.
+	ProtocolErrorStatus
+	// AuthErrorStatus represents an authentication failure.
+	AuthErrorStatus
+	// UnavailableStatus represents a query which couldn't reach its consistency level.
+	UnavailableStatus
+	// OverloadedStatus represents a coordinator which is overloaded.
+	OverloadedStatus
+	// TimeoutStatus represents a read or write timeout.
+	TimeoutStatus
+	// FailureStatus represents a read, write or function failure of the replicas.
+	FailureStatus
+	// SyntaxErrorStatus represents a query with a syntax error.
+	SyntaxErrorStatus
+	// InvalidStatus represents a query which is syntactically correct but invalid.
+	InvalidStatus
+	// UnpreparedStatus represents the execution of a statement unknown to the server.
+	UnpreparedStatus
+	// ErrorStatus represents any other error.
+	ErrorStatus
+)
+
+// String returns the string representation of the status.
+func (s Status) String() string {
+	switch s {
+	case OkStatus:
+		return "OK"
+	case ServerErrorStatus:
+		return "SERVER_ERROR"
+	case ProtocolErrorStatus:
+		return "PROTOCOL_ERROR"
+	case AuthErrorStatus:
+		return "AUTH_ERROR"
+	case UnavailableStatus:
+		return "UNAVAILABLE"
+	case OverloadedStatus:
+		return "OVERLOADED"
+	case TimeoutStatus:
+		return "TIMEOUT"
+	case FailureStatus:
+		return "FAILURE"
+	case SyntaxErrorStatus:
+		return "SYNTAX_ERROR"
+	case InvalidStatus:
+		return "INVALID"
+	case UnpreparedStatus:
+		return "UNPREPARED"
+	case ErrorStatus:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseStatus returns the status of a transaction, given the opcode of its response and the code it carries
+// (the error code of ERROR responses).
+func ParseStatus(responseOpcode uint8, code uint32) Status {
+	switch responseOpcode {
+	case resultResponseOpcode:
+		return OkStatus
+	case errorResponseOpcode:
+	default:
+		return UnknownStatus
+	}
+
+	// Error codes, as defined in the section 9 of the specification.
+	switch code {
+	case 0x0000:
+		return ServerErrorStatus
+	case 0x000A:
+		return ProtocolErrorStatus
+	case 0x0100:
+		return AuthErrorStatus
+	case 0x1000:
+		return UnavailableStatus
+	case 0x1001:
+		return OverloadedStatus
+	case 0x1100, 0x1200:
+		return TimeoutStatus
+	case 0x1300, 0x1400, 0x1500:
+		return FailureStatus
+	case 0x2000:
+		return SyntaxErrorStatus
+	case 0x2200:
+		return InvalidStatus
+	case 0x2500:
+		return UnpreparedStatus
+	default:
+		return ErrorStatus
+	}
+}
+
+// keyspaceKeywords are the keywords followed by a (possibly qualified) table name, or a keyspace for USE.
+var keyspaceKeywords = map[string]struct{}{
+	"FROM":   {},
+	"INTO":   {},
+	"UPDATE": {},
+	"TABLE":  {},
+	"USE":    {},
+}
+
+// ParseKeyspace returns the keyspace a query refers to, or an empty string if the query doesn't qualify its table
+// (in which case the session keyspace is used).
+func ParseKeyspace(query string) string {
+	fields := strings.Fields(query)
+	for i := 0; i < len(fields)-1; i++ {
+		keyword := strings.ToUpper(fields[i])
+		if _, ok := keyspaceKeywords[keyword]; !ok {
+			continue
+		}
+
+		name := strings.TrimRight(fields[i+1], ";(")
+		if keyword == "USE" {
+			return unquote(name)
+		}
+		if keyspace, _, found := strings.Cut(name, "."); found {
+			return unquote(keyspace)
+		}
+		return ""
+	}
+	return ""
+}
+
+// unquote removes the quotes of a quoted identifier, and lower-cases unquoted identifiers as CQL does.
+func unquote(name string) string {
+	if len(name) >= 2 && name[0] == '"' && name[len(name)-1] == '"' {
+		return name[1 : len(name)-1]
+	}
+	return strings.ToLower(name)
+}
@@ -0,0 +1,63 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package cassandra
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name           string
+		responseOpcode uint8
+		code           uint32
+		expected       Status
+	}{
+		{name: "rows", responseOpcode: resultResponseOpcode, code: 0x0002, expected: OkStatus},
+		{name: "void", responseOpcode: resultResponseOpcode, code: 0x0001, expected: OkStatus},
+		{name: "server error", responseOpcode: errorResponseOpcode, code: 0x0000, expected: ServerErrorStatus},
+		{name: "unavailable", responseOpcode: errorResponseOpcode, code: 0x1000, expected: UnavailableStatus},
+		{name: "read timeout", responseOpcode: errorResponseOpcode, code: 0x1200, expected: TimeoutStatus},
+		{name: "write failure", responseOpcode: errorResponseOpcode, code: 0x1500, expected: FailureStatus},
+		{name: "syntax error", responseOpcode: errorResponseOpcode, code: 0x2000, expected: SyntaxErrorStatus},
+		{name: "already exists", responseOpcode: errorResponseOpcode, code: 0x2400, expected: ErrorStatus},
+		{name: "ready", responseOpcode: 0x02, code: 0, expected: UnknownStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseStatus(tt.responseOpcode, tt.code))
+		})
+	}
+}
+
+func TestParseKeyspace(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string
+	}{
+		{query: "SELECT * FROM shop.orders WHERE id = ?", expected: "shop"},
+		{query: "select * from Shop.orders", expected: "shop"},
+		{query: `SELECT * FROM "Shop".orders`, expected: "Shop"},
+		{query: "SELECT * FROM orders", expected: ""},
+		{query: "INSERT INTO shop.orders (id, total) VALUES (?, ?)", expected: "shop"},
+		{query: "INSERT INTO shop.orders(id, total) VALUES (?, ?)", expected: "shop"},
+		{query: "UPDATE shop.orders SET total = ? WHERE id = ?", expected: "shop"},
+		{query: "DELETE FROM shop.orders WHERE id = ?", expected: "shop"},
+		{query: "USE shop;", expected: "shop"},
+		{query: "CREATE TABLE shop.orders (id int PRIMARY KEY)", expected: "shop"},
+		{query: "SELECT", expected: ""},
+		{query: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseKeyspace(tt.query))
+		})
+	}
+}
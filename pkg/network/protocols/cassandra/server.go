@@ -0,0 +1,36 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+// Package cassandra provides the Cassandra (CQL) protocol monitoring.
+package cassandra
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/testutil"
+	protocolsUtils "github.com/DataDog/datadog-agent/pkg/network/protocols/testutil"
+)
+
+// startupTimeout is the time we wait for the server to accept clients, as Cassandra is much slower to start than
+// the other servers.
+const startupTimeout = 3 * time.Minute
+
+// RunServer runs a Cassandra server in a docker container
+func RunServer(t testing.TB, serverAddr, serverPort string) error {
+	t.Helper()
+	dir, _ := testutil.CurDir()
+
+	env := []string{
+		"CASSANDRA_ADDR=" + serverAddr,
+		"CASSANDRA_PORT=" + serverPort,
+	}
+
+	return protocolsUtils.RunDockerServer(t, "cassandra", filepath.Join(dir, "testdata", "docker-compose.yml"), env, regexp.MustCompile(".*Starting listening for CQL clients"), startupTimeout, 3)
+}
@@ -0,0 +1,67 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package cassandra
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+)
+
+func newEvent(opcode Opcode, query string, responseOpcode uint8, code uint32) *EbpfEvent {
+	event := &EbpfEvent{
+		Tx: EbpfTx{
+			Request_started:     1,
+			Response_last_seen:  10,
+			Request_opcode:      uint8(opcode),
+			Response_opcode:     responseOpcode,
+			Response_code:       code,
+			Original_query_size: uint32(len(query)),
+		},
+	}
+	copy(event.Tx.Request_fragment[:], query)
+	return event
+}
+
+func TestStatKeeperProcess(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxCassandraStatsBuffered = 100
+	s := NewStatkeeper(cfg)
+	for i := 0; i < 20; i++ {
+		s.Process(newEvent(QueryOpcode, "SELECT * FROM shop.orders", resultResponseOpcode, 0x0002))
+	}
+	s.Process(newEvent(QueryOpcode, "SELECT * FROM shop.orders", errorResponseOpcode, 0x1200))
+
+	require.Len(t, s.stats, 2)
+	for k, stat := range s.stats {
+		require.Equal(t, QueryOpcode, k.Opcode)
+		require.Equal(t, "shop", k.Keyspace)
+		switch k.Status {
+		case OkStatus:
+			require.Equal(t, 20, stat.Count)
+			require.Equal(t, float64(20), stat.Latencies.GetCount())
+		case TimeoutStatus:
+			require.Equal(t, 1, stat.Count)
+			require.Nil(t, stat.Latencies)
+		default:
+			t.Fatalf("unexpected status %s", k.Status)
+		}
+	}
+}
+
+func TestKeyspaceOfTruncatedQuery(t *testing.T) {
+	padding := strings.Repeat("a", BufferSize-len("SELECT  FROM sh"))
+	event := newEvent(QueryOpcode, "SELECT "+padding+" FROM shop.orders", resultResponseOpcode, 0x0002)
+	require.Empty(t, event.Keyspace())
+
+	event = newEvent(QueryOpcode, "SELECT * FROM shop.orders WHERE "+strings.Repeat("a", BufferSize), resultResponseOpcode, 0x0002)
+	require.Equal(t, "shop", event.Keyspace())
+}
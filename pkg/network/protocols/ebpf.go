@@ -53,6 +53,10 @@ func toProtocolType(protoNum uint8, layerBit uint16) ProtocolType {
 		return Redis
 	case ebpfMySQL:
 		return MySQL
+	case ebpfMemcached:
+		return Memcached
+	case ebpfCassandra:
+		return Cassandra
 	default:
 		log.Errorf("unknown eBPF protocol type: %x", protocol)
 		return Unknown
@@ -59,6 +59,12 @@ const (
 	ProgramRedis ProgramType = 0x15
 
 	ProgramRedisTermination ProgramType = 0x16
+
+	ProgramMemcached ProgramType = 0x17
+
+	ProgramMemcachedTermination ProgramType = 0x18
+
+	ProgramCassandra ProgramType = 0x19
 )
 
 type ebpfProtocolType uint16
@@ -84,5 +90,9 @@ const (
 
 	ebpfMySQL ebpfProtocolType = 0x4008
 
+	ebpfMemcached ebpfProtocolType = 0x4009
+
+	ebpfCassandra ebpfProtocolType = 0x400a
+
 	ebpfGRPC ebpfProtocolType = 0x2001
 )
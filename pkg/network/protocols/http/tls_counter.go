@@ -14,23 +14,27 @@ import (
 // TLSCounter is a TLS aware counter, it has a plain counter and a counter for each TLS library
 // It enables the use of a single metric that increments based on the TLS library, avoiding the need for separate metrics for each TLS library
 type TLSCounter struct {
-	counterPlain     *libtelemetry.Counter
-	counterGnuTLS    *libtelemetry.Counter
-	counterOpenSSL   *libtelemetry.Counter
-	counterGoTLS     *libtelemetry.Counter
-	counterIstioTLS  *libtelemetry.Counter
-	counterNodeJSTLS *libtelemetry.Counter
+	counterPlain        *libtelemetry.Counter
+	counterGnuTLS       *libtelemetry.Counter
+	counterOpenSSL      *libtelemetry.Counter
+	counterGoTLS        *libtelemetry.Counter
+	counterIstioTLS     *libtelemetry.Counter
+	counterNodeJSTLS    *libtelemetry.Counter
+	counterBoringSSLTLS *libtelemetry.Counter
+	counterRustlsTLS    *libtelemetry.Counter
 }
 
 // NewTLSCounter creates and returns a new instance of TLSCounter
 func NewTLSCounter(metricGroup *libtelemetry.MetricGroup, metricName string, tags ...string) *TLSCounter {
 	return &TLSCounter{
 		// tls_library:none is a must, as prometheus metrics must have the same cardinality of tags
-		counterPlain:     metricGroup.NewCounter(metricName, append(tags, "encrypted:false", "tls_library:none")...),
-		counterGnuTLS:    metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:gnutls")...),
-		counterOpenSSL:   metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:openssl")...),
-		counterGoTLS:     metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:go")...),
-		counterIstioTLS:  metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:istio")...),
-		counterNodeJSTLS: metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:nodejs")...),
+		counterPlain:        metricGroup.NewCounter(metricName, append(tags, "encrypted:false", "tls_library:none")...),
+		counterGnuTLS:       metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:gnutls")...),
+		counterOpenSSL:      metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:openssl")...),
+		counterGoTLS:        metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:go")...),
+		counterIstioTLS:     metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:istio")...),
+		counterNodeJSTLS:    metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:nodejs")...),
+		counterBoringSSLTLS: metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:boringssl")...),
+		counterRustlsTLS:    metricGroup.NewCounter(metricName, append(tags, "encrypted:true", "tls_library:rustls")...),
 	}
 }
@@ -20,6 +20,10 @@ func (t *TLSCounter) Add(tx Transaction) {
 		t.counterIstioTLS.Add(1)
 	case NodeJS:
 		t.counterNodeJSTLS.Add(1)
+	case BoringSSL:
+		t.counterBoringSSLTLS.Add(1)
+	case Rustls:
+		t.counterRustlsTLS.Add(1)
 	default:
 		t.counterPlain.Add(1)
 	}
@@ -0,0 +1,293 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package memcached
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// This file decodes the command and the status of memcached transactions, from the beginning of their request and
+// response. Both the text and the binary protocols are supported:
+// https://github.com/memcached/memcached/blob/master/doc/protocol.txt
+// https://github.com/memcached/memcached/wiki/BinaryProtocolRevamped
+
+const (
+	binaryRequestMagic  = 0x80
+	binaryResponseMagic = 0x81
+	// binaryStatusOffset is the offset of the status in the header of binary responses
+	binaryStatusOffset = 6
+)
+
+// Command represents a memcached command supported by our decoder.
+type Command uint8
+
+const (
+	// UnknownCommand represents an unknown command.
+	UnknownCommand Command = iota
+	// GetCommand represents a get command.
+	GetCommand
+	// GetsCommand represents a gets command.
+	GetsCommand
+	// GatCommand represents a gat (get and touch) command.
+	GatCommand
+	// GatsCommand represents a gats command.
+	GatsCommand
+	// SetCommand represents a set command.
+	SetCommand
+	// AddCommand represents an add command.
+	AddCommand
+	// ReplaceCommand represents a replace command.
+	ReplaceCommand
+	// AppendCommand represents an append command.
+	AppendCommand
+	// PrependCommand represents a prepend command.
+	PrependCommand
+	// CasCommand represents a cas (check and set) command.
+	CasCommand
+	// DeleteCommand represents a delete command.
+	DeleteCommand
+	// IncrCommand represents an incr command.
+	IncrCommand
+	// DecrCommand represents a decr command.
+	DecrCommand
+	// TouchCommand represents a touch command.
+	TouchCommand
+)
+
+var textCommands = map[string]Command{
+	"get":     GetCommand,
+	"gets":    GetsCommand,
+	"gat":     GatCommand,
+	"gats":    GatsCommand,
+	"set":     SetCommand,
+	"add":     AddCommand,
+	"replace": ReplaceCommand,
+	"append":  AppendCommand,
+	"prepend": PrependCommand,
+	"cas":     CasCommand,
+	"delete":  DeleteCommand,
+	"incr":    IncrCommand,
+	"decr":    DecrCommand,
+	"touch":   TouchCommand,
+}
+
+// binaryCommands maps the opcodes of the binary protocol to commands, quiet variants and variants returning the key
+// are reported as their base command.
+var binaryCommands = map[byte]Command{
+	0x00: GetCommand,     // Get
+	0x01: SetCommand,     // Set
+	0x02: AddCommand,     // Add
+	0x03: ReplaceCommand, // Replace
+	0x04: DeleteCommand,  // Delete
+	0x05: IncrCommand,    // Increment
+	0x06: DecrCommand,    // Decrement
+	0x09: GetCommand,     // GetQ
+	0x0c: GetCommand,     // GetK
+	0x0d: GetCommand,     // GetKQ
+	0x0e: AppendCommand,  // Append
+	0x0f: PrependCommand, // Prepend
+	0x11: SetCommand,     // SetQ
+	0x12: AddCommand,     // AddQ
+	0x13: ReplaceCommand, // ReplaceQ
+	0x14: DeleteCommand,  // DeleteQ
+	0x15: IncrCommand,    // IncrementQ
+	0x16: DecrCommand,    // DecrementQ
+	0x19: AppendCommand,  // AppendQ
+	0x1a: PrependCommand, // PrependQ
+	0x1c: TouchCommand,   // Touch
+	0x1d: GatCommand,     // GAT
+	0x1e: GatCommand,     // GATQ
+	0x23: GatCommand,     // GATK
+	0x24: GatCommand,     // GATKQ
+}
+
+// String returns the string representation of the command.
+func (c Command) String() string {
+	switch c {
+	case GetCommand:
+		return "GET"
+	case GetsCommand:
+		return "GETS"
+	case GatCommand:
+		return "GAT"
+	case GatsCommand:
+		return "GATS"
+	case SetCommand:
+		return "SET"
+	case AddCommand:
+		return "ADD"
+	case ReplaceCommand:
+		return "REPLACE"
+	case AppendCommand:
+		return "APPEND"
+	case PrependCommand:
+		return "PREPEND"
+	case CasCommand:
+		return "CAS"
+	case DeleteCommand:
+		return "DELETE"
+	case IncrCommand:
+		return "INCR"
+	case DecrCommand:
+		return "DECR"
+	case TouchCommand:
+		return "TOUCH"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// isRetrieval returns whether the command retrieves items, in which case its successful responses are hits.
+func (c Command) isRetrieval() bool {
+	return c == GetCommand || c == GetsCommand || c == GatCommand || c == GatsCommand
+}
+
+// Status represents the outcome of a memcached transaction.
+type Status uint8
+
+const (
+	// UnknownStatus represents an unknown outcome.
+	UnknownStatus Status = iota
+	// HitStatus represents a retrieval command which found the item.
+	HitStatus
+	// MissStatus represents a retrieval command which didn't find the item.
+	MissStatus
+	// StoredStatus represents a storage command which stored the item.
+	StoredStatus
+	// NotStoredStatus represents a storage command whose condition wasn't met (e.g. add of an existing item).
+	NotStoredStatus
+	// ExistsStatus represents a cas command on an item modified since it was fetched.
+	ExistsStatus
+	// NotFoundStatus represents a command on an item which doesn't exist.
+	NotFoundStatus
+	// DeletedStatus represents a delete command which deleted the item.
+	DeletedStatus
+	// TouchedStatus represents a touch command which touched the item.
+	TouchedStatus
+	// OkStatus represents another successful command (e.g. incr and decr).
+	OkStatus
+	// ErrorStatus represents a command which failed.
+	ErrorStatus
+)
+
+var textStatuses = []struct {
+	prefix []byte
+	status Status
+}{
+	{[]byte("VALUE "), HitStatus},
+	{[]byte("END\r\n"), MissStatus},
+	{[]byte("STORED\r\n"), StoredStatus},
+	{[]byte("NOT_STORED\r\n"), NotStoredStatus},
+	{[]byte("EXISTS\r\n"), ExistsStatus},
+	{[]byte("NOT_FOUND\r\n"), NotFoundStatus},
+	{[]byte("DELETED\r\n"), DeletedStatus},
+	{[]byte("TOUCHED\r\n"), TouchedStatus},
+	{[]byte("ERROR\r\n"), ErrorStatus},
+	{[]byte("CLIENT_ERROR "), ErrorStatus},
+	{[]byte("SERVER_ERROR "), ErrorStatus},
+}
+
+// String returns the string representation of the status.
+func (s Status) String() string {
+	switch s {
+	case HitStatus:
+		return "HIT"
+	case MissStatus:
+		return "MISS"
+	case StoredStatus:
+		return "STORED"
+	case NotStoredStatus:
+		return "NOT_STORED"
+	case ExistsStatus:
+		return "EXISTS"
+	case NotFoundStatus:
+		return "NOT_FOUND"
+	case DeletedStatus:
+		return "DELETED"
+	case TouchedStatus:
+		return "TOUCHED"
+	case OkStatus:
+		return "OK"
+	case ErrorStatus:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// ParseCommand returns the command of a request, given its beginning.
+func ParseCommand(request []byte) Command {
+	if len(request) == 0 {
+		return UnknownCommand
+	}
+	if request[0] == binaryRequestMagic {
+		if len(request) < 2 {
+			return UnknownCommand
+		}
+		return binaryCommands[request[1]]
+	}
+
+	name, _, found := bytes.Cut(request, []byte(" "))
+	if !found {
+		return UnknownCommand
+	}
+	return textCommands[string(name)]
+}
+
+// ParseStatus returns the status of a response, given its beginning and the command of the request.
+func ParseStatus(command Command, response []byte) Status {
+	if len(response) == 0 {
+		return UnknownStatus
+	}
+	if response[0] == binaryResponseMagic {
+		return parseBinaryStatus(command, response)
+	}
+
+	for _, s := range textStatuses {
+		if bytes.HasPrefix(response, s.prefix) {
+			return s.status
+		}
+	}
+	// incr and decr reply with the new value of the item
+	if '0' <= response[0] && response[0] <= '9' {
+		return OkStatus
+	}
+	return UnknownStatus
+}
+
+func parseBinaryStatus(command Command, response []byte) Status {
+	if len(response) < binaryStatusOffset+2 {
+		return UnknownStatus
+	}
+
+	switch binary.BigEndian.Uint16(response[binaryStatusOffset:]) {
+	case 0x0000: // No error
+		switch {
+		case command.isRetrieval():
+			return HitStatus
+		case command == DeleteCommand:
+			return DeletedStatus
+		case command == TouchCommand:
+			return TouchedStatus
+		case command == IncrCommand || command == DecrCommand:
+			return OkStatus
+		default:
+			return StoredStatus
+		}
+	case 0x0001: // Key not found
+		if command.isRetrieval() {
+			return MissStatus
+		}
+		return NotFoundStatus
+	case 0x0002: // Key exists
+		return ExistsStatus
+	case 0x0005: // Item not stored
+		return NotStoredStatus
+	default:
+		return ErrorStatus
+	}
+}
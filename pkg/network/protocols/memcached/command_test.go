@@ -0,0 +1,79 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package memcached
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func binaryHeader(magic, opcode byte, status uint16) []byte {
+	header := make([]byte, 24)
+	header[0] = magic
+	header[1] = opcode
+	header[6] = byte(status >> 8)
+	header[7] = byte(status)
+	return header
+}
+
+func TestParseCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  []byte
+		expected Command
+	}{
+		{name: "text get", request: []byte("get foo\r\n"), expected: GetCommand},
+		{name: "text gats", request: []byte("gats 10 foo bar\r\n"), expected: GatsCommand},
+		{name: "text set", request: []byte("set foo 0 0 3\r\nbar"), expected: SetCommand},
+		{name: "text delete", request: []byte("delete foo\r\n"), expected: DeleteCommand},
+		{name: "text unsupported", request: []byte("stats\r\n"), expected: UnknownCommand},
+		{name: "text truncated", request: []byte("getfooba"), expected: UnknownCommand},
+		{name: "binary get", request: binaryHeader(binaryRequestMagic, 0x00, 0), expected: GetCommand},
+		{name: "binary quiet set", request: binaryHeader(binaryRequestMagic, 0x11, 0), expected: SetCommand},
+		{name: "binary gatkq", request: binaryHeader(binaryRequestMagic, 0x24, 0), expected: GatCommand},
+		{name: "binary unsupported", request: binaryHeader(binaryRequestMagic, 0x10, 0), expected: UnknownCommand},
+		{name: "empty", request: nil, expected: UnknownCommand},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseCommand(tt.request))
+		})
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  Command
+		response []byte
+		expected Status
+	}{
+		{name: "text hit", command: GetCommand, response: []byte("VALUE foo 0 3\r\n"), expected: HitStatus},
+		{name: "text miss", command: GetCommand, response: []byte("END\r\n"), expected: MissStatus},
+		{name: "text stored", command: SetCommand, response: []byte("STORED\r\n"), expected: StoredStatus},
+		{name: "text not stored", command: AddCommand, response: []byte("NOT_STORED\r\n"), expected: NotStoredStatus},
+		{name: "text exists", command: CasCommand, response: []byte("EXISTS\r\n"), expected: ExistsStatus},
+		{name: "text not found", command: DeleteCommand, response: []byte("NOT_FOUND\r\n"), expected: NotFoundStatus},
+		{name: "text incr", command: IncrCommand, response: []byte("42\r\n"), expected: OkStatus},
+		{name: "text server error", command: SetCommand, response: []byte("SERVER_ERROR out of memory"), expected: ErrorStatus},
+		{name: "binary hit", command: GetCommand, response: binaryHeader(binaryResponseMagic, 0x00, 0x0000), expected: HitStatus},
+		{name: "binary miss", command: GetCommand, response: binaryHeader(binaryResponseMagic, 0x00, 0x0001), expected: MissStatus},
+		{name: "binary deleted", command: DeleteCommand, response: binaryHeader(binaryResponseMagic, 0x04, 0x0000), expected: DeletedStatus},
+		{name: "binary not found", command: TouchCommand, response: binaryHeader(binaryResponseMagic, 0x1c, 0x0001), expected: NotFoundStatus},
+		{name: "binary stored", command: SetCommand, response: binaryHeader(binaryResponseMagic, 0x01, 0x0000), expected: StoredStatus},
+		{name: "binary not stored", command: AddCommand, response: binaryHeader(binaryResponseMagic, 0x02, 0x0005), expected: NotStoredStatus},
+		{name: "binary error", command: IncrCommand, response: binaryHeader(binaryResponseMagic, 0x05, 0x0006), expected: ErrorStatus},
+		{name: "binary truncated", command: GetCommand, response: []byte{binaryResponseMagic, 0x00}, expected: UnknownStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseStatus(tt.command, tt.response))
+		})
+	}
+}
@@ -0,0 +1,43 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package memcached
+
+import (
+	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/types"
+)
+
+// ConnTuple returns the connection tuple for the transaction
+func (e *EbpfEvent) ConnTuple() types.ConnectionKey {
+	return types.ConnectionKey{
+		SrcIPHigh: e.Tuple.Saddr_h,
+		SrcIPLow:  e.Tuple.Saddr_l,
+		DstIPHigh: e.Tuple.Daddr_h,
+		DstIPLow:  e.Tuple.Daddr_l,
+		SrcPort:   e.Tuple.Sport,
+		DstPort:   e.Tuple.Dport,
+	}
+}
+
+// Command returns the command of the request
+func (e *EbpfEvent) Command() Command {
+	return ParseCommand(e.Tx.Request_fragment[:])
+}
+
+// Status returns the status of the response
+func (e *EbpfEvent) Status() Status {
+	return ParseStatus(e.Command(), e.Tx.Response_fragment[:])
+}
+
+// RequestLatency returns the latency of the request in nanoseconds
+func (e *EbpfEvent) RequestLatency() float64 {
+	if e.Tx.Request_started == 0 || e.Tx.Response_last_seen == 0 {
+		return 0
+	}
+	return protocols.NSTimestampToFloat(e.Tx.Response_last_seen - e.Tx.Request_started)
+}
@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build test
+
+// Package memcached provides the Memcached protocol monitoring.
+package memcached
+
+import (
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/testutil"
+	protocolsUtils "github.com/DataDog/datadog-agent/pkg/network/protocols/testutil"
+)
+
+// RunServer runs a Memcached server in a docker container
+func RunServer(t testing.TB, serverAddr, serverPort string) error {
+	t.Helper()
+	dir, _ := testutil.CurDir()
+
+	env := []string{
+		"MEMCACHED_ADDR=" + serverAddr,
+		"MEMCACHED_PORT=" + serverPort,
+	}
+
+	return protocolsUtils.RunDockerServer(t, "memcached", filepath.Join(dir, "testdata", "docker-compose.yml"), env, regexp.MustCompile(".*server listening"), protocolsUtils.DefaultTimeout, 3)
+}
@@ -0,0 +1,66 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package memcached
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+)
+
+func newEvent(request, response string) *EbpfEvent {
+	event := &EbpfEvent{
+		Tx: EbpfTx{
+			Request_started:    1,
+			Response_last_seen: 10,
+		},
+	}
+	copy(event.Tx.Request_fragment[:], request)
+	copy(event.Tx.Response_fragment[:], response)
+	return event
+}
+
+func TestStatKeeperProcess(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxMemcachedStatsBuffered = 100
+	s := NewStatkeeper(cfg)
+	for i := 0; i < 20; i++ {
+		s.Process(newEvent("get foo\r\n", "VALUE foo 0 3\r\n"))
+	}
+	s.Process(newEvent("get bar\r\n", "END\r\n"))
+
+	require.Len(t, s.stats, 2)
+	for k, stat := range s.stats {
+		require.Equal(t, GetCommand, k.Command)
+		switch k.Status {
+		case HitStatus:
+			require.Equal(t, 20, stat.Count)
+			require.Equal(t, float64(20), stat.Latencies.GetCount())
+		case MissStatus:
+			require.Equal(t, 1, stat.Count)
+			require.Nil(t, stat.Latencies)
+			require.Equal(t, float64(9), stat.FirstLatencySample)
+		default:
+			t.Fatalf("unexpected status %s", k.Status)
+		}
+	}
+}
+
+func TestStatKeeperMaxEntries(t *testing.T) {
+	cfg := config.New()
+	cfg.MaxMemcachedStatsBuffered = 1
+	s := NewStatkeeper(cfg)
+	s.Process(newEvent("set foo 0 0 3\r\n", "STORED\r\n"))
+	s.Process(newEvent("delete foo\r\n", "DELETED\r\n"))
+
+	stats := s.GetAndResetAllStats()
+	require.Len(t, stats, 1)
+	require.Empty(t, s.stats)
+}
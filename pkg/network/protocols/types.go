@@ -31,6 +31,10 @@ const (
 	MySQL
 	// GRPC protocol
 	GRPC
+	// Memcached protocol
+	Memcached
+	// Cassandra protocol
+	Cassandra
 )
 
 // String returns the string representation of the protocol
@@ -58,6 +62,10 @@ func (p ProtocolType) String() string {
 		return "MySQL"
 	case GRPC:
 		return "gRPC"
+	case Memcached:
+		return "Memcached"
+	case Cassandra:
+		return "Cassandra"
 	default:
 		// shouldn't happen
 		return "Invalid"
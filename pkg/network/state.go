@@ -18,8 +18,10 @@ import (
 	telemetryComponent "github.com/DataDog/datadog-agent/comp/core/telemetry"
 	"github.com/DataDog/datadog-agent/pkg/network/dns"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/cassandra"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/http"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/kafka"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/memcached"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/postgres"
 	"github.com/DataDog/datadog-agent/pkg/network/protocols/redis"
 	"github.com/DataDog/datadog-agent/pkg/network/slice"
@@ -45,6 +47,8 @@ var stateTelemetry = struct {
 	kafkaStatsDropped      *telemetry.StatCounterWrapper
 	postgresStatsDropped   *telemetry.StatCounterWrapper
 	redisStatsDropped      *telemetry.StatCounterWrapper
+	memcachedStatsDropped  *telemetry.StatCounterWrapper
+	cassandraStatsDropped  *telemetry.StatCounterWrapper
 	dnsPidCollisions       *telemetry.StatCounterWrapper
 	incomingDirectionFixes telemetry.Counter
 	outgoingDirectionFixes telemetry.Counter
@@ -60,6 +64,8 @@ var stateTelemetry = struct {
 	telemetry.NewStatCounterWrapper(stateModuleName, "kafka_stats_dropped", []string{}, "Counter measuring the number of kafka stats dropped"),
 	telemetry.NewStatCounterWrapper(stateModuleName, "postgres_stats_dropped", []string{}, "Counter measuring the number of postgres stats dropped"),
 	telemetry.NewStatCounterWrapper(stateModuleName, "redis_stats_dropped", []string{}, "Counter measuring the number of redis stats dropped"),
+	telemetry.NewStatCounterWrapper(stateModuleName, "memcached_stats_dropped", []string{}, "Counter measuring the number of memcached stats dropped"),
+	telemetry.NewStatCounterWrapper(stateModuleName, "cassandra_stats_dropped", []string{}, "Counter measuring the number of cassandra stats dropped"),
 	telemetry.NewStatCounterWrapper(stateModuleName, "dns_pid_collisions", []string{}, "Counter measuring the number of DNS PID collisions"),
 	telemetry.NewCounter(stateModuleName, "incoming_direction_fixes", []string{}, "Counter measuring the number of udp direction fixes for incoming connections"),
 	telemetry.NewCounter(stateModuleName, "outgoing_direction_fixes", []string{}, "Counter measuring the number of udp/tcp direction fixes for outgoing connections"),
@@ -123,12 +129,14 @@ type State interface {
 
 // Delta represents a delta of network data compared to the last call to State.
 type Delta struct {
-	Conns    []ConnectionStats
-	HTTP     map[http.Key]*http.RequestStats
-	HTTP2    map[http.Key]*http.RequestStats
-	Kafka    map[kafka.Key]*kafka.RequestStats
-	Postgres map[postgres.Key]*postgres.RequestStat
-	Redis    map[redis.Key]*redis.RequestStat
+	Conns     []ConnectionStats
+	HTTP      map[http.Key]*http.RequestStats
+	HTTP2     map[http.Key]*http.RequestStats
+	Kafka     map[kafka.Key]*kafka.RequestStats
+	Postgres  map[postgres.Key]*postgres.RequestStat
+	Redis     map[redis.Key]*redis.RequestStat
+	Memcached map[memcached.Key]*memcached.RequestStat
+	Cassandra map[cassandra.Key]*cassandra.RequestStat
 }
 
 type lastStateTelemetry struct {
@@ -143,6 +151,8 @@ type lastStateTelemetry struct {
 	kafkaStatsDropped     int64
 	postgresStatsDropped  int64
 	redisStatsDropped     int64
+	memcachedStatsDropped int64
+	cassandraStatsDropped int64
 	dnsPidCollisions      int64
 }
 
@@ -239,13 +249,15 @@ type client struct {
 	closed    *closedConnections
 	stats     map[StatCookie]StatCounters
 	// maps by dns key the domain (string) to stats structure
-	dnsStats           dns.StatsByKeyByNameByType
-	httpStatsDelta     map[http.Key]*http.RequestStats
-	http2StatsDelta    map[http.Key]*http.RequestStats
-	kafkaStatsDelta    map[kafka.Key]*kafka.RequestStats
-	postgresStatsDelta map[postgres.Key]*postgres.RequestStat
-	redisStatsDelta    map[redis.Key]*redis.RequestStat
-	lastTelemetries    map[ConnTelemetryType]int64
+	dnsStats            dns.StatsByKeyByNameByType
+	httpStatsDelta      map[http.Key]*http.RequestStats
+	http2StatsDelta     map[http.Key]*http.RequestStats
+	kafkaStatsDelta     map[kafka.Key]*kafka.RequestStats
+	postgresStatsDelta  map[postgres.Key]*postgres.RequestStat
+	redisStatsDelta     map[redis.Key]*redis.RequestStat
+	memcachedStatsDelta map[memcached.Key]*memcached.RequestStat
+	cassandraStatsDelta map[cassandra.Key]*cassandra.RequestStat
+	lastTelemetries     map[ConnTelemetryType]int64
 }
 
 func (c *client) Reset() {
@@ -262,6 +274,8 @@ func (c *client) Reset() {
 	c.kafkaStatsDelta = make(map[kafka.Key]*kafka.RequestStats)
 	c.postgresStatsDelta = make(map[postgres.Key]*postgres.RequestStat)
 	c.redisStatsDelta = make(map[redis.Key]*redis.RequestStat)
+	c.memcachedStatsDelta = make(map[memcached.Key]*memcached.RequestStat)
+	c.cassandraStatsDelta = make(map[cassandra.Key]*cassandra.RequestStat)
 }
 
 type networkState struct {
@@ -282,6 +296,8 @@ type networkState struct {
 	maxKafkaStats               int
 	maxPostgresStats            int
 	maxRedisStats               int
+	maxMemcachedStats           int
+	maxCassandraStats           int
 	enableConnectionRollup      bool
 	processEventConsumerEnabled bool
 
@@ -291,7 +307,7 @@ type networkState struct {
 }
 
 // NewState creates a new network state
-func NewState(_ telemetryComponent.Component, clientExpiry time.Duration, maxClosedConns uint32, maxClientStats, maxDNSStats, maxHTTPStats, maxKafkaStats, maxPostgresStats, maxRedisStats int, enableConnectionRollup bool, processEventConsumerEnabled bool) State {
+func NewState(_ telemetryComponent.Component, clientExpiry time.Duration, maxClosedConns uint32, maxClientStats, maxDNSStats, maxHTTPStats, maxKafkaStats, maxPostgresStats, maxRedisStats, maxMemcachedStats, maxCassandraStats int, enableConnectionRollup bool, processEventConsumerEnabled bool) State {
 	ns := &networkState{
 		clients:                map[string]*client{},
 		clientExpiry:           clientExpiry,
@@ -302,6 +318,8 @@ func NewState(_ telemetryComponent.Component, clientExpiry time.Duration, maxClo
 		maxKafkaStats:          maxKafkaStats,
 		maxPostgresStats:       maxPostgresStats,
 		maxRedisStats:          maxRedisStats,
+		maxMemcachedStats:      maxMemcachedStats,
+		maxCassandraStats:      maxCassandraStats,
 		enableConnectionRollup: enableConnectionRollup,
 		mergeStatsBuffers: [2][]byte{
 			make([]byte, ConnectionByteKeyMaxLen),
@@ -424,16 +442,24 @@ func (ns *networkState) GetDelta(
 		case protocols.Redis:
 			stats := protocolStats.(map[redis.Key]*redis.RequestStat)
 			ns.storeRedisStats(stats)
+		case protocols.Memcached:
+			stats := protocolStats.(map[memcached.Key]*memcached.RequestStat)
+			ns.storeMemcachedStats(stats)
+		case protocols.Cassandra:
+			stats := protocolStats.(map[cassandra.Key]*cassandra.RequestStat)
+			ns.storeCassandraStats(stats)
 		}
 	}
 
 	return Delta{
-		Conns:    append(active, closed...),
-		HTTP:     client.httpStatsDelta,
-		HTTP2:    client.http2StatsDelta,
-		Kafka:    client.kafkaStatsDelta,
-		Postgres: client.postgresStatsDelta,
-		Redis:    client.redisStatsDelta,
+		Conns:     append(active, closed...),
+		HTTP:      client.httpStatsDelta,
+		HTTP2:     client.http2StatsDelta,
+		Kafka:     client.kafkaStatsDelta,
+		Postgres:  client.postgresStatsDelta,
+		Redis:     client.redisStatsDelta,
+		Memcached: client.memcachedStatsDelta,
+		Cassandra: client.cassandraStatsDelta,
 	}
 }
 
@@ -488,11 +514,14 @@ func (ns *networkState) logTelemetry() {
 	kafkaStatsDroppedDelta := stateTelemetry.kafkaStatsDropped.Load() - ns.lastTelemetry.kafkaStatsDropped
 	postgresStatsDroppedDelta := stateTelemetry.postgresStatsDropped.Load() - ns.lastTelemetry.postgresStatsDropped
 	redisStatsDroppedDelta := stateTelemetry.redisStatsDropped.Load() - ns.lastTelemetry.redisStatsDropped
+	memcachedStatsDroppedDelta := stateTelemetry.memcachedStatsDropped.Load() - ns.lastTelemetry.memcachedStatsDropped
+	cassandraStatsDroppedDelta := stateTelemetry.cassandraStatsDropped.Load() - ns.lastTelemetry.cassandraStatsDropped
 	dnsPidCollisionsDelta := stateTelemetry.dnsPidCollisions.Load() - ns.lastTelemetry.dnsPidCollisions
 
 	// Flush log line if any metric is non-zero
 	if connDroppedDelta > 0 || closedConnDroppedDelta > 0 || dnsStatsDroppedDelta > 0 || httpStatsDroppedDelta > 0 ||
-		http2StatsDroppedDelta > 0 || kafkaStatsDroppedDelta > 0 || postgresStatsDroppedDelta > 0 || redisStatsDroppedDelta > 0 {
+		http2StatsDroppedDelta > 0 || kafkaStatsDroppedDelta > 0 || postgresStatsDroppedDelta > 0 || redisStatsDroppedDelta > 0 ||
+		memcachedStatsDroppedDelta > 0 || cassandraStatsDroppedDelta > 0 {
 		s := "State telemetry: "
 		s += " [%d connections dropped due to stats]"
 		s += " [%d closed connections dropped]"
@@ -502,6 +531,8 @@ func (ns *networkState) logTelemetry() {
 		s += " [%d Kafka stats dropped]"
 		s += " [%d postgres stats dropped]"
 		s += " [%d redis stats dropped]"
+		s += " [%d memcached stats dropped]"
+		s += " [%d cassandra stats dropped]"
 		log.Warnf(s,
 			connDroppedDelta,
 			closedConnDroppedDelta,
@@ -511,6 +542,8 @@ func (ns *networkState) logTelemetry() {
 			kafkaStatsDroppedDelta,
 			postgresStatsDroppedDelta,
 			redisStatsDroppedDelta,
+			memcachedStatsDroppedDelta,
+			cassandraStatsDroppedDelta,
 		)
 	}
 
@@ -541,6 +574,8 @@ func (ns *networkState) logTelemetry() {
 	ns.lastTelemetry.kafkaStatsDropped = stateTelemetry.kafkaStatsDropped.Load()
 	ns.lastTelemetry.postgresStatsDropped = stateTelemetry.postgresStatsDropped.Load()
 	ns.lastTelemetry.redisStatsDropped = stateTelemetry.redisStatsDropped.Load()
+	ns.lastTelemetry.memcachedStatsDropped = stateTelemetry.memcachedStatsDropped.Load()
+	ns.lastTelemetry.cassandraStatsDropped = stateTelemetry.cassandraStatsDropped.Load()
 	ns.lastTelemetry.dnsPidCollisions = stateTelemetry.dnsPidCollisions.Load()
 }
 
@@ -843,22 +878,86 @@ func (ns *networkState) storeRedisStats(allStats map[redis.Key]*redis.RequestSta
 	}
 }
 
+// storeMemcachedStats stores the latest Memcached stats for all clients
+func (ns *networkState) storeMemcachedStats(allStats map[memcached.Key]*memcached.RequestStat) {
+	if len(ns.clients) == 1 {
+		for _, client := range ns.clients {
+			if len(client.memcachedStatsDelta) == 0 && len(allStats) <= ns.maxMemcachedStats {
+				// optimization for the common case:
+				// if there is only one client and no previous state, no memory allocation is needed
+				client.memcachedStatsDelta = allStats
+				return
+			}
+		}
+	}
+
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.memcachedStatsDelta[key]
+			if !ok && len(client.memcachedStatsDelta) >= ns.maxMemcachedStats {
+				stateTelemetry.memcachedStatsDropped.Inc()
+				continue
+			}
+
+			if prevStats != nil {
+				prevStats.CombineWith(stats)
+				client.memcachedStatsDelta[key] = prevStats
+			} else {
+				client.memcachedStatsDelta[key] = stats
+			}
+		}
+	}
+}
+
+// storeCassandraStats stores the latest Cassandra stats for all clients
+func (ns *networkState) storeCassandraStats(allStats map[cassandra.Key]*cassandra.RequestStat) {
+	if len(ns.clients) == 1 {
+		for _, client := range ns.clients {
+			if len(client.cassandraStatsDelta) == 0 && len(allStats) <= ns.maxCassandraStats {
+				// optimization for the common case:
+				// if there is only one client and no previous state, no memory allocation is needed
+				client.cassandraStatsDelta = allStats
+				return
+			}
+		}
+	}
+
+	for key, stats := range allStats {
+		for _, client := range ns.clients {
+			prevStats, ok := client.cassandraStatsDelta[key]
+			if !ok && len(client.cassandraStatsDelta) >= ns.maxCassandraStats {
+				stateTelemetry.cassandraStatsDropped.Inc()
+				continue
+			}
+
+			if prevStats != nil {
+				prevStats.CombineWith(stats)
+				client.cassandraStatsDelta[key] = prevStats
+			} else {
+				client.cassandraStatsDelta[key] = stats
+			}
+		}
+	}
+}
+
 func (ns *networkState) getClient(clientID string) *client {
 	if c, ok := ns.clients[clientID]; ok {
 		return c
 	}
 	closedConnections := &closedConnections{conns: make([]ConnectionStats, 0, minClosedCapacity), byCookie: make(map[StatCookie]int)}
 	c := &client{
-		lastFetch:          time.Now(),
-		stats:              make(map[StatCookie]StatCounters),
-		closed:             closedConnections,
-		dnsStats:           dns.StatsByKeyByNameByType{},
-		httpStatsDelta:     map[http.Key]*http.RequestStats{},
-		http2StatsDelta:    map[http.Key]*http.RequestStats{},
-		kafkaStatsDelta:    map[kafka.Key]*kafka.RequestStats{},
-		postgresStatsDelta: map[postgres.Key]*postgres.RequestStat{},
-		redisStatsDelta:    map[redis.Key]*redis.RequestStat{},
-		lastTelemetries:    make(map[ConnTelemetryType]int64),
+		lastFetch:           time.Now(),
+		stats:               make(map[StatCookie]StatCounters),
+		closed:              closedConnections,
+		dnsStats:            dns.StatsByKeyByNameByType{},
+		httpStatsDelta:      map[http.Key]*http.RequestStats{},
+		http2StatsDelta:     map[http.Key]*http.RequestStats{},
+		kafkaStatsDelta:     map[kafka.Key]*kafka.RequestStats{},
+		postgresStatsDelta:  map[postgres.Key]*postgres.RequestStat{},
+		redisStatsDelta:     map[redis.Key]*redis.RequestStat{},
+		memcachedStatsDelta: map[memcached.Key]*memcached.RequestStat{},
+		cassandraStatsDelta: map[cassandra.Key]*cassandra.RequestStat{},
+		lastTelemetries:     make(map[ConnTelemetryType]int64),
 	}
 	ns.clients[clientID] = c
 	return c
@@ -24,6 +24,10 @@ const (
 	ConnTagIstio = http.Istio
 	// ConnTagNodeJS is the tag for NodeJS TLS connections
 	ConnTagNodeJS = http.NodeJS
+	// ConnTagBoringSSL is the tag for statically linked BoringSSL connections
+	ConnTagBoringSSL = http.BoringSSL
+	// ConnTagRustls is the tag for rustls connections
+	ConnTagRustls = http.Rustls
 )
 
 // GetStaticTags return the string list of static tags from network.ConnectionStats.Tags
@@ -38,5 +42,5 @@ func GetStaticTags(staticTags uint64) (tags []string) {
 
 // IsTLSTag return if the tag is a TLS tag
 func IsTLSTag(staticTags uint64) bool {
-	return staticTags&(ConnTagGnuTLS|ConnTagOpenSSL|ConnTagGo|ConnTagTLS|ConnTagIstio|ConnTagNodeJS) > 0
+	return staticTags&(ConnTagGnuTLS|ConnTagOpenSSL|ConnTagGo|ConnTagTLS|ConnTagIstio|ConnTagNodeJS|ConnTagBoringSSL|ConnTagRustls) > 0
 }
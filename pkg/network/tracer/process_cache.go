@@ -57,6 +57,10 @@ type processCache struct {
 	in      chan *events.Process
 	stopped chan struct{}
 	stop    sync.Once
+
+	// keepCmdlines keeps the processes without tags nor container, whose
+	// command line is needed to exclude their connections
+	keepCmdlines bool
 }
 
 type processCacheKey struct {
@@ -125,7 +129,7 @@ func (pc *processCache) HandleProcessEvent(entry *events.Process) {
 }
 
 func (pc *processCache) processEvent(entry *events.Process) *events.Process {
-	if len(entry.Tags) == 0 && entry.ContainerID == nil {
+	if len(entry.Tags) == 0 && entry.ContainerID == nil && (!pc.keepCmdlines || entry.Cmdline == nil) {
 		return nil
 	}
 
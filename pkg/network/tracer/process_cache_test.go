@@ -63,6 +63,19 @@ func TestProcessCacheProcessEvent(t *testing.T) {
 		testFunc(t, t.Name(), &entry)
 	})
 
+	t.Run("without container id, with command line", func(t *testing.T) {
+		entry := events.Process{Pid: 1234, Cmdline: intern.GetByString("/usr/bin/backup")}
+
+		pc, err := newProcessCache(10)
+		require.NoError(t, err)
+		t.Cleanup(pc.Stop)
+		assert.Nil(t, pc.processEvent(&entry))
+
+		// the command line is kept when the connections are excluded by process
+		pc.keepCmdlines = true
+		assert.Equal(t, &entry, pc.processEvent(&entry))
+	})
+
 	t.Run("empty container id", func(t *testing.T) {
 		entry := events.Process{
 			Pid:         1234,
@@ -63,12 +63,14 @@ const tracerModuleName = "network_tracer"
 // to determine whether a connection is truly closed or not
 var tracerTelemetry = struct {
 	skippedConns         telemetry.Counter
+	excludedConns        telemetry.Counter
 	expiredTCPConns      telemetry.Counter
 	closedConns          *telemetry.StatCounterWrapper
 	connStatsMapSize     telemetry.Gauge
 	payloadSizePerClient telemetry.Gauge
 }{
 	telemetry.NewCounter(tracerModuleName, "skipped_conns", []string{"ip_proto"}, "Counter measuring skipped connections"),
+	telemetry.NewCounter(tracerModuleName, "excluded_conns", []string{"ip_proto"}, "Counter measuring connections excluded by their process, container or namespace"),
 	telemetry.NewCounter(tracerModuleName, "expired_tcp_conns", []string{}, "Counter measuring expired TCP connections"),
 	telemetry.NewStatCounterWrapper(tracerModuleName, "closed_conns", []string{"ip_proto"}, "Counter measuring closed TCP connections"),
 	telemetry.NewGauge(tracerModuleName, "conn_stats_map_size", []string{}, "Gauge measuring the size of the active connections map"),
@@ -90,6 +92,8 @@ type Tracer struct {
 	// Connections for the tracer to exclude
 	sourceExcludes []*network.ConnectionFilter
 	destExcludes   []*network.ConnectionFilter
+	// processExcludes is applied once the process of the connections is resolved from the process cache
+	processExcludes *network.ProcessFilter
 
 	gwLookup network.GatewayLookup
 
@@ -182,10 +186,13 @@ func newTracer(cfg *config.Config, telemetryComponent telemetryComponent.Compone
 	tr.reverseDNS = newReverseDNS(cfg, telemetryComponent)
 	tr.usmMonitor = newUSMMonitor(cfg, tr.ebpfTracer)
 
+	processExcludes := network.ParseProcessFilter(cfg.ExcludedProcessCmdlines, cfg.ExcludedContainerImages, cfg.ExcludedKubeNamespaces)
 	if cfg.EnableProcessEventMonitoring {
 		if tr.processCache, err = newProcessCache(cfg.MaxProcessesTracked); err != nil {
 			return nil, fmt.Errorf("could not create process cache; %w", err)
 		}
+		// the host processes are only cached for their command line when connections are excluded by process
+		tr.processCache.keepCmdlines = processExcludes != nil
 		telemetry.GetCompatComponent().RegisterCollector(tr.processCache)
 
 		if tr.timeResolver, err = timeresolver.NewResolver(); err != nil {
@@ -201,6 +208,14 @@ func newTracer(cfg *config.Config, telemetryComponent telemetryComponent.Compone
 
 	tr.sourceExcludes = network.ParseConnectionFilters(cfg.ExcludedSourceConnections)
 	tr.destExcludes = network.ParseConnectionFilters(cfg.ExcludedDestinationConnections)
+	if processExcludes != nil {
+		if tr.processCache == nil {
+			log.Warn("process event monitoring is disabled, connections won't be excluded by process command line, container image or Kubernetes namespace")
+		} else {
+			tr.processExcludes = processExcludes
+			events.CollectProcessDetails()
+		}
+	}
 	tr.state = network.NewState(
 		telemetryComponent,
 		cfg.ClientStateExpiry,
@@ -211,6 +226,8 @@ func newTracer(cfg *config.Config, telemetryComponent telemetryComponent.Compone
 		cfg.MaxKafkaStatsBuffered,
 		cfg.MaxPostgresStatsBuffered,
 		cfg.MaxRedisStatsBuffered,
+		cfg.MaxMemcachedStatsBuffered,
+		cfg.MaxCassandraStatsBuffered,
 		cfg.EnableNPMConnectionRollup,
 		cfg.EnableProcessEventMonitoring,
 	)
@@ -310,7 +327,10 @@ func (t *Tracer) storeClosedConnection(cs *network.ConnectionStats) {
 		t.conntracker.DeleteTranslation(cs)
 	}
 
-	t.addProcessInfo(cs)
+	if !t.addProcessInfo(cs) {
+		tracerTelemetry.excludedConns.IncWithTags(cs.Type.Tags())
+		return
+	}
 
 	tracerTelemetry.closedConns.IncWithTags(cs.Type.Tags())
 	t.ebpfTracer.GetFailedConnections().MatchFailedConn(cs)
@@ -318,9 +338,11 @@ func (t *Tracer) storeClosedConnection(cs *network.ConnectionStats) {
 	t.state.StoreClosedConnection(cs)
 }
 
-func (t *Tracer) addProcessInfo(c *network.ConnectionStats) {
+// addProcessInfo adds the process tags and container ID to a connection, it returns
+// false if the connection is excluded by its process
+func (t *Tracer) addProcessInfo(c *network.ConnectionStats) bool {
 	if t.processCache == nil {
-		return
+		return true
 	}
 
 	c.ContainerID.Source, c.ContainerID.Dest = nil, nil
@@ -328,7 +350,7 @@ func (t *Tracer) addProcessInfo(c *network.ConnectionStats) {
 	ts := t.timeResolver.ResolveMonotonicTimestamp(c.LastUpdateEpoch)
 	p, ok := t.processCache.Get(c.Pid, ts.UnixNano())
 	if !ok {
-		return
+		return true
 	}
 
 	if log.ShouldLog(seelog.TraceLvl) {
@@ -343,6 +365,26 @@ func (t *Tracer) addProcessInfo(c *network.ConnectionStats) {
 	if p.ContainerID != nil {
 		c.ContainerID.Source = p.ContainerID
 	}
+
+	return !t.isExcludedProcess(p)
+}
+
+func (t *Tracer) isExcludedProcess(p *events.Process) bool {
+	if t.processExcludes == nil {
+		return false
+	}
+	var cmdline string
+	if p.Cmdline != nil {
+		cmdline = p.Cmdline.Get().(string)
+	}
+	var containerTags []string
+	if len(p.ContainerTags) > 0 {
+		containerTags = make([]string, 0, len(p.ContainerTags))
+		for _, tag := range p.ContainerTags {
+			containerTags = append(containerTags, tag.Get().(string))
+		}
+	}
+	return t.processExcludes.IsExcluded(cmdline, containerTags)
 }
 
 // Pause bypasses the eBPF programs
@@ -435,6 +477,8 @@ func (t *Tracer) GetActiveConnections(clientID string) (*network.Connections, er
 	conns.Kafka = delta.Kafka
 	conns.Postgres = delta.Postgres
 	conns.Redis = delta.Redis
+	conns.Memcached = delta.Memcached
+	conns.Cassandra = delta.Cassandra
 	conns.ConnTelemetry = t.state.GetTelemetryDelta(clientID, t.getConnTelemetry(len(active)))
 	conns.CompilationTelemetryByAsset = t.getRuntimeCompilationTelemetry()
 	conns.KernelHeaderFetchResult = int32(kernel.HeaderProvider.GetResult())
@@ -536,6 +580,10 @@ func (t *Tracer) getConnections(activeBuffer *network.ConnectionBuffer) (latestU
 	}
 
 	activeConnections = activeBuffer.Connections()
+	// the excluded connections are still in the eBPF map, they are counted in its size
+	entryCount := len(activeConnections)
+	// connections excluded by their process are removed in place
+	included := activeConnections[:0]
 	for i := range activeConnections {
 		activeConnections[i].IPTranslation = t.conntracker.GetTranslationForConn(&activeConnections[i])
 		// do gateway resolution only on active connections outside
@@ -544,8 +592,13 @@ func (t *Tracer) getConnections(activeBuffer *network.ConnectionBuffer) (latestU
 		// since gateway resolution connects to the ec2 metadata
 		// endpoint)
 		t.connVia(&activeConnections[i])
-		t.addProcessInfo(&activeConnections[i])
+		if !t.addProcessInfo(&activeConnections[i]) {
+			tracerTelemetry.excludedConns.IncWithTags(activeConnections[i].Type.Tags())
+			continue
+		}
+		included = append(included, activeConnections[i])
 	}
+	activeConnections = included
 
 	// get rid of stale process entries in the cache
 	t.processCache.Trim()
@@ -553,7 +606,6 @@ func (t *Tracer) getConnections(activeBuffer *network.ConnectionBuffer) (latestU
 	// remove stale failed connections from map
 	t.ebpfTracer.GetFailedConnections().RemoveExpired()
 
-	entryCount := len(activeConnections)
 	if entryCount >= int(t.config.MaxTrackedConnections) {
 		log.Errorf("connection tracking map size has reached the limit of %d. Accurate connection count and data volume metrics will be affected. Increase config value `system_probe_config.max_tracked_connections` to correct this.", t.config.MaxTrackedConnections)
 	} else if (float64(entryCount) / float64(t.config.MaxTrackedConnections)) >= 0.9 {
@@ -766,6 +818,12 @@ func (t *Tracer) connVia(cs *network.ConnectionStats) {
 	cs.Via = t.gwLookup.Lookup(cs)
 }
 
+// GetDNSResolverStats returns the per-resolver DNS statistics and the top
+// failing domains accumulated since the last call
+func (t *Tracer) GetDNSResolverStats() dns.ResolverReport {
+	return t.reverseDNS.GetDNSResolverStats()
+}
+
 // DebugCachedConntrack dumps the cached NAT conntrack data
 func (t *Tracer) DebugCachedConntrack(ctx context.Context) (interface{}, error) {
 	ns, err := t.config.GetRootNetNs()
@@ -31,15 +31,18 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go4.org/intern"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/DataDog/datadog-agent/pkg/config/env"
 	"github.com/DataDog/datadog-agent/pkg/ebpf/ebpftest"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/events"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer/testutil"
 	"github.com/DataDog/datadog-agent/pkg/network/tracer/testutil/testdns"
 	"github.com/DataDog/datadog-agent/pkg/process/util"
+	timeresolver "github.com/DataDog/datadog-agent/pkg/security/resolvers/time"
 	"github.com/DataDog/datadog-agent/pkg/util/log"
 )
 
@@ -714,6 +717,33 @@ func TestSkipConnectionDNS(t *testing.T) {
 	})
 }
 
+func TestExcludeHostProcessByCmdline(t *testing.T) {
+	pc, err := newProcessCache(10)
+	require.NoError(t, err)
+	t.Cleanup(pc.Stop)
+	pc.keepCmdlines = true
+
+	resolver, err := timeresolver.NewResolver()
+	require.NoError(t, err)
+	tr := &Tracer{
+		processCache:    pc,
+		timeResolver:    resolver,
+		processExcludes: network.ParseProcessFilter([]string{"^/usr/bin/backup"}, nil, nil),
+	}
+
+	// host processes have neither tags nor container, they are cached for their command line
+	for pid, cmdline := range map[uint32]string{1: "/usr/bin/backup --all", 2: "/usr/sbin/nginx"} {
+		p := pc.processEvent(&events.Process{Pid: pid, Cmdline: intern.GetByString(cmdline)})
+		require.NotNil(t, p)
+		pc.add(p)
+	}
+
+	assert.False(t, tr.addProcessInfo(&network.ConnectionStats{Pid: 1}))
+	assert.True(t, tr.addProcessInfo(&network.ConnectionStats{Pid: 2}))
+	// processes which are not in the cache are never excluded
+	assert.True(t, tr.addProcessInfo(&network.ConnectionStats{Pid: 3}))
+}
+
 func findConnection(l, r net.Addr, c *network.Connections) (*network.ConnectionStats, bool) {
 	res := network.FirstConnection(c, network.ByTuple(l, r))
 	return res, res != nil
@@ -16,6 +16,7 @@ import (
 	"github.com/DataDog/datadog-agent/pkg/ebpf"
 	"github.com/DataDog/datadog-agent/pkg/network"
 	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/dns"
 )
 
 // Tracer is not implemented
@@ -64,6 +65,11 @@ func (t *Tracer) DebugEBPFMaps(_ io.Writer, _ ...string) error {
 	return ebpf.ErrNotImplemented
 }
 
+// GetDNSResolverStats is not implemented on this OS for Tracer
+func (t *Tracer) GetDNSResolverStats() dns.ResolverReport {
+	return dns.ResolverReport{}
+}
+
 // DebugCachedConntrack is not implemented on this OS for Tracer
 func (t *Tracer) DebugCachedConntrack(context.Context) (interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
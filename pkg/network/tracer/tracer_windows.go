@@ -90,6 +90,8 @@ func NewTracer(config *config.Config, telemetry telemetry.Component) (*Tracer, e
 		config.MaxKafkaStatsBuffered,
 		config.MaxPostgresStatsBuffered,
 		config.MaxRedisStatsBuffered,
+		config.MaxMemcachedStatsBuffered,
+		config.MaxCassandraStatsBuffered,
 		config.EnableNPMConnectionRollup,
 		config.EnableProcessEventMonitoring,
 	)
@@ -294,6 +296,12 @@ func (t *Tracer) DebugEBPFMaps(_ io.Writer, _ ...string) error {
 	return ebpf.ErrNotImplemented
 }
 
+// GetDNSResolverStats returns the per-resolver DNS statistics and the top
+// failing domains accumulated since the last call
+func (t *Tracer) GetDNSResolverStats() dns.ResolverReport {
+	return t.reverseDNS.GetDNSResolverStats()
+}
+
 // DebugCachedConntrack is not implemented on this OS for Tracer
 func (t *Tracer) DebugCachedConntrack(_ context.Context) (interface{}, error) {
 	return nil, ebpf.ErrNotImplemented
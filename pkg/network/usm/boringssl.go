@@ -0,0 +1,195 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package usm
+
+import (
+	"debug/elf"
+	"fmt"
+	"strings"
+
+	manager "github.com/DataDog/ebpf-manager"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf/uprobes"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/go/bininspect"
+	"github.com/DataDog/datadog-agent/pkg/util/common"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	boringSSLReadRetprobe  = "boringssl_uretprobe__SSL_read"
+	boringSSLWriteRetprobe = "boringssl_uretprobe__SSL_write"
+
+	// boringSSLSymbolPrefix is shared by symbols that only exist in BoringSSL
+	// (e.g. BORINGSSL_self_test), and allows telling it apart from OpenSSL,
+	// which exposes the same SSL_* API.
+	boringSSLSymbolPrefix    = "BORINGSSL_"
+	boringSSLSymbolMaxLength = 64
+
+	boringSSLAttacherName = "boringssl"
+)
+
+var (
+	boringSSLProbes = []manager.ProbesSelector{
+		&manager.AllOf{
+			Selectors: []manager.ProbesSelector{
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: sslDoHandshakeProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: sslDoHandshakeRetprobe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: sslReadProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: boringSSLReadRetprobe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: sslWriteProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: boringSSLWriteRetprobe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: sslShutdownProbe,
+					},
+				},
+			},
+		},
+		// The socket setup functions are frequently inlined or discarded by the
+		// linker in statically linked binaries. When that happens we rely on the
+		// tcp_sendmsg based fallback to map the SSL context to a connection.
+		&manager.BestEffort{
+			Selectors: []manager.ProbesSelector{
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: sslSetBioProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: sslSetFDProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: bioNewSocketProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: bioNewSocketRetprobe,
+					},
+				},
+			},
+		},
+	}
+)
+
+// boringSSLMonitor scans for executables statically linked against BoringSSL,
+// such as Envoy based gateways, and attaches SSL uprobes to them.
+type boringSSLMonitor struct {
+	attacher *uprobes.UprobeAttacher
+}
+
+func newBoringSSLMonitor(c *config.Config, mgr *manager.Manager) (*boringSSLMonitor, error) {
+	if !c.EnableBoringSSLMonitoring {
+		return nil, nil
+	}
+
+	procRoot := kernel.ProcFSRoot()
+	filter := newStaticTLSExecutableFilter(procRoot, isStaticBoringSSLBinary)
+
+	executableFilter := filter.Filter
+	if c.EnableIstioMonitoring {
+		// Envoy processes are already handled by the istio monitor, attaching
+		// to them twice would report every request twice.
+		executableFilter = func(path string, procInfo *uprobes.ProcInfo) bool {
+			return !isEnvoyBinary(path, c.EnvoyPath) && filter.Filter(path, procInfo)
+		}
+	}
+
+	attachCfg := uprobes.AttacherConfig{
+		ProcRoot: procRoot,
+		Rules: []*uprobes.AttachRule{{
+			Targets:          uprobes.AttachToExecutable,
+			ProbesSelector:   boringSSLProbes,
+			ExecutableFilter: executableFilter,
+		}},
+		EbpfConfig:                     &c.Config,
+		ExcludeTargets:                 uprobes.ExcludeSelf | uprobes.ExcludeInternal | uprobes.ExcludeBuildkit | uprobes.ExcludeContainerdTmp,
+		EnablePeriodicScanNewProcesses: true,
+	}
+
+	attacher, err := uprobes.NewUprobeAttacher(boringSSLAttacherName, attachCfg, mgr, uprobes.NopOnAttachCallback, &uprobes.NativeBinaryInspector{})
+	if err != nil {
+		return nil, fmt.Errorf("cannot create uprobe attacher: %w", err)
+	}
+
+	return &boringSSLMonitor{
+		attacher: attacher,
+	}, nil
+}
+
+// Start the boringSSLMonitor
+func (m *boringSSLMonitor) Start() {
+	if m == nil {
+		return
+	}
+
+	m.attacher.Start()
+	log.Info("BoringSSL TLS monitoring enabled")
+}
+
+// Stop the boringSSLMonitor.
+func (m *boringSSLMonitor) Stop() {
+	if m == nil {
+		return
+	}
+
+	m.attacher.Stop()
+}
+
+// isEnvoyBinary mirrors the check done by the istio monitor.
+func isEnvoyBinary(path, envoyCmd string) bool {
+	return envoyCmd != "" && strings.Contains(path, envoyCmd)
+}
+
+// isStaticBoringSSLBinary returns true if the ELF file implements the SSL_read
+// and SSL_write functions itself and carries BoringSSL specific symbols.
+// Executables importing those functions from a shared library are handled by
+// the shared library watcher instead.
+func isStaticBoringSSLBinary(elfFile *elf.File) bool {
+	symbols, err := bininspect.GetAllSymbolsInSetByName(elfFile, common.NewStringSet("SSL_read", "SSL_write"))
+	if err != nil {
+		return false
+	}
+	for _, sym := range symbols {
+		if !isDefinedSymbol(sym) {
+			return false
+		}
+	}
+
+	marker, err := bininspect.GetAnySymbolWithPrefix(elfFile, boringSSLSymbolPrefix, boringSSLSymbolMaxLength)
+	return err == nil && isDefinedSymbol(*marker)
+}
@@ -273,6 +273,9 @@ var opensslSpec = &protocols.ProtocolSpec{
 		{
 			Name: "ssl_ctx_by_pid_tgid",
 		},
+		{
+			Name: rustlsOffsetsDataMap,
+		},
 	},
 	Probes: []*manager.Probe{
 		{
@@ -419,15 +422,18 @@ var opensslSpec = &protocols.ProtocolSpec{
 }
 
 type sslProgram struct {
-	cfg           *config.Config
-	watcher       *sharedlibraries.Watcher
-	istioMonitor  *istioMonitor
-	nodeJSMonitor *nodeJSMonitor
+	cfg              *config.Config
+	watcher          *sharedlibraries.Watcher
+	istioMonitor     *istioMonitor
+	nodeJSMonitor    *nodeJSMonitor
+	boringSSLMonitor *boringSSLMonitor
+	rustlsMonitor    *rustlsMonitor
 }
 
 func newSSLProgramProtocolFactory(m *manager.Manager) protocols.ProtocolFactory {
 	return func(c *config.Config) (protocols.Protocol, error) {
-		if (!c.EnableNativeTLSMonitoring || !usmconfig.TLSSupported(c)) && !c.EnableIstioMonitoring && !c.EnableNodeJSMonitoring {
+		if (!c.EnableNativeTLSMonitoring || !usmconfig.TLSSupported(c)) && !c.EnableIstioMonitoring && !c.EnableNodeJSMonitoring &&
+			!c.EnableBoringSSLMonitoring && !c.EnableRustlsMonitoring {
 			return nil, nil
 		}
 
@@ -466,11 +472,23 @@ func newSSLProgramProtocolFactory(m *manager.Manager) protocols.ProtocolFactory
 			return nil, fmt.Errorf("error initializing nodejs monitor: %w", err)
 		}
 
+		boringSSL, err := newBoringSSLMonitor(c, m)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing boringssl monitor: %w", err)
+		}
+
+		rustls, err := newRustlsMonitor(c, m)
+		if err != nil {
+			return nil, fmt.Errorf("error initializing rustls monitor: %w", err)
+		}
+
 		return &sslProgram{
-			cfg:           c,
-			watcher:       watcher,
-			istioMonitor:  newIstioMonitor(c, m),
-			nodeJSMonitor: nodejs,
+			cfg:              c,
+			watcher:          watcher,
+			istioMonitor:     newIstioMonitor(c, m),
+			nodeJSMonitor:    nodejs,
+			boringSSLMonitor: boringSSL,
+			rustlsMonitor:    rustls,
 		}, nil
 	}
 }
@@ -493,6 +511,8 @@ func (o *sslProgram) PreStart(*manager.Manager) error {
 	o.watcher.Start()
 	o.istioMonitor.Start()
 	o.nodeJSMonitor.Start()
+	o.boringSSLMonitor.Start()
+	o.rustlsMonitor.Start()
 	return nil
 }
 
@@ -506,6 +526,8 @@ func (o *sslProgram) Stop(*manager.Manager) {
 	o.watcher.Stop()
 	o.istioMonitor.Stop()
 	o.nodeJSMonitor.Stop()
+	o.boringSSLMonitor.Stop()
+	o.rustlsMonitor.Stop()
 }
 
 // DumpMaps dumps the content of the map represented by mapName & currentMap, if it used by the eBPF program, to output.
@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"math/rand"
+	"net"
 	nethttp "net/http"
 	"os"
 	"os/exec"
@@ -944,3 +945,119 @@ func (s *tlsSuite) TestNodeJSTLS() {
 		}
 	}
 }
+
+func (s *tlsSuite) TestRustlsTLS() {
+	t := s.T()
+
+	cert, key := staticTLSCertsPaths(t)
+	for _, v0Mangling := range []bool{false, true} {
+		t.Run(fmt.Sprintf("v0 mangling %t", v0Mangling), func(t *testing.T) {
+			const serverAddr = "127.0.0.1:4445"
+			serverBin := buildRustlsBinary(t, v0Mangling)
+			build, err := getRustlsBuild(openELF(t, serverBin))
+			require.NoError(t, err)
+			if _, ok := rustlsLayouts[build]; !ok {
+				t.Skipf("%s is not supported", build)
+			}
+
+			cfg := config.New()
+			cfg.EnableHTTPMonitoring = true
+			cfg.EnableRustlsMonitoring = true
+			testStaticTLSServer(t, cfg, rustlsAttacherName, []string{serverBin, "server", serverAddr, cert, key}, serverAddr, network.ConnTagRustls)
+		})
+	}
+}
+
+func (s *tlsSuite) TestBoringSSLTLS() {
+	t := s.T()
+
+	const (
+		serverPort = "4446"
+		serverAddr = "127.0.0.1:" + serverPort
+	)
+	curDir, err := testutil.CurDir()
+	require.NoError(t, err)
+	dir := filepath.Join(curDir, "testdata", "statictls", "boringssl")
+	serverBin, err := usmtestutil.BuildBinaryWithDocker(dir, "boringssl-server", filepath.Join(dir, "boringssl-server"))
+	require.NoError(t, err)
+	require.True(t, isStaticBoringSSLBinary(openELF(t, serverBin)))
+
+	cert, key := staticTLSCertsPaths(t)
+	cfg := config.New()
+	cfg.EnableHTTPMonitoring = true
+	cfg.EnableBoringSSLMonitoring = true
+	testStaticTLSServer(t, cfg, boringSSLAttacherName, []string{serverBin, serverPort, cert, key}, serverAddr, network.ConnTagBoringSSL)
+}
+
+// staticTLSCertsPaths returns the certificate and key of the servers of
+// testdata/statictls, which unlike the certificates of the other tests are
+// accepted by rustls.
+func staticTLSCertsPaths(t *testing.T) (string, string) {
+	curDir, err := testutil.CurDir()
+	require.NoError(t, err)
+	dir := filepath.Join(curDir, "testdata", "statictls", "certs")
+	return filepath.Join(dir, "server.pem"), filepath.Join(dir, "server.key")
+}
+
+// testStaticTLSServer runs an HTTPS server statically embedding its TLS
+// library, and checks that the requests it serves are captured with the given
+// tag.
+func testStaticTLSServer(t *testing.T, cfg *config.Config, attacherName string, serverCmd []string, serverAddr string, expectedTag uint64) {
+	const expectedOccurrences = 10
+
+	usmMonitor := setupUSMTLSMonitor(t, cfg)
+
+	server := exec.Command(serverCmd[0], serverCmd[1:]...)
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		_ = server.Process.Kill()
+		_ = server.Wait()
+	})
+	utils.WaitForProgramsToBeTraced(t, attacherName, server.Process.Pid, utils.ManualTracingFallbackEnabled)
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", serverAddr)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}, 5*time.Second, 100*time.Millisecond, "server not listening")
+
+	client, requestFn := simpleGetRequestsGenerator(t, serverAddr)
+	var requests []*nethttp.Request
+	for i := 0; i < expectedOccurrences; i++ {
+		requests = append(requests, requestFn())
+	}
+	client.CloseIdleConnections()
+
+	assert.Eventually(t, func() bool {
+		stats := getHTTPLikeProtocolStats(usmMonitor, protocols.HTTP)
+		for _, req := range requests {
+			if !isRequestIncludedWithTag(stats, req, expectedTag) {
+				t.Logf("request %v was not found", req.URL)
+				return false
+			}
+		}
+		return true
+	}, 3*time.Second, 100*time.Millisecond, "connection not found")
+
+	if t.Failed() {
+		ebpftest.DumpMapsTestHelper(t, usmMonitor.DumpMaps, "http_in_flight", "ssl_sock_by_ctx")
+	}
+}
+
+// isRequestIncludedWithTag returns true if the request was captured with the
+// given static tag.
+func isRequestIncludedWithTag(allStats map[http.Key]*http.RequestStats, req *nethttp.Request, tag uint64) bool {
+	expectedStatus := testutil.StatusFromPath(req.URL.Path)
+	for key, stats := range allStats {
+		if key.Path.Content.Get() != req.URL.Path {
+			continue
+		}
+		if requests, exists := stats.Data[expectedStatus]; exists && requests.Count > 0 && requests.StaticTags&tag != 0 {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,461 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package usm
+
+import (
+	"debug/elf"
+	"errors"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+
+	manager "github.com/DataDog/ebpf-manager"
+	"github.com/cilium/ebpf"
+	"golang.org/x/sys/unix"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf/uprobes"
+	"github.com/DataDog/datadog-agent/pkg/network/config"
+	"github.com/DataDog/datadog-agent/pkg/network/go/bininspect"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/gotls"
+	rustlsgen "github.com/DataDog/datadog-agent/pkg/network/protocols/tls/rustls"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/utils"
+	"github.com/DataDog/datadog-agent/pkg/util/kernel"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	rustlsReaderReadProbe     = "uprobe__rustls_reader_read"
+	rustlsReaderReadRetprobe  = "uretprobe__rustls_reader_read"
+	rustlsWriterWriteProbe    = "uprobe__rustls_writer_write"
+	rustlsWriterWriteRetprobe = "uretprobe__rustls_writer_write"
+	rustlsWriteTLSProbe       = "uprobe__rustls_write_tls"
+	rustlsWriteTLSRetprobe    = "uretprobe__rustls_write_tls"
+
+	// Logical symbol names, as parsed from the probe names by the attacher.
+	// They are translated to the actual mangled symbols by rustlsBinaryInspector.
+	rustlsReaderReadSymbol  = "rustls_reader_read"
+	rustlsWriterWriteSymbol = "rustls_writer_write"
+	rustlsWriteTLSSymbol    = "rustls_write_tls"
+
+	// rustHashSuffixLength is the length of the "17h<16 hex digits>E" suffix
+	// appended by the legacy Rust mangling scheme.
+	rustHashSuffixLength = len("17h") + 16 + len("E")
+	// rustV0SymbolMaxLength bounds the length of the v0 mangled symbols we
+	// look for, whose crate disambiguators have no fixed length.
+	rustV0SymbolMaxLength = 256
+
+	// Building blocks of the v0 mangled symbols: an optional disambiguator, a
+	// crate root with its disambiguator, and a back reference to a path
+	// already mangled in the same symbol.
+	rustV0Disambiguator = `(?:s[0-9A-Za-z]*_)?`
+	rustV0CrateRoot     = `C` + rustV0Disambiguator
+	rustV0BackRef       = `B[0-9A-Za-z]*_`
+
+	rustlsOffsetsDataMap = "rustls_offsets_data"
+
+	rustlsAttacherName = "rustls"
+)
+
+var (
+	rustlsProbes = []manager.ProbesSelector{
+		&manager.AllOf{
+			Selectors: []manager.ProbesSelector{
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: rustlsReaderReadProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: rustlsReaderReadRetprobe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: rustlsWriterWriteProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: rustlsWriterWriteRetprobe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: rustlsWriteTLSProbe,
+					},
+				},
+				&manager.ProbeSelector{
+					ProbeIdentificationPair: manager.ProbeIdentificationPair{
+						EBPFFuncName: rustlsWriteTLSRetprobe,
+					},
+				},
+			},
+		},
+	}
+
+	// rustlsSymbols maps each logical symbol to the mangled names of the
+	// functions implementing it, with both the legacy and the v0 mangling
+	// schemes. rustls moved the Reader and Writer types from rustls::conn to
+	// rustls::conn::connection in 0.22, so both paths are looked up.
+	rustlsSymbols = map[string]rustSymbol{
+		rustlsReaderReadSymbol: {
+			legacyPrefixes: []string{
+				rustLegacyMangledPrefix("<rustls::conn::connection::Reader as std::io::Read>", "read"),
+				rustLegacyMangledPrefix("<rustls::conn::Reader as std::io::Read>", "read"),
+			},
+			v0: regexp.MustCompile(`^_RNvX` + rustV0Disambiguator + `(?:Nt)+` + rustV0CrateRoot + `6rustls4conn(?:10connection)?` +
+				`Nt(?:` + rustV0BackRef + `|(?:Nt)+` + rustV0CrateRoot + `6rustls4conn(?:10connection)?)6Reader` +
+				`NtNt` + rustV0CrateRoot + `3std2io4Read4read$`),
+		},
+		rustlsWriterWriteSymbol: {
+			legacyPrefixes: []string{
+				rustLegacyMangledPrefix("<rustls::conn::connection::Writer as std::io::Write>", "write"),
+				rustLegacyMangledPrefix("<rustls::conn::Writer as std::io::Write>", "write"),
+			},
+			v0: regexp.MustCompile(`^_RNvX` + rustV0Disambiguator + `(?:Nt)+` + rustV0CrateRoot + `6rustls4conn(?:10connection)?` +
+				`Nt(?:` + rustV0BackRef + `|(?:Nt)+` + rustV0CrateRoot + `6rustls4conn(?:10connection)?)6Writer` +
+				`NtNt` + rustV0CrateRoot + `3std2io5Write5write$`),
+		},
+		rustlsWriteTLSSymbol: {
+			legacyPrefixes: []string{
+				rustLegacyMangledPrefix("rustls", "vecbuf", "ChunkVecBuffer", "write_to"),
+			},
+			v0: regexp.MustCompile(`^_RNvM` + rustV0Disambiguator + `Nt` + rustV0CrateRoot + `6rustls6vecbuf` +
+				`Nt(?:` + rustV0BackRef + `|Nt` + rustV0CrateRoot + `6rustls6vecbuf)14ChunkVecBuffer8write_to$`),
+		},
+	}
+
+	// rustlsVersionRegexp matches the paths of the rustls sources embedded in
+	// the panic locations, e.g. ".cargo/registry/src/index.crates.io-<hash>/rustls-0.23.27/src/conn.rs".
+	rustlsVersionRegexp = regexp.MustCompile(`rustls-([0-9]+\.[0-9]+\.[0-9]+)[/\\]src[/\\]`)
+	// rustcVersionRegexp matches the compiler identification stored in the
+	// .comment section, e.g. "rustc version 1.90.0 (1159e78c4 2025-09-14)".
+	rustcVersionRegexp = regexp.MustCompile(`rustc version ([0-9]+\.[0-9]+)\.`)
+
+	// rustlsLayouts lists the builds of rustls we support, along with the
+	// layout of their types. The Rust compiler is free to reorder the fields
+	// of a struct, and the io::Result<usize> returned by the hooked functions
+	// has no guaranteed representation, so each entry was verified by
+	// disassembling the testdata/statictls/rustls program built with the
+	// given versions, for both the client and the server connections.
+	rustlsLayouts = map[rustlsBuild]rustlsgen.OffsetsData{
+		{rustlsVersion: "0.23.27", rustcVersion: "1.90", arch: bininspect.GoArchX86_64}: {
+			Reader_buffer_offset: 0x0,
+			Writer_conn_offset:   0x0,
+			Conn_buffer_offset:   0x50,
+			Sendable_tls_offset:  0x88,
+		},
+	}
+)
+
+// rustSymbol describes the mangled names of a Rust function.
+type rustSymbol struct {
+	// legacyPrefixes are the legacy mangled names of the function, without
+	// the trailing hash.
+	legacyPrefixes []string
+	// v0 matches the v0 mangled name of the function, whose crate
+	// disambiguators cannot be predicted.
+	v0 *regexp.Regexp
+}
+
+// rustLegacyMangledPrefix returns the legacy mangled name of the given path,
+// without the trailing hash. For instance, the trait implementation path
+// "<rustls::conn::Reader as std::io::Read>" and the method "read" become
+// "_ZN54_$LT$rustls..conn..Reader$u20$as$u20$std..io..Read$GT$4read".
+func rustLegacyMangledPrefix(path ...string) string {
+	replacer := strings.NewReplacer("<", "_$LT$", ">", "$GT$", " ", "$u20$", "::", "..")
+	var b strings.Builder
+	b.WriteString("_ZN")
+	for _, component := range path {
+		component = replacer.Replace(component)
+		b.WriteString(strconv.Itoa(len(component)))
+		b.WriteString(component)
+	}
+	return b.String()
+}
+
+// findRustlsSymbol looks for the implementation of the given logical symbol in
+// the ELF file.
+func findRustlsSymbol(elfFile *elf.File, name string) (*elf.Symbol, error) {
+	symbol, ok := rustlsSymbols[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown rustls symbol %q", name)
+	}
+
+	for _, prefix := range symbol.legacyPrefixes {
+		sym, err := bininspect.GetAnySymbolWithPrefix(elfFile, prefix, len(prefix)+rustHashSuffixLength)
+		if err == nil && isDefinedSymbol(*sym) {
+			return sym, nil
+		}
+	}
+	sym, err := bininspect.GetAnySymbolMatching(elfFile, symbol.v0, rustV0SymbolMaxLength)
+	if err == nil && isDefinedSymbol(*sym) {
+		return sym, nil
+	}
+	return nil, fmt.Errorf("rustls symbol %q not found", name)
+}
+
+// isRustlsBinary returns true if the ELF file contains the rustls plaintext
+// Reader implementation.
+func isRustlsBinary(elfFile *elf.File) bool {
+	_, err := findRustlsSymbol(elfFile, rustlsReaderReadSymbol)
+	return err == nil
+}
+
+// isSupportedRustlsBinary returns true if the ELF file embeds a build of
+// rustls whose layout is known.
+func isSupportedRustlsBinary(elfFile *elf.File) bool {
+	if !isRustlsBinary(elfFile) {
+		return false
+	}
+
+	if _, err := getRustlsLayout(elfFile); err != nil {
+		log.Debugf("not monitoring rustls binary: %s", err)
+		return false
+	}
+	return true
+}
+
+// rustlsBuild identifies the versions of rustls and of the Rust compiler
+// used to build a binary, which determine the layout of the rustls types.
+type rustlsBuild struct {
+	rustlsVersion string
+	// rustcVersion is the major.minor version of the compiler
+	rustcVersion string
+	arch         bininspect.GoArch
+}
+
+func (b rustlsBuild) String() string {
+	return fmt.Sprintf("rustls %s built with rustc %s for %s", b.rustlsVersion, b.rustcVersion, b.arch)
+}
+
+// getRustlsBuild detects the versions of rustls and of the Rust compiler used
+// to build the ELF file.
+func getRustlsBuild(elfFile *elf.File) (rustlsBuild, error) {
+	arch, err := bininspect.GetArchitecture(elfFile)
+	if err != nil {
+		return rustlsBuild{}, err
+	}
+
+	rodata := elfFile.Section(".rodata")
+	if rodata == nil {
+		return rustlsBuild{}, errors.New("no .rodata section")
+	}
+	data, err := rodata.Data()
+	if err != nil {
+		return rustlsBuild{}, fmt.Errorf("cannot read .rodata section: %w", err)
+	}
+	var rustlsVersion string
+	for _, match := range rustlsVersionRegexp.FindAllSubmatch(data, -1) {
+		version := string(match[1])
+		if rustlsVersion != "" && rustlsVersion != version {
+			return rustlsBuild{}, fmt.Errorf("several rustls versions found: %s and %s", rustlsVersion, version)
+		}
+		rustlsVersion = version
+	}
+	if rustlsVersion == "" {
+		return rustlsBuild{}, errors.New("rustls version not found")
+	}
+
+	comment := elfFile.Section(".comment")
+	if comment == nil {
+		return rustlsBuild{}, errors.New("no .comment section")
+	}
+	data, err = comment.Data()
+	if err != nil {
+		return rustlsBuild{}, fmt.Errorf("cannot read .comment section: %w", err)
+	}
+	match := rustcVersionRegexp.FindSubmatch(data)
+	if match == nil {
+		return rustlsBuild{}, errors.New("rustc version not found")
+	}
+
+	return rustlsBuild{
+		rustlsVersion: rustlsVersion,
+		rustcVersion:  string(match[1]),
+		arch:          arch,
+	}, nil
+}
+
+// getRustlsLayout returns the layout of the rustls types in the ELF file, if
+// its build is supported.
+func getRustlsLayout(elfFile *elf.File) (rustlsgen.OffsetsData, error) {
+	build, err := getRustlsBuild(elfFile)
+	if err != nil {
+		return rustlsgen.OffsetsData{}, fmt.Errorf("cannot detect the rustls build: %w", err)
+	}
+	layout, ok := rustlsLayouts[build]
+	if !ok {
+		return rustlsgen.OffsetsData{}, fmt.Errorf("unsupported build: %s", build)
+	}
+	return layout, nil
+}
+
+// inspectRustlsBinary resolves the requested rustls symbols and the layout of
+// the rustls types in the ELF file.
+func inspectRustlsBinary(elfFile *elf.File, requests []uprobes.SymbolRequest) (map[string]bininspect.FunctionMetadata, rustlsgen.OffsetsData, error) {
+	// Same restrictions as the native binary inspector: only 64-bit
+	// binaries of the host architecture are supported.
+	arch, err := bininspect.GetArchitecture(elfFile)
+	if err != nil {
+		return nil, rustlsgen.OffsetsData{}, fmt.Errorf("cannot get architecture: %w", err)
+	}
+	if string(arch) != runtime.GOARCH {
+		return nil, rustlsgen.OffsetsData{}, fmt.Errorf("architecture mismatch: %s != %s", arch, runtime.GOARCH)
+	}
+
+	layout, err := getRustlsLayout(elfFile)
+	if err != nil {
+		return nil, rustlsgen.OffsetsData{}, err
+	}
+
+	result := make(map[string]bininspect.FunctionMetadata, len(requests))
+	for _, req := range requests {
+		if req.IncludeReturnLocations {
+			return nil, rustlsgen.OffsetsData{}, errors.New("return locations are not supported by the rustls binary inspector")
+		}
+
+		sym, err := findRustlsSymbol(elfFile, req.Name)
+		if err != nil {
+			if req.BestEffort {
+				continue
+			}
+			return nil, rustlsgen.OffsetsData{}, err
+		}
+
+		manager.SanitizeUprobeAddresses(elfFile, []elf.Symbol{*sym})
+		offset, err := bininspect.SymbolToOffset(elfFile, *sym)
+		if err != nil {
+			return nil, rustlsgen.OffsetsData{}, err
+		}
+		result[req.Name] = bininspect.FunctionMetadata{EntryLocation: uint64(offset)}
+	}
+
+	return result, layout, nil
+}
+
+// rustlsBinaryInspector is a uprobes.BinaryInspector resolving the logical
+// rustls symbols to the offsets of their mangled implementations. It also
+// stores the layout of the rustls types of each binary in the
+// rustls_offsets_data map, for the eBPF programs.
+type rustlsBinaryInspector struct {
+	offsetsDataMap *ebpf.Map
+}
+
+// Ensure rustlsBinaryInspector implements BinaryInspector
+var _ uprobes.BinaryInspector = &rustlsBinaryInspector{}
+
+// Inspect resolves the requested rustls symbols in the ELF file at the given path.
+func (i *rustlsBinaryInspector) Inspect(fpath utils.FilePath, requests []uprobes.SymbolRequest) (map[string]bininspect.FunctionMetadata, error) {
+	path := fpath.HostPath
+	elfFile, err := elf.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer elfFile.Close()
+
+	result, layout, err := inspectRustlsBinary(elfFile, requests)
+	if err != nil {
+		return nil, fmt.Errorf("cannot inspect %s: %w", path, err)
+	}
+
+	key := rustlsBinaryKey(fpath.ID)
+	if err := i.offsetsDataMap.Put(unsafe.Pointer(&key), unsafe.Pointer(&layout)); err != nil {
+		return nil, fmt.Errorf("could not write rustls layout to map for binID %v: %w", fpath.ID, err)
+	}
+	return result, nil
+}
+
+// Cleanup removes the layout of the rustls types of the binary from the map.
+func (i *rustlsBinaryInspector) Cleanup(fpath utils.FilePath) {
+	key := rustlsBinaryKey(fpath.ID)
+	if err := i.offsetsDataMap.Delete(unsafe.Pointer(&key)); err != nil && !errors.Is(err, ebpf.ErrKeyNotExist) {
+		log.Errorf("could not remove rustls layout from map for binID %v: %s", fpath.ID, err)
+	}
+}
+
+// rustlsBinaryKey returns the key of the binary in the rustls_offsets_data
+// map, which is shared with the Go TLS offsets_data map.
+func rustlsBinaryKey(binID utils.PathIdentifier) gotls.TlsBinaryId {
+	return gotls.TlsBinaryId{
+		Id_major: unix.Major(binID.Dev),
+		Id_minor: unix.Minor(binID.Dev),
+		Ino:      binID.Inode,
+	}
+}
+
+// rustlsMonitor scans for Rust executables using rustls and attaches uprobes
+// to their plaintext Reader and Writer implementations.
+type rustlsMonitor struct {
+	mgr       *manager.Manager
+	inspector *rustlsBinaryInspector
+	attacher  *uprobes.UprobeAttacher
+}
+
+func newRustlsMonitor(c *config.Config, mgr *manager.Manager) (*rustlsMonitor, error) {
+	if !c.EnableRustlsMonitoring {
+		return nil, nil
+	}
+
+	procRoot := kernel.ProcFSRoot()
+	filter := newStaticTLSExecutableFilter(procRoot, isSupportedRustlsBinary)
+
+	attachCfg := uprobes.AttacherConfig{
+		ProcRoot: procRoot,
+		Rules: []*uprobes.AttachRule{{
+			Targets:          uprobes.AttachToExecutable,
+			ProbesSelector:   rustlsProbes,
+			ExecutableFilter: filter.Filter,
+		}},
+		EbpfConfig:                     &c.Config,
+		ExcludeTargets:                 uprobes.ExcludeSelf | uprobes.ExcludeInternal | uprobes.ExcludeBuildkit | uprobes.ExcludeContainerdTmp,
+		EnablePeriodicScanNewProcesses: true,
+	}
+
+	inspector := &rustlsBinaryInspector{}
+	attacher, err := uprobes.NewUprobeAttacher(rustlsAttacherName, attachCfg, mgr, uprobes.NopOnAttachCallback, inspector)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create uprobe attacher: %w", err)
+	}
+
+	return &rustlsMonitor{
+		mgr:       mgr,
+		inspector: inspector,
+		attacher:  attacher,
+	}, nil
+}
+
+// Start the rustlsMonitor
+func (m *rustlsMonitor) Start() {
+	if m == nil {
+		return
+	}
+
+	offsetsDataMap, _, err := m.mgr.GetMap(rustlsOffsetsDataMap)
+	if err != nil {
+		log.Errorf("rustls TLS monitoring disabled: could not get %s map: %s", rustlsOffsetsDataMap, err)
+		return
+	}
+	m.inspector.offsetsDataMap = offsetsDataMap
+
+	m.attacher.Start()
+	log.Info("rustls TLS monitoring enabled")
+}
+
+// Stop the rustlsMonitor.
+func (m *rustlsMonitor) Stop() {
+	if m == nil {
+		return
+	}
+
+	m.attacher.Stop()
+}
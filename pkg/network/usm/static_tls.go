@@ -0,0 +1,86 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package usm
+
+import (
+	"debug/elf"
+	"sync"
+
+	"github.com/hashicorp/golang-lru/v2/simplelru"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf/uprobes"
+	"github.com/DataDog/datadog-agent/pkg/network/usm/utils"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+const (
+	// staticTLSFilterCacheSize is the number of executables for which we keep
+	// the result of the symbol inspection.
+	staticTLSFilterCacheSize = 2000
+)
+
+// staticTLSExecutableFilter decides whether an executable statically embeds a
+// TLS library by inspecting its symbols. As the attacher calls the filter for
+// every new process, the result is cached per (device, inode) pair so that each
+// executable is parsed only once.
+type staticTLSExecutableFilter struct {
+	procRoot string
+	match    func(*elf.File) bool
+
+	mux   sync.Mutex
+	cache *simplelru.LRU[utils.PathIdentifier, bool]
+}
+
+func newStaticTLSExecutableFilter(procRoot string, match func(*elf.File) bool) *staticTLSExecutableFilter {
+	// simplelru.NewLRU only fails for non-positive sizes
+	cache, _ := simplelru.NewLRU[utils.PathIdentifier, bool](staticTLSFilterCacheSize, nil)
+	return &staticTLSExecutableFilter{
+		procRoot: procRoot,
+		match:    match,
+		cache:    cache,
+	}
+}
+
+// Filter implements the uprobes.AttachRule ExecutableFilter signature.
+func (f *staticTLSExecutableFilter) Filter(path string, procInfo *uprobes.ProcInfo) bool {
+	fpath, err := utils.NewFilePath(f.procRoot, path, procInfo.PID)
+	if err != nil {
+		return false
+	}
+
+	f.mux.Lock()
+	matched, ok := f.cache.Get(fpath.ID)
+	f.mux.Unlock()
+	if ok {
+		return matched
+	}
+
+	matched = f.inspect(fpath.HostPath)
+
+	f.mux.Lock()
+	f.cache.Add(fpath.ID, matched)
+	f.mux.Unlock()
+	return matched
+}
+
+func (f *staticTLSExecutableFilter) inspect(hostPath string) bool {
+	elfFile, err := elf.Open(hostPath)
+	if err != nil {
+		log.Tracef("cannot open %s for static TLS inspection: %s", hostPath, err)
+		return false
+	}
+	defer elfFile.Close()
+
+	return f.match(elfFile)
+}
+
+// isDefinedSymbol returns true if the symbol is implemented by the ELF file
+// itself rather than being imported from a shared library.
+func isDefinedSymbol(sym elf.Symbol) bool {
+	return sym.Section != elf.SHN_UNDEF
+}
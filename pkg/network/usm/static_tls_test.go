@@ -0,0 +1,210 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build linux_bpf
+
+package usm
+
+import (
+	"debug/elf"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/DataDog/datadog-agent/pkg/ebpf/uprobes"
+	"github.com/DataDog/datadog-agent/pkg/network/protocols/http/testutil"
+	usmtestutil "github.com/DataDog/datadog-agent/pkg/network/usm/testutil"
+)
+
+// buildStaticTLSBinary builds one of the fake executables in testdata/statictls.
+func buildStaticTLSBinary(t *testing.T, name string) string {
+	curDir, err := testutil.CurDir()
+	require.NoError(t, err)
+
+	src := filepath.Join(curDir, "testdata", "statictls", name+".c")
+	out, err := usmtestutil.BuildFreestandingCBinary(src, filepath.Join(t.TempDir(), name))
+	require.NoError(t, err)
+	return out
+}
+
+// buildRustlsBinary builds the testdata/statictls/rustls program, with either
+// the legacy or the v0 symbol mangling scheme.
+func buildRustlsBinary(t *testing.T, v0Mangling bool) string {
+	curDir, err := testutil.CurDir()
+	require.NoError(t, err)
+
+	srcDir := filepath.Join(curDir, "testdata", "statictls", "rustls")
+	name := "rustls-test"
+	if v0Mangling {
+		name += "-v0"
+	}
+	out, err := usmtestutil.BuildRustBinary(srcDir, "rustls-test", filepath.Join(srcDir, name), v0Mangling)
+	require.NoError(t, err)
+	return out
+}
+
+func openELF(t *testing.T, path string) *elf.File {
+	elfFile, err := elf.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { elfFile.Close() })
+	return elfFile
+}
+
+func TestRustLegacyMangledPrefix(t *testing.T) {
+	assert.Equal(t,
+		"_ZN54_$LT$rustls..conn..Reader$u20$as$u20$std..io..Read$GT$4read",
+		rustLegacyMangledPrefix("<rustls::conn::Reader as std::io::Read>", "read"))
+	assert.Equal(t,
+		"_ZN67_$LT$rustls..conn..connection..Writer$u20$as$u20$std..io..Write$GT$5write",
+		rustLegacyMangledPrefix("<rustls::conn::connection::Writer as std::io::Write>", "write"))
+	assert.Equal(t,
+		"_ZN6rustls6vecbuf14ChunkVecBuffer8write_to",
+		rustLegacyMangledPrefix("rustls", "vecbuf", "ChunkVecBuffer", "write_to"))
+}
+
+func TestRustV0MangledSymbols(t *testing.T) {
+	// Symbols of rustls 0.23 built with -C symbol-mangling-version=v0
+	tests := map[string]string{
+		rustlsReaderReadSymbol:  "_RNvXs2_NtNtCsvwaYw67nn7_6rustls4conn10connectionNtB5_6ReaderNtNtCscKkwsb9kWaL_3std2io4Read4read",
+		rustlsWriterWriteSymbol: "_RNvXs5_NtNtCsvwaYw67nn7_6rustls4conn10connectionNtB5_6WriterNtNtCscKkwsb9kWaL_3std2io5Write5write",
+		rustlsWriteTLSSymbol:    "_RNvMs_NtCsvwaYw67nn7_6rustls6vecbufNtB4_14ChunkVecBuffer8write_to",
+	}
+	for name, symbol := range tests {
+		assert.True(t, rustlsSymbols[name].v0.MatchString(symbol), name)
+		for other := range tests {
+			if other != name {
+				assert.False(t, rustlsSymbols[other].v0.MatchString(symbol), "%s matches %s", other, name)
+			}
+		}
+	}
+
+	// Before 0.22, Reader was defined in rustls::conn
+	assert.True(t, rustlsSymbols[rustlsReaderReadSymbol].v0.MatchString(
+		"_RNvXs0_NtCs4fqI2P2rA04_6rustls4connNtB5_6ReaderNtNtCscKkwsb9kWaL_3std2io4Read4read"))
+}
+
+func TestStaticTLSBinaryDetection(t *testing.T) {
+	curDir, err := testutil.CurDir()
+	require.NoError(t, err)
+
+	boringSSL := openELF(t, buildStaticTLSBinary(t, "fakeboringssl"))
+	rustls := openELF(t, buildRustlsBinary(t, false))
+	rustlsV0 := openELF(t, buildRustlsBinary(t, true))
+	// OpenSSL exposes the same SSL_* API, but no BoringSSL specific symbols.
+	openSSL := openELF(t, filepath.Join(curDir, "testdata", "site-packages", "ddtrace", fmt.Sprintf("libssl.so.%s", runtime.GOARCH)))
+
+	assert.True(t, isStaticBoringSSLBinary(boringSSL))
+	assert.False(t, isStaticBoringSSLBinary(rustls))
+	assert.False(t, isStaticBoringSSLBinary(openSSL))
+
+	assert.True(t, isRustlsBinary(rustls))
+	assert.True(t, isRustlsBinary(rustlsV0))
+	assert.False(t, isRustlsBinary(boringSSL))
+	assert.False(t, isRustlsBinary(openSSL))
+}
+
+func TestRustlsSymbols(t *testing.T) {
+	for _, v0Mangling := range []bool{false, true} {
+		t.Run(fmt.Sprintf("v0 mangling %t", v0Mangling), func(t *testing.T) {
+			elfFile := openELF(t, buildRustlsBinary(t, v0Mangling))
+
+			for name := range rustlsSymbols {
+				sym, err := findRustlsSymbol(elfFile, name)
+				require.NoError(t, err, name)
+				if v0Mangling {
+					assert.True(t, strings.HasPrefix(sym.Name, "_R"), sym.Name)
+				} else {
+					assert.True(t, strings.HasPrefix(sym.Name, "_ZN"), sym.Name)
+				}
+			}
+
+			_, err := findRustlsSymbol(elfFile, "SSL_read")
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestRustlsBuildDetection(t *testing.T) {
+	build, err := getRustlsBuild(openELF(t, buildRustlsBinary(t, false)))
+	require.NoError(t, err)
+	// Pinned by testdata/statictls/rustls
+	assert.Equal(t, "0.23.27", build.rustlsVersion)
+	assert.Equal(t, "1.90", build.rustcVersion)
+	assert.Equal(t, runtime.GOARCH, string(build.arch))
+
+	_, err = getRustlsBuild(openELF(t, buildStaticTLSBinary(t, "fakeboringssl")))
+	require.Error(t, err)
+
+	unsupported := build
+	unsupported.rustlsVersion = "0.23.0"
+	_, ok := rustlsLayouts[unsupported]
+	assert.False(t, ok)
+}
+
+func TestRustlsBinaryInspector(t *testing.T) {
+	requests := []uprobes.SymbolRequest{
+		{Name: rustlsReaderReadSymbol},
+		{Name: rustlsWriterWriteSymbol},
+		{Name: rustlsWriteTLSSymbol},
+	}
+
+	for _, v0Mangling := range []bool{false, true} {
+		t.Run(fmt.Sprintf("v0 mangling %t", v0Mangling), func(t *testing.T) {
+			elfFile := openELF(t, buildRustlsBinary(t, v0Mangling))
+			build, err := getRustlsBuild(elfFile)
+			require.NoError(t, err)
+			expectedLayout, ok := rustlsLayouts[build]
+			if !ok {
+				t.Skipf("%s is not supported", build)
+			}
+
+			result, layout, err := inspectRustlsBinary(elfFile, requests)
+			require.NoError(t, err)
+			assert.Equal(t, expectedLayout, layout)
+			require.Len(t, result, 3)
+			locations := make(map[uint64]struct{})
+			for _, req := range requests {
+				assert.NotZero(t, result[req.Name].EntryLocation, req.Name)
+				locations[result[req.Name].EntryLocation] = struct{}{}
+			}
+			assert.Len(t, locations, 3)
+
+			_, _, err = inspectRustlsBinary(elfFile, []uprobes.SymbolRequest{{Name: "SSL_read"}})
+			require.Error(t, err)
+
+			result, _, err = inspectRustlsBinary(elfFile, []uprobes.SymbolRequest{{Name: "SSL_read", BestEffort: true}})
+			require.NoError(t, err)
+			assert.Empty(t, result)
+		})
+	}
+
+	t.Run("unsupported build", func(t *testing.T) {
+		_, _, err := inspectRustlsBinary(openELF(t, buildStaticTLSBinary(t, "fakeboringssl")), requests)
+		require.Error(t, err)
+	})
+}
+
+func TestStaticTLSExecutableFilterCache(t *testing.T) {
+	path := buildStaticTLSBinary(t, "fakeboringssl")
+
+	calls := 0
+	filter := newStaticTLSExecutableFilter("/proc", func(elfFile *elf.File) bool {
+		calls++
+		return isStaticBoringSSLBinary(elfFile)
+	})
+
+	procInfo := uprobes.NewProcInfo("/proc", uint32(os.Getpid()))
+	assert.True(t, filter.Filter(path, procInfo))
+	assert.True(t, filter.Filter(path, procInfo))
+	assert.Equal(t, 1, calls)
+
+	assert.False(t, filter.Filter(filepath.Join(t.TempDir(), "missing"), procInfo))
+}
@@ -0,0 +1,92 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Minimal HTTPS server statically linking BoringSSL, answering each request
+// with the status code of its "/status/<code>/..." path, or 200.
+//
+// Usage: boringssl-server <port> <cert> <key>
+
+#include <arpa/inet.h>
+#include <netinet/in.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include <sys/socket.h>
+#include <unistd.h>
+
+#include <openssl/err.h>
+#include <openssl/ssl.h>
+
+static int status_from_request(const char *request) {
+    static const char prefix[] = "GET /status/";
+    if (strncmp(request, prefix, sizeof(prefix) - 1) != 0) {
+        return 200;
+    }
+    int status = atoi(request + sizeof(prefix) - 1);
+    return status > 0 ? status : 200;
+}
+
+static void serve(SSL *ssl) {
+    char request[4096];
+    char response[128];
+
+    // Requests are expected to fit in a single record.
+    for (;;) {
+        int n = SSL_read(ssl, request, sizeof(request) - 1);
+        if (n <= 0) {
+            return;
+        }
+        request[n] = '\0';
+
+        int len = snprintf(response, sizeof(response), "HTTP/1.1 %d Test\r\nContent-Length: 0\r\n\r\n", status_from_request(request));
+        if (SSL_write(ssl, response, len) <= 0) {
+            return;
+        }
+    }
+}
+
+int main(int argc, char **argv) {
+    if (argc != 4) {
+        fprintf(stderr, "usage: %s <port> <cert> <key>\n", argv[0]);
+        return 2;
+    }
+
+    SSL_CTX *ctx = SSL_CTX_new(TLS_server_method());
+    if (ctx == NULL ||
+        SSL_CTX_use_certificate_chain_file(ctx, argv[2]) != 1 ||
+        SSL_CTX_use_PrivateKey_file(ctx, argv[3], SSL_FILETYPE_PEM) != 1) {
+        ERR_print_errors_fp(stderr);
+        return 1;
+    }
+
+    int listener = socket(AF_INET, SOCK_STREAM, 0);
+    int one = 1;
+    setsockopt(listener, SOL_SOCKET, SO_REUSEADDR, &one, sizeof(one));
+    struct sockaddr_in addr = {0};
+    addr.sin_family = AF_INET;
+    addr.sin_port = htons(atoi(argv[1]));
+    addr.sin_addr.s_addr = htonl(INADDR_LOOPBACK);
+    if (bind(listener, (struct sockaddr *)&addr, sizeof(addr)) != 0 || listen(listener, 16) != 0) {
+        perror("listen");
+        return 1;
+    }
+    printf("listening on port %s\n", argv[1]);
+    fflush(stdout);
+
+    for (;;) {
+        int sock = accept(listener, NULL, NULL);
+        if (sock < 0) {
+            continue;
+        }
+        SSL *ssl = SSL_new(ctx);
+        SSL_set_fd(ssl, sock);
+        if (SSL_accept(ssl) == 1) {
+            serve(ssl);
+            SSL_shutdown(ssl);
+        }
+        SSL_free(ssl);
+        close(sock);
+    }
+}
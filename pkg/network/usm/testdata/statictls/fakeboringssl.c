@@ -0,0 +1,31 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2024-present Datadog, Inc.
+
+//go:build ignore
+
+// Dummy executable statically embedding the BoringSSL functions we hook, along
+// with a BoringSSL specific symbol.
+
+void BORINGSSL_self_test(void) {
+}
+void SSL_do_handshake(void) {
+}
+void SSL_set_bio(void) {
+}
+void SSL_set_fd(void) {
+}
+void SSL_read(void) {
+}
+void SSL_write(void) {
+}
+void SSL_shutdown(void) {
+}
+void BIO_new_socket(void) {
+}
+
+void _start(void) {
+    for (;;) {
+    }
+}
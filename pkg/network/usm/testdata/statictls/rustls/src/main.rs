@@ -0,0 +1,137 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//! Minimal HTTPS client and server statically linking rustls.
+//!
+//! Usage:
+//!   rustls-test client <addr> <ca cert> <path> [<count>]
+//!   rustls-test server <addr> <cert> <key>
+
+use std::io::{BufRead, BufReader, Read, Write};
+use std::net::{TcpListener, TcpStream};
+use std::sync::Arc;
+
+use rustls::pki_types::pem::PemObject;
+use rustls::pki_types::{CertificateDer, PrivateKeyDer, ServerName};
+
+fn main() {
+    let args: Vec<String> = std::env::args().collect();
+    match args.get(1).map(String::as_str) {
+        Some("client") if args.len() >= 5 => {
+            let count = args
+                .get(5)
+                .map_or(1, |c| c.parse().expect("invalid request count"));
+            client(&args[2], &args[3], &args[4], count)
+        }
+        Some("server") if args.len() == 5 => server(&args[2], &args[3], &args[4]),
+        _ => {
+            eprintln!(
+                "usage: {} client <addr> <ca cert> <path> [<count>] | server <addr> <cert> <key>",
+                args[0]
+            );
+            std::process::exit(2);
+        }
+    }
+}
+
+fn client(addr: &str, ca_cert: &str, path: &str, count: usize) {
+    let mut roots = rustls::RootCertStore::empty();
+    for cert in CertificateDer::pem_file_iter(ca_cert).expect("cannot read CA certificate") {
+        roots
+            .add(cert.expect("invalid CA certificate"))
+            .expect("cannot add CA certificate");
+    }
+    let config = Arc::new(
+        rustls::ClientConfig::builder()
+            .with_root_certificates(roots)
+            .with_no_client_auth(),
+    );
+    let host = addr
+        .rsplit_once(':')
+        .map_or(addr, |(host, _)| host)
+        .to_string();
+
+    for _ in 0..count {
+        let server_name = ServerName::try_from(host.clone()).expect("invalid server name");
+        let mut conn = rustls::ClientConnection::new(config.clone(), server_name)
+            .expect("cannot create connection");
+        let mut sock = TcpStream::connect(addr).expect("cannot connect");
+        let mut tls = rustls::Stream::new(&mut conn, &mut sock);
+
+        let request = format!(
+            "GET {} HTTP/1.1\r\nHost: {}\r\nConnection: close\r\n\r\n",
+            path, host
+        );
+        tls.write_all(request.as_bytes())
+            .expect("cannot send request");
+
+        let mut response = Vec::new();
+        // Servers may close the connection without a close_notify alert.
+        let _ = tls.read_to_end(&mut response);
+        println!(
+            "{}",
+            String::from_utf8_lossy(&response)
+                .lines()
+                .next()
+                .unwrap_or("")
+        );
+    }
+}
+
+fn server(addr: &str, cert: &str, key: &str) {
+    let certs = CertificateDer::pem_file_iter(cert)
+        .expect("cannot read certificate")
+        .map(|c| c.expect("invalid certificate"))
+        .collect();
+    let key = PrivateKeyDer::from_pem_file(key).expect("cannot read private key");
+    let config = Arc::new(
+        rustls::ServerConfig::builder()
+            .with_no_client_auth()
+            .with_single_cert(certs, key)
+            .expect("invalid certificate or key"),
+    );
+
+    let listener = TcpListener::bind(addr).expect("cannot listen");
+    for sock in listener.incoming() {
+        let mut sock = match sock {
+            Ok(sock) => sock,
+            Err(_) => continue,
+        };
+        let mut conn =
+            rustls::ServerConnection::new(config.clone()).expect("cannot create connection");
+        let mut tls = rustls::Stream::new(&mut conn, &mut sock);
+
+        // Read the request line and headers, the body is ignored.
+        let mut request = BufReader::new(&mut tls);
+        let mut request_line = String::new();
+        if request.read_line(&mut request_line).is_err() {
+            continue;
+        }
+        let mut line = String::new();
+        while request.read_line(&mut line).map_or(false, |n| n > 0) && line != "\r\n" {
+            line.clear();
+        }
+
+        let response = format!(
+            "HTTP/1.1 {} Test\r\nContent-Length: 0\r\nConnection: close\r\n\r\n",
+            status_from_request(&request_line)
+        );
+        let _ = tls.write_all(response.as_bytes());
+        tls.conn.send_close_notify();
+        let _ = tls.flush();
+    }
+}
+
+/// Returns the status code requested by a "/status/<code>/..." path, like the
+/// HTTP test servers of the agent, or 200.
+fn status_from_request(request_line: &str) -> u16 {
+    request_line
+        .split_whitespace()
+        .nth(1)
+        .and_then(|path| path.strip_prefix("/status/"))
+        .and_then(|path| path.split('/').next())
+        .and_then(|code| code.parse().ok())
+        .unwrap_or(200)
+}
@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// cBuildMux serializes the compilation of C test binaries
+var cBuildMux sync.Mutex
+
+// BuildFreestandingCBinary builds a static executable without libc from the
+// given C source and returns the path to it. Such binaries are only meant to be
+// inspected, not executed.
+// If the binary is already built (meanly in the CI), it returns the
+// path to the binary.
+func BuildFreestandingCBinary(srcPath, outPath string) (string, error) {
+	cBuildMux.Lock()
+	defer cBuildMux.Unlock()
+
+	// If there is a compiled binary already, skip the compilation.
+	// Meant for the CI.
+	if _, err := os.Stat(outPath); err == nil {
+		return outPath, nil
+	}
+
+	c := exec.Command("clang", "-static", "-nostdlib", srcPath, "-o", outPath)
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("could not build C test binary: %s\noutput: %s", err, string(out))
+	}
+
+	return outPath, nil
+}
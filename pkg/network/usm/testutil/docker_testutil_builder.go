@@ -0,0 +1,56 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+// dockerBuildMux serializes the builds of test binaries with docker
+var dockerBuildMux sync.Mutex
+
+// BuildBinaryWithDocker builds the Dockerfile in the given directory, whose
+// final stage must contain the binary at its root, and returns the path to
+// the binary. It is used for the binaries whose toolchain is not available on
+// the test hosts.
+// If the binary is already built (meanly in the CI), it returns the
+// path to the binary.
+func BuildBinaryWithDocker(dockerfileDir, binName, outPath string) (string, error) {
+	dockerBuildMux.Lock()
+	defer dockerBuildMux.Unlock()
+
+	// If there is a compiled binary already, skip the compilation.
+	// Meant for the CI.
+	if _, err := os.Stat(outPath); err == nil {
+		return outPath, nil
+	}
+
+	outputDir, err := os.MkdirTemp("", "docker-build-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(outputDir)
+
+	c := exec.Command("docker", "build", "--output", "type=local,dest="+outputDir, dockerfileDir)
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("could not build test binary with docker: %s\noutput: %s", err, string(out))
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, binName))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(outPath, content, 0755); err != nil {
+		return "", err
+	}
+
+	return outPath, nil
+}
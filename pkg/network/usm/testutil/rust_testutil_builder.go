@@ -0,0 +1,59 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package testutil
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+)
+
+// rustBuildMux serializes the compilation of Rust test binaries
+var rustBuildMux sync.Mutex
+
+// BuildRustBinary builds the release binary of the given Cargo package, using
+// the v0 symbol mangling scheme if requested, and returns the path to it.
+// If the binary is already built (meanly in the CI), it returns the
+// path to the binary.
+func BuildRustBinary(srcDir, binName, outPath string, v0Mangling bool) (string, error) {
+	rustBuildMux.Lock()
+	defer rustBuildMux.Unlock()
+
+	// If there is a compiled binary already, skip the compilation.
+	// Meant for the CI.
+	if _, err := os.Stat(outPath); err == nil {
+		return outPath, nil
+	}
+
+	targetDir, err := os.MkdirTemp("", "rust-build-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(targetDir)
+
+	c := exec.Command("cargo", "build", "--release", "--target-dir", targetDir)
+	c.Dir = srcDir
+	c.Env = os.Environ()
+	if v0Mangling {
+		c.Env = append(c.Env, "RUSTFLAGS=-C symbol-mangling-version=v0")
+	}
+	out, err := c.CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("could not build Rust test binary: %s\noutput: %s", err, string(out))
+	}
+
+	content, err := os.ReadFile(filepath.Join(targetDir, "release", binName))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(outPath, content, 0755); err != nil {
+		return "", err
+	}
+
+	return outPath, nil
+}
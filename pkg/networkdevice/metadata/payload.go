@@ -40,6 +40,7 @@ type NetworkDevicesMetadata struct {
 	Interfaces       []InterfaceMetadata    `json:"interfaces,omitempty"`
 	IPAddresses      []IPAddressMetadata    `json:"ip_addresses,omitempty"`
 	Links            []TopologyLinkMetadata `json:"links,omitempty"`
+	BGPPeers         []BGPPeerMetadata      `json:"bgp_peers,omitempty"`
 	NetflowExporters []NetflowExporter      `json:"netflow_exporters,omitempty"`
 	Diagnoses        []DiagnosisMetadata    `json:"diagnoses,omitempty"`
 	DeviceOIDs       []DeviceOID            `json:"device_oids,omitempty"`
@@ -137,6 +138,24 @@ type TopologyLinkMetadata struct {
 	Remote     *TopologyLinkSide `json:"remote"`
 }
 
+// BGPPeerMetadata contains BGP peer session metadata
+type BGPPeerMetadata struct {
+	ID                string `json:"id"`
+	DeviceID          string `json:"device_id"`
+	RemoteAddress     string `json:"remote_address"`
+	RemoteAS          uint32 `json:"remote_as,omitempty"`
+	RemotePort        int32  `json:"remote_port,omitempty"`
+	LocalAddress      string `json:"local_address,omitempty"`
+	LocalPort         int32  `json:"local_port,omitempty"`
+	Identifier        string `json:"identifier,omitempty"` // BGP identifier (router ID) of the peer
+	State             string `json:"state,omitempty"`
+	AdminStatus       string `json:"admin_status,omitempty"`
+	NegotiatedVersion int32  `json:"negotiated_version,omitempty"`
+	EstablishedTime   uint32 `json:"established_time,omitempty"` // seconds the peer has been (or was last) in the established state
+	LastErrorCode     int32  `json:"last_error_code,omitempty"`
+	LastErrorSubcode  int32  `json:"last_error_subcode,omitempty"`
+}
+
 // NetflowExporter contains netflow exporters info
 type NetflowExporter struct {
 	ID        string `json:"id"` // used by backend as unique id (e.g. in cache)
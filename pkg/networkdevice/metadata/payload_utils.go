@@ -13,7 +13,7 @@ import (
 )
 
 // BatchPayloads batch NDM metadata payloads
-func BatchPayloads(namespace string, subnet string, collectTime time.Time, batchSize int, devices []DeviceMetadata, interfaces []InterfaceMetadata, ipAddresses []IPAddressMetadata, topologyLinks []TopologyLinkMetadata, netflowExporters []NetflowExporter, diagnoses []DiagnosisMetadata) []NetworkDevicesMetadata {
+func BatchPayloads(namespace string, subnet string, collectTime time.Time, batchSize int, devices []DeviceMetadata, interfaces []InterfaceMetadata, ipAddresses []IPAddressMetadata, topologyLinks []TopologyLinkMetadata, bgpPeers []BGPPeerMetadata, netflowExporters []NetflowExporter, diagnoses []DiagnosisMetadata) []NetworkDevicesMetadata {
 
 	var payloads []NetworkDevicesMetadata
 	var resourceCount int
@@ -40,6 +40,11 @@ func BatchPayloads(namespace string, subnet string, collectTime time.Time, batch
 		curPayload.Links = append(curPayload.Links, linkMetadata)
 	}
 
+	for _, bgpPeer := range bgpPeers {
+		payloads, curPayload, resourceCount = appendToPayloads(namespace, subnet, collectTime, batchSize, resourceCount, payloads, curPayload)
+		curPayload.BGPPeers = append(curPayload.BGPPeers, bgpPeer)
+	}
+
 	for _, netflowExporter := range netflowExporters {
 		payloads, curPayload, resourceCount = appendToPayloads(namespace, subnet, collectTime, batchSize, resourceCount, payloads, curPayload)
 		curPayload.NetflowExporters = append(curPayload.NetflowExporters, netflowExporter)
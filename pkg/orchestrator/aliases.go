@@ -83,6 +83,16 @@ const (
 	K8sLimitRange = pkgorchestratormodel.K8sLimitRange
 	// K8sStorageClass alias for pkgorchestratormodel.K8sStorageClass
 	K8sStorageClass = pkgorchestratormodel.K8sStorageClass
+	// K8sPodDisruptionBudget alias for pkgorchestratormodel.K8sPodDisruptionBudget
+	K8sPodDisruptionBudget = pkgorchestratormodel.K8sPodDisruptionBudget
+	// K8sEndpointSlice alias for pkgorchestratormodel.K8sEndpointSlice
+	K8sEndpointSlice = pkgorchestratormodel.K8sEndpointSlice
+	// K8sResourceQuota alias for pkgorchestratormodel.K8sResourceQuota
+	K8sResourceQuota = pkgorchestratormodel.K8sResourceQuota
+	// K8sGateway alias for pkgorchestratormodel.K8sGateway
+	K8sGateway = pkgorchestratormodel.K8sGateway
+	// K8sHTTPRoute alias for pkgorchestratormodel.K8sHTTPRoute
+	K8sHTTPRoute = pkgorchestratormodel.K8sHTTPRoute
 	// ECSTask alias for pkgorchestratormodel.ECSTask
 	ECSTask = pkgorchestratormodel.ECSTask
 )
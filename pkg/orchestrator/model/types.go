@@ -83,6 +83,16 @@ const (
 	K8sLimitRange = 25
 	// K8sStorageClass represents a Kubernetes StorageClass
 	K8sStorageClass = 26
+	// K8sPodDisruptionBudget represents a Kubernetes PodDisruptionBudget
+	K8sPodDisruptionBudget = 27
+	// K8sEndpointSlice represents a Kubernetes EndpointSlice
+	K8sEndpointSlice = 28
+	// K8sResourceQuota represents a Kubernetes ResourceQuota
+	K8sResourceQuota = 29
+	// K8sGateway represents a Kubernetes Gateway API Gateway
+	K8sGateway = 30
+	// K8sHTTPRoute represents a Kubernetes Gateway API HTTPRoute
+	K8sHTTPRoute = 31
 	// ECSTask represents an ECS Task
 	ECSTask = 150
 )
@@ -116,6 +126,11 @@ func NodeTypes() []NodeType {
 		K8sNetworkPolicy,
 		K8sLimitRange,
 		K8sStorageClass,
+		K8sPodDisruptionBudget,
+		K8sEndpointSlice,
+		K8sResourceQuota,
+		K8sGateway,
+		K8sHTTPRoute,
 		ECSTask,
 	}
 }
@@ -174,6 +189,16 @@ func (n NodeType) String() string {
 		return "LimitRange"
 	case K8sStorageClass:
 		return "StorageClass"
+	case K8sPodDisruptionBudget:
+		return "PodDisruptionBudget"
+	case K8sEndpointSlice:
+		return "EndpointSlice"
+	case K8sResourceQuota:
+		return "ResourceQuota"
+	case K8sGateway:
+		return "Gateway"
+	case K8sHTTPRoute:
+		return "HTTPRoute"
 	case K8sUnsetType:
 		return "UnsetType"
 	case ECSTask:
@@ -213,6 +238,11 @@ func (n NodeType) Orchestrator() string {
 		K8sNetworkPolicy,
 		K8sLimitRange,
 		K8sStorageClass,
+		K8sPodDisruptionBudget,
+		K8sEndpointSlice,
+		K8sResourceQuota,
+		K8sGateway,
+		K8sHTTPRoute,
 		K8sUnsetType:
 		return "k8s"
 	case ECSTask:
@@ -20,6 +20,7 @@ import (
 	oomkill "github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/oomkill/model"
 	tcpqueuelength "github.com/DataDog/datadog-agent/pkg/collector/corechecks/ebpf/probe/tcpqueuelength/model"
 	gpu "github.com/DataDog/datadog-agent/pkg/collector/corechecks/gpu/model"
+	network "github.com/DataDog/datadog-agent/pkg/collector/corechecks/net/network/model"
 )
 
 const (
@@ -75,6 +76,13 @@ func (r *RemoteSysProbeUtil) GetCheck(module sysconfigtypes.ModuleName) (interfa
 			return nil, err
 		}
 		return stats, nil
+	} else if module == sysconfig.NetworkTracerModule {
+		var stats network.DNSResolverStats
+		err = json.Unmarshal(body, &stats)
+		if err != nil {
+			return nil, err
+		}
+		return stats, nil
 	}
 
 	return nil, fmt.Errorf("invalid check name: %s", module)
@@ -0,0 +1,40 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package runtime
+
+import (
+	"math"
+	"runtime/metrics"
+)
+
+var memLimitSamples = []string{
+	"/memory/classes/total:bytes",
+	"/memory/classes/heap/released:bytes",
+	"/gc/gomemlimit:bytes",
+}
+
+// GoMemLimitUsage returns the memory used by the Go runtime, as accounted by
+// the Go memory limit, and the Go memory limit set with GOMEMLIMIT or
+// SetGoMemLimit. The limit is 0 when there is none.
+func GoMemLimitUsage() (used uint64, limit uint64) {
+	samples := make([]metrics.Sample, len(memLimitSamples))
+	for i, name := range memLimitSamples {
+		samples[i].Name = name
+	}
+	metrics.Read(samples)
+	for _, sample := range samples {
+		if sample.Value.Kind() != metrics.KindUint64 {
+			return 0, 0
+		}
+	}
+
+	used = samples[0].Value.Uint64() - samples[1].Value.Uint64()
+	limit = samples[2].Value.Uint64()
+	if limit == math.MaxInt64 {
+		limit = 0
+	}
+	return used, limit
+}
@@ -0,0 +1,27 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package runtime
+
+import (
+	"math"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoMemLimitUsage(t *testing.T) {
+	defer debug.SetMemoryLimit(debug.SetMemoryLimit(math.MaxInt64))
+
+	used, limit := GoMemLimitUsage()
+	assert.Positive(t, used)
+	assert.Zero(t, limit)
+
+	debug.SetMemoryLimit(1 << 40)
+	used, limit = GoMemLimitUsage()
+	assert.Positive(t, used)
+	assert.Equal(t, uint64(1<<40), limit)
+}
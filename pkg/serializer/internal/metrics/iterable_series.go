@@ -142,56 +142,58 @@ func (series *IterableSeries) MarshalSplitCompress(bufferContext *marshaler.Buff
 // One set of payloads contains all metrics, and the other contains only those that pass the provided filter function.
 // This function exists because we need a way to build both payloads in a single pass over the input data, which cannot be iterated over twice.
 func (series *IterableSeries) MarshalSplitCompressMultiple(config config.Component, strategy compression.Component, filterFunc func(s *metrics.Serie) bool) (transaction.BytesPayloads, transaction.BytesPayloads, error) {
-	bufferContext := marshaler.NewBufferContext()
-	pb, err := series.NewPayloadsBuilder(bufferContext, config, strategy)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	bufferContext2 := marshaler.NewBufferContext()
-	pb2, err := series.NewPayloadsBuilder(bufferContext2, config, strategy)
+	payloads, err := series.MarshalSplitCompressFilters(config, strategy, []func(s *metrics.Serie) bool{nil, filterFunc})
 	if err != nil {
 		return nil, nil, err
 	}
+	return payloads[0], payloads[1], nil
+}
 
-	err = pb.startPayload()
-	if err != nil {
-		return nil, nil, err
+// MarshalSplitCompressFilters uses the stream compressor to marshal and compress one series into one set of payloads
+// per filter function. Each set contains only the metrics that pass its filter function, a nil filter function
+// keeping all the metrics.
+func (series *IterableSeries) MarshalSplitCompressFilters(config config.Component, strategy compression.Component, filterFuncs []func(s *metrics.Serie) bool) ([]transaction.BytesPayloads, error) {
+	builders := make([]PayloadsBuilder, 0, len(filterFuncs))
+	for range filterFuncs {
+		pb, err := series.NewPayloadsBuilder(marshaler.NewBufferContext(), config, strategy)
+		if err != nil {
+			return nil, err
+		}
+		builders = append(builders, pb)
 	}
 
-	err = pb2.startPayload()
-	if err != nil {
-		return nil, nil, err
+	for i := range builders {
+		err := builders[i].startPayload()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Use series.source.MoveNext() instead of series.MoveNext() because this function supports
 	// the serie.NoIndex field.
 	for series.source.MoveNext() {
-		err = pb.writeSerie(series.source.Current())
-		if err != nil {
-			return nil, nil, err
-		}
-
-		if filterFunc(series.source.Current()) {
-			err = pb2.writeSerie(series.source.Current())
+		for i, filterFunc := range filterFuncs {
+			if filterFunc != nil && !filterFunc(series.source.Current()) {
+				continue
+			}
+			err := builders[i].writeSerie(series.source.Current())
 			if err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		}
 	}
 
 	// if the last payload has any data, flush it
-	err = pb.finishPayload()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	err = pb2.finishPayload()
-	if err != nil {
-		return nil, nil, err
+	payloads := make([]transaction.BytesPayloads, 0, len(builders))
+	for i := range builders {
+		err := builders[i].finishPayload()
+		if err != nil {
+			return nil, err
+		}
+		payloads = append(payloads, builders[i].payloads)
 	}
 
-	return pb.payloads, pb2.payloads, nil
+	return payloads, nil
 }
 
 // NewPayloadsBuilder initializes a new PayloadsBuilder to be used for serializing series into a set of output payloads.
@@ -473,6 +473,48 @@ func TestMarshalSplitCompressMultiplePointsLimit(t *testing.T) {
 	}
 }
 
+func TestMarshalSplitCompressFilters(t *testing.T) {
+	tests := map[string]struct {
+		kind string
+	}{
+		"zlib": {kind: compressionimpl.ZlibKind},
+		"zstd": {kind: compressionimpl.ZstdKind},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockConfig := mock.New(t)
+			mockConfig.SetWithoutSource("serializer_compressor_kind", tc.kind)
+			mockConfig.SetWithoutSource("serializer_max_series_points_per_payload", 10)
+
+			// 100 series, each with 1 point, so 10 should fit in each payload
+			rawSeries := metrics.Series{}
+			for i := 0; i < 100; i++ {
+				rawSeries = append(rawSeries, &metrics.Serie{
+					Points:   []metrics.Point{{Ts: 12345.0, Value: float64(i)}},
+					MType:    metrics.APIGaugeType,
+					Name:     fmt.Sprintf("test.metrics%d", i),
+					Interval: 1,
+					Host:     "localhost",
+				})
+			}
+			series := CreateIterableSeries(CreateSerieSource(rawSeries))
+
+			payloads, err := series.MarshalSplitCompressFilters(mockConfig, compressionimpl.NewCompressor(mockConfig), []func(s *metrics.Serie) bool{
+				nil,
+				func(s *metrics.Serie) bool { return s.Points[0].Value < 30 },
+				func(s *metrics.Serie) bool { return s.Name == "test.metrics42" },
+				func(_ *metrics.Serie) bool { return false },
+			})
+			require.NoError(t, err)
+			require.Len(t, payloads, 4)
+			require.Len(t, payloads[0], 10)
+			require.Len(t, payloads[1], 3)
+			require.Len(t, payloads[2], 1)
+			require.Len(t, payloads[3], 0)
+		})
+	}
+}
+
 func TestMarshalSplitCompressPointsLimitTooBig(t *testing.T) {
 	tests := map[string]struct {
 		kind string
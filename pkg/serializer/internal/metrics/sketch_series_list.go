@@ -86,51 +86,57 @@ func (sl SketchSeriesList) MarshalSplitCompress(bufferContext *marshaler.BufferC
 // build both payloads in a single pass over the input data, which cannot be
 // iterated over twice.
 func (sl SketchSeriesList) MarshalSplitCompressMultiple(config config.Component, strategy compression.Component, filterFunc func(ss *metrics.SketchSeries) bool) (transaction.BytesPayloads, transaction.BytesPayloads, error) {
-	var err error
+	payloads, err := sl.MarshalSplitCompressFilters(config, strategy, []func(ss *metrics.SketchSeries) bool{nil, filterFunc})
+	if err != nil {
+		return nil, nil, err
+	}
+	return payloads[0], payloads[1], nil
+}
 
-	bufferContext := marshaler.NewBufferContext()
-	bufferContext2 := marshaler.NewBufferContext()
+// MarshalSplitCompressFilters uses the stream compressor to marshal and
+// compress one sketch list into one set of payloads per filter function. Each
+// set contains only the metrics that pass its filter function, a nil filter
+// function keeping all the metrics.
+func (sl SketchSeriesList) MarshalSplitCompressFilters(config config.Component, strategy compression.Component, filterFuncs []func(ss *metrics.SketchSeries) bool) ([]transaction.BytesPayloads, error) {
+	var err error
 
-	pb := newPayloadsBuilder(bufferContext, config, strategy)
-	pb2 := newPayloadsBuilder(bufferContext2, config, strategy)
+	builders := make([]payloadsBuilder, 0, len(filterFuncs))
+	for range filterFuncs {
+		builders = append(builders, newPayloadsBuilder(marshaler.NewBufferContext(), config, strategy))
+	}
 
 	// start things off
-	err = pb.startPayload()
-	if err != nil {
-		return nil, nil, err
-	}
-	err = pb2.startPayload()
-	if err != nil {
-		return nil, nil, err
+	for i := range builders {
+		err = builders[i].startPayload()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	for sl.MoveNext() {
 		ss := sl.Current()
-		err = pb.marshal(ss)
-		if err != nil {
-			return nil, nil, err
-		}
-		if filterFunc(ss) {
-			err = pb2.marshal(ss)
+		for i, filterFunc := range filterFuncs {
+			if filterFunc != nil && !filterFunc(ss) {
+				continue
+			}
+			err = builders[i].marshal(ss)
 			if err != nil {
-				return nil, nil, err
+				return nil, err
 			}
 		}
 	}
 
-	err = pb.finishPayload()
-	if err != nil {
-		log.Debugf("Failed to finish payload with err %v", err)
-		return nil, nil, err
-	}
-
-	err = pb2.finishPayload()
-	if err != nil {
-		log.Debugf("Failed to finish payload with err %v", err)
-		return nil, nil, err
+	payloads := make([]transaction.BytesPayloads, 0, len(builders))
+	for i := range builders {
+		err = builders[i].finishPayload()
+		if err != nil {
+			log.Debugf("Failed to finish payload with err %v", err)
+			return nil, err
+		}
+		payloads = append(payloads, builders[i].payloads)
 	}
 
-	return pb.payloads, pb2.payloads, nil
+	return payloads, nil
 }
 
 func newPayloadsBuilder(bufferContext *marshaler.BufferContext, config config.Component, strategy compression.Component) payloadsBuilder {
@@ -0,0 +1,102 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package serializer
+
+import (
+	"path"
+	"sort"
+
+	"github.com/DataDog/datadog-agent/comp/core/config"
+	"github.com/DataDog/datadog-agent/comp/forwarder/defaultforwarder/transaction"
+	"github.com/DataDog/datadog-agent/pkg/config/utils"
+	"github.com/DataDog/datadog-agent/pkg/util/log"
+)
+
+// metricRoute restricts the series and sketches sent to an additional endpoint
+// to the ones whose name matches one of its patterns.
+type metricRoute struct {
+	domain   string
+	patterns []string
+}
+
+func (r metricRoute) match(name string) bool {
+	for _, pattern := range r.patterns {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// newMetricRoutes returns the routes configured with `additional_endpoints_routing`, sorted by domain.
+func newMetricRoutes(config config.Component) []metricRoute {
+	var routes []metricRoute
+	for domain, patterns := range utils.GetMetricsRoutingRules(config) {
+		route := metricRoute{domain: domain}
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				log.Errorf("Ignoring the invalid metric name pattern %q routed to %q: %v", pattern, domain, err)
+				continue
+			}
+			route.patterns = append(route.patterns, pattern)
+		}
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].domain < routes[j].domain })
+	return routes
+}
+
+// metricFilters returns the filters used to split the series and sketches
+// payloads in a single pass. The first filter keeps all the metrics, it is
+// followed by the filter of the failover allowlist, if any, and by the filter
+// of each route.
+func (s *Serializer) metricFilters() (filters []func(name string) bool, failoverSplit bool) {
+	filters = []func(name string) bool{nil}
+
+	failoverActive, allowlist := s.getFailoverAllowlist()
+	if failoverSplit = failoverActive && len(allowlist) > 0; failoverSplit {
+		filters = append(filters, func(name string) bool {
+			_, allowed := allowlist[name]
+			return allowed
+		})
+	}
+	for _, route := range s.metricRoutes {
+		filters = append(filters, route.match)
+	}
+	return filters, failoverSplit
+}
+
+// routePayloads sets the destination and the route of the payloads built for
+// each filter returned by metricFilters and flattens them.
+func (s *Serializer) routePayloads(payloadsPerFilter []transaction.BytesPayloads, failoverSplit bool) transaction.BytesPayloads {
+	var payloads transaction.BytesPayloads
+	for _, payload := range payloadsPerFilter[0] {
+		if failoverSplit {
+			payload.Destination = transaction.PrimaryOnly
+		} else {
+			payload.Destination = transaction.AllRegions
+		}
+	}
+	payloads = append(payloads, payloadsPerFilter[0]...)
+
+	routed := payloadsPerFilter[1:]
+	if failoverSplit {
+		for _, payload := range payloadsPerFilter[1] {
+			payload.Destination = transaction.SecondaryOnly
+		}
+		payloads = append(payloads, payloadsPerFilter[1]...)
+		routed = payloadsPerFilter[2:]
+	}
+
+	for i, route := range s.metricRoutes {
+		for _, payload := range routed[i] {
+			payload.Destination = transaction.AllRegions
+			payload.Route = route.domain
+		}
+		payloads = append(payloads, routed[i]...)
+	}
+	return payloads
+}
@@ -130,6 +130,9 @@ type Serializer struct {
 	enableEventsJSONStream        bool
 	enableSketchProtobufStream    bool
 	hostname                      string
+
+	// metricRoutes lists the additional endpoints which only receive some of the series and sketches
+	metricRoutes []metricRoute
 }
 
 // NewSerializer returns a new Serializer initialized
@@ -157,6 +160,7 @@ func NewSerializer(forwarder forwarder.Forwarder, orchestratorForwarder orchestr
 		protobufExtraHeaders:                make(http.Header),
 		jsonExtraHeadersWithCompression:     make(http.Header),
 		protobufExtraHeadersWithCompression: make(http.Header),
+		metricRoutes:                        newMetricRoutes(config),
 	}
 
 	initExtraHeaders(s)
@@ -177,6 +181,10 @@ func NewSerializer(forwarder forwarder.Forwarder, orchestratorForwarder orchestr
 		log.Warn("JSON to V1 intake is disabled: all payloads to that endpoint will be dropped")
 	}
 
+	if len(s.metricRoutes) > 0 && (!config.GetBool("use_v2_api.series") || !s.enableSketchProtobufStream) {
+		log.Warn("'additional_endpoints_routing' requires the v2 series API and the sketch stream payload serialization: the routed endpoints will not receive the other payloads")
+	}
+
 	if !config.GetBool("enable_sketch_stream_payload_serialization") {
 		log.Warn("'enable_sketch_stream_payload_serialization' is set to false which is not recommended. This option is deprecated and will removed in the future. If you need this option, please reach out to support")
 	}
@@ -350,21 +358,20 @@ func (s *Serializer) SendIterableSeries(serieSource metrics.SerieSource) error {
 	} else if useV1API && !s.enableJSONStream {
 		seriesBytesPayloads, extraHeaders, err = s.serializePayloadJSON(seriesSerializer, true)
 	} else {
-		failoverActive, allowlist := s.getFailoverAllowlist()
-
-		if failoverActive && len(allowlist) > 0 {
-			var filtered transaction.BytesPayloads
-			seriesBytesPayloads, filtered, err = seriesSerializer.MarshalSplitCompressMultiple(s.config, s.Strategy, func(s *metrics.Serie) bool {
-				_, allowed := allowlist[s.Name]
-				return allowed
-			})
-			for _, seriesBytesPayload := range seriesBytesPayloads {
-				seriesBytesPayload.Destination = transaction.PrimaryOnly
+		filters, failoverSplit := s.metricFilters()
+
+		if len(filters) > 1 {
+			seriesFilters := make([]func(*metrics.Serie) bool, len(filters))
+			for i, filter := range filters {
+				if filter != nil {
+					seriesFilters[i] = func(serie *metrics.Serie) bool { return filter(serie.Name) }
+				}
 			}
-			for _, seriesBytesPayload := range filtered {
-				seriesBytesPayload.Destination = transaction.SecondaryOnly
+			var payloadsPerFilter []transaction.BytesPayloads
+			payloadsPerFilter, err = seriesSerializer.MarshalSplitCompressFilters(s.config, s.Strategy, seriesFilters)
+			if err == nil {
+				seriesBytesPayloads = s.routePayloads(payloadsPerFilter, failoverSplit)
 			}
-			seriesBytesPayloads = append(seriesBytesPayloads, filtered...)
 		} else {
 			seriesBytesPayloads, err = seriesSerializer.MarshalSplitCompress(marshaler.NewBufferContext(), s.config, s.Strategy)
 			for _, seriesBytesPayload := range seriesBytesPayloads {
@@ -410,25 +417,21 @@ func (s *Serializer) SendSketch(sketches metrics.SketchesSource) error {
 	}
 	sketchesSerializer := metricsserializer.SketchSeriesList{SketchesSource: sketches}
 	if s.enableSketchProtobufStream {
-		failoverActive, allowlist := s.getFailoverAllowlist()
+		filters, failoverSplit := s.metricFilters()
 
-		if failoverActive && len(allowlist) > 0 {
-			payloads, filteredPayloads, err := sketchesSerializer.MarshalSplitCompressMultiple(s.config, s.Strategy, func(ss *metrics.SketchSeries) bool {
-				_, allowed := allowlist[ss.Name]
-				return allowed
-			})
+		if len(filters) > 1 {
+			sketchFilters := make([]func(*metrics.SketchSeries) bool, len(filters))
+			for i, filter := range filters {
+				if filter != nil {
+					sketchFilters[i] = func(ss *metrics.SketchSeries) bool { return filter(ss.Name) }
+				}
+			}
+			payloadsPerFilter, err := sketchesSerializer.MarshalSplitCompressFilters(s.config, s.Strategy, sketchFilters)
 			if err != nil {
 				return fmt.Errorf("dropping sketch payload: %v", err)
 			}
-			for _, payload := range payloads {
-				payload.Destination = transaction.PrimaryOnly
-			}
-			for _, payload := range filteredPayloads {
-				payload.Destination = transaction.SecondaryOnly
-			}
-			payloads = append(payloads, filteredPayloads...)
 
-			return s.Forwarder.SubmitSketchSeries(payloads, s.protobufExtraHeadersWithCompression)
+			return s.Forwarder.SubmitSketchSeries(s.routePayloads(payloadsPerFilter, failoverSplit), s.protobufExtraHeadersWithCompression)
 		} else {
 			payloads, err := sketchesSerializer.MarshalSplitCompress(marshaler.NewBufferContext(), s.config, s.Strategy)
 			if err != nil {
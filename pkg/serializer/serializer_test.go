@@ -391,6 +391,42 @@ func TestSendSeries(t *testing.T) {
 	}
 }
 
+func TestSendSeriesWithRoutes(t *testing.T) {
+	f := &forwarder.MockedForwarder{}
+	mockConfig := configmock.New(t)
+	mockConfig.SetWithoutSource("additional_endpoints", map[string][]string{
+		"https://billing.datadoghq.com": {"billingapikey"},
+		"https://other.datadoghq.com":   {"otherapikey"},
+	})
+	mockConfig.SetWithoutSource("additional_endpoints_routing", map[string][]string{
+		"https://billing.datadoghq.com": {"app.billing.*"},
+	})
+	s := NewSerializer(f, nil, compressionimpl.NewCompressor(mockConfig), mockConfig, "testhost")
+	require.Len(t, s.metricRoutes, 1)
+
+	matcher := mock.MatchedBy(func(payloads transaction.BytesPayloads) bool {
+		return len(payloads) == 2 &&
+			payloads[0].Route == "" && payloads[0].GetPointCount() == 2 &&
+			payloads[1].Route == "https://billing.datadoghq.com" && payloads[1].GetPointCount() == 1
+	})
+	f.On("SubmitSeries", matcher, s.protobufExtraHeadersWithCompression).Return(nil).Times(1)
+
+	err := s.SendIterableSeries(metricsserializer.CreateSerieSource(metrics.Series{
+		&metrics.Serie{Name: "app.billing.total", Points: []metrics.Point{{Ts: 10, Value: 1}}},
+		&metrics.Serie{Name: "app.requests", Points: []metrics.Point{{Ts: 10, Value: 1}}},
+	}))
+	require.Nil(t, err)
+	f.AssertExpectations(t)
+}
+
+func TestMetricRoutesMatch(t *testing.T) {
+	route := metricRoute{domain: "https://billing.datadoghq.com", patterns: []string{"app.billing.*", "app.invoices"}}
+	assert.True(t, route.match("app.billing.total"))
+	assert.True(t, route.match("app.invoices"))
+	assert.False(t, route.match("app.invoices.count"))
+	assert.False(t, route.match("app.requests"))
+}
+
 func TestSendSketch(t *testing.T) {
 	tests := map[string]struct {
 		kind string
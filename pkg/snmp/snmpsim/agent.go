@@ -0,0 +1,301 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+// Package snmpsim implements an SNMP agent serving recorded walks, to develop
+// and test profiles without access to the real devices.
+package snmpsim
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+const (
+	// maxPacketSize is the largest UDP datagram we accept and send
+	maxPacketSize = 65507
+	// defaultMaxRepetitions is used for GETBULK requests that don't set it
+	defaultMaxRepetitions = 10
+	// maxResponseVarbinds caps the size of GETBULK responses
+	maxResponseVarbinds = 1000
+
+	usmStatsUnknownEngineIDs = ".1.3.6.1.6.3.15.1.1.4.0"
+)
+
+// Agent is an SNMP agent answering GET, GETNEXT and GETBULK requests from a
+// Store. It supports SNMP v1, v2c and v3 (USM).
+type Agent struct {
+	store  *Store
+	params *gosnmp.GoSNMP
+	// usm holds the v3 security parameters of the agent, nil if v3 is disabled
+	usm       *gosnmp.UsmSecurityParameters
+	startTime time.Time
+
+	unknownEngineIDs atomic.Uint32
+
+	mu     sync.Mutex
+	conn   net.PacketConn
+	closed bool
+}
+
+// NewAgent returns an agent serving the store.
+//
+// The Version, Community, MsgFlags and SecurityParameters fields of params
+// define which requests are accepted: v1 and v2c requests must use the
+// community string, and v3 requests the user defined in the USM security
+// parameters. If AuthoritativeEngineID is empty, an engine ID is generated.
+func NewAgent(store *Store, params *gosnmp.GoSNMP) (*Agent, error) {
+	a := &Agent{
+		store:     store,
+		params:    params,
+		startTime: time.Now(),
+	}
+
+	if params.Version == gosnmp.Version3 {
+		usm, ok := params.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		if !ok || usm == nil {
+			return nil, errors.New("SNMP v3 requires USM security parameters")
+		}
+		if usm.AuthoritativeEngineID == "" {
+			usm.AuthoritativeEngineID = defaultEngineID()
+		}
+		usm.AuthoritativeEngineBoots = 1
+		params.SecurityModel = gosnmp.UserSecurityModel
+		if err := usm.InitSecurityKeys(); err != nil {
+			return nil, fmt.Errorf("invalid SNMP v3 security parameters: %w", err)
+		}
+		a.usm = usm
+	}
+
+	return a, nil
+}
+
+// defaultEngineID builds an engine ID in the RFC 3411 text format, using the
+// Datadog enterprise number.
+func defaultEngineID() string {
+	return string([]byte{0x80, 0x00, 0x98, 0x93, 0x04}) + "ddsnmpsim"
+}
+
+// Serve answers the requests received on conn until Close is called.
+func (a *Agent) Serve(conn net.PacketConn) error {
+	a.mu.Lock()
+	if a.closed {
+		a.mu.Unlock()
+		return errors.New("agent is closed")
+	}
+	a.conn = conn
+	a.mu.Unlock()
+
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			a.mu.Lock()
+			closed := a.closed
+			a.mu.Unlock()
+			if closed {
+				return nil
+			}
+			return err
+		}
+
+		msg := make([]byte, n)
+		copy(msg, buf[:n])
+
+		response, err := a.HandlePacket(msg)
+		if err != nil {
+			a.params.Logger.Printf("snmpsim: dropping request from %s: %s", addr, err)
+			continue
+		}
+		if response == nil {
+			continue
+		}
+		if _, err := conn.WriteTo(response, addr); err != nil {
+			a.params.Logger.Printf("snmpsim: failed to answer %s: %s", addr, err)
+		}
+	}
+}
+
+// Close stops serving requests.
+func (a *Agent) Close() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.closed = true
+	if a.conn != nil {
+		return a.conn.Close()
+	}
+	return nil
+}
+
+// HandlePacket decodes a request and returns the encoded response, or nil if
+// the request must be ignored.
+func (a *Agent) HandlePacket(msg []byte) ([]byte, error) {
+	request, err := a.params.UnmarshalTrap(msg, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Version != a.params.Version {
+		return nil, fmt.Errorf("unexpected SNMP version %s", request.Version)
+	}
+
+	// the response reuses the request packet, to keep its identifiers and
+	// security parameters
+	requested := request.Variables
+	response := request
+	response.Logger = a.params.Logger
+
+	if request.Version == gosnmp.Version3 {
+		sp, ok := request.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+		if !ok {
+			return nil, errors.New("unexpected security parameters")
+		}
+
+		// RFC 3414 3.2.3: report the engine ID to the clients discovering it
+		if sp.AuthoritativeEngineID != a.usm.AuthoritativeEngineID {
+			a.unknownEngineIDs.Add(1)
+			return a.marshalReport(request, sp)
+		}
+		if sp.UserName != a.usm.UserName {
+			return nil, fmt.Errorf("unknown user %q", sp.UserName)
+		}
+		if request.MsgFlags&gosnmp.AuthPriv < a.params.MsgFlags&gosnmp.AuthPriv {
+			return nil, fmt.Errorf("unsupported security level %s", request.MsgFlags)
+		}
+
+		a.setEngineParameters(sp)
+		response.MsgFlags = request.MsgFlags &^ gosnmp.Reportable
+		if response.MsgFlags&gosnmp.AuthPriv == gosnmp.AuthPriv {
+			if err := a.usm.InitPacket(response); err != nil {
+				return nil, err
+			}
+		}
+	} else if request.Community != a.params.Community {
+		return nil, errors.New("invalid community string")
+	}
+
+	switch request.PDUType {
+	case gosnmp.GetRequest:
+		response.Variables = a.get(request)
+	case gosnmp.GetNextRequest:
+		response.Variables = a.getNext(request)
+	case gosnmp.GetBulkRequest:
+		if request.Version == gosnmp.Version1 {
+			return nil, errors.New("GETBULK is not supported with SNMP v1")
+		}
+		response.Variables = a.getBulk(request)
+	default:
+		return nil, fmt.Errorf("unsupported PDU type %s", request.PDUType)
+	}
+
+	response.PDUType = gosnmp.GetResponse
+	response.Error = gosnmp.NoError
+	response.ErrorIndex = 0
+	response.NonRepeaters = 0
+	response.MaxRepetitions = 0
+
+	if request.Version == gosnmp.Version1 {
+		// SNMP v1 has no exception values, errors are reported in the PDU
+		for i, v := range response.Variables {
+			if v.Type == gosnmp.NoSuchObject || v.Type == gosnmp.NoSuchInstance || v.Type == gosnmp.EndOfMibView {
+				response.Variables = requested
+				response.Error = gosnmp.NoSuchName
+				response.ErrorIndex = uint8(i + 1)
+				break
+			}
+		}
+	}
+
+	return response.MarshalMsg()
+}
+
+func (a *Agent) get(request *gosnmp.SnmpPacket) []gosnmp.SnmpPDU {
+	variables := make([]gosnmp.SnmpPDU, 0, len(request.Variables))
+	for _, v := range request.Variables {
+		variables = append(variables, a.store.Get(v.Name))
+	}
+	return variables
+}
+
+func (a *Agent) getNext(request *gosnmp.SnmpPacket) []gosnmp.SnmpPDU {
+	variables := make([]gosnmp.SnmpPDU, 0, len(request.Variables))
+	for _, v := range request.Variables {
+		variables = append(variables, a.store.GetNext(v.Name))
+	}
+	return variables
+}
+
+// getBulk implements RFC 3416 4.2.3
+func (a *Agent) getBulk(request *gosnmp.SnmpPacket) []gosnmp.SnmpPDU {
+	nonRepeaters := int(request.NonRepeaters)
+	if nonRepeaters > len(request.Variables) {
+		nonRepeaters = len(request.Variables)
+	}
+	maxRepetitions := int(request.MaxRepetitions)
+	if maxRepetitions == 0 {
+		maxRepetitions = defaultMaxRepetitions
+	}
+
+	var variables []gosnmp.SnmpPDU
+	for _, v := range request.Variables[:nonRepeaters] {
+		variables = append(variables, a.store.GetNext(v.Name))
+	}
+
+	repeaters := request.Variables[nonRepeaters:]
+	if len(repeaters) == 0 {
+		return variables
+	}
+
+	current := make([]string, len(repeaters))
+	for i, v := range repeaters {
+		current[i] = v.Name
+	}
+	for r := 0; r < maxRepetitions && len(variables)+len(repeaters) <= maxResponseVarbinds; r++ {
+		done := true
+		for i := range current {
+			next := a.store.GetNext(current[i])
+			if next.Type != gosnmp.EndOfMibView {
+				done = false
+				current[i] = next.Name
+			}
+			variables = append(variables, next)
+		}
+		if done {
+			break
+		}
+	}
+	return variables
+}
+
+// marshalReport builds the report sent to the clients that don't know the
+// engine ID of the agent yet.
+func (a *Agent) marshalReport(request *gosnmp.SnmpPacket, sp *gosnmp.UsmSecurityParameters) ([]byte, error) {
+	a.setEngineParameters(sp)
+
+	report := request
+	report.Logger = a.params.Logger
+	report.PDUType = gosnmp.Report
+	report.MsgFlags = gosnmp.NoAuthNoPriv
+	report.ContextEngineID = a.usm.AuthoritativeEngineID
+	report.Error = gosnmp.NoError
+	report.ErrorIndex = 0
+	report.Variables = []gosnmp.SnmpPDU{{
+		Name:  usmStatsUnknownEngineIDs,
+		Type:  gosnmp.Counter32,
+		Value: a.unknownEngineIDs.Load(),
+	}}
+	return report.MarshalMsg()
+}
+
+func (a *Agent) setEngineParameters(sp *gosnmp.UsmSecurityParameters) {
+	sp.AuthoritativeEngineID = a.usm.AuthoritativeEngineID
+	sp.AuthoritativeEngineBoots = a.usm.AuthoritativeEngineBoots
+	sp.AuthoritativeEngineTime = uint32(time.Since(a.startTime).Seconds())
+}
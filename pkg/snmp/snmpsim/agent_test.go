@@ -0,0 +1,163 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package snmpsim
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSnmprec = `# recorded walk
+1.3.6.1.2.1.1.1.0|4|Cisco IOS Software
+1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1.9.1.1208
+1.3.6.1.2.1.1.3.0|67|123456
+1.3.6.1.2.1.2.2.1.2.1|4|GigabitEthernet0/1
+1.3.6.1.2.1.2.2.1.2.2|4|GigabitEthernet0/2
+1.3.6.1.2.1.2.2.1.6.1|4x|0050568a1b2c
+1.3.6.1.2.1.2.2.1.6.2|4x|0050568a1b2d
+1.3.6.1.2.1.31.1.1.1.6.1|70|18446744073709
+1.3.6.1.2.1.4.20.1.1.10.0.0.1|64|10.0.0.1
+1.3.6.1.2.1.2.2.1.8.1|2|1
+`
+
+func startAgent(t *testing.T, params *gosnmp.GoSNMP) (*Agent, uint16) {
+	t.Helper()
+
+	pdus, err := ParseSnmprec(strings.NewReader(testSnmprec))
+	require.NoError(t, err)
+	store, err := NewStore(pdus)
+	require.NoError(t, err)
+
+	agent, err := NewAgent(store, params)
+	require.NoError(t, err)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go agent.Serve(conn) //nolint:errcheck
+	t.Cleanup(func() { agent.Close() })
+
+	return agent, uint16(conn.LocalAddr().(*net.UDPAddr).Port)
+}
+
+func newClient(t *testing.T, port uint16, client *gosnmp.GoSNMP) *gosnmp.GoSNMP {
+	t.Helper()
+	client.Target = "127.0.0.1"
+	client.Port = port
+	client.Timeout = 2 * time.Second
+	client.Retries = 1
+	require.NoError(t, client.Connect())
+	t.Cleanup(func() { client.Conn.Close() })
+	return client
+}
+
+func TestAgentV2c(t *testing.T) {
+	_, port := startAgent(t, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"})
+	client := newClient(t, port, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"})
+
+	result, err := client.Get([]string{"1.3.6.1.2.1.1.1.0", "1.3.6.1.2.1.1.5.0"})
+	require.NoError(t, err)
+	require.Len(t, result.Variables, 2)
+	assert.Equal(t, []byte("Cisco IOS Software"), result.Variables[0].Value)
+	assert.Equal(t, gosnmp.NoSuchObject, result.Variables[1].Type)
+
+	result, err = client.GetNext([]string{"1.3.6.1.2.1.1.3.0"})
+	require.NoError(t, err)
+	require.Len(t, result.Variables, 1)
+	assert.Equal(t, ".1.3.6.1.2.1.2.2.1.2.1", result.Variables[0].Name)
+
+	pdus, err := client.BulkWalkAll("1.3.6.1.2.1.2.2.1.6")
+	require.NoError(t, err)
+	require.Len(t, pdus, 2)
+	assert.Equal(t, []byte{0x00, 0x50, 0x56, 0x8a, 0x1b, 0x2d}, pdus[1].Value)
+
+	pdus, err = client.WalkAll("1.3.6.1.2.1")
+	require.NoError(t, err)
+	assert.Len(t, pdus, 10)
+}
+
+func TestAgentWrongCommunity(t *testing.T) {
+	_, port := startAgent(t, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"})
+	client := newClient(t, port, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "private"})
+	client.Retries = 0
+	client.Timeout = 200 * time.Millisecond
+
+	_, err := client.Get([]string{"1.3.6.1.2.1.1.1.0"})
+	assert.Error(t, err)
+}
+
+func TestAgentV1(t *testing.T) {
+	_, port := startAgent(t, &gosnmp.GoSNMP{Version: gosnmp.Version1, Community: "public"})
+	client := newClient(t, port, &gosnmp.GoSNMP{Version: gosnmp.Version1, Community: "public"})
+
+	result, err := client.Get([]string{"1.3.6.1.2.1.1.5.0"})
+	require.NoError(t, err)
+	assert.Equal(t, gosnmp.NoSuchName, result.Error)
+	assert.Equal(t, uint8(1), result.ErrorIndex)
+
+	pdus, err := client.WalkAll("1.3.6.1.2.1.2.2.1.2")
+	require.NoError(t, err)
+	assert.Len(t, pdus, 2)
+}
+
+func TestAgentV3(t *testing.T) {
+	_, port := startAgent(t, &gosnmp.GoSNMP{
+		Version:       gosnmp.Version3,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      gosnmp.AuthPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 "datadog",
+			AuthenticationProtocol:   gosnmp.SHA,
+			AuthenticationPassphrase: "authpassword",
+			PrivacyProtocol:          gosnmp.AES,
+			PrivacyPassphrase:        "privpassword",
+		},
+	})
+	client := newClient(t, port, &gosnmp.GoSNMP{
+		Version:       gosnmp.Version3,
+		SecurityModel: gosnmp.UserSecurityModel,
+		MsgFlags:      gosnmp.AuthPriv,
+		SecurityParameters: &gosnmp.UsmSecurityParameters{
+			UserName:                 "datadog",
+			AuthenticationProtocol:   gosnmp.SHA,
+			AuthenticationPassphrase: "authpassword",
+			PrivacyProtocol:          gosnmp.AES,
+			PrivacyPassphrase:        "privpassword",
+		},
+	})
+
+	result, err := client.Get([]string{"1.3.6.1.2.1.1.2.0"})
+	require.NoError(t, err)
+	require.Len(t, result.Variables, 1)
+	assert.Equal(t, ".1.3.6.1.4.1.9.1.1208", result.Variables[0].Value)
+
+	pdus, err := client.BulkWalkAll("1.3.6.1.2.1.2.2.1")
+	require.NoError(t, err)
+	assert.Len(t, pdus, 5)
+}
+
+func TestStore(t *testing.T) {
+	store, err := NewStore([]gosnmp.SnmpPDU{
+		{Name: "1.3.6.1.2.1.1.10.0", Type: gosnmp.Integer, Value: 1},
+		{Name: "1.3.6.1.2.1.1.9.0", Type: gosnmp.Integer, Value: 2},
+		{Name: "1.3.6.1.2.1.1.9.0", Type: gosnmp.Integer, Value: 3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, store.Len())
+
+	assert.Equal(t, 3, store.Get(".1.3.6.1.2.1.1.9.0").Value)
+	assert.Equal(t, gosnmp.NoSuchObject, store.Get("1.3.6.1.2.1.1.9").Type)
+
+	// OIDs are compared numerically
+	assert.Equal(t, "1.3.6.1.2.1.1.9.0", store.GetNext("1.3.6.1.2.1.1").Name)
+	assert.Equal(t, "1.3.6.1.2.1.1.10.0", store.GetNext("1.3.6.1.2.1.1.9.0").Name)
+	assert.Equal(t, gosnmp.EndOfMibView, store.GetNext("1.3.6.1.2.1.1.10.0").Type)
+}
@@ -0,0 +1,186 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package snmpsim
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// snmprecTags maps the ASN.1 types to the tags used in the .snmprec format.
+// See https://docs.lextudio.com/snmpsim/documentation/managing-data.html
+var snmprecTags = map[gosnmp.Asn1BER]string{
+	gosnmp.Integer:          "2",
+	gosnmp.OctetString:      "4",
+	gosnmp.Null:             "5",
+	gosnmp.ObjectIdentifier: "6",
+	gosnmp.IPAddress:        "64",
+	gosnmp.Counter32:        "65",
+	gosnmp.Gauge32:          "66",
+	gosnmp.TimeTicks:        "67",
+	gosnmp.Opaque:           "68",
+	gosnmp.Counter64:        "70",
+}
+
+var snmprecTypes = func() map[string]gosnmp.Asn1BER {
+	types := make(map[string]gosnmp.Asn1BER, len(snmprecTags))
+	for t, tag := range snmprecTags {
+		types[tag] = t
+	}
+	return types
+}()
+
+// FormatSnmprec formats a PDU as a line of a .snmprec file, without the
+// trailing newline.
+func FormatSnmprec(pdu gosnmp.SnmpPDU) (string, error) {
+	oid := strings.TrimLeft(pdu.Name, ".")
+
+	tag, ok := snmprecTags[pdu.Type]
+	if !ok {
+		return "", fmt.Errorf("oid %s: unsupported type %s", oid, pdu.Type)
+	}
+
+	var value string
+	switch pdu.Type {
+	case gosnmp.OctetString, gosnmp.Opaque:
+		b, ok := pdu.Value.([]byte)
+		if !ok {
+			return "", fmt.Errorf("oid %s: %s should be []byte type but got type `%T`", oid, pdu.Type, pdu.Value)
+		}
+		if isPrintable(b) {
+			value = string(b)
+		} else {
+			tag += "x"
+			value = hex.EncodeToString(b)
+		}
+	case gosnmp.ObjectIdentifier, gosnmp.IPAddress:
+		s, ok := pdu.Value.(string)
+		if !ok {
+			return "", fmt.Errorf("oid %s: %s should be string type but got type `%T`", oid, pdu.Type, pdu.Value)
+		}
+		value = strings.TrimLeft(s, ".")
+	case gosnmp.Null:
+	default:
+		value = gosnmp.ToBigInt(pdu.Value).String()
+	}
+
+	return oid + "|" + tag + "|" + value, nil
+}
+
+// ParseSnmprec reads the records of a .snmprec file.
+func ParseSnmprec(r io.Reader) ([]gosnmp.SnmpPDU, error) {
+	var pdus []gosnmp.SnmpPDU
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "|", 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("line %d: expected <oid>|<tag>|<value>, got %q", lineNumber, line)
+		}
+
+		pdu, err := parseSnmprecRecord(parts[0], parts[1], parts[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		pdus = append(pdus, pdu)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return pdus, nil
+}
+
+func parseSnmprecRecord(oid, tag, value string) (gosnmp.SnmpPDU, error) {
+	oid = strings.TrimLeft(oid, ".")
+
+	hexValue := strings.HasSuffix(tag, "x")
+	tag = strings.TrimSuffix(tag, "x")
+
+	asnType, ok := snmprecTypes[tag]
+	if !ok {
+		return gosnmp.SnmpPDU{}, fmt.Errorf("oid %s: unsupported tag %q", oid, tag)
+	}
+
+	pdu := gosnmp.SnmpPDU{Name: oid, Type: asnType}
+
+	if hexValue {
+		if asnType != gosnmp.OctetString && asnType != gosnmp.Opaque {
+			return pdu, fmt.Errorf("oid %s: hex values are only supported for octet strings", oid)
+		}
+		b, err := hex.DecodeString(value)
+		if err != nil {
+			return pdu, fmt.Errorf("oid %s: invalid hex value: %w", oid, err)
+		}
+		pdu.Value = b
+		return pdu, nil
+	}
+
+	var err error
+	pdu.Value, err = parseValue(asnType, value)
+	if err != nil {
+		return pdu, fmt.Errorf("oid %s: %w", oid, err)
+	}
+	return pdu, nil
+}
+
+// parseValue converts a string value to the Go type gosnmp expects for the
+// given ASN.1 type.
+func parseValue(asnType gosnmp.Asn1BER, value string) (interface{}, error) {
+	switch asnType {
+	case gosnmp.OctetString, gosnmp.Opaque:
+		return []byte(value), nil
+	case gosnmp.ObjectIdentifier, gosnmp.IPAddress:
+		return strings.TrimLeft(value, "."), nil
+	case gosnmp.Null:
+		return nil, nil
+	case gosnmp.Integer:
+		v, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer value %q: %w", value, err)
+		}
+		return int(v), nil
+	case gosnmp.Counter32, gosnmp.Gauge32, gosnmp.TimeTicks:
+		v, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", asnType, value, err)
+		}
+		return uint32(v), nil
+	case gosnmp.Counter64:
+		v, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q: %w", asnType, value, err)
+		}
+		return v, nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", asnType)
+	}
+}
+
+// isPrintable returns whether the bytes can be written as is in a .snmprec
+// record, which is line based.
+func isPrintable(b []byte) bool {
+	for _, c := range b {
+		if c < 32 || c > 126 {
+			return false
+		}
+	}
+	return true
+}
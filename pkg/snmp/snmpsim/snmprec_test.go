@@ -0,0 +1,94 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package snmpsim
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSnmprec(t *testing.T) {
+	tests := []struct {
+		pdu  gosnmp.SnmpPDU
+		want string
+	}{
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.1.1.0", Type: gosnmp.OctetString, Value: []byte("Linux host")}, "1.3.6.1.2.1.1.1.0|4|Linux host"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.2.2.1.6.1", Type: gosnmp.OctetString, Value: []byte{0x00, 0x50, 0x56}}, "1.3.6.1.2.1.2.2.1.6.1|4x|005056"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.1.1.0", Type: gosnmp.OctetString, Value: []byte("multi\nline")}, "1.3.6.1.2.1.1.1.0|4x|6d756c74690a6c696e65"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.1.2.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.9"}, "1.3.6.1.2.1.1.2.0|6|1.3.6.1.4.1.9"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(42)}, "1.3.6.1.2.1.1.3.0|67|42"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.2.2.1.8.1", Type: gosnmp.Integer, Value: -1}, "1.3.6.1.2.1.2.2.1.8.1|2|-1"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.2.2.1.10.1", Type: gosnmp.Counter32, Value: uint(7)}, "1.3.6.1.2.1.2.2.1.10.1|65|7"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.31.1.1.1.6.1", Type: gosnmp.Counter64, Value: uint64(1 << 40)}, "1.3.6.1.2.1.31.1.1.1.6.1|70|1099511627776"},
+		{gosnmp.SnmpPDU{Name: ".1.3.6.1.2.1.4.20.1.1.10.0.0.1", Type: gosnmp.IPAddress, Value: "10.0.0.1"}, "1.3.6.1.2.1.4.20.1.1.10.0.0.1|64|10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			line, err := FormatSnmprec(tt.pdu)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, line)
+
+			// records can be read back
+			pdus, err := ParseSnmprec(strings.NewReader(line))
+			require.NoError(t, err)
+			require.Len(t, pdus, 1)
+			assert.Equal(t, tt.pdu.Type, pdus[0].Type)
+			assert.Equal(t, gosnmp.ToBigInt(tt.pdu.Value), gosnmp.ToBigInt(pdus[0].Value))
+		})
+	}
+
+	_, err := FormatSnmprec(gosnmp.SnmpPDU{Name: "1.3", Type: gosnmp.EndOfMibView})
+	assert.Error(t, err)
+}
+
+func TestParseSnmprecErrors(t *testing.T) {
+	for _, content := range []string{
+		"1.3.6.1.2.1.1.1.0|4",
+		"1.3.6.1.2.1.1.1.0|99|foo",
+		"1.3.6.1.2.1.1.3.0|67|-1",
+		"1.3.6.1.2.1.1.1.0|4x|zz",
+		"1.3.6.1.2.1.1.3.0|2x|00",
+	} {
+		_, err := ParseSnmprec(strings.NewReader(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestParseWalk(t *testing.T) {
+	walk := `.1.3.6.1.2.1.1.1.0 = STRING: "Linux host 5.10
+#1 SMP"
+.1.3.6.1.2.1.1.2.0 = OID: .1.3.6.1.4.1.8072.3.2.10
+.1.3.6.1.2.1.1.3.0 = Timeticks: (4224) 0:00:42.24
+.1.3.6.1.2.1.1.4.0 = ""
+.1.3.6.1.2.1.2.2.1.6.2 = Hex-STRING: 00 50 56 8A 1B 2C
+.1.3.6.1.2.1.2.2.1.8.1 = INTEGER: up(1)
+.1.3.6.1.2.1.2.2.1.10.1 = Counter32: 1234
+.1.3.6.1.2.1.31.1.1.1.6.1 = Counter64: 123456789012
+.1.3.6.1.2.1.2.2.1.5.1 = Gauge32: 1000000000
+.1.3.6.1.2.1.4.20.1.1.10.0.0.1 = IpAddress: 10.0.0.1
+.1.3.6.1.2.1.25.1.1.0 = 4242
+.1.3.6.1.2.1.25.1.2.0 = No Such Object available on this agent at this OID
+`
+	pdus, err := ParseWalk(strings.NewReader(walk))
+	require.NoError(t, err)
+	require.Len(t, pdus, 11)
+
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.1.1.0", Type: gosnmp.OctetString, Value: []byte("Linux host 5.10\n#1 SMP")}, pdus[0])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.1.2.0", Type: gosnmp.ObjectIdentifier, Value: "1.3.6.1.4.1.8072.3.2.10"}, pdus[1])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(4224)}, pdus[2])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.1.4.0", Type: gosnmp.OctetString, Value: []byte{}}, pdus[3])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.6.2", Type: gosnmp.OctetString, Value: []byte{0x00, 0x50, 0x56, 0x8a, 0x1b, 0x2c}}, pdus[4])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.8.1", Type: gosnmp.Integer, Value: 1}, pdus[5])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.10.1", Type: gosnmp.Counter32, Value: uint32(1234)}, pdus[6])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.31.1.1.1.6.1", Type: gosnmp.Counter64, Value: uint64(123456789012)}, pdus[7])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.2.2.1.5.1", Type: gosnmp.Gauge32, Value: uint32(1000000000)}, pdus[8])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.4.20.1.1.10.0.0.1", Type: gosnmp.IPAddress, Value: "10.0.0.1"}, pdus[9])
+	assert.Equal(t, gosnmp.SnmpPDU{Name: "1.3.6.1.2.1.25.1.1.0", Type: gosnmp.TimeTicks, Value: uint32(4242)}, pdus[10])
+}
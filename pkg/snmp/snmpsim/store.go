@@ -0,0 +1,101 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package snmpsim
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gosnmp/gosnmp"
+
+	"github.com/DataDog/datadog-agent/pkg/snmp/gosnmplib"
+)
+
+type storeEntry struct {
+	oid []int
+	pdu gosnmp.SnmpPDU
+}
+
+// Store holds the recorded values of a device, ordered by OID.
+type Store struct {
+	entries []storeEntry
+}
+
+// NewStore returns a store serving the given PDUs. When an OID is recorded
+// several times, the last value wins.
+func NewStore(pdus []gosnmp.SnmpPDU) (*Store, error) {
+	entries := make([]storeEntry, 0, len(pdus))
+	for _, pdu := range pdus {
+		oid, err := gosnmplib.OIDToInts(pdu.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(oid) == 0 {
+			return nil, fmt.Errorf("empty OID")
+		}
+		entries = append(entries, storeEntry{oid: oid, pdu: pdu})
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return gosnmplib.CmpOIDs(entries[i].oid, entries[j].oid).IsBefore()
+	})
+
+	// dedupe, keeping the last recorded value
+	deduped := entries[:0]
+	for _, e := range entries {
+		if n := len(deduped); n > 0 && gosnmplib.CmpOIDs(deduped[n-1].oid, e.oid) == gosnmplib.EQUAL {
+			deduped[n-1] = e
+			continue
+		}
+		deduped = append(deduped, e)
+	}
+
+	return &Store{entries: deduped}, nil
+}
+
+// Len returns the number of OIDs in the store.
+func (s *Store) Len() int {
+	return len(s.entries)
+}
+
+// Get returns the value of the given OID, or a NoSuchObject PDU if it was
+// not recorded.
+func (s *Store) Get(oid string) gosnmp.SnmpPDU {
+	oidInts, err := gosnmplib.OIDToInts(oid)
+	if err == nil {
+		i := s.search(oidInts)
+		if i < len(s.entries) && gosnmplib.CmpOIDs(s.entries[i].oid, oidInts) == gosnmplib.EQUAL {
+			return s.entries[i].pdu
+		}
+	}
+	return gosnmp.SnmpPDU{Name: oid, Type: gosnmp.NoSuchObject}
+}
+
+// GetNext returns the value of the first OID after the given one, or an
+// EndOfMibView PDU if there is none.
+func (s *Store) GetNext(oid string) gosnmp.SnmpPDU {
+	oidInts, err := gosnmplib.OIDToInts(oid)
+	if err != nil {
+		return gosnmp.SnmpPDU{Name: oid, Type: gosnmp.EndOfMibView}
+	}
+
+	i := s.search(oidInts)
+	if i < len(s.entries) && gosnmplib.CmpOIDs(s.entries[i].oid, oidInts) == gosnmplib.EQUAL {
+		i++
+	}
+	if i >= len(s.entries) {
+		return gosnmp.SnmpPDU{Name: oid, Type: gosnmp.EndOfMibView}
+	}
+	return s.entries[i].pdu
+}
+
+// search returns the index of the first entry whose OID is not before the
+// given one.
+func (s *Store) search(oid []int) int {
+	return sort.Search(len(s.entries), func(i int) bool {
+		return !gosnmplib.CmpOIDs(s.entries[i].oid, oid).IsBefore()
+	})
+}
@@ -0,0 +1,162 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package snmpsim
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/gosnmp/gosnmp"
+)
+
+// walkTypes maps the type prefixes printed by snmpwalk to the ASN.1 types.
+var walkTypes = map[string]gosnmp.Asn1BER{
+	"STRING":     gosnmp.OctetString,
+	"Hex-STRING": gosnmp.OctetString,
+	"OID":        gosnmp.ObjectIdentifier,
+	"INTEGER":    gosnmp.Integer,
+	"Counter32":  gosnmp.Counter32,
+	"Gauge32":    gosnmp.Gauge32,
+	"Counter64":  gosnmp.Counter64,
+	"Timeticks":  gosnmp.TimeTicks,
+	"IpAddress":  gosnmp.IPAddress,
+	"Opaque":     gosnmp.Opaque,
+}
+
+var (
+	// enumValueRegex matches the enum values printed when a MIB is loaded, e.g. `up(1)`
+	enumValueRegex = regexp.MustCompile(`^[^(]*\((-?\d+)\)$`)
+	// timeticksValueRegex matches the timeticks values printed by snmpwalk, e.g. `(12345) 0:02:03.45`
+	timeticksValueRegex = regexp.MustCompile(`^\((\d+)\)`)
+)
+
+// ParseWalk reads the output of `snmpwalk -On` (or `agent snmp walk`), one
+// `<oid> = <type>: <value>` line per OID.
+func ParseWalk(r io.Reader) ([]gosnmp.SnmpPDU, error) {
+	var pdus []gosnmp.SnmpPDU
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		oid, value, found := strings.Cut(line, " = ")
+		if !found || !strings.HasPrefix(oid, ".") && !startsWithDigit(oid) {
+			// snmpwalk prints strings containing newlines over several lines
+			if len(pdus) > 0 && pdus[len(pdus)-1].Type == gosnmp.OctetString {
+				last := &pdus[len(pdus)-1]
+				last.Value = append(last.Value.([]byte), []byte("\n"+strings.TrimSuffix(line, `"`))...)
+				continue
+			}
+			return nil, fmt.Errorf("line %d: expected <oid> = <type>: <value>, got %q", lineNumber, line)
+		}
+
+		pdu, skip, err := parseWalkRecord(oid, value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		if !skip {
+			pdus = append(pdus, pdu)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return pdus, nil
+}
+
+func parseWalkRecord(oid, value string) (gosnmp.SnmpPDU, bool, error) {
+	oid = strings.TrimLeft(strings.TrimSpace(oid), ".")
+	value = strings.TrimSpace(value)
+
+	switch {
+	case value == `""`:
+		return gosnmp.SnmpPDU{Name: oid, Type: gosnmp.OctetString, Value: []byte{}}, false, nil
+	case strings.HasPrefix(value, "No Such") || strings.HasPrefix(value, "No more variables"):
+		return gosnmp.SnmpPDU{}, true, nil
+	}
+
+	typeName, rawValue, found := strings.Cut(value, ": ")
+	if !found {
+		typeName, rawValue = strings.TrimSuffix(value, ":"), ""
+	}
+
+	asnType, ok := walkTypes[typeName]
+	if !ok {
+		// `agent snmp walk` prints timeticks without type
+		if startsWithDigit(value) {
+			asnType, rawValue = gosnmp.TimeTicks, value
+		} else {
+			return gosnmp.SnmpPDU{}, false, fmt.Errorf("oid %s: unsupported value %q", oid, value)
+		}
+	}
+
+	pdu := gosnmp.SnmpPDU{Name: oid, Type: asnType}
+
+	switch {
+	case typeName == "Hex-STRING" || asnType == gosnmp.Opaque:
+		b, err := hex.DecodeString(strings.ReplaceAll(rawValue, " ", ""))
+		if err != nil {
+			return pdu, false, fmt.Errorf("oid %s: invalid hex value: %w", oid, err)
+		}
+		pdu.Value = b
+		return pdu, false, nil
+	case asnType == gosnmp.OctetString:
+		pdu.Value = []byte(strings.TrimSuffix(strings.TrimPrefix(rawValue, `"`), `"`))
+		return pdu, false, nil
+	case asnType == gosnmp.TimeTicks:
+		if m := timeticksValueRegex.FindStringSubmatch(rawValue); m != nil {
+			rawValue = m[1]
+		}
+	case asnType == gosnmp.Integer:
+		if m := enumValueRegex.FindStringSubmatch(rawValue); m != nil {
+			rawValue = m[1]
+		}
+	}
+
+	// Strip the units printed when a MIB is loaded, e.g. `1000 octets`
+	if asnType != gosnmp.ObjectIdentifier && asnType != gosnmp.IPAddress {
+		rawValue, _, _ = strings.Cut(rawValue, " ")
+	}
+
+	var err error
+	pdu.Value, err = parseValue(asnType, rawValue)
+	if err != nil {
+		return pdu, false, fmt.Errorf("oid %s: %w", oid, err)
+	}
+	return pdu, false, nil
+}
+
+// LoadFile loads a recorded walk, either in the .snmprec format or as the
+// output of snmpwalk, depending on the file extension.
+func LoadFile(path string) ([]gosnmp.SnmpPDU, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if filepath.Ext(path) == ".snmprec" {
+		return ParseSnmprec(f)
+	}
+	return ParseWalk(f)
+}
+
+func startsWithDigit(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9'
+}
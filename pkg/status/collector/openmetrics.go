@@ -0,0 +1,73 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package collector
+
+import (
+	"encoding/json"
+	"expvar"
+
+	"github.com/DataDog/datadog-agent/comp/core/status"
+)
+
+// runnerStats is the part of the runner expvar exposed as OpenMetrics
+type runnerStats struct {
+	Checks        map[string]map[string]checkStats
+	RunningChecks int64
+	StuckChecks   int64
+}
+
+type checkStats struct {
+	CheckName        string
+	CheckID          string
+	Stuck            bool
+	TotalRuns        uint64
+	TotalErrors      uint64
+	TotalWarnings    uint64
+	TotalRunTimeouts uint64
+}
+
+// OpenMetrics returns the check run counts and errors
+func (Provider) OpenMetrics() ([]*status.MetricFamily, error) {
+	runner := expvar.Get("runner")
+	if runner == nil {
+		return nil, nil
+	}
+	return runnerMetricFamilies([]byte(runner.String()))
+}
+
+func runnerMetricFamilies(runnerStatsJSON []byte) ([]*status.MetricFamily, error) {
+	var stats runnerStats
+	if err := json.Unmarshal(runnerStatsJSON, &stats); err != nil {
+		return nil, err
+	}
+
+	runs := status.NewCounter("check_runs", "Number of runs of the check instance.")
+	errors := status.NewCounter("check_errors", "Number of runs of the check instance which returned an error.")
+	warnings := status.NewCounter("check_warnings", "Number of warnings raised by the check instance.")
+	timeouts := status.NewCounter("check_run_timeouts", "Number of runs of the check instance which exceeded the run timeout.")
+	stuck := status.NewGauge("check_stuck", "Whether the check instance is still running after exceeding its run timeout.")
+	for _, instances := range stats.Checks {
+		for _, instance := range instances {
+			labels := []string{"check_name", instance.CheckName, "check_id", instance.CheckID}
+			runs.Add(float64(instance.TotalRuns), labels...)
+			errors.Add(float64(instance.TotalErrors), labels...)
+			warnings.Add(float64(instance.TotalWarnings), labels...)
+			timeouts.Add(float64(instance.TotalRunTimeouts), labels...)
+			stuckValue := 0.0
+			if instance.Stuck {
+				stuckValue = 1
+			}
+			stuck.Add(stuckValue, labels...)
+		}
+	}
+
+	running := status.NewGauge("running_checks", "Number of check instances currently running.")
+	running.Add(float64(stats.RunningChecks))
+	stuckChecks := status.NewGauge("stuck_checks", "Number of check instances still running after exceeding their run timeout.")
+	stuckChecks.Add(float64(stats.StuckChecks))
+
+	return []*status.MetricFamily{runs, errors, warnings, timeouts, stuck, running, stuckChecks}, nil
+}
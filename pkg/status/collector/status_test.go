@@ -59,3 +59,33 @@ func TestRender(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenMetrics(t *testing.T) {
+	runnerStatsJSON := `{
+		"Checks": {
+			"cpu": {"cpu": {"CheckName": "cpu", "CheckID": "cpu", "TotalRuns": 10, "TotalErrors": 1, "TotalWarnings": 2}},
+			"http_check": {"http_check:1234": {"CheckName": "http_check", "CheckID": "http_check:1234", "TotalRuns": 3, "TotalRunTimeouts": 1, "Stuck": true}}
+		},
+		"RunningChecks": 2,
+		"StuckChecks": 1
+	}`
+	families, err := runnerMetricFamilies([]byte(runnerStatsJSON))
+	require.NoError(t, err)
+
+	output := new(bytes.Buffer)
+	require.NoError(t, status.RenderOpenMetrics(output, families))
+
+	for _, line := range []string{
+		`datadog_agent_check_runs_total{check_id="cpu",check_name="cpu"} 10`,
+		`datadog_agent_check_runs_total{check_id="http_check:1234",check_name="http_check"} 3`,
+		`datadog_agent_check_errors_total{check_id="cpu",check_name="cpu"} 1`,
+		`datadog_agent_check_warnings_total{check_id="cpu",check_name="cpu"} 2`,
+		`datadog_agent_check_run_timeouts_total{check_id="http_check:1234",check_name="http_check"} 1`,
+		`datadog_agent_check_stuck{check_id="cpu",check_name="cpu"} 0`,
+		`datadog_agent_check_stuck{check_id="http_check:1234",check_name="http_check"} 1`,
+		`datadog_agent_running_checks 2`,
+		`datadog_agent_stuck_checks 1`,
+	} {
+		require.Contains(t, output.String(), line+"\n")
+	}
+}
@@ -37,6 +37,11 @@ type Endpoint struct {
 	// NoProxy will be set to true when the proxy setting for the trace API endpoint
 	// needs to be ignored (e.g. it is part of the "no_proxy" list in the yaml settings).
 	NoProxy bool
+
+	// RoutingRules restricts the traces sent to this endpoint to the ones matching
+	// at least one of the rules, in the "key:value" format. All the traces are sent
+	// when it is empty.
+	RoutingRules []string
 }
 
 // TelemetryEndpointPrefix specifies the prefix of the telemetry endpoint URL.
@@ -8,6 +8,7 @@ package watchdog
 import (
 	"fmt"
 	"runtime"
+	"sync"
 
 	"github.com/DataDog/datadog-agent/pkg/trace/log"
 
@@ -25,6 +26,20 @@ func shortErrMsg(msg string) string {
 	return msg[:shortErrMsgLen] + "..."
 }
 
+var (
+	panicHookMu sync.RWMutex
+	panicHook   func(msg string, stacktrace string)
+)
+
+// SetPanicHook sets a function called by LogOnPanic with the panics it
+// catches, before propagating them. It can be used to capture the state of the
+// process before it crashes.
+func SetPanicHook(hook func(msg string, stacktrace string)) {
+	panicHookMu.Lock()
+	defer panicHookMu.Unlock()
+	panicHook = hook
+}
+
 // LogOnPanic catches panics and logs them on the fly. It also flushes
 // the log file, ensuring the message appears. Then it propagates the panic
 // so that the program flow remains unchanged.
@@ -44,6 +59,13 @@ func LogOnPanic(statsd statsd.ClientInterface) {
 		log.Error(logMsg)
 		log.Flush()
 
+		panicHookMu.RLock()
+		hook := panicHook
+		panicHookMu.RUnlock()
+		if hook != nil {
+			hook(errMsg, stacktrace)
+		}
+
 		panic(err)
 	}
 }
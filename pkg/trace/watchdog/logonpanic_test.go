@@ -77,6 +77,23 @@ func TestLogOnPanicGoroutine(t *testing.T) {
 	wg.Wait()
 }
 
+func TestLogOnPanicHook(t *testing.T) {
+	var hookMsg, hookStacktrace string
+	SetPanicHook(func(msg string, stacktrace string) {
+		hookMsg, hookStacktrace = msg, stacktrace
+	})
+	defer SetPanicHook(nil)
+
+	defer func() {
+		r := recover()
+		assert.NotNil(t, r, "panic should bubble up and be trapped here")
+		assert.Equal(t, "hook me", hookMsg)
+		assert.Contains(t, hookStacktrace, "github.com/DataDog/datadog-agent/pkg/trace/watchdog.TestLogOnPanicHook")
+	}()
+	defer LogOnPanic(&statsd.NoOpClient{})
+	panic("hook me")
+}
+
 func TestShortErrMsg(t *testing.T) {
 	assert := assert.New(t)
 
@@ -0,0 +1,254 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package writer
+
+import (
+	"path"
+	"strings"
+
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/log"
+)
+
+// routingRule matches the traces whose env, host, service or tracer payload
+// tag has the given value. The value may contain glob patterns.
+type routingRule struct {
+	key   string
+	value string
+}
+
+func parseRoutingRule(rule string) (routingRule, bool) {
+	key, value, found := strings.Cut(rule, ":")
+	if !found || key == "" || value == "" {
+		return routingRule{}, false
+	}
+	if _, err := path.Match(value, ""); err != nil {
+		return routingRule{}, false
+	}
+	return routingRule{key: key, value: value}, true
+}
+
+func (r routingRule) matchValue(value string) bool {
+	matched, _ := path.Match(r.value, value)
+	return matched
+}
+
+// senderRoute restricts the traces sent by a sender to the chunks matching at
+// least one of its rules.
+type senderRoute struct {
+	sender *sender
+	rules  []routingRule
+
+	// defaultEnv and hostname are used for the tracer payloads which do not set them.
+	defaultEnv string
+	hostname   string
+}
+
+func newSenderRoute(s *sender, rules []string, defaultEnv string, hostname string) *senderRoute {
+	r := &senderRoute{sender: s, defaultEnv: defaultEnv, hostname: hostname}
+	for _, rule := range rules {
+		parsed, ok := parseRoutingRule(rule)
+		if !ok {
+			log.Errorf("Ignoring the invalid trace routing rule %q, rules must use the \"key:value\" format", rule)
+			continue
+		}
+		r.rules = append(r.rules, parsed)
+	}
+	return r
+}
+
+// newSenderRoutes returns the senders of the endpoints without routing rules,
+// which receive all the payloads, and the routes of the other senders.
+func newSenderRoutes(cfg *config.AgentConfig, senders []*sender) ([]*sender, []*senderRoute) {
+	var unrouted []*sender
+	var routes []*senderRoute
+	for i, endpoint := range cfg.Endpoints {
+		if len(endpoint.RoutingRules) == 0 {
+			unrouted = append(unrouted, senders[i])
+			continue
+		}
+		routes = append(routes, newSenderRoute(senders[i], endpoint.RoutingRules, cfg.DefaultEnv, cfg.Hostname))
+	}
+	return unrouted, routes
+}
+
+// filter returns the tracer payloads restricted to the matching chunks. The
+// payloads are not modified, the ones which partially match are copied.
+func (r *senderRoute) filter(payloads []*pb.TracerPayload) []*pb.TracerPayload {
+	var filtered []*pb.TracerPayload
+	for _, tp := range payloads {
+		chunks := make([]*pb.TraceChunk, 0, len(tp.Chunks))
+		for _, chunk := range tp.Chunks {
+			if r.match(tp, chunk) {
+				chunks = append(chunks, chunk)
+			}
+		}
+		switch len(chunks) {
+		case 0:
+		case len(tp.Chunks):
+			filtered = append(filtered, tp)
+		default:
+			filtered = append(filtered, &pb.TracerPayload{
+				ContainerID:     tp.ContainerID,
+				LanguageName:    tp.LanguageName,
+				LanguageVersion: tp.LanguageVersion,
+				TracerVersion:   tp.TracerVersion,
+				RuntimeID:       tp.RuntimeID,
+				Chunks:          chunks,
+				Tags:            tp.Tags,
+				Env:             tp.Env,
+				Hostname:        tp.Hostname,
+				AppVersion:      tp.AppVersion,
+			})
+		}
+	}
+	return filtered
+}
+
+func (r *senderRoute) match(tp *pb.TracerPayload, chunk *pb.TraceChunk) bool {
+	tag := func(key string) (string, bool) {
+		value, ok := tp.Tags[key]
+		return value, ok
+	}
+	if r.matchAttributes(tp.Env, tp.Hostname, tag) {
+		return true
+	}
+	for _, span := range chunk.Spans {
+		if r.matchService(span.Service) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterStats returns the stats payload restricted to the matching client
+// payloads and, when only the service rules match, to the matching groups.
+// The payload is not modified, nil is returned when nothing matches.
+func (r *senderRoute) filterStats(sp *pb.StatsPayload) *pb.StatsPayload {
+	var stats []*pb.ClientStatsPayload
+	for _, p := range sp.Stats {
+		env := p.Env
+		if env == "" {
+			env = sp.AgentEnv
+		}
+		hostname := p.Hostname
+		if hostname == "" {
+			hostname = sp.AgentHostname
+		}
+		tag := func(key string) (string, bool) {
+			for _, t := range p.Tags {
+				if k, v, found := strings.Cut(t, ":"); found && k == key {
+					return v, true
+				}
+			}
+			return "", false
+		}
+		if r.matchAttributes(env, hostname, tag) {
+			stats = append(stats, p)
+			continue
+		}
+		if filtered := r.filterStatsServices(p); filtered != nil {
+			stats = append(stats, filtered)
+		}
+	}
+	if len(stats) == 0 {
+		return nil
+	}
+	return &pb.StatsPayload{
+		AgentHostname:  sp.AgentHostname,
+		AgentEnv:       sp.AgentEnv,
+		Stats:          stats,
+		AgentVersion:   sp.AgentVersion,
+		ClientComputed: sp.ClientComputed,
+		SplitPayload:   sp.SplitPayload,
+	}
+}
+
+// filterStatsServices returns the client payload restricted to the groups of
+// the matching services, or nil when none matches.
+func (r *senderRoute) filterStatsServices(p *pb.ClientStatsPayload) *pb.ClientStatsPayload {
+	var buckets []*pb.ClientStatsBucket
+	for _, b := range p.Stats {
+		var groups []*pb.ClientGroupedStats
+		for _, g := range b.Stats {
+			service := g.Service
+			if service == "" {
+				service = p.Service
+			}
+			if r.matchService(service) {
+				groups = append(groups, g)
+			}
+		}
+		if len(groups) == 0 {
+			continue
+		}
+		buckets = append(buckets, &pb.ClientStatsBucket{
+			Start:          b.Start,
+			Duration:       b.Duration,
+			Stats:          groups,
+			AgentTimeShift: b.AgentTimeShift,
+		})
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+	return &pb.ClientStatsPayload{
+		Hostname:         p.Hostname,
+		Env:              p.Env,
+		Version:          p.Version,
+		Stats:            buckets,
+		Lang:             p.Lang,
+		TracerVersion:    p.TracerVersion,
+		RuntimeID:        p.RuntimeID,
+		Sequence:         p.Sequence,
+		AgentAggregation: p.AgentAggregation,
+		Service:          p.Service,
+		ContainerID:      p.ContainerID,
+		Tags:             p.Tags,
+		GitCommitSha:     p.GitCommitSha,
+		ImageTag:         p.ImageTag,
+	}
+}
+
+// matchAttributes returns true if one of the env, host or tag rules matches.
+// tag returns the value of a tag of the payload.
+func (r *senderRoute) matchAttributes(env string, hostname string, tag func(key string) (string, bool)) bool {
+	for _, rule := range r.rules {
+		switch rule.key {
+		case "env":
+			if env == "" {
+				env = r.defaultEnv
+			}
+			if rule.matchValue(env) {
+				return true
+			}
+		case "host":
+			if hostname == "" {
+				hostname = r.hostname
+			}
+			if rule.matchValue(hostname) {
+				return true
+			}
+		case "service":
+		default:
+			if value, ok := tag(rule.key); ok && rule.matchValue(value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchService returns true if one of the service rules matches service
+func (r *senderRoute) matchService(service string) bool {
+	for _, rule := range r.rules {
+		if rule.key == "service" && rule.matchValue(service) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,224 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+package writer
+
+import (
+	stdgzip "compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/DataDog/datadog-go/v5/statsd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tinylib/msgp/msgp"
+	"google.golang.org/protobuf/proto"
+
+	gzip "github.com/DataDog/datadog-agent/comp/trace/compression/impl-gzip"
+	pb "github.com/DataDog/datadog-agent/pkg/proto/pbgo/trace"
+	"github.com/DataDog/datadog-agent/pkg/trace/config"
+	"github.com/DataDog/datadog-agent/pkg/trace/telemetry"
+	"github.com/DataDog/datadog-agent/pkg/trace/timing"
+)
+
+func newRoutingTestChunk(service string) *pb.TraceChunk {
+	return &pb.TraceChunk{Spans: []*pb.Span{{Service: service, Name: "span", TraceID: 1, SpanID: 1}}}
+}
+
+func TestParseRoutingRule(t *testing.T) {
+	rule, ok := parseRoutingRule("env:prod")
+	assert.True(t, ok)
+	assert.Equal(t, routingRule{key: "env", value: "prod"}, rule)
+
+	for _, invalid := range []string{"prod", "env:", ":prod", "env:[prod"} {
+		_, ok := parseRoutingRule(invalid)
+		assert.False(t, ok, invalid)
+	}
+}
+
+func TestSenderRouteFilter(t *testing.T) {
+	payments := newRoutingTestChunk("payments")
+	checkout := newRoutingTestChunk("checkout")
+	prod := &pb.TracerPayload{Env: "prod", Chunks: []*pb.TraceChunk{checkout}}
+	staging := &pb.TracerPayload{Env: "staging", Hostname: "web-1", Chunks: []*pb.TraceChunk{checkout, payments}}
+	tagged := &pb.TracerPayload{Tags: map[string]string{"team": "billing"}, Chunks: []*pb.TraceChunk{checkout}}
+	payloads := []*pb.TracerPayload{prod, staging, tagged}
+
+	t.Run("env", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"env:prod"}, "", "")
+		assert.Equal(t, []*pb.TracerPayload{prod}, route.filter(payloads))
+	})
+
+	t.Run("default-env", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"env:prod"}, "prod", "")
+		assert.Equal(t, []*pb.TracerPayload{prod, tagged}, route.filter(payloads))
+	})
+
+	t.Run("host", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"host:web-*"}, "", "")
+		assert.Equal(t, []*pb.TracerPayload{staging}, route.filter(payloads))
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"team:billing"}, "", "")
+		assert.Equal(t, []*pb.TracerPayload{tagged}, route.filter(payloads))
+	})
+
+	t.Run("service", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"invalid", "service:pay*"}, "", "")
+		filtered := route.filter(payloads)
+		require.Len(t, filtered, 1)
+		assert.NotSame(t, staging, filtered[0])
+		assert.Equal(t, "staging", filtered[0].Env)
+		assert.Equal(t, "web-1", filtered[0].Hostname)
+		assert.Equal(t, []*pb.TraceChunk{payments}, filtered[0].Chunks)
+		// the original payload is not modified
+		assert.Len(t, staging.Chunks, 2)
+	})
+}
+
+func TestTraceWriterRoutes(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	routedSrv := newTestServer()
+	defer routedSrv.Close()
+	cfg := &config.AgentConfig{
+		Hostname:   testHostname,
+		DefaultEnv: testEnv,
+		Endpoints: []*config.Endpoint{
+			{APIKey: "123", Host: srv.URL},
+			{APIKey: "456", Host: routedSrv.URL, RoutingRules: []string{"service:payments"}},
+		},
+		TraceWriter: &config.WriterConfig{ConnectionLimit: 200},
+	}
+	compressor := gzip.NewComponent()
+	tw := NewTraceWriter(cfg, mockSampler, mockSampler, mockSampler, telemetry.NewNoopCollector(), &statsd.NoOpClient{}, &timing.NoopReporter{}, compressor)
+
+	tw.WriteChunks(&SampledChunks{TracerPayload: &pb.TracerPayload{Chunks: []*pb.TraceChunk{newRoutingTestChunk("checkout")}}, Size: 100, SpanCount: 1})
+	tw.WriteChunks(&SampledChunks{TracerPayload: &pb.TracerPayload{Chunks: []*pb.TraceChunk{newRoutingTestChunk("payments")}}, Size: 100, SpanCount: 1})
+	tw.Stop()
+
+	services := func(payloads []*payload) []string {
+		var services []string
+		for _, p := range payloads {
+			reader, err := compressor.NewReader(p.body)
+			require.NoError(t, err)
+			slurp, err := io.ReadAll(reader)
+			require.NoError(t, err)
+			var ap pb.AgentPayload
+			require.NoError(t, proto.Unmarshal(slurp, &ap))
+			for _, tp := range ap.TracerPayloads {
+				for _, chunk := range tp.Chunks {
+					services = append(services, chunk.Spans[0].Service)
+				}
+			}
+		}
+		return services
+	}
+	assert.ElementsMatch(t, []string{"checkout", "payments"}, services(srv.Payloads()))
+	assert.Equal(t, []string{"payments"}, services(routedSrv.Payloads()))
+}
+
+func TestSenderRouteFilterStats(t *testing.T) {
+	payments := &pb.ClientGroupedStats{Service: "payments", Name: "span", Hits: 1}
+	checkout := &pb.ClientGroupedStats{Service: "checkout", Name: "span", Hits: 1}
+	prod := &pb.ClientStatsPayload{Env: "prod", Stats: []*pb.ClientStatsBucket{{Start: 1, Duration: 10, Stats: []*pb.ClientGroupedStats{checkout}}}}
+	staging := &pb.ClientStatsPayload{Env: "staging", Hostname: "web-1", Stats: []*pb.ClientStatsBucket{{Start: 1, Duration: 10, Stats: []*pb.ClientGroupedStats{checkout, payments}}}}
+	tagged := &pb.ClientStatsPayload{Tags: []string{"team:billing"}, Stats: []*pb.ClientStatsBucket{{Start: 1, Duration: 10, Stats: []*pb.ClientGroupedStats{checkout}}}}
+	sp := &pb.StatsPayload{AgentHostname: "agent", AgentVersion: "v", Stats: []*pb.ClientStatsPayload{prod, staging, tagged}}
+
+	t.Run("env", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"env:prod"}, "", "")
+		filtered := route.filterStats(sp)
+		require.NotNil(t, filtered)
+		assert.Equal(t, "agent", filtered.AgentHostname)
+		assert.Equal(t, []*pb.ClientStatsPayload{prod}, filtered.Stats)
+	})
+
+	t.Run("agent-env", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"env:prod"}, "", "")
+		filtered := route.filterStats(&pb.StatsPayload{AgentEnv: "prod", Stats: []*pb.ClientStatsPayload{prod, staging, tagged}})
+		require.NotNil(t, filtered)
+		assert.Equal(t, []*pb.ClientStatsPayload{prod, tagged}, filtered.Stats)
+	})
+
+	t.Run("host", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"host:agent"}, "", "")
+		filtered := route.filterStats(sp)
+		require.NotNil(t, filtered)
+		assert.Equal(t, []*pb.ClientStatsPayload{prod, tagged}, filtered.Stats)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"team:billing"}, "", "")
+		filtered := route.filterStats(sp)
+		require.NotNil(t, filtered)
+		assert.Equal(t, []*pb.ClientStatsPayload{tagged}, filtered.Stats)
+	})
+
+	t.Run("service", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"service:pay*"}, "", "")
+		filtered := route.filterStats(sp)
+		require.NotNil(t, filtered)
+		require.Len(t, filtered.Stats, 1)
+		assert.NotSame(t, staging, filtered.Stats[0])
+		assert.Equal(t, "staging", filtered.Stats[0].Env)
+		require.Len(t, filtered.Stats[0].Stats, 1)
+		assert.Equal(t, []*pb.ClientGroupedStats{payments}, filtered.Stats[0].Stats[0].Stats)
+		// the original payload is not modified
+		assert.Len(t, staging.Stats[0].Stats, 2)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		route := newSenderRoute(nil, []string{"service:unknown"}, "", "")
+		assert.Nil(t, route.filterStats(sp))
+	})
+}
+
+func TestStatsWriterRoutes(t *testing.T) {
+	srv := newTestServer()
+	defer srv.Close()
+	routedSrv := newTestServer()
+	defer routedSrv.Close()
+	cfg := &config.AgentConfig{
+		Endpoints: []*config.Endpoint{
+			{APIKey: "123", Host: srv.URL},
+			{APIKey: "456", Host: routedSrv.URL, RoutingRules: []string{"service:payments"}},
+		},
+		StatsWriter:   &config.WriterConfig{ConnectionLimit: 20, QueueSize: 20},
+		ContainerTags: func(_ string) ([]string, error) { return nil, nil },
+	}
+	sw := NewStatsWriter(cfg, telemetry.NewNoopCollector(), &statsd.NoOpClient{}, &timing.NoopReporter{})
+	go sw.Run()
+
+	sw.Write(&pb.StatsPayload{Stats: []*pb.ClientStatsPayload{{
+		Env: testEnv,
+		Stats: []*pb.ClientStatsBucket{{Start: 1, Duration: 10, Stats: []*pb.ClientGroupedStats{
+			{Service: "checkout", Name: "span", Hits: 1},
+			{Service: "payments", Name: "span", Hits: 1},
+		}}},
+	}}})
+	sw.Stop()
+
+	services := func(payloads []*payload) []string {
+		var services []string
+		for _, p := range payloads {
+			r, err := stdgzip.NewReader(p.body)
+			require.NoError(t, err)
+			var sp pb.StatsPayload
+			require.NoError(t, msgp.Decode(r, &sp))
+			for _, csp := range sp.Stats {
+				for _, b := range csp.Stats {
+					for _, g := range b.Stats {
+						services = append(services, g.Service)
+					}
+				}
+			}
+		}
+		return services
+	}
+	assert.ElementsMatch(t, []string{"checkout", "payments"}, services(srv.Payloads()))
+	assert.Equal(t, []string{"payments"}, services(routedSrv.Payloads()))
+}
@@ -48,6 +48,11 @@ type DatadogStatsWriter struct {
 	stats   *info.StatsWriterInfo
 	conf    *config.AgentConfig
 
+	// unroutedSenders receive all the stats, the senders of the endpoints
+	// with routing rules only receive the stats matching their route.
+	unroutedSenders []*sender
+	routes          []*senderRoute
+
 	// syncMode reports whether the writer should flush on its own or only when FlushSync is called
 	syncMode  bool
 	payloads  []*pb.StatsPayload // payloads buffered for sync mode
@@ -93,6 +98,7 @@ func NewStatsWriter(
 	}
 	log.Debugf("Stats writer initialized (climit=%d qsize=%d)", climit, qsize)
 	sw.senders = newSenders(cfg, sw, pathStats, climit, qsize, telemetryCollector, statsd)
+	sw.unroutedSenders, sw.routes = newSenderRoutes(cfg, sw.senders)
 	return sw
 }
 
@@ -162,6 +168,17 @@ func (w *DatadogStatsWriter) addStats(sp *pb.StatsPayload) {
 
 // SendPayload sends a stats payload to the Datadog backend.
 func (w *DatadogStatsWriter) SendPayload(p *pb.StatsPayload) {
+	if len(w.unroutedSenders) > 0 {
+		w.sendPayload(p, w.unroutedSenders)
+	}
+	for _, route := range w.routes {
+		if routed := route.filterStats(p); routed != nil {
+			w.sendPayload(routed, []*sender{route.sender})
+		}
+	}
+}
+
+func (w *DatadogStatsWriter) sendPayload(p *pb.StatsPayload, senders []*sender) {
 	req := newPayload(map[string]string{
 		headerLanguages:    strings.Join(info.Languages(), "|"),
 		"Content-Type":     "application/msgpack",
@@ -171,7 +188,7 @@ func (w *DatadogStatsWriter) SendPayload(p *pb.StatsPayload) {
 		log.Errorf("Stats encoding error: %v", err)
 		return
 	}
-	sendPayloads(w.senders, req, w.syncMode)
+	sendPayloads(senders, req, w.syncMode)
 }
 
 func (w *DatadogStatsWriter) sendPayloads() {
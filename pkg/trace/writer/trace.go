@@ -68,6 +68,11 @@ type TraceWriter struct {
 	tick         time.Duration  // flush frequency
 	agentVersion string
 
+	// unroutedSenders receive all the traces, the senders of the endpoints
+	// with routing rules only receive the traces matching their route.
+	unroutedSenders []*sender
+	routes          []*senderRoute
+
 	tracerPayloads []*pb.TracerPayload // tracer payloads buffered
 	bufferedSize   int                 // estimated buffer size
 
@@ -129,6 +134,7 @@ func NewTraceWriter(
 	qsize := 1
 	log.Infof("Trace writer initialized (climit=%d qsize=%d compression=%s)", climit, qsize, compressor.Encoding())
 	tw.senders = newSenders(cfg, tw, pathTraces, climit, qsize, telemetryCollector, statsd)
+	tw.unroutedSenders, tw.routes = newSenderRoutes(cfg, tw.senders)
 	tw.wg.Add(1)
 	go tw.timeFlush()
 	tw.wg.Add(1)
@@ -266,7 +272,25 @@ func (w *TraceWriter) flushPayloads(payloads []*pb.TracerPayload) {
 	}
 	log.Debugf("Reported agent rates: target_tps=%v errors_tps=%v rare_sampling=%v", p.TargetTPS, p.ErrorTPS, p.RareSamplerEnabled)
 
-	w.serialize(&p)
+	if len(w.unroutedSenders) > 0 {
+		w.serialize(&p, w.unroutedSenders)
+	}
+	for _, route := range w.routes {
+		routed := route.filter(payloads)
+		if len(routed) == 0 {
+			continue
+		}
+		rp := pb.AgentPayload{
+			AgentVersion:       p.AgentVersion,
+			HostName:           p.HostName,
+			Env:                p.Env,
+			TargetTPS:          p.TargetTPS,
+			ErrorTPS:           p.ErrorTPS,
+			RareSamplerEnabled: p.RareSamplerEnabled,
+			TracerPayloads:     routed,
+		}
+		w.serialize(&rp, []*sender{route.sender})
+	}
 }
 
 var outPool = sync.Pool{}
@@ -283,7 +307,7 @@ func getBS(size int) []byte {
 	return bs[:size]
 }
 
-func (w *TraceWriter) serialize(pl *pb.AgentPayload) {
+func (w *TraceWriter) serialize(pl *pb.AgentPayload, senders []*sender) {
 	b := getBS(pl.SizeVT())
 	defer outPool.Put(b)
 	n, err := pl.MarshalToSizedBufferVT(b)
@@ -313,7 +337,7 @@ func (w *TraceWriter) serialize(pl *pb.AgentPayload) {
 	if err := writer.Close(); err != nil {
 		log.Errorf("Error closing %s stream when writing trace payload: %v", w.compressor.Encoding(), err)
 	}
-	sendPayloads(w.senders, p, w.syncMode)
+	sendPayloads(senders, p, w.syncMode)
 
 }
 
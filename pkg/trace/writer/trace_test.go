@@ -520,7 +520,7 @@ func BenchmarkSerialize(b *testing.B) {
 			b.ResetTimer()
 			b.ReportAllocs()
 			for i := 0; i < b.N; i++ {
-				tw.serialize(&p)
+				tw.serialize(&p, tw.senders)
 			}
 		})
 	}
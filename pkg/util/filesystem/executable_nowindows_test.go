@@ -0,0 +1,45 @@
+// Unless explicitly stated otherwise all files in this repository are licensed
+// under the Apache License Version 2.0.
+// This product includes software developed at Datadog (https://www.datadoghq.com/).
+// Copyright 2016-present Datadog, Inc.
+
+//go:build !windows
+
+package filesystem
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckExecutableRights(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "executable")
+	assert.Error(t, CheckExecutableRights(path, false))
+
+	for _, tc := range []struct {
+		mode           os.FileMode
+		allowGroupExec bool
+		valid          bool
+	}{
+		{0700, false, true},
+		{0100, false, true},
+		{0600, false, false},
+		{0710, false, false},
+		{0701, false, false},
+		{0750, true, true},
+		{0770, true, false},
+		{0701, true, false},
+	} {
+		assert.NoError(t, os.WriteFile(path, nil, tc.mode))
+		assert.NoError(t, os.Chmod(path, tc.mode))
+		err := CheckExecutableRights(path, tc.allowGroupExec)
+		if tc.valid {
+			assert.NoError(t, err, "%o", tc.mode)
+		} else {
+			assert.Error(t, err, "%o", tc.mode)
+		}
+	}
+}
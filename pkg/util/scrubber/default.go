@@ -123,6 +123,16 @@ func AddDefaultReplacers(scrubber *Scrubber) {
 		"community_strings",
 		[]byte(`$1 "********"`),
 	)
+	encryptionKeyReplacer := matchYAMLKey(
+		`(forwarder_storage_encryption_key)`,
+		[]string{"forwarder_storage_encryption_key"},
+		[]byte(`$1 "********"`),
+	)
+	encryptionKeysMultilineReplacer := matchYAMLKeyWithListValue(
+		"(forwarder_storage_encryption_previous_keys)",
+		"forwarder_storage_encryption_previous_keys",
+		[]byte(`$1 "********"`),
+	)
 	certReplacer := Replacer{
 		/*
 		   Try to match as accurately as possible. RFC 7468's ABNF
@@ -182,11 +192,13 @@ func AddDefaultReplacers(scrubber *Scrubber) {
 	scrubber.AddReplacer(SingleLine, passwordReplacer)
 	scrubber.AddReplacer(SingleLine, tokenReplacer)
 	scrubber.AddReplacer(SingleLine, snmpReplacer)
+	scrubber.AddReplacer(SingleLine, encryptionKeyReplacer)
 
 	scrubber.AddReplacer(SingleLine, apiKeyYaml)
 	scrubber.AddReplacer(SingleLine, appKeyYaml)
 
 	scrubber.AddReplacer(MultiLine, snmpMultilineReplacer)
+	scrubber.AddReplacer(MultiLine, encryptionKeysMultilineReplacer)
 	scrubber.AddReplacer(MultiLine, certReplacer)
 
 	dynamicReplacersMutex.Lock()
@@ -556,6 +556,26 @@ other_config_with_list: [abc]
 		`privacy_key: "********"`)
 }
 
+func TestForwarderStorageEncryptionKeys(t *testing.T) {
+	assertClean(t,
+		`forwarder_storage_encryption_key: 0123456789abcdef0123456789abcdef`,
+		`forwarder_storage_encryption_key: "********"`)
+	assertClean(t,
+		`
+forwarder_storage_encryption_previous_keys:
+  - 0123456789abcdef0123456789abcdef
+  - fedcba9876543210fedcba9876543210
+other_config: 1
+`,
+		`
+forwarder_storage_encryption_previous_keys: "********"
+other_config: 1
+`)
+	assertClean(t,
+		`forwarder_storage_encryption_previous_keys: ['0123456789abcdef0123456789abcdef']`,
+		`forwarder_storage_encryption_previous_keys: "********"`)
+}
+
 func TestAddStrippedKeys(t *testing.T) {
 	contents := `foobar: baz`
 	cleaned, err := ScrubBytes([]byte(contents))
@@ -187,6 +187,26 @@ DATADOG_AGENT_RTLOADER_API char *run_check(rtloader_t *, rtloader_pyobject_t *ch
 */
 DATADOG_AGENT_RTLOADER_API void cancel_check(rtloader_t *, rtloader_pyobject_t *check);
 
+/*! \fn unsigned long get_thread_id(rtloader_t *)
+    \brief Returns the identifier of the Python thread running on the calling thread, to
+    interrupt it with interrupt_thread. The GIL must be held.
+    \param rtloader_t A rtloader_t * pointer to the RtLoader instance.
+    \return The identifier of the Python thread.
+    \sa rtloader_t
+*/
+DATADOG_AGENT_RTLOADER_API unsigned long get_thread_id(rtloader_t *);
+
+/*! \fn int interrupt_thread(rtloader_t *, unsigned long thread_id)
+    \brief Interrupts a Python thread, for instance running a check which exceeded its run
+    timeout, by raising a TimeoutError in it the next time it executes Python code. The GIL
+    must be held.
+    \param rtloader_t A rtloader_t * pointer to the RtLoader instance.
+    \param thread_id The identifier of the Python thread, as returned by get_thread_id.
+    \return An integer set to 1 if the thread was interrupted, 0 if it wasn't found.
+    \sa rtloader_t
+*/
+DATADOG_AGENT_RTLOADER_API int interrupt_thread(rtloader_t *, unsigned long thread_id);
+
 /*! \fn char **get_checks_warnings(rtloader_t *, rtloader_pyobject_t *check)
     \brief Get all warnings, if any, for a check instance.
     \param rtloader_t A rtloader_t * pointer to the RtLoader instance.
@@ -115,6 +115,25 @@ public:
     */
     virtual void cancelCheck(RtLoaderPyObject *check) = 0;
 
+    //! Pure virtual getThreadId member.
+    /*!
+      \return The identifier of the Python thread running on the calling thread.
+
+      The GIL must be held when calling this method.
+    */
+    virtual unsigned long getThreadId() = 0;
+
+    //! Pure virtual interruptThread member.
+    /*!
+      \param thread_id The identifier of the Python thread we wish to interrupt.
+      \return A boolean indicating whether a thread was found and interrupted.
+
+      Raises a TimeoutError in the Python thread the next time it executes Python code,
+      it doesn't interrupt blocking calls in C code. The GIL must be held when calling this
+      method.
+    */
+    virtual bool interruptThread(unsigned long thread_id) = 0;
+
     //! Pure virtual getCheckWarnings member.
     /*!
       \param check The python object pointer to the check we wish to collect existing warnings for.
@@ -272,6 +272,16 @@ void cancel_check(rtloader_t *rtloader, rtloader_pyobject_t *check)
     AS_TYPE(RtLoader, rtloader)->cancelCheck(AS_TYPE(RtLoaderPyObject, check));
 }
 
+unsigned long get_thread_id(rtloader_t *rtloader)
+{
+    return AS_TYPE(RtLoader, rtloader)->getThreadId();
+}
+
+int interrupt_thread(rtloader_t *rtloader, unsigned long thread_id)
+{
+    return AS_TYPE(RtLoader, rtloader)->interruptThread(thread_id) ? 1 : 0;
+}
+
 char **get_checks_warnings(rtloader_t *rtloader, rtloader_pyobject_t *check)
 {
     return AS_TYPE(RtLoader, rtloader)->getCheckWarnings(AS_TYPE(RtLoaderPyObject, check));
@@ -482,6 +482,17 @@ void Three::cancelCheck(RtLoaderPyObject *check)
     Py_XDECREF(result);
 }
 
+unsigned long Three::getThreadId()
+{
+    return PyThread_get_thread_ident();
+}
+
+bool Three::interruptThread(unsigned long thread_id)
+{
+    // returns the number of thread states modified, 0 if the thread wasn't found
+    return PyThreadState_SetAsyncExc(thread_id, PyExc_TimeoutError) == 1;
+}
+
 char **Three::getCheckWarnings(RtLoaderPyObject *check)
 {
     if (check == NULL) {
@@ -86,6 +86,13 @@ CLANG_VERSION_RUNTIME = "12.0.1"
 CLANG_VERSION_SYSTEM_PREFIX = "12.0"
 
 TEST_HELPER_CBINS = ["cudasample"]
+# Rust binaries built for the tests: the Cargo package, relative to the test
+# package, the binary it builds, the name of the copy next to the manifest and
+# the RUSTFLAGS to build it with.
+TEST_HELPER_RUST_BINS = [
+    ("testdata/statictls/rustls", "rustls-test", "rustls-test", ""),
+    ("testdata/statictls/rustls", "rustls-test", "rustls-test-v0", "-C symbol-mangling-version=v0"),
+]
 
 
 def get_ebpf_build_dir(arch: Arch) -> Path:
@@ -472,6 +479,9 @@ def ninja_cgo_type_files(nw: NinjaWriter):
             "pkg/network/protocols/http/gotls/go_tls_types.go": [
                 "pkg/network/ebpf/c/protocols/tls/go-tls-types.h",
             ],
+            "pkg/network/protocols/tls/rustls/types.go": [
+                "pkg/network/ebpf/c/protocols/tls/rustls-types.h",
+            ],
             "pkg/network/protocols/http/types.go": [
                 "pkg/network/ebpf/c/tracer/tracer.h",
                 "pkg/network/ebpf/c/protocols/tls/tags-types.h",
@@ -492,6 +502,12 @@ def ninja_cgo_type_files(nw: NinjaWriter):
             "pkg/network/protocols/redis/types.go": [
                 "pkg/network/ebpf/c/protocols/redis/types.h",
             ],
+            "pkg/network/protocols/memcached/types.go": [
+                "pkg/network/ebpf/c/protocols/memcached/types.h",
+            ],
+            "pkg/network/protocols/cassandra/types.go": [
+                "pkg/network/ebpf/c/protocols/cassandra/types.h",
+            ],
             "pkg/ebpf/telemetry/types.go": [
                 "pkg/ebpf/c/telemetry_types.h",
             ],
@@ -990,6 +1006,16 @@ def kitchen_prepare(ctx, kernel_release=None, ci=False, packages=""):
                 binary = Path(target_path) / cbin
                 ctx.run(f"clang -o {binary} {source}")
 
+        for rust_pkg, rust_bin, output, rustflags in TEST_HELPER_RUST_BINS:
+            manifest = Path(pkg) / rust_pkg / "Cargo.toml"
+            if not is_windows and manifest.is_file():
+                with tempfile.TemporaryDirectory() as target_dir:
+                    ctx.run(
+                        f"cargo build --release --manifest-path {manifest} --target-dir {target_dir}",
+                        env={"RUSTFLAGS": rustflags},
+                    )
+                    shutil.copy(Path(target_dir) / "release" / rust_bin, Path(target_path) / rust_pkg / output)
+
     gopath = os.getenv("GOPATH")
     copy_files = [
         "/opt/datadog-agent/embedded/bin/clang-bpf",